@@ -0,0 +1,89 @@
+#!/usr/bin/env bash
+# Runs the e2e checks of envtop against the fixtures of e2e/fixtures:
+#   - `envtop expect` with e2e/expected/apps.yaml (resolved values)
+#   - `envtop get env` and `envtop diff` against the outputs in e2e/golden
+#     (sources, envFrom precedence, optional refs, sealed secrets, diff statuses)
+#
+# E2E_CLUSTER selects the cluster:
+#   offline  (default) the fixtures served from memory, no cluster needed
+#   kind     a throwaway kind cluster (named $KIND_CLUSTER, default envtop-e2e)
+#   current  the current kubeconfig context, e.g. an envtest API server
+#
+# With UPDATE=1 the golden outputs are rewritten instead of compared.
+set -euo pipefail
+
+root="$(cd "$(dirname "$0")/.." && pwd)"
+e2e="$root/e2e"
+cluster="${E2E_CLUSTER:-offline}"
+kind_cluster="${KIND_CLUSTER:-envtop-e2e}"
+work="$(mktemp -d)"
+trap 'rm -rf "$work"' EXIT
+
+envtop="$work/envtop"
+(cd "$root" && go build -o "$envtop" .)
+
+case "$cluster" in
+offline)
+  export ENVTOP_OFFLINE="$e2e/fixtures"
+  ;;
+kind)
+  if ! kind get clusters | grep -qx "$kind_cluster"; then
+    kind create cluster --name "$kind_cluster" --wait 120s
+    if [ -z "${KEEP_CLUSTER:-}" ]; then
+      trap 'rm -rf "$work"; kind delete cluster --name "$kind_cluster"' EXIT
+    fi
+  fi
+  export KUBECONFIG="$work/kubeconfig"
+  kind get kubeconfig --name "$kind_cluster" >"$KUBECONFIG"
+  ;;
+current) ;;
+*)
+  echo "unknown E2E_CLUSTER: $cluster (offline, kind or current)" >&2
+  exit 2
+  ;;
+esac
+if [ "$cluster" != offline ]; then
+  kubectl apply -f "$e2e/fixtures/00-namespaces.yaml"
+  kubectl apply -f "$e2e/fixtures"
+fi
+
+# Isolate the runs from the config of the developer
+export ENVTOP_CONFIG="$work/config.yaml"
+
+failed=0
+
+echo "--- expect"
+if ! "$envtop" expect -f "$e2e/expected/apps.yaml"; then
+  failed=1
+fi
+
+# check NAME CMD... runs an envtop command and compares its output with
+# e2e/golden/NAME. diff exits with 2 on differences, which is expected here.
+check() {
+  local name="$1"
+  shift
+  echo "--- $name"
+  local status=0
+  "$envtop" "$@" >"$work/$name" || status=$?
+  if [ "$status" -ne 0 ] && [ "$status" -ne 2 ]; then
+    echo "envtop $* exited with $status" >&2
+    failed=1
+    return
+  fi
+  if [ -n "${UPDATE:-}" ]; then
+    cp "$work/$name" "$e2e/golden/$name"
+  elif ! diff -u "$e2e/golden/$name" "$work/$name"; then
+    failed=1
+  fi
+}
+
+check get-env-e2e-a.txt get env -n e2e-a
+check get-env-e2e-b.txt get env -n e2e-b
+check diff-api.txt diff --ns-a e2e-a --ns-b e2e-b --app api
+check diff-worker.diff diff --ns-a e2e-a --ns-b e2e-b --app worker --format unified
+
+if [ "$failed" -ne 0 ]; then
+  echo "FAIL ($cluster)"
+  exit 1
+fi
+echo "PASS ($cluster)"
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/policy"
+	"github.com/ginbear/k8s-envtop/internal/upload"
+)
+
+// loadConfig loads the envtop config file from its default location
+func loadConfig() (*config.Config, error) {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return config.Load(path)
+}
+
+// loadPolicies loads the value policies of the config file plus extra policy files
+func loadPolicies(cfg *config.Config, extra []string) (*policy.Set, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	paths := append([]string{}, cfg.Policies...)
+	for _, p := range extra {
+		// Policy files given on the command line are relative to the working directory
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, abs)
+	}
+	return policy.LoadFiles(dir, paths)
+}
+
+// regoHook builds the Rego hook from the config file plus extra policy paths
+func regoHook(cfg *config.Config, extra []string) (*policy.RegoHook, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	paths := policy.ResolvePaths(dir, cfg.Rego.Policies)
+	for _, p := range extra {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, abs)
+	}
+	return &policy.RegoHook{
+		Binary:   cfg.Rego.Binary,
+		Policies: paths,
+		Query:    cfg.Rego.Query,
+	}, nil
+}
+
+// newResolver creates an env resolver applying the configured redaction rules
+func newResolver(client *k8s.Client) (*env.Resolver, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	resolver := env.NewResolver(client)
+	resolver.SetRedactRule(cfg.IsRedacted)
+	return resolver, nil
+}
+
+// namespaceTier returns the tier of a namespace as resolved by tierOf from its labels
+func namespaceTier(ctx context.Context, client *k8s.Client, tierOf func(string, map[string]string) string, namespace string) (string, error) {
+	namespaces, err := client.ListNamespaceDetails(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, ns := range namespaces {
+		if ns.Name == namespace {
+			return tierOf(ns.Name, ns.Labels), nil
+		}
+	}
+	return tierOf(namespace, nil), nil
+}
+
+// teeUpload returns a writer that also buffers the output, and a function
+// uploading the buffer to a presigned URL. Without a URL, w is returned as is.
+func teeUpload(w io.Writer, url, contentType string) (io.Writer, func(ctx context.Context) error) {
+	if url == "" {
+		return w, func(context.Context) error { return nil }
+	}
+	var buf bytes.Buffer
+	return io.MultiWriter(w, &buf), func(ctx context.Context) error {
+		return upload.Put(ctx, url, contentType, buf.Bytes())
+	}
+}
+
+// findApp looks up an app by name in a namespace, optionally restricted to a kind
+func findApp(ctx context.Context, client *k8s.Client, namespace, name, kind string) (k8s.App, error) {
+	apps, err := client.ListApps(ctx, namespace)
+	if err != nil {
+		return k8s.App{}, err
+	}
+
+	for _, app := range apps {
+		if app.Name == name && (kind == "" || string(app.Kind) == kind) {
+			return app, nil
+		}
+	}
+	return k8s.App{}, fmt.Errorf("app %s not found in namespace %s", name, namespace)
+}
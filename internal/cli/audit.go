@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// rotationEntry is a Secret key in the JSON output of `envtop audit rotation`
+type rotationEntry struct {
+	Secret     string             `json:"secret"`
+	Key        string             `json:"key"`
+	Sealed     bool               `json:"sealed"`
+	Missing    bool               `json:"missing,omitempty"`
+	CreatedAt  *time.Time         `json:"createdAt,omitempty"`
+	ModifiedAt *time.Time         `json:"modifiedAt,omitempty"`
+	AgeDays    int                `json:"ageDays"`
+	Consumers  []rotationConsumer `json:"consumers"`
+}
+
+// rotationConsumer is an app variable reading the Secret key
+type rotationConsumer struct {
+	App       string      `json:"app"`
+	Kind      k8s.AppKind `json:"kind"`
+	Container string      `json:"container"`
+	Variable  string      `json:"variable"`
+}
+
+// RunAudit implements the `envtop audit` subcommands
+func RunAudit(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: envtop audit rotation [flags]")
+	}
+	switch args[0] {
+	case "rotation":
+		return runAuditRotation(args[1:], stdout)
+	}
+	return fmt.Errorf("unknown audit command: %s", args[0])
+}
+
+// runAuditRotation lists every secret-backed variable of a namespace by Secret key,
+// with the age and last change of the Secret and the apps consuming it, as the
+// input of rotation planning. Values are never read out.
+func runAuditRotation(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("audit rotation", flag.ContinueOnError)
+	namespace := fs.String("namespace", "", "namespace to audit")
+	fs.StringVar(namespace, "n", "", "namespace to audit (shorthand)")
+	output := fs.String("output", "text", "output format: text or json")
+	fs.StringVar(output, "o", "text", "output format (shorthand)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *namespace == "" {
+		return errors.New("--namespace is required")
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format: %s", *output)
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	resolver, err := newResolver(client)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	apps, err := client.ListApps(ctx, *namespace)
+	if err != nil {
+		return err
+	}
+	usages, err := resolver.SecretKeyUsages(ctx, apps)
+	if err != nil {
+		return &ResolutionError{Err: err}
+	}
+
+	now := time.Now()
+	if *output == "json" {
+		entries := make([]rotationEntry, 0, len(usages))
+		for _, u := range usages {
+			entries = append(entries, newRotationEntry(u, now))
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(usages) == 0 {
+		fmt.Fprintf(stdout, "No secret-backed variables in %s\n", *namespace)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SECRET\tKEY\tAGE\tLAST CHANGE\tCONSUMERS")
+	for _, u := range usages {
+		secret := u.Secret
+		if u.Sealed {
+			secret += " (sealed)"
+		}
+		age, changed := "missing", "missing"
+		if !u.Missing {
+			age = fmt.Sprintf("%dd", ageDays(u.CreatedAt, now))
+			changed = u.ModifiedAt.Format("2006-01-02")
+		}
+		consumers := make([]string, 0, len(u.Consumers))
+		for _, c := range u.Consumers {
+			consumers = append(consumers, fmt.Sprintf("%s/%s:%s", c.App.Name, c.Container, c.Variable))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", secret, u.Key, age, changed, strings.Join(consumers, ","))
+	}
+	return tw.Flush()
+}
+
+func newRotationEntry(u env.SecretKeyUsage, now time.Time) rotationEntry {
+	entry := rotationEntry{Secret: u.Secret, Key: u.Key, Sealed: u.Sealed, Missing: u.Missing}
+	if !u.Missing {
+		created, modified := u.CreatedAt, u.ModifiedAt
+		entry.CreatedAt, entry.ModifiedAt = &created, &modified
+		entry.AgeDays = ageDays(u.CreatedAt, now)
+	}
+	entry.Consumers = make([]rotationConsumer, 0, len(u.Consumers))
+	for _, c := range u.Consumers {
+		entry.Consumers = append(entry.Consumers, rotationConsumer{App: c.App.Name, Kind: c.App.Kind, Container: c.Container, Variable: c.Variable})
+	}
+	return entry
+}
+
+// ageDays returns the number of whole days since t
+func ageDays(t, now time.Time) int {
+	return int(now.Sub(t).Hours() / 24)
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/preview"
+)
+
+// RunCleanup implements the `envtop cleanup` subcommand, reporting stale
+// preview namespaces with the commands deleting them. Nothing is deleted.
+func RunCleanup(args []string, stdout io.Writer) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("cleanup", flag.ContinueOnError)
+	days := fs.Int("older-than", cfg.PreviewMaxAgeDays(), "report preview namespaces older than this many days")
+	commandsOnly := fs.Bool("commands", false, "print only the deletion commands")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	now := time.Now()
+	stale, err := preview.FindStale(ctx, client, cfg, time.Duration(*days)*24*time.Hour, now)
+	if err != nil {
+		return err
+	}
+	commands := preview.DeleteCommands(client.GetCurrentContext(), stale)
+
+	if *commandsOnly {
+		for _, command := range commands {
+			fmt.Fprintln(stdout, command)
+		}
+		return nil
+	}
+
+	if len(stale) == 0 {
+		fmt.Fprintf(stdout, "No preview namespaces older than %d days\n", *days)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tCREATED\tAGE\tAPPS")
+	for _, ns := range stale {
+		age := int(now.Sub(ns.CreatedAt).Hours() / 24)
+		fmt.Fprintf(tw, "%s\t%s\t%dd\t%s\n", ns.Name, ns.CreatedAt.Format("2006-01-02"), age, strings.Join(ns.Apps, ","))
+	}
+	tw.Flush()
+
+	fmt.Fprintln(stdout)
+	for _, command := range commands {
+		fmt.Fprintln(stdout, command)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Exit codes shared by all headless subcommands
+const (
+	ExitOK         = 0 // success, no drift
+	ExitError      = 1 // usage or unexpected error
+	ExitDrift      = 2 // drift (or a stale/failed check) was detected
+	ExitResolution = 3 // env resolution failed
+	ExitAuth       = 4 // authentication or authorization failure
+	ExitUnverified = 5 // a check passed only partly, e.g. values were not compared
+)
+
+// ErrDrift is returned by subcommands when they detected drift.
+// It is reported through the exit code only.
+var ErrDrift = errors.New("drift detected")
+
+// ErrUnverified is returned by subcommands when a check could not be completed,
+// e.g. verify without a private key. It is reported through the exit code only.
+var ErrUnverified = errors.New("check not completed")
+
+// ResolutionError wraps a failure to resolve workloads or their env sources
+type ResolutionError struct {
+	Err error
+}
+
+func (e *ResolutionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// subcommand is the signature of a headless subcommand
+type subcommand func(args []string, stdin io.Reader, stdout io.Writer) error
+
+// subcommands maps names to their implementations
+var subcommands = map[string]subcommand{
+	"seal": RunSeal,
+	"verify": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunVerify(args, stdout)
+	},
+	"diff": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunDiff(args, stdout)
+	},
+	"lint": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunLint(args, stdout)
+	},
+	"export": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunExport(args, stdout)
+	},
+	"report": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunReport(args, stdout)
+	},
+	"expect": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunExpect(args, stdout)
+	},
+	"cleanup": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunCleanup(args, stdout)
+	},
+	"query": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunQuery(args, stdout)
+	},
+	"list": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunList(args, stdout)
+	},
+	"debug": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunDebug(args, stdout)
+	},
+	"audit": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunAudit(args, stdout)
+	},
+	"get": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunGet(args, stdout)
+	},
+	"preview": func(args []string, _ io.Reader, stdout io.Writer) error {
+		return RunPreview(args, stdout)
+	},
+}
+
+// Run executes the named subcommand and returns its exit code.
+// ok is false when name is not a subcommand.
+func Run(name string, args []string) (code int, ok bool) {
+	run, ok := subcommands[name]
+	if !ok {
+		return 0, false
+	}
+
+	err := run(args, os.Stdin, os.Stdout)
+	if err != nil && !errors.Is(err, ErrDrift) && !errors.Is(err, ErrUnverified) && !errors.Is(err, flag.ErrHelp) {
+		printError(os.Stderr, err, wantsJSON(args))
+	}
+	return ExitCode(err), true
+}
+
+// errorReport is the JSON form of a failure on stderr
+type errorReport struct {
+	Error struct {
+		Kind     k8s.ErrorKind `json:"kind,omitempty"`
+		Message  string        `json:"message"`
+		Hint     string        `json:"hint,omitempty"`
+		ExitCode int           `json:"exitCode"`
+	} `json:"error"`
+}
+
+// printError reports the failure of a subcommand with the remediation hint of
+// the errors envtop can classify, as JSON for callers that asked for JSON output
+func printError(w io.Writer, err error, asJSON bool) {
+	var classified *k8s.Error
+	errors.As(k8s.Classify(err), &classified)
+
+	if asJSON {
+		var rep errorReport
+		rep.Error.Message = err.Error()
+		rep.Error.ExitCode = ExitCode(err)
+		if classified != nil {
+			rep.Error.Kind = classified.Kind
+			rep.Error.Hint = classified.Hint
+		}
+		data, _ := json.Marshal(rep)
+		fmt.Fprintln(w, string(data))
+		return
+	}
+	fmt.Fprintf(w, "Error: %v\n", err)
+	if classified != nil {
+		fmt.Fprintf(w, "Hint: %s\n", classified.Hint)
+	}
+}
+
+// wantsJSON returns true if the arguments of a subcommand ask for JSON output
+// (--format json, --output json or -o json), or $ENVTOP_ERRORS is "json"
+func wantsJSON(args []string) bool {
+	if os.Getenv("ENVTOP_ERRORS") == "json" {
+		return true
+	}
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || (name != "format" && name != "output" && name != "o") {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			value = args[i+1]
+		}
+		if value == "json" {
+			return true
+		}
+	}
+	return false
+}
+
+// ExitCode maps an error returned by a subcommand to its exit code
+func ExitCode(err error) int {
+	var resErr *ResolutionError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.Is(err, ErrDrift):
+		return ExitDrift
+	case errors.Is(err, ErrUnverified):
+		return ExitUnverified
+	case apierrors.IsUnauthorized(err), apierrors.IsForbidden(err):
+		return ExitAuth
+	case errors.As(err, &resErr):
+		return ExitResolution
+	default:
+		return ExitError
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/fixture"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/report"
+)
+
+// defaultFixturePath is the bundle written by `envtop debug capture`
+const defaultFixturePath = "envtop-fixture.json"
+
+// RunDebug implements the `envtop debug` subcommands used for bug reports:
+// capture records the API responses of a resolution, replay resolves from them
+func RunDebug(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: envtop debug capture|replay [flags]")
+	}
+	switch args[0] {
+	case "capture":
+		return runDebugCapture(args[1:], stdout)
+	case "replay":
+		return runDebugReplay(args[1:], stdout)
+	}
+	return fmt.Errorf("unknown debug command: %s", args[0])
+}
+
+// runDebugCapture resolves an app through a recording client and writes the
+// sanitized responses to a fixture bundle. The bundle is written even when the
+// resolution fails, since that is usually what the bug report is about.
+func runDebugCapture(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("debug capture", flag.ContinueOnError)
+	namespace := fs.String("namespace", "", "namespace of the app")
+	fs.StringVar(namespace, "n", "", "namespace of the app (shorthand)")
+	appName := fs.String("app", "", "app to capture")
+	kind := fs.String("kind", "", "restrict --app to Deployment, StatefulSet, CronJob or Job")
+	file := fs.String("file", defaultFixturePath, "fixture bundle to write")
+	fs.StringVar(file, "f", defaultFixturePath, "fixture bundle to write (shorthand)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *namespace == "" || *appName == "" {
+		return errors.New("--namespace and --app are required")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	recorder := fixture.NewRecorder(cfg.IsRedacted)
+	client, err = client.WithTransport(recorder.Wrap)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	app, resolveErr := findApp(ctx, client, *namespace, *appName, *kind)
+	if resolveErr == nil {
+		resolver := env.NewResolver(client)
+		resolver.SetRedactRule(cfg.IsRedacted)
+		_, resolveErr = resolver.ResolveAppEnvVars(ctx, app)
+	} else {
+		app = k8s.App{Name: *appName, Namespace: *namespace, Kind: k8s.AppKind(*kind)}
+	}
+
+	bundle := recorder.Bundle(client.GetCurrentContext(), *namespace, app)
+	if err := bundle.Write(*file); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Wrote %d responses to %s\n", len(bundle.Entries), *file)
+	fmt.Fprintln(stdout, "Secret values are replaced by hashes; review the file before attaching it to a bug report.")
+
+	if resolveErr != nil {
+		fmt.Fprintf(os.Stderr, "resolution failed (captured): %v\n", resolveErr)
+		return &ResolutionError{Err: resolveErr}
+	}
+	return nil
+}
+
+// runDebugReplay resolves the app of a fixture bundle from its recorded responses
+func runDebugReplay(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("debug replay", flag.ContinueOnError)
+	file := fs.String("file", defaultFixturePath, "fixture bundle to replay")
+	fs.StringVar(file, "f", defaultFixturePath, "fixture bundle to replay (shorthand)")
+	output := fs.String("output", "text", "output format: text or json")
+	fs.StringVar(output, "o", "text", "output format (shorthand)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format: %s", *output)
+	}
+
+	bundle, err := fixture.Load(*file)
+	if err != nil {
+		return err
+	}
+	client, err := bundle.Client()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	app, err := findApp(ctx, client, bundle.Namespace, bundle.App, string(bundle.Kind))
+	if err != nil {
+		return &ResolutionError{Err: err}
+	}
+	resolver, err := newResolver(client)
+	if err != nil {
+		return err
+	}
+	envVars, err := resolver.ResolveAppEnvVars(ctx, app)
+	if err != nil {
+		return &ResolutionError{Err: err}
+	}
+	return printAppEnv(stdout, report.NewAppEnv(client.GetCurrentContext(), app, envVars), *output)
+}
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/drift"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/report"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RunDiff implements the `envtop diff` subcommand, comparing an app's env
+// between two namespaces. With --format html and no --app, every app shared by
+// both namespaces is compared into a single report.
+func RunDiff(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	nsA := fs.String("ns-a", "", "namespace A (compare from)")
+	nsB := fs.String("ns-b", "", "namespace B (compare with)")
+	appName := fs.String("app", "", "name of the Deployment/StatefulSet (optional with --format html)")
+	kind := fs.String("kind", "", "restrict to Deployment, StatefulSet, CronJob or Job")
+	format := fs.String("format", "text", "output format: text, json, markdown, unified or html")
+	output := fs.String("o", "", "output file (default stdout)")
+	uploadURL := fs.String("upload", "", "also upload the output to this presigned S3/GCS/Azure Blob URL")
+	schema := fs.Bool("schema", false, "print the JSON schema of the json output and exit")
+	includeSidecars := fs.Bool("include-sidecars", false, "also compare the variables of Istio/Linkerd sidecar containers")
+	record := fs.Bool("record", false, "record the drift scores of --format html without --app in the history file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *schema {
+		_, err := stdout.Write(report.DiffSchema())
+		return err
+	}
+
+	if *nsA == "" || *nsB == "" {
+		return errors.New("--ns-a and --ns-b are required")
+	}
+	switch *format {
+	case "text", "json", "markdown", "unified":
+		if *appName == "" {
+			return errors.New("--app is required")
+		}
+	case "html":
+	default:
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		stdout = f
+	}
+	stdout, upload := teeUpload(stdout, *uploadURL, diffContentType(*format))
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	resolver, err := newResolver(client)
+	if err != nil {
+		return err
+	}
+	resolver.SetIncludeSidecars(*includeSidecars)
+
+	if *format == "html" && *appName == "" {
+		err := runBulkDiff(ctx, client, resolver, *nsA, *nsB, *record, stdout)
+		if uploadErr := upload(ctx); uploadErr != nil {
+			return uploadErr
+		}
+		return err
+	}
+
+	app, err := findApp(ctx, client, *nsA, *appName, *kind)
+	if err != nil {
+		return &ResolutionError{Err: err}
+	}
+
+	envsA, err := resolver.ResolveAppDiffEnvVars(ctx, app)
+	if err != nil {
+		return &ResolutionError{Err: err}
+	}
+	envsB, err := resolver.ResolveAppDiffEnvVars(ctx, k8s.App{Name: app.Name, Namespace: *nsB, Kind: app.Kind})
+	if err != nil {
+		return &ResolutionError{Err: err}
+	}
+
+	results := env.CompareEnvVars(envsA, envsB)
+	rep := report.NewDiffReport(client.GetCurrentContext(), app, *nsA, *nsB, results)
+
+	switch *format {
+	case "json", "markdown", "unified":
+		if err := report.WriteDiff(stdout, report.DiffFormat(*format), rep); err != nil {
+			return err
+		}
+	case "html":
+		bulk := report.NewBulkDiffReport(client.GetCurrentContext(), *nsA, *nsB,
+			[]env.AppDiff{{App: app, Results: results}}, time.Now())
+		if err := report.WriteHTML(stdout, bulk); err != nil {
+			return err
+		}
+	default:
+		printDiffText(stdout, rep)
+	}
+	if err := upload(ctx); err != nil {
+		return err
+	}
+
+	if err := forbiddenErr(results); err != nil {
+		return err
+	}
+	if hasDrift(results) {
+		return ErrDrift
+	}
+	return nil
+}
+
+// diffContentType returns the MIME type of a diff output format
+func diffContentType(format string) string {
+	switch format {
+	case "json":
+		return "application/json"
+	case "markdown":
+		return "text/markdown; charset=utf-8"
+	case "unified":
+		return "text/x-diff; charset=utf-8"
+	case "html":
+		return "text/html; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// runBulkDiff compares every app shared by two namespaces and writes an HTML
+// report. The drift scores are recorded in the history with record only.
+func runBulkDiff(ctx context.Context, client *k8s.Client, resolver *env.Resolver, nsA, nsB string, record bool, stdout io.Writer) error {
+	diffs, err := resolver.CompareNamespaces(ctx, nsA, nsB)
+	if err != nil {
+		return &ResolutionError{Err: err}
+	}
+
+	now := time.Now()
+	scores, _, history, err := drift.LoadScores(diffs, client.GetCurrentContext(), nsA, nsB)
+	if err != nil {
+		return err
+	}
+	if record {
+		if err := history.Record(client.GetCurrentContext(), nsA, nsB, scores, now); err != nil {
+			return err
+		}
+	}
+	rep := report.NewBulkDiffReport(client.GetCurrentContext(), nsA, nsB, diffs, now)
+	rep.SetScores(scores)
+	if err := report.WriteHTML(stdout, rep); err != nil {
+		return err
+	}
+
+	for _, diff := range diffs {
+		if diff.Err != nil {
+			return &ResolutionError{Err: fmt.Errorf("%s: %w", diff.App.Name, diff.Err)}
+		}
+	}
+	for _, diff := range diffs {
+		if err := forbiddenErr(diff.Results); err != nil {
+			return err
+		}
+	}
+	for _, diff := range diffs {
+		if hasDrift(diff.Results) {
+			return ErrDrift
+		}
+	}
+	return nil
+}
+
+// hasDrift returns true if any compared variable differs
+func hasDrift(results []env.DiffResult) bool {
+	for _, r := range results {
+		if r.Status.IsDrift() {
+			return true
+		}
+	}
+	return false
+}
+
+// forbiddenErr returns a Forbidden error naming the first source the user may
+// not read among the compared variables, if any. Their values are unknown, so
+// a comparison without drift does not prove parity: it exits with ExitAuth.
+func forbiddenErr(results []env.DiffResult) error {
+	for _, r := range results {
+		if r.Status != env.DiffStatusForbidden {
+			continue
+		}
+		ev := r.EnvA
+		if ev == nil || !ev.Forbidden {
+			ev = r.EnvB
+		}
+		resource := "configmaps"
+		if ev.SourceKind == k8s.EnvSourceSecret {
+			resource = "secrets"
+		}
+		return apierrors.NewForbidden(schema.GroupResource{Resource: resource}, ev.SourceName,
+			fmt.Errorf("cannot compare %s", r.Name))
+	}
+	return nil
+}
+
+// printDiffText writes the diff as an aligned table
+func printDiffText(w io.Writer, rep *report.DiffReport) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "NAME\t%s\t%s\tSTATUS\n", rep.NamespaceA, rep.NamespaceB)
+	for _, r := range rep.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Name, displayValue(r.A), displayValue(r.B), r.Status)
+	}
+	tw.Flush()
+}
+
+// displayValue renders a report value for text output
+func displayValue(v *report.Value) string {
+	if v == nil {
+		return "(not present)"
+	}
+	if v.Redacted {
+		return "HASH: " + v.Hash
+	}
+	return v.Value
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/expected"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/report"
+)
+
+// RunExpect implements the `envtop expect` subcommand, cross-checking the live
+// env against an expected manifest exported by an IaC pipeline
+func RunExpect(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("expect", flag.ContinueOnError)
+	file := fs.String("f", "", "expected manifest (YAML or JSON)")
+	namespace := fs.String("namespace", "", "namespace of apps that do not declare one")
+	fs.StringVar(namespace, "n", "", "namespace of apps that do not declare one (shorthand)")
+	format := fs.String("format", "text", "output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return errors.New("-f is required")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+
+	manifest, err := expected.Load(*file)
+	if err != nil {
+		return err
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	resolver, err := newResolver(client)
+	if err != nil {
+		return err
+	}
+
+	reports := make([]*report.DiffReport, 0, len(manifest.Apps))
+	drift := false
+	var forbidden error
+	for i := range manifest.Apps {
+		want := &manifest.Apps[i]
+		ns := want.Namespace
+		if ns == "" {
+			ns = *namespace
+		}
+		if ns == "" {
+			return fmt.Errorf("%s: no namespace (set it in the manifest or pass -n)", want.Name)
+		}
+
+		app, err := findApp(ctx, client, ns, want.Name, string(want.Kind))
+		if err != nil {
+			return &ResolutionError{Err: err}
+		}
+		live, err := resolver.ResolveAppEnvVars(ctx, app)
+		if err != nil {
+			return &ResolutionError{Err: fmt.Errorf("%s: %w", app.Name, err)}
+		}
+
+		results := want.Compare(live)
+		drift = drift || hasDrift(results)
+		if forbidden == nil {
+			forbidden = forbiddenErr(results)
+		}
+		reports = append(reports, report.NewDiffReport(client.GetCurrentContext(), app, "expected", ns, results))
+	}
+
+	if *format == "json" {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+	} else {
+		printExpectText(stdout, reports)
+	}
+
+	if forbidden != nil {
+		return forbidden
+	}
+	if drift {
+		return ErrDrift
+	}
+	return nil
+}
+
+// printExpectText prints the differing variables of each app
+func printExpectText(w io.Writer, reports []*report.DiffReport) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "APP\tNAME\tEXPECTED\tLIVE\tSTATUS")
+	clean := true
+	for _, rep := range reports {
+		for _, r := range rep.Results {
+			if r.Status == env.DiffStatusSame {
+				continue
+			}
+			clean = false
+			fmt.Fprintf(tw, "%s/%s\t%s\t%s\t%s\t%s\n", rep.NamespaceB, rep.App, r.Name, displayValue(r.A), displayValue(r.B), expectStatus(r.Status))
+		}
+	}
+	if clean {
+		fmt.Fprintln(w, "Live env matches the expected manifest")
+		return
+	}
+	tw.Flush()
+}
+
+// expectStatus names a diff status from the point of view of the expected manifest
+func expectStatus(status env.DiffStatus) string {
+	switch status {
+	case env.DiffStatusOnlyInA:
+		return "MISSING"
+	case env.DiffStatusOnlyInB:
+		return "UNMANAGED"
+	default:
+		return string(status)
+	}
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/report"
+)
+
+// RunExport implements the `envtop export` subcommand, writing the resolved env
+// of a namespace as CSV or XLSX with one row per (app, variable), or the env of
+// one app as a dotenv, JSON or YAML file for local development
+func RunExport(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	namespace := fs.String("namespace", "", "namespace to export")
+	fs.StringVar(namespace, "n", "", "namespace to export (shorthand)")
+	appName := fs.String("app", "", "export only this Deployment/StatefulSet")
+	kind := fs.String("kind", "", "restrict --app to Deployment, StatefulSet, CronJob or Job")
+	format := fs.String("format", "csv", "output format: csv, xlsx, dotenv, json or yaml")
+	output := fs.String("o", "", "output file (default stdout)")
+	includeSecrets := fs.Bool("include-secrets", false, "write secret and redacted values in clear (dotenv, json and yaml only)")
+	uploadURL := fs.String("upload", "", "also upload the output to this presigned S3/GCS/Azure Blob URL")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *namespace == "" {
+		return errors.New("--namespace is required")
+	}
+	envFile, isEnvFile := report.ParseEnvFileFormat(*format)
+	if *format != "csv" && *format != "xlsx" && !isEnvFile {
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+	if isEnvFile && *appName == "" {
+		return fmt.Errorf("--app is required for --format %s", *format)
+	}
+	if *includeSecrets && !isEnvFile {
+		return errors.New("--include-secrets requires --format dotenv, json or yaml")
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	var apps []k8s.App
+	if *appName != "" {
+		app, err := findApp(ctx, client, *namespace, *appName, *kind)
+		if err != nil {
+			return &ResolutionError{Err: err}
+		}
+		apps = []k8s.App{app}
+	} else {
+		apps, err = client.ListApps(ctx, *namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	policies, err := loadPolicies(cfg, nil)
+	if err != nil {
+		return err
+	}
+	tier, err := namespaceTier(ctx, client, cfg.TierOf, *namespace)
+	if err != nil {
+		return err
+	}
+	resolver, err := newResolver(client)
+	if err != nil {
+		return err
+	}
+
+	if isEnvFile {
+		envVars, err := resolver.ResolveAppEnvVars(ctx, apps[0])
+		if err != nil {
+			return &ResolutionError{Err: fmt.Errorf("%s: %w", apps[0].Name, err)}
+		}
+		w := stdout
+		if *output != "" {
+			// The file may hold secret values: keep it private to the user
+			f, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		w, upload := teeUpload(w, *uploadURL, "text/plain")
+		if err := report.WriteEnvFile(w, envFile, envVars, *includeSecrets); err != nil {
+			return err
+		}
+		return upload(ctx)
+	}
+
+	var rows []report.EnvRow
+	for _, app := range apps {
+		envVars, err := resolver.ResolveAppEnvVars(ctx, app)
+		if err != nil {
+			return &ResolutionError{Err: fmt.Errorf("%s: %w", app.Name, err)}
+		}
+		warnings := make(map[string][]string)
+		for _, v := range policies.Evaluate(app.Namespace, tier, envVars) {
+			warnings[v.Name] = append(warnings[v.Name], fmt.Sprintf("%s: %s", v.Policy, v.Message))
+		}
+		rows = append(rows, report.NewEnvRows(app, envVars, warnings)...)
+	}
+
+	w := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	contentType := "text/csv"
+	if *format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	w, upload := teeUpload(w, *uploadURL, contentType)
+
+	if *format == "xlsx" {
+		err = report.WriteXLSX(w, rows)
+	} else {
+		err = report.WriteCSV(w, rows)
+	}
+	if err != nil {
+		return err
+	}
+	return upload(ctx)
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/report"
+)
+
+// RunGet implements the `envtop get env` subcommand: a kubectl-style table of
+// the resolved env of one app, or of every app of a namespace, filtered with
+// the selector syntax of the TUI search
+func RunGet(args []string, stdout io.Writer) error {
+	if len(args) == 0 || (args[0] != "env" && args[0] != "envs") {
+		return errors.New("usage: envtop get env --namespace NS [--app APP] [--selector EXPR] [--sort-by KEY]")
+	}
+
+	fs := flag.NewFlagSet("get env", flag.ContinueOnError)
+	namespace := fs.String("namespace", "", "namespace of the apps")
+	fs.StringVar(namespace, "n", "", "namespace of the apps (shorthand)")
+	appName := fs.String("app", "", "only this app (default: every app of the namespace)")
+	kind := fs.String("kind", "", "restrict --app to Deployment, StatefulSet, CronJob or Job")
+	selector := fs.String("selector", "", "filter expression, e.g. 'name=DB_*,kind!=Secret'")
+	fs.StringVar(selector, "l", "", "filter expression (shorthand)")
+	sortBy := fs.String("sort-by", "", "sort the variables of each app by "+strings.Join(env.SortKeys, ", "))
+	output := fs.String("output", "text", "output format: text or json")
+	fs.StringVar(output, "o", "text", "output format (shorthand)")
+	noHeaders := fs.Bool("no-headers", false, "do not print the column headers")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *namespace == "" {
+		return errors.New("--namespace is required")
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format: %s", *output)
+	}
+	filter, err := env.ParseFilter(*selector)
+	if err != nil {
+		return err
+	}
+	if *sortBy != "" && !slices.Contains(env.SortKeys, *sortBy) {
+		return fmt.Errorf("unknown sort key: %s", *sortBy)
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	var apps []k8s.App
+	if *appName != "" {
+		app, err := findApp(ctx, client, *namespace, *appName, *kind)
+		if err != nil {
+			return &ResolutionError{Err: err}
+		}
+		apps = []k8s.App{app}
+	} else {
+		apps, err = client.ListApps(ctx, *namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	resolver, err := newResolver(client)
+	if err != nil {
+		return err
+	}
+
+	ctx = env.WithFetchCache(ctx)
+	listings := make([]*report.AppEnv, 0, len(apps))
+	for _, app := range apps {
+		envVars, err := resolver.ResolveAppEnvVars(ctx, app)
+		if err != nil {
+			return &ResolutionError{Err: fmt.Errorf("%s: %w", app.Name, err)}
+		}
+		var selected []k8s.EnvVar
+		for _, ev := range envVars {
+			if filter.Match(ev) {
+				selected = append(selected, ev)
+			}
+		}
+		if *sortBy != "" {
+			if err := env.SortEnvVars(selected, *sortBy); err != nil {
+				return err
+			}
+		}
+		listings = append(listings, report.NewAppEnv(client.GetCurrentContext(), app, selected))
+	}
+
+	if *output == "json" {
+		data, err := json.MarshalIndent(listings, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	if !*noHeaders {
+		fmt.Fprintln(tw, "APP\tNAME\tCONTAINER\tSOURCE\tVALUE")
+	}
+	for _, listing := range listings {
+		for _, entry := range listing.Env {
+			source := string(entry.Value.SourceKind)
+			if entry.Value.SourceName != "" {
+				source += "/" + entry.Value.SourceName
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", listing.App, entry.Name, entry.Container, source, displayValue(entry.Value))
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/lint"
+	"github.com/ginbear/k8s-envtop/internal/policy"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RunLint implements the `envtop lint` subcommand, checking the env of the
+// apps in a namespace for common misconfigurations
+func RunLint(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	namespace := fs.String("namespace", "", "namespace to lint")
+	fs.StringVar(namespace, "n", "", "namespace to lint (shorthand)")
+	appName := fs.String("app", "", "lint only this Deployment/StatefulSet")
+	kind := fs.String("kind", "", "restrict --app to Deployment, StatefulSet, CronJob or Job")
+	format := fs.String("format", "text", "output format: text or json")
+	failOn := fs.String("fail-on", string(policy.SeverityWarning), "lowest severity failing the run: info, warning or error")
+	var policyFiles []string
+	fs.Func("policy", "value policy file (repeatable, in addition to the config file's policies)", func(s string) error {
+		policyFiles = append(policyFiles, s)
+		return nil
+	})
+	var regoFiles []string
+	fs.Func("rego", "Rego policy file or directory evaluated with opa (repeatable)", func(s string) error {
+		regoFiles = append(regoFiles, s)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	threshold, err := policy.ParseSeverity(*failOn)
+	if err != nil {
+		return err
+	}
+
+	if *namespace == "" {
+		return errors.New("--namespace is required")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	var apps []k8s.App
+	if *appName != "" {
+		app, err := findApp(ctx, client, *namespace, *appName, *kind)
+		if err != nil {
+			return &ResolutionError{Err: err}
+		}
+		apps = []k8s.App{app}
+	} else {
+		apps, err = client.ListApps(ctx, *namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	findings, err := lintApps(ctx, client, cfg, apps, policyFiles, regoFiles)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+	} else {
+		printLintText(stdout, findings)
+	}
+
+	for _, f := range findings {
+		if f.Rule == lint.RuleForbiddenSource {
+			// The env was only partly linted
+			return apierrors.NewForbidden(schema.GroupResource{}, "", fmt.Errorf("%s %s: %s", f.App, f.Name, f.Message))
+		}
+	}
+	for _, f := range findings {
+		if f.Severity.AtLeast(threshold) {
+			return ErrDrift
+		}
+	}
+	return nil
+}
+
+// lintApps lints apps with the policies and Rego hook of the config file plus
+// extra policy files
+func lintApps(ctx context.Context, client *k8s.Client, cfg *config.Config, apps []k8s.App, policyFiles, regoFiles []string) ([]lint.Finding, error) {
+	policies, err := loadPolicies(cfg, policyFiles)
+	if err != nil {
+		return nil, err
+	}
+	resolver, err := newResolver(client)
+	if err != nil {
+		return nil, err
+	}
+	linter := lint.NewLinter(client, resolver)
+	linter.SetPolicies(policies, cfg.TierOf)
+	linter.SetEnvSizeBudget(cfg.EnvSizeBudget())
+	rego, err := regoHook(cfg, regoFiles)
+	if err != nil {
+		return nil, err
+	}
+	linter.SetRegoHook(rego)
+	findings, err := linter.LintApps(ctx, apps)
+	if err != nil {
+		return nil, &ResolutionError{Err: err}
+	}
+	return findings, nil
+}
+
+// printLintText prints lint findings as an aligned table
+func printLintText(w io.Writer, findings []lint.Finding) {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "No problems found")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "APP\tNAME\tRULE\tSEVERITY\tMESSAGE")
+	for _, f := range findings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", f.App, f.Name, f.Rule, f.Severity, f.Message)
+	}
+	tw.Flush()
+}
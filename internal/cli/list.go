@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/report"
+)
+
+// listedApp is an app in the JSON output of `envtop list` without --app
+type listedApp struct {
+	Namespace string      `json:"namespace"`
+	Name      string      `json:"name"`
+	Kind      k8s.AppKind `json:"kind"`
+}
+
+// RunList implements the `envtop list` subcommand, printing the resolved env of an
+// app (or the apps of a namespace without --app) for scripts and CI pipelines
+func RunList(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	namespace := fs.String("namespace", "", "namespace of the app")
+	fs.StringVar(namespace, "n", "", "namespace of the app (shorthand)")
+	appName := fs.String("app", "", "app to print the env of (default: list the apps of the namespace)")
+	kind := fs.String("kind", "", "restrict --app to Deployment, StatefulSet, CronJob or Job")
+	output := fs.String("output", "text", "output format: text or json")
+	fs.StringVar(output, "o", "text", "output format (shorthand)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *namespace == "" {
+		return errors.New("--namespace is required")
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format: %s", *output)
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	if *appName == "" {
+		apps, err := client.ListApps(ctx, *namespace)
+		if err != nil {
+			return err
+		}
+		return printApps(stdout, apps, *output)
+	}
+
+	app, err := findApp(ctx, client, *namespace, *appName, *kind)
+	if err != nil {
+		return &ResolutionError{Err: err}
+	}
+	resolver, err := newResolver(client)
+	if err != nil {
+		return err
+	}
+	envVars, err := resolver.ResolveAppEnvVars(ctx, app)
+	if err != nil {
+		return &ResolutionError{Err: err}
+	}
+	return printAppEnv(stdout, report.NewAppEnv(client.GetCurrentContext(), app, envVars), *output)
+}
+
+// printAppEnv prints the resolved env of an app as a table or JSON
+func printAppEnv(w io.Writer, listing *report.AppEnv, output string) error {
+	if output == "json" {
+		data, err := json.MarshalIndent(listing, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCONTAINER\tSOURCE\tVALUE")
+	for _, entry := range listing.Env {
+		source := string(entry.Value.SourceKind)
+		if entry.Value.SourceName != "" {
+			source += "/" + entry.Value.SourceName
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", entry.Name, entry.Container, source, displayValue(entry.Value))
+	}
+	return tw.Flush()
+}
+
+// printApps prints the apps of a namespace
+func printApps(w io.Writer, apps []k8s.App, output string) error {
+	if output == "json" {
+		listed := make([]listedApp, 0, len(apps))
+		for _, app := range apps {
+			listed = append(listed, listedApp{Namespace: app.Namespace, Name: app.Name, Kind: app.Kind})
+		}
+		data, err := json.MarshalIndent(listed, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tKIND")
+	for _, app := range apps {
+		fmt.Fprintf(tw, "%s\t%s\n", app.Name, app.Kind)
+	}
+	return tw.Flush()
+}
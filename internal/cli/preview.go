@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/helm"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/manifest"
+	"github.com/ginbear/k8s-envtop/internal/report"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// RunPreview implements the `envtop preview` subcommand: it renders a local Helm
+// chart (or reads local manifests) and compares the env its workloads would get
+// with the live release, so chart authors see env changes before upgrading. The
+// live side is A, the chart or manifests B.
+func RunPreview(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("preview", flag.ContinueOnError)
+	chartPath := fs.String("chart", "", "local chart directory or packaged chart")
+	release := fs.String("release", "", "release name (default: the chart directory name)")
+	namespace := fs.String("namespace", "", "namespace of the release")
+	fs.StringVar(namespace, "n", "", "namespace of the release (shorthand)")
+	var valueFiles []string
+	fs.Func("f", "values file (repeatable, later files take precedence)", func(s string) error {
+		valueFiles = append(valueFiles, s)
+		return nil
+	})
+	var manifestPaths []string
+	fs.Func("manifests", "manifest file or directory to compare instead of a chart, - for stdin (repeatable)", func(s string) error {
+		manifestPaths = append(manifestPaths, s)
+		return nil
+	})
+	appName := fs.String("app", "", "compare only this workload of the chart")
+	format := fs.String("format", "text", "output format: text, json, markdown or unified")
+	includeSidecars := fs.Bool("include-sidecars", false, "also compare the variables of Istio/Linkerd sidecar containers of the live release")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if (*chartPath == "") == (len(manifestPaths) == 0) || *namespace == "" {
+		return errors.New("--namespace and one of --chart or --manifests are required")
+	}
+	switch *format {
+	case "text", "json", "markdown", "unified":
+	default:
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+	if *release == "" {
+		*release = releaseName(*chartPath)
+	}
+
+	ctx := context.Background()
+	var set *manifest.Set
+	var err error
+	label := "chart"
+	if *chartPath != "" {
+		renderer := &helm.Renderer{}
+		set, err = renderer.Template(ctx, helm.Chart{Path: *chartPath, Release: *release, Namespace: *namespace, ValueFiles: valueFiles})
+	} else {
+		set, err = manifest.Load(manifestPaths, *namespace)
+		label = "manifests"
+	}
+	if err != nil {
+		return err
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	resolver, err := newResolver(client)
+	if err != nil {
+		return err
+	}
+	resolver.SetIncludeSidecars(*includeSidecars)
+	overlay := &env.Overlay{ConfigMaps: set.ConfigMaps, Secrets: set.Secrets}
+
+	var reports []*report.DiffReport
+	var compared [][]env.DiffResult
+	for _, rendered := range set.Apps {
+		if *appName != "" && rendered.App.Name != *appName {
+			continue
+		}
+		// A workload the chart adds is compared against an empty env
+		live, err := resolver.ResolveAppDiffEnvVars(ctx, rendered.App)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return &ResolutionError{Err: err}
+		}
+		preview := resolver.ResolveTemplateEnvVars(ctx, rendered.App.Namespace, &rendered.Template, overlay)
+		results := env.CompareEnvVars(live, preview)
+		compared = append(compared, results)
+		reports = append(reports, report.NewDiffReport(client.GetCurrentContext(), rendered.App, "live", label, results))
+	}
+	if len(reports) == 0 {
+		if *appName != "" {
+			return fmt.Errorf("no workload named %s in the %s", *appName, label)
+		}
+		return fmt.Errorf("no Deployment, StatefulSet, CronJob or Job in the %s", label)
+	}
+
+	if err := writePreview(stdout, *format, reports); err != nil {
+		return err
+	}
+	for _, results := range compared {
+		if err := forbiddenErr(results); err != nil {
+			return err
+		}
+	}
+	for _, results := range compared {
+		if hasDrift(results) {
+			return ErrDrift
+		}
+	}
+	return nil
+}
+
+// writePreview writes the diff of every workload of the chart
+func writePreview(w io.Writer, format string, reports []*report.DiffReport) error {
+	if format == "json" {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	for i, rep := range reports {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if format == "text" {
+			fmt.Fprintf(w, "%s %s\n", rep.Kind, rep.App)
+			printDiffText(w, rep)
+			continue
+		}
+		if err := report.WriteDiff(w, report.DiffFormat(format), rep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseName derives the default release name from the chart directory or archive name
+func releaseName(chartPath string) string {
+	name := filepath.Base(filepath.Clean(chartPath))
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
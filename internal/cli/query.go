@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/fleet"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/report"
+)
+
+// RunQuery implements the `envtop query` subcommand, looking up a variable in
+// every app of the current context or, with --all-contexts, of every configured context
+func RunQuery(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	name := fs.String("name", "", "variable name or glob pattern (e.g. IMAGE_PULL_POLICY, *_URL)")
+	allContexts := fs.Bool("all-contexts", false, "query every context listed in the config file")
+	namespace := fs.String("namespace", "", "only query this namespace (default: all namespaces)")
+	fs.StringVar(namespace, "n", "", "only query this namespace (shorthand)")
+	concurrency := fs.Int("concurrency", fleet.DefaultConcurrency, "maximum number of API requests in flight")
+	format := fs.String("format", "text", "output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		return errors.New("--name is required")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	var clients []*k8s.Client
+	if *allContexts {
+		if len(cfg.Contexts) == 0 {
+			return errors.New("--all-contexts requires contexts in the config file")
+		}
+		clients, err = fleet.NewClients(cfg.Contexts)
+	} else {
+		var client *k8s.Client
+		client, err = k8s.NewClient()
+		clients = []*k8s.Client{client}
+	}
+	if err != nil {
+		return err
+	}
+
+	targets := make([]fleet.Target, 0, len(clients))
+	for _, client := range clients {
+		resolver := env.NewResolver(client)
+		resolver.SetRedactRule(cfg.IsRedacted)
+		targets = append(targets, fleet.Target{Client: client, Resolver: resolver})
+	}
+
+	result, err := fleet.Query(context.Background(), targets, *namespace, *name, *concurrency)
+	if err != nil {
+		return err
+	}
+
+	matches := make([]report.QueryMatch, 0, len(result.Matches))
+	for _, m := range result.Matches {
+		matches = append(matches, report.NewQueryMatch(m.Context, m.App, &m.Var))
+	}
+
+	if *format == "json" {
+		data, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+	} else {
+		printQueryText(stdout, *name, matches)
+	}
+
+	if len(result.Failures) > 0 {
+		for _, f := range result.Failures {
+			fmt.Fprintf(stdout, "failed: %v\n", f)
+		}
+		return &ResolutionError{Err: fmt.Errorf("%d of the queried contexts, namespaces or apps failed", len(result.Failures))}
+	}
+	return nil
+}
+
+// printQueryText prints one row per match and a summary of the distinct values
+func printQueryText(w io.Writer, name string, matches []report.QueryMatch) {
+	if len(matches) == 0 {
+		fmt.Fprintf(w, "No variable matching %s found\n", name)
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONTEXT\tNAMESPACE\tAPP\tCONTAINER\tNAME\tVALUE")
+	values := make(map[string]bool)
+	for _, m := range matches {
+		value := displayValue(m.Value)
+		values[m.Name+"="+value] = true
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", m.Context, m.Namespace, m.App, m.Container, m.Name, value)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n%d matches, %d distinct values\n", len(matches), len(values))
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"github.com/ginbear/k8s-envtop/internal/drift"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/notify"
+	"github.com/ginbear/k8s-envtop/internal/report"
+	"github.com/ginbear/k8s-envtop/internal/upload"
+)
+
+// RunReport implements the `envtop report` subcommand, generating the namespace
+// parity report (plus optional lint findings) and mailing it via the configured
+// SMTP server. With --every it keeps running and sends the report again after
+// each interval. That is a plain interval counted from the process start, not a
+// schedule: weekly reports at a set time are left to cron or systemd timers.
+func RunReport(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	nsA := fs.String("ns-a", "", "namespace A (compare from)")
+	nsB := fs.String("ns-b", "", "namespace B (compare with)")
+	lintNs := fs.String("lint", "", "also lint this namespace and attach the findings")
+	every := fs.Duration("every", 0, "keep running and repeat after this interval, counted from the start (default: run once; use cron for a schedule)")
+	noMail := fs.Bool("no-mail", false, "write the HTML report to stdout instead of mailing it")
+	uploadURL := fs.String("upload", "", "also upload the HTML report to this presigned S3/GCS/Azure Blob URL (single run only)")
+	record := fs.Bool("record", false, "record the drift scores in the history file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *nsA == "" || *nsB == "" {
+		return errors.New("--ns-a and --ns-b are required")
+	}
+	if *every < 0 {
+		return errors.New("--every must be positive")
+	}
+	if *every > 0 && *uploadURL != "" {
+		// Presigned URLs expire and address a single object
+		return errors.New("--upload cannot be combined with --every")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	var mailer *notify.Mailer
+	if !*noMail {
+		mailer, err = notify.NewMailer(cfg.SMTP)
+		if err != nil {
+			return err
+		}
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	job := reportJob{client: client, cfg: cfg, mailer: mailer, nsA: *nsA, nsB: *nsB, lintNs: *lintNs, uploadURL: *uploadURL, record: *record}
+	if *every == 0 {
+		return job.run(ctx, stdout)
+	}
+
+	ticker := time.NewTicker(*every)
+	defer ticker.Stop()
+	for {
+		// Failures of a single run are logged and retried on the next tick
+		if err := job.run(ctx, stdout); err != nil && !errors.Is(err, ErrDrift) {
+			fmt.Fprintf(os.Stderr, "%s report failed: %v\n", time.Now().Format(time.RFC3339), err)
+		}
+		<-ticker.C
+	}
+}
+
+// reportJob generates and delivers one parity report
+type reportJob struct {
+	client    *k8s.Client
+	cfg       *config.Config
+	mailer    *notify.Mailer // nil writes the report to stdout
+	nsA, nsB  string
+	lintNs    string
+	uploadURL string
+	record    bool // record the drift scores in the history
+}
+
+// run generates the report and mails it, returning ErrDrift when drift or
+// lint findings were reported
+func (j reportJob) run(ctx context.Context, stdout io.Writer) error {
+	resolver, err := newResolver(j.client)
+	if err != nil {
+		return err
+	}
+	diffs, err := resolver.CompareNamespaces(ctx, j.nsA, j.nsB)
+	if err != nil {
+		return &ResolutionError{Err: err}
+	}
+
+	now := time.Now()
+	scores, _, history, err := drift.LoadScores(diffs, j.client.GetCurrentContext(), j.nsA, j.nsB)
+	if err != nil {
+		return err
+	}
+	if j.record {
+		if err := history.Record(j.client.GetCurrentContext(), j.nsA, j.nsB, scores, now); err != nil {
+			return err
+		}
+	}
+	rep := report.NewBulkDiffReport(j.client.GetCurrentContext(), j.nsA, j.nsB, diffs, now)
+	rep.SetScores(scores)
+	var body bytes.Buffer
+	if err := report.WriteHTML(&body, rep); err != nil {
+		return err
+	}
+
+	if j.uploadURL != "" {
+		if err := upload.Put(ctx, j.uploadURL, "text/html; charset=utf-8", body.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	drift := rep.DriftedApps() > 0
+	var attachments []notify.Attachment
+	if j.lintNs != "" {
+		apps, err := j.client.ListApps(ctx, j.lintNs)
+		if err != nil {
+			return err
+		}
+		findings, err := lintApps(ctx, j.client, j.cfg, apps, nil, nil)
+		if err != nil {
+			return err
+		}
+		var text bytes.Buffer
+		printLintText(&text, findings)
+		attachments = append(attachments, notify.Attachment{
+			Filename:    fmt.Sprintf("lint-%s.txt", j.lintNs),
+			ContentType: "text/plain; charset=utf-8",
+			Data:        text.Bytes(),
+		})
+		drift = drift || len(findings) > 0
+	}
+
+	if j.mailer == nil {
+		_, err := stdout.Write(body.Bytes())
+		if err != nil {
+			return err
+		}
+	} else {
+		subject := fmt.Sprintf("envtop parity report: %s vs %s (%d of %d apps drifted, score %d %s)",
+			j.nsA, j.nsB, rep.DriftedApps(), len(rep.Apps), scores.Total.Value, scores.Total.Trend())
+		if err := j.mailer.Send(subject, body.Bytes(), attachments...); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "%s sent %s\n", now.Format(time.RFC3339), subject)
+	}
+
+	for _, diff := range diffs {
+		if err := forbiddenErr(diff.Results); err != nil {
+			return err
+		}
+	}
+	if drift {
+		return ErrDrift
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/seal"
+	"sigs.k8s.io/yaml"
+)
+
+// RunSeal implements the `envtop seal` subcommand.
+// The plaintext is read from stdin, a file descriptor, or a file so that it
+// never has to appear in shell history or pass through the clipboard.
+// Output is compatible with kubeseal: a raw ciphertext (--raw) or a full
+// SealedSecret manifest (-o yaml / -o json).
+func RunSeal(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("seal", flag.ContinueOnError)
+	namespace := fs.String("namespace", "", "namespace of the target Secret")
+	fs.StringVar(namespace, "n", "", "namespace of the target Secret (shorthand)")
+	name := fs.String("name", "", "name of the target Secret")
+	secretKey := fs.String("key", "", "key of the value in the Secret (required for manifest output)")
+	output := fs.String("output", "raw", "output format: raw, yaml or json")
+	fs.StringVar(output, "o", "raw", "output format (shorthand)")
+	raw := fs.Bool("raw", false, "output only the encrypted value (same as -o raw)")
+	scopeName := fs.String("scope", "strict", "sealing scope: strict, namespace-wide or cluster-wide")
+	certFile := fs.String("cert", "", "controller certificate PEM file (fetched from the cluster if omitted)")
+	controllerNs := fs.String("controller-namespace", k8s.DefaultSealedSecretsControllerNamespace, "namespace of the sealed-secrets controller")
+	controllerName := fs.String("controller-name", k8s.DefaultSealedSecretsControllerName, "name of the sealed-secrets controller")
+	fromStdin := fs.Bool("from-stdin", false, "read the plaintext from stdin")
+	fromFD := fs.Int("from-fd", -1, "read the plaintext from the given file descriptor")
+	fromFile := fs.String("from-file", "", "read the plaintext from a file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *raw {
+		*output = "raw"
+	}
+
+	scope, err := seal.ParseScope(*scopeName)
+	if err != nil {
+		return err
+	}
+	if *output != "raw" && *output != "yaml" && *output != "json" {
+		return fmt.Errorf("unknown output format: %s", *output)
+	}
+	// As with kubeseal, a raw value is bound to the name only in strict scope
+	// and to the namespace unless cluster-wide. A manifest always names the
+	// Secret it creates.
+	manifest := *output != "raw"
+	if *name == "" && (scope == seal.ScopeStrict || manifest) {
+		return errors.New("--name is required for strict scope and manifest output")
+	}
+	if *namespace == "" && (scope != seal.ScopeClusterWide || manifest) {
+		return errors.New("--namespace is required unless a raw value is sealed cluster-wide")
+	}
+	if *output != "raw" && *secretKey == "" {
+		return errors.New("--key is required for manifest output")
+	}
+
+	plainText, err := readPlainText(stdin, *fromStdin, *fromFD, *fromFile)
+	if err != nil {
+		return err
+	}
+	if len(plainText) == 0 {
+		return errors.New("plaintext is empty")
+	}
+
+	certPEM, err := loadSealingCert(*certFile, *controllerNs, *controllerName)
+	if err != nil {
+		return err
+	}
+	pubKey, err := seal.ParsePublicKey(certPEM)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := seal.EncryptRaw(pubKey, *namespace, *name, scope, plainText)
+	if err != nil {
+		return err
+	}
+
+	switch *output {
+	case "yaml":
+		manifest := seal.NewManifest(*namespace, *name, scope, map[string]string{*secretKey: sealed})
+		data, err := yaml.Marshal(manifest)
+		if err != nil {
+			return err
+		}
+		_, err = stdout.Write(data)
+		return err
+	case "json":
+		manifest := seal.NewManifest(*namespace, *name, scope, map[string]string{*secretKey: sealed})
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(stdout, string(data))
+		return err
+	default:
+		_, err = fmt.Fprintln(stdout, sealed)
+		return err
+	}
+}
+
+// loadSealingCert reads the controller certificate from a file or fetches it from the cluster
+func loadSealingCert(certFile, controllerNs, controllerName string) ([]byte, error) {
+	if certFile != "" {
+		data, err := os.ReadFile(certFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", certFile, err)
+		}
+		return data, nil
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.FetchSealingCert(context.Background(), controllerNs, controllerName)
+}
+
+// readPlainText reads the plaintext from exactly one of the configured sources
+func readPlainText(stdin io.Reader, fromStdin bool, fromFD int, fromFile string) ([]byte, error) {
+	sources := 0
+	if fromStdin {
+		sources++
+	}
+	if fromFD >= 0 {
+		sources++
+	}
+	if fromFile != "" {
+		sources++
+	}
+	if sources != 1 {
+		return nil, errors.New("exactly one of --from-stdin, --from-fd or --from-file is required")
+	}
+
+	switch {
+	case fromStdin:
+		return io.ReadAll(stdin)
+	case fromFD >= 0:
+		f := os.NewFile(uintptr(fromFD), fmt.Sprintf("fd%d", fromFD))
+		if f == nil {
+			return nil, fmt.Errorf("invalid file descriptor: %d", fromFD)
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	default:
+		data, err := os.ReadFile(fromFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", fromFile, err)
+		}
+		return data, nil
+	}
+}
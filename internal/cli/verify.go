@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/seal"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RunVerify implements the `envtop verify` subcommand, which checks that a
+// SealedSecret (from the cluster or a manifest file) matches the live Secret.
+func RunVerify(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	namespace := fs.String("namespace", "", "namespace of the SealedSecret")
+	fs.StringVar(namespace, "n", "", "namespace of the SealedSecret (shorthand)")
+	name := fs.String("name", "", "name of the SealedSecret in the cluster")
+	file := fs.String("file", "", "SealedSecret manifest file (e.g. from Git) instead of the cluster object")
+	privateKeyFile := fs.String("private-key", "", "controller private key PEM file to compare decrypted values")
+	controllerNs := fs.String("controller-namespace", k8s.DefaultSealedSecretsControllerNamespace, "namespace of the sealed-secrets controller")
+	controllerName := fs.String("controller-name", k8s.DefaultSealedSecretsControllerName, "name of the sealed-secrets controller")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" && (*namespace == "" || *name == "") {
+		return errors.New("either --file or --namespace and --name are required")
+	}
+
+	opts := seal.VerifyOptions{
+		ControllerNamespace: *controllerNs,
+		ControllerName:      *controllerName,
+	}
+	if *privateKeyFile != "" {
+		data, err := os.ReadFile(*privateKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", *privateKeyFile, err)
+		}
+		opts.PrivateKey, err = seal.ParsePrivateKey(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	var sealed *unstructured.Unstructured
+	if *file != "" {
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", *file, err)
+		}
+		sealed, err = seal.ParseManifest(data)
+		if err != nil {
+			return err
+		}
+		if *namespace != "" {
+			sealed.SetNamespace(*namespace)
+		}
+		if sealed.GetNamespace() == "" {
+			// As without --file, the namespace is never guessed
+			return fmt.Errorf("%s has no metadata.namespace: pass --namespace", *file)
+		}
+	} else {
+		sealed, err = client.GetSealedSecret(ctx, *namespace, *name)
+		if err != nil {
+			return &ResolutionError{Err: fmt.Errorf("failed to get sealedsecret %s: %w", *name, err)}
+		}
+	}
+
+	result, err := seal.Verify(ctx, client, sealed, opts)
+	if err != nil {
+		return &ResolutionError{Err: err}
+	}
+
+	printVerifyResult(stdout, result)
+	if result.Stale() {
+		return ErrDrift
+	}
+	if result.Unverified() {
+		return ErrUnverified
+	}
+	return nil
+}
+
+// printVerifyResult writes a human-readable verification report
+func printVerifyResult(w io.Writer, r *seal.VerifyResult) {
+	fmt.Fprintf(w, "SealedSecret: %s/%s -> Secret %s/%s\n", r.Namespace, r.Name, r.Namespace, r.SecretName)
+
+	decrypt := "unknown"
+	if r.Decryptable != nil {
+		decrypt = "OK"
+		if !*r.Decryptable {
+			decrypt = "FAILED"
+		}
+	}
+	fmt.Fprintf(w, "Controller decrypt: %s\n", decrypt)
+
+	if len(r.MissingKeys) > 0 {
+		fmt.Fprintf(w, "Missing in live Secret: %s\n", strings.Join(r.MissingKeys, ", "))
+	}
+	if len(r.ExtraKeys) > 0 {
+		fmt.Fprintf(w, "Not in sealed manifest: %s\n", strings.Join(r.ExtraKeys, ", "))
+	}
+	if len(r.MismatchedKeys) > 0 {
+		fmt.Fprintf(w, "Value mismatch: %s\n", strings.Join(r.MismatchedKeys, ", "))
+	}
+	if len(r.MatchedKeys) > 0 {
+		fmt.Fprintf(w, "Value match: %s\n", strings.Join(r.MatchedKeys, ", "))
+	}
+	if len(r.UncomparedKeys) > 0 {
+		fmt.Fprintf(w, "Values not compared (no private key): %s\n", strings.Join(r.UncomparedKeys, ", "))
+	}
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+
+	switch {
+	case r.Stale():
+		fmt.Fprintln(w, "Result: STALE")
+	case r.Unverified():
+		fmt.Fprintln(w, "Result: UNVERIFIED (keys match, values not compared: pass --private-key)")
+	default:
+		fmt.Fprintln(w, "Result: OK")
+	}
+}
@@ -0,0 +1,368 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Config holds user settings loaded from the envtop config file
+type Config struct {
+	// FeatureFlags lists variable name patterns (glob) treated as feature flags
+	FeatureFlags []string `json:"featureFlags,omitempty"`
+
+	// Redact lists variable name patterns (glob) whose values are masked like secrets,
+	// even when sourced from ConfigMaps or inline values
+	Redact []string `json:"redact,omitempty"`
+
+	// Policies lists value policy files, relative to the config directory
+	Policies []string `json:"policies,omitempty"`
+
+	// Rego configures the optional OPA/Rego policy hook used by lint
+	Rego RegoConfig `json:"rego,omitempty"`
+
+	// Contexts aggregates several kubeconfig contexts into one session
+	Contexts []ContextRef `json:"contexts,omitempty"`
+
+	// Tiers declares environment tiers and the promotion path between them
+	Tiers TierConfig `json:"tiers,omitempty"`
+
+	// Preview declares how ephemeral preview namespaces are detected
+	Preview PreviewConfig `json:"preview,omitempty"`
+
+	// IdleLock blanks the UI after a period without key presses
+	IdleLock IdleLockConfig `json:"idleLock,omitempty"`
+
+	// Reveal restricts where secret values may be revealed
+	Reveal RevealConfig `json:"reveal,omitempty"`
+
+	// SMTP configures mail delivery of reports by `envtop report`
+	SMTP SMTPConfig `json:"smtp,omitempty"`
+
+	// Locale selects the UI language and the timestamp and number formats
+	Locale LocaleConfig `json:"locale,omitempty"`
+
+	// Session runs the TUI with a short-lived service account token
+	Session SessionConfig `json:"session,omitempty"`
+
+	// Cache keeps the ConfigMaps and Secrets read by the TUI for a while
+	Cache CacheConfig `json:"cache,omitempty"`
+
+	// Startup bounds the optional cluster checks run in the background at startup
+	Startup StartupConfig `json:"startup,omitempty"`
+
+	// Keys remaps the keys of TUI actions, e.g. reveal: ["R"]; an empty list disables the action
+	Keys map[string][]string `json:"keys,omitempty"`
+
+	// Hooks run external commands when an app is selected or on a key
+	Hooks []HookConfig `json:"hooks,omitempty"`
+
+	// EnvSizeBudgetKiB is the env size per container above which the TUI and
+	// lint warn (default 64); the container runtime fails to start containers
+	// whose env does not fit in the kernel's limits
+	EnvSizeBudgetKiB int `json:"envSizeBudgetKiB,omitempty"`
+
+	// HashDisplay renders the hashes of secret values as "hex" (default), "words" or "emoji"
+	HashDisplay string `json:"hashDisplay,omitempty"`
+}
+
+// LocaleConfig overrides the language detected from LANG and its formats
+type LocaleConfig struct {
+	// Language is the UI language, e.g. "ja" (default from LC_ALL, LC_MESSAGES or LANG)
+	Language string `json:"language,omitempty"`
+	// DateFormat and DateTimeFormat are Go time layouts, e.g. "2006-01-02"
+	DateFormat     string `json:"dateFormat,omitempty"`
+	DateTimeFormat string `json:"dateTimeFormat,omitempty"`
+	// Thousands is the digit group separator of numbers
+	Thousands string `json:"thousands,omitempty"`
+}
+
+// Events firing a hook
+const (
+	HookOnNamespace = "namespace" // the apps of a namespace were loaded
+	HookOnApp       = "app"       // the env of an app (or pod) was loaded
+	HookOnKey       = "key"       // the key of the hook was pressed
+)
+
+// HookConfig runs an external command on a TUI event, e.g. to open the
+// dashboard of the selected app or tail its logs in another tmux pane. The
+// command is run by sh -c with $CONTEXT, $NAMESPACE, $APP, $APP_KIND, $POD,
+// $ENV_NAME and $ENV_SOURCE set to the selection.
+type HookConfig struct {
+	// Name identifies the hook in the help and the status line
+	Name string `json:"name"`
+	// On is HookOnNamespace, HookOnApp or HookOnKey
+	On string `json:"on"`
+	// Key fires the hook when On is HookOnKey, e.g. "ctrl+g"
+	Key string `json:"key,omitempty"`
+	// Command is the shell command
+	Command string `json:"command"`
+	// Interactive suspends the TUI while the command runs, for commands using
+	// the terminal (e.g. less, k9s); others run in the background
+	Interactive bool `json:"interactive,omitempty"`
+}
+
+// SessionConfig designates a read-only service account whose short-lived token
+// is used for the session instead of the user's credentials
+type SessionConfig struct {
+	// ServiceAccount is the "namespace/name" of the service account
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// TTLMinutes is the lifetime of the token (default 60)
+	TTLMinutes int `json:"ttlMinutes,omitempty"`
+}
+
+// DefaultSessionTTLMinutes is the lifetime of session tokens when none is configured
+const DefaultSessionTTLMinutes = 60
+
+// CacheConfig configures how long the TUI reuses the ConfigMaps and Secrets it
+// read. The live watch and a manual reload drop them earlier.
+type CacheConfig struct {
+	// TTLSeconds is how long an object is reused (default 30, negative disables the cache)
+	TTLSeconds int `json:"ttlSeconds,omitempty"`
+}
+
+// DefaultEnvSizeBudgetKiB is the env size budget per container when none is configured
+const DefaultEnvSizeBudgetKiB = 64
+
+// DefaultCacheTTLSeconds is how long ConfigMaps and Secrets are reused when none is configured
+const DefaultCacheTTLSeconds = 30
+
+// StartupConfig bounds the discovery of optional cluster features (CRDs such as
+// SealedSecrets). The checks run in the background and never delay the first
+// paint; each one gives up after its deadline.
+type StartupConfig struct {
+	// CheckTimeoutSeconds is the deadline of each check (default 5)
+	CheckTimeoutSeconds int `json:"checkTimeoutSeconds,omitempty"`
+	// Skip lists capabilities not to discover, e.g. ["flux", "argocd"]
+	Skip []string `json:"skip,omitempty"`
+}
+
+// DefaultCheckTimeoutSeconds is the deadline of startup checks when none is configured
+const DefaultCheckTimeoutSeconds = 5
+
+// IdleLockConfig configures the idle lock screen
+type IdleLockConfig struct {
+	// TimeoutMinutes is the idle time before the UI is locked (0 disables the lock)
+	TimeoutMinutes int `json:"timeoutMinutes,omitempty"`
+	// Confirm requires typing OK to unlock instead of any key
+	Confirm bool `json:"confirm,omitempty"`
+}
+
+// RevealConfig restricts the reveal feature
+type RevealConfig struct {
+	// RequireAltScreen disables reveal unless the terminal supports the alternate
+	// screen, so revealed values cannot end up in the scrollback
+	RequireAltScreen bool `json:"requireAltScreen,omitempty"`
+	// RecordEvents creates a Kubernetes Event on the Secret each time one of its
+	// values is revealed or copied, recording who did it and when
+	RecordEvents bool `json:"recordEvents,omitempty"`
+}
+
+// SMTPConfig configures the SMTP server reports are sent through
+type SMTPConfig struct {
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	// PasswordEnv names the environment variable holding the password
+	PasswordEnv string   `json:"passwordEnv,omitempty"`
+	From        string   `json:"from,omitempty"`
+	To          []string `json:"to,omitempty"`
+}
+
+// Defaults for preview namespace detection
+const (
+	DefaultPreviewLabel      = "envtop.io/preview"
+	DefaultPreviewMaxAgeDays = 7
+)
+
+// PreviewConfig declares ephemeral preview namespaces
+type PreviewConfig struct {
+	// Label marks preview namespaces when set to "true"
+	Label string `json:"label,omitempty"`
+	// Namespaces lists namespace name patterns (glob) of preview namespaces
+	Namespaces []string `json:"namespaces,omitempty"`
+	// MaxAgeDays is the age after which a preview namespace is reported as stale
+	MaxAgeDays int `json:"maxAgeDays,omitempty"`
+}
+
+// DefaultTierLabel is the namespace label read when tiers.label is not set
+const DefaultTierLabel = "envtop.io/tier"
+
+// TierConfig declares environment tiers (e.g. dev -> staging -> prod)
+type TierConfig struct {
+	// Order is the promotion path, from the first to the last tier
+	Order []string `json:"order,omitempty"`
+	// Label is the namespace label holding the tier name
+	Label string `json:"label,omitempty"`
+	// Namespaces maps namespace name patterns (glob) to tiers for unlabeled namespaces
+	Namespaces []TierMapping `json:"namespaces,omitempty"`
+}
+
+// RegoConfig configures evaluation of Rego policies through the opa binary
+type RegoConfig struct {
+	// Policies lists .rego files or directories, relative to the config directory
+	Policies []string `json:"policies,omitempty"`
+	// Query is the rule producing denials (default data.envtop.deny)
+	Query string `json:"query,omitempty"`
+	// Binary is the opa executable (default "opa" from PATH)
+	Binary string `json:"binary,omitempty"`
+}
+
+// ContextRef names a kubeconfig context, optionally in another kubeconfig file
+type ContextRef struct {
+	Name       string `json:"name"`
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+}
+
+// TierMapping maps a namespace name pattern to a tier
+type TierMapping struct {
+	Pattern string `json:"pattern"`
+	Tier    string `json:"tier"`
+}
+
+// Dir returns the envtop configuration directory (~/.config/envtop).
+// When $ENVTOP_CONFIG is set, its directory is used instead.
+func Dir() (string, error) {
+	if p := os.Getenv("ENVTOP_CONFIG"); p != "" {
+		return filepath.Dir(p), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(dir, "envtop"), nil
+}
+
+// DefaultPath returns the config file path ($ENVTOP_CONFIG or ~/.config/envtop/config.yaml)
+func DefaultPath() (string, error) {
+	if p := os.Getenv("ENVTOP_CONFIG"); p != "" {
+		return p, nil
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// Load reads the config file at the given path. A missing file yields an empty config.
+func Load(p string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config %s: %w", p, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", p, err)
+	}
+	return cfg, nil
+}
+
+// IsFeatureFlag returns true if the variable name matches a feature flag pattern
+func (c *Config) IsFeatureFlag(name string) bool {
+	return matchAny(c.FeatureFlags, name)
+}
+
+// IsRedacted returns true if the variable name matches a redaction pattern
+func (c *Config) IsRedacted(name string) bool {
+	return matchAny(c.Redact, name)
+}
+
+// TierOf returns the tier of a namespace from its labels or the name mapping ("" if unknown)
+func (c *Config) TierOf(namespace string, labels map[string]string) string {
+	label := c.Tiers.Label
+	if label == "" {
+		label = DefaultTierLabel
+	}
+	if tier := labels[label]; tier != "" {
+		return tier
+	}
+	for _, mapping := range c.Tiers.Namespaces {
+		if ok, _ := path.Match(mapping.Pattern, namespace); ok {
+			return mapping.Tier
+		}
+	}
+	return ""
+}
+
+// NextTier returns the tier that follows the given one on the promotion path ("" if none)
+func (c *Config) NextTier(tier string) string {
+	for i, t := range c.Tiers.Order {
+		if t == tier && i+1 < len(c.Tiers.Order) {
+			return c.Tiers.Order[i+1]
+		}
+	}
+	return ""
+}
+
+// IsPreview returns true if the namespace is an ephemeral preview namespace
+func (c *Config) IsPreview(namespace string, labels map[string]string) bool {
+	label := c.Preview.Label
+	if label == "" {
+		label = DefaultPreviewLabel
+	}
+	return labels[label] == "true" || matchAny(c.Preview.Namespaces, namespace)
+}
+
+// PreviewMaxAgeDays returns the configured preview namespace max age
+func (c *Config) PreviewMaxAgeDays() int {
+	if c.Preview.MaxAgeDays > 0 {
+		return c.Preview.MaxAgeDays
+	}
+	return DefaultPreviewMaxAgeDays
+}
+
+// SessionTTL returns the configured lifetime of session tokens
+func (c *Config) SessionTTL() time.Duration {
+	if c.Session.TTLMinutes > 0 {
+		return time.Duration(c.Session.TTLMinutes) * time.Minute
+	}
+	return DefaultSessionTTLMinutes * time.Minute
+}
+
+// CacheTTL returns how long ConfigMaps and Secrets are reused, 0 if never
+func (c *Config) CacheTTL() time.Duration {
+	switch {
+	case c.Cache.TTLSeconds < 0:
+		return 0
+	case c.Cache.TTLSeconds > 0:
+		return time.Duration(c.Cache.TTLSeconds) * time.Second
+	}
+	return DefaultCacheTTLSeconds * time.Second
+}
+
+// CheckTimeout returns the configured deadline of startup checks
+func (c *Config) CheckTimeout() time.Duration {
+	if c.Startup.CheckTimeoutSeconds > 0 {
+		return time.Duration(c.Startup.CheckTimeoutSeconds) * time.Second
+	}
+	return DefaultCheckTimeoutSeconds * time.Second
+}
+
+// EnvSizeBudget returns the env size budget per container in bytes
+func (c *Config) EnvSizeBudget() int {
+	if c.EnvSizeBudgetKiB <= 0 {
+		return DefaultEnvSizeBudgetKiB * 1024
+	}
+	return c.EnvSizeBudgetKiB * 1024
+}
+
+// matchAny returns true if name matches any of the glob patterns
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
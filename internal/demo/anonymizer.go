@@ -0,0 +1,163 @@
+package demo
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	mathrand "math/rand/v2"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Anonymizer replaces names and values by fake ones. Text is split into
+// tokens at every non-alphanumeric character; each token is replaced on its
+// own, the same way everywhere, so "shop-staging" and "shop-prod" stay
+// related and an env var keeps matching the Secret key it is read from.
+// Mappings are derived from a random key: they differ between sessions.
+type Anonymizer struct {
+	key    []byte
+	tokens map[string]string // lower-case token → fake token
+	used   map[string]bool   // fake tokens handed out
+}
+
+// NewAnonymizer creates an anonymizer with a random session key
+func NewAnonymizer() (*Anonymizer, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate the session key: %w", err)
+	}
+	return &Anonymizer{key: key, tokens: make(map[string]string), used: make(map[string]bool)}, nil
+}
+
+// Text replaces every token of s, keeping separators, case and generic words
+// (prod, api, URL, ...)
+func (a *Anonymizer) Text(s string) string {
+	if s == "" {
+		return s
+	}
+	var b strings.Builder
+	start := -1
+	for i, r := range s {
+		if isTokenRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			b.WriteString(a.token(s[start:i]))
+			start = -1
+		}
+		b.WriteRune(r)
+	}
+	if start >= 0 {
+		b.WriteString(a.token(s[start:]))
+	}
+	return b.String()
+}
+
+// Secret replaces a secret value by random data of the same length and shape:
+// letters stay letters and digits stay digits. Equal values stay equal.
+func (a *Anonymizer) Secret(value []byte) []byte {
+	if len(value) == 0 {
+		return value
+	}
+	rng := a.rng("secret", string(value))
+	if !utf8.Valid(value) {
+		out := make([]byte, len(value))
+		for i := range out {
+			out[i] = byte(rng.UintN(256))
+		}
+		return out
+	}
+	return []byte(shapeOf(string(value), rng))
+}
+
+func isTokenRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// token returns the fake token of a real one
+func (a *Anonymizer) token(t string) string {
+	lower := strings.ToLower(t)
+	if genericWords[lower] || len(t) == 1 {
+		return t
+	}
+	fake, ok := a.tokens[lower]
+	if !ok {
+		fake = a.newToken(lower)
+		a.tokens[lower] = fake
+		a.used[fake] = true
+	}
+	return withCaseOf(t, fake)
+}
+
+// newToken picks the fake token of a lower-case token: a word for a word, and
+// random characters of the same shape for numbers and hash-like tokens
+func (a *Anonymizer) newToken(t string) string {
+	rng := a.rng("token", t)
+	if strings.IndexFunc(t, unicode.IsDigit) >= 0 {
+		return shapeOf(t, rng)
+	}
+
+	start := rng.IntN(len(fakeWords))
+	for i := range fakeWords {
+		word := fakeWords[(start+i)%len(fakeWords)]
+		if !a.used[word] && !genericWords[word] {
+			return word
+		}
+	}
+	for n := 2; ; n++ {
+		word := fmt.Sprintf("%s%d", fakeWords[start], n)
+		if !a.used[word] {
+			return word
+		}
+	}
+}
+
+// rng returns a random source derived from the session key and a value
+func (a *Anonymizer) rng(purpose, value string) *mathrand.Rand {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(purpose + ":" + value))
+	var seed [32]byte
+	copy(seed[:], mac.Sum(nil))
+	return mathrand.New(mathrand.NewChaCha8(seed))
+}
+
+// shapeOf replaces every letter by a random letter and every digit by a random
+// digit, keeping the other characters and the case
+func shapeOf(s string, rng *mathrand.Rand) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteByte(byte('0' + rng.IntN(10)))
+		case unicode.IsUpper(r):
+			b.WriteByte(letters[rng.IntN(len(letters))] - 'a' + 'A')
+		case unicode.IsLetter(r):
+			b.WriteByte(letters[rng.IntN(len(letters))])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// withCaseOf applies the case of the real token (UPPER, Capitalized or lower) to the fake one
+func withCaseOf(real, fake string) string {
+	switch {
+	case strings.ToUpper(real) == real && strings.ToLower(real) != real:
+		return strings.ToUpper(fake)
+	case unicode.IsUpper(firstRune(real)):
+		return strings.ToUpper(fake[:1]) + fake[1:]
+	}
+	return fake
+}
+
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
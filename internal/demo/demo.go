@@ -0,0 +1,242 @@
+// Package demo runs envtop against a pseudonymized copy of a cluster
+// (`envtop --demo`): namespace, app, variable names and values are replaced
+// by realistic fake ones, so that screenshots, recordings and docs leak
+// nothing from the real cluster
+package demo
+
+import (
+	"context"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ContextName is the context name shown in demo mode
+const ContextName = "demo"
+
+// NewClient snapshots the cluster of source and returns a client of an
+// in-memory cluster holding its pseudonymized copy. Nothing is written back.
+func NewClient(ctx context.Context, source *k8s.Client) (*k8s.Client, error) {
+	objs, err := source.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a, err := NewAnonymizer()
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objs {
+		a.Object(obj)
+	}
+	return k8s.NewFakeClient(ContextName, objs...), nil
+}
+
+// Object pseudonymizes an object of a snapshot in place
+func (a *Anonymizer) Object(obj runtime.Object) {
+	switch o := obj.(type) {
+	case *corev1.Namespace:
+		a.meta(&o.ObjectMeta)
+	case *appsv1.Deployment:
+		a.meta(&o.ObjectMeta)
+		a.selector(o.Spec.Selector)
+		a.template(&o.Spec.Template)
+	case *appsv1.StatefulSet:
+		a.meta(&o.ObjectMeta)
+		a.selector(o.Spec.Selector)
+		a.template(&o.Spec.Template)
+		o.Spec.ServiceName = a.Text(o.Spec.ServiceName)
+	case *batchv1.CronJob:
+		a.meta(&o.ObjectMeta)
+		a.meta(&o.Spec.JobTemplate.ObjectMeta)
+		a.selector(o.Spec.JobTemplate.Spec.Selector)
+		a.template(&o.Spec.JobTemplate.Spec.Template)
+	case *batchv1.Job:
+		a.meta(&o.ObjectMeta)
+		a.selector(o.Spec.Selector)
+		a.template(&o.Spec.Template)
+	case *corev1.Pod:
+		a.meta(&o.ObjectMeta)
+		a.podSpec(&o.Spec)
+		o.Status.PodIP = a.Text(o.Status.PodIP)
+		o.Status.HostIP = a.Text(o.Status.HostIP)
+		o.Status.PodIPs, o.Status.HostIPs = nil, nil
+		for i := range o.Status.ContainerStatuses {
+			s := &o.Status.ContainerStatuses[i]
+			s.Name, s.Image, s.ImageID, s.ContainerID = a.Text(s.Name), a.Text(s.Image), "", ""
+		}
+		for i := range o.Status.InitContainerStatuses {
+			s := &o.Status.InitContainerStatuses[i]
+			s.Name, s.Image, s.ImageID, s.ContainerID = a.Text(s.Name), a.Text(s.Image), "", ""
+		}
+	case *corev1.ConfigMap:
+		a.meta(&o.ObjectMeta)
+		data := make(map[string]string, len(o.Data))
+		for k, v := range o.Data {
+			data[a.Text(k)] = a.Text(v)
+		}
+		o.Data = data
+		binary := make(map[string][]byte, len(o.BinaryData))
+		for k, v := range o.BinaryData {
+			binary[a.Text(k)] = a.Secret(v)
+		}
+		o.BinaryData = binary
+	case *corev1.Secret:
+		a.meta(&o.ObjectMeta)
+		data := make(map[string][]byte, len(o.Data))
+		for k, v := range o.Data {
+			data[a.Text(k)] = a.Secret(v)
+		}
+		o.Data, o.StringData = data, nil
+	case *corev1.ServiceAccount:
+		a.meta(&o.ObjectMeta)
+		o.Secrets, o.ImagePullSecrets = nil, nil
+	}
+}
+
+// meta replaces the names and label values of an object. Annotations are
+// dropped: they often hold whole manifests.
+func (a *Anonymizer) meta(meta *metav1.ObjectMeta) {
+	meta.Name = a.Text(meta.Name)
+	meta.GenerateName = a.Text(meta.GenerateName)
+	meta.Namespace = a.Text(meta.Namespace)
+	meta.Labels = a.labels(meta.Labels)
+	meta.Annotations = nil
+	meta.ManagedFields = nil
+	for i := range meta.OwnerReferences {
+		meta.OwnerReferences[i].Name = a.Text(meta.OwnerReferences[i].Name)
+	}
+}
+
+// labels replaces the values of labels, keeping their keys, so that
+// selectors keep matching
+func (a *Anonymizer) labels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	out := make(map[string]string, len(labels))
+	for k, v := range labels {
+		out[k] = a.Text(v)
+	}
+	return out
+}
+
+func (a *Anonymizer) selector(selector *metav1.LabelSelector) {
+	if selector == nil {
+		return
+	}
+	selector.MatchLabels = a.labels(selector.MatchLabels)
+	for i := range selector.MatchExpressions {
+		values := selector.MatchExpressions[i].Values
+		for j := range values {
+			values[j] = a.Text(values[j])
+		}
+	}
+}
+
+func (a *Anonymizer) template(template *corev1.PodTemplateSpec) {
+	template.Labels = a.labels(template.Labels)
+	template.Annotations = nil
+	a.podSpec(&template.Spec)
+}
+
+// podSpec replaces the names, references and values of the env, volumes and
+// images of a pod
+func (a *Anonymizer) podSpec(spec *corev1.PodSpec) {
+	spec.ServiceAccountName = a.Text(spec.ServiceAccountName)
+	spec.DeprecatedServiceAccount = a.Text(spec.DeprecatedServiceAccount)
+	spec.NodeName = a.Text(spec.NodeName)
+	spec.Hostname = a.Text(spec.Hostname)
+	spec.Subdomain = a.Text(spec.Subdomain)
+	spec.NodeSelector = a.labels(spec.NodeSelector)
+	spec.ImagePullSecrets = nil
+	for i := range spec.Containers {
+		a.container(&spec.Containers[i])
+	}
+	for i := range spec.InitContainers {
+		a.container(&spec.InitContainers[i])
+	}
+	spec.EphemeralContainers = nil
+
+	for i := range spec.Volumes {
+		v := &spec.Volumes[i]
+		v.Name = a.Text(v.Name)
+		switch {
+		case v.ConfigMap != nil:
+			v.ConfigMap.Name = a.Text(v.ConfigMap.Name)
+			a.items(v.ConfigMap.Items)
+		case v.Secret != nil:
+			v.Secret.SecretName = a.Text(v.Secret.SecretName)
+			a.items(v.Secret.Items)
+		case v.Projected != nil:
+			for j := range v.Projected.Sources {
+				source := &v.Projected.Sources[j]
+				if source.ConfigMap != nil {
+					source.ConfigMap.Name = a.Text(source.ConfigMap.Name)
+					a.items(source.ConfigMap.Items)
+				}
+				if source.Secret != nil {
+					source.Secret.Name = a.Text(source.Secret.Name)
+					a.items(source.Secret.Items)
+				}
+			}
+		case v.PersistentVolumeClaim != nil:
+			v.PersistentVolumeClaim.ClaimName = a.Text(v.PersistentVolumeClaim.ClaimName)
+		case v.HostPath != nil:
+			v.HostPath.Path = a.Text(v.HostPath.Path)
+		}
+	}
+}
+
+func (a *Anonymizer) container(c *corev1.Container) {
+	c.Name = a.Text(c.Name)
+	c.Image = a.Text(c.Image)
+	for i := range c.Command {
+		c.Command[i] = a.Text(c.Command[i])
+	}
+	for i := range c.Args {
+		c.Args[i] = a.Text(c.Args[i])
+	}
+	c.WorkingDir = a.Text(c.WorkingDir)
+
+	for i := range c.Env {
+		e := &c.Env[i]
+		e.Name = a.Text(e.Name)
+		e.Value = a.Text(e.Value)
+		if from := e.ValueFrom; from != nil {
+			if ref := from.ConfigMapKeyRef; ref != nil {
+				ref.Name, ref.Key = a.Text(ref.Name), a.Text(ref.Key)
+			}
+			if ref := from.SecretKeyRef; ref != nil {
+				ref.Name, ref.Key = a.Text(ref.Name), a.Text(ref.Key)
+			}
+			if ref := from.ResourceFieldRef; ref != nil {
+				ref.ContainerName = a.Text(ref.ContainerName)
+			}
+		}
+	}
+	for i := range c.EnvFrom {
+		e := &c.EnvFrom[i]
+		e.Prefix = a.Text(e.Prefix)
+		if e.ConfigMapRef != nil {
+			e.ConfigMapRef.Name = a.Text(e.ConfigMapRef.Name)
+		}
+		if e.SecretRef != nil {
+			e.SecretRef.Name = a.Text(e.SecretRef.Name)
+		}
+	}
+	for i := range c.VolumeMounts {
+		m := &c.VolumeMounts[i]
+		m.Name, m.MountPath, m.SubPath = a.Text(m.Name), a.Text(m.MountPath), a.Text(m.SubPath)
+	}
+}
+
+// items replaces the keys and file paths of a ConfigMap or Secret volume
+func (a *Anonymizer) items(items []corev1.KeyToPath) {
+	for i := range items {
+		items[i].Key, items[i].Path = a.Text(items[i].Key), a.Text(items[i].Path)
+	}
+}
@@ -0,0 +1,52 @@
+package demo
+
+// genericWords are kept as they are: they carry the structure of names and
+// values (tiers, kinds of services, well-known variables) but nothing specific
+// to a cluster
+var genericWords = toSet(
+	// environments and tiers
+	"prod", "production", "prd", "stg", "staging", "stage", "dev", "development", "qa", "test", "testing",
+	"uat", "sandbox", "preview", "pr", "local", "canary", "blue", "green", "primary", "replica",
+	// kinds of workloads and objects
+	"api", "app", "web", "frontend", "backend", "worker", "workers", "job", "cron", "batch", "migrate",
+	"migration", "server", "client", "proxy", "gateway", "admin", "service", "svc", "config", "configs",
+	"secret", "secrets", "env", "settings", "default", "system", "public", "private", "internal", "external",
+	// infrastructure
+	"db", "database", "cache", "redis", "postgres", "postgresql", "pg", "mysql", "mongo", "mongodb", "kafka",
+	"rabbitmq", "amqp", "queue", "s3", "bucket", "cdn", "smtp", "mail", "ldap", "oauth", "oidc", "jwt", "sso",
+	"http", "https", "grpc", "tcp", "udp", "tls", "ssl", "cert", "crt", "ca", "pem", "kube", "kubernetes", "k8s",
+	"cluster", "localhost", "com", "io", "net", "org", "aws", "gcp", "azure", "region", "zone", "metrics",
+	// variable name parts
+	"url", "uri", "dsn", "host", "hostname", "port", "addr", "address", "endpoint", "path", "dir", "file",
+	"name", "id", "key", "keys", "token", "password", "pass", "passwd", "user", "username", "auth", "access",
+	"timeout", "ttl", "interval", "retry", "retries", "max", "min", "size", "limit", "pool", "threads",
+	"log", "logging", "level", "format", "mode", "version", "enabled", "enable", "disabled", "disable",
+	"feature", "flag", "flags", "debug", "info", "warn", "warning", "error", "trace", "true", "false",
+	"yes", "no", "on", "off", "none", "null", "json", "yaml", "yml", "conf", "ini", "txt", "etc", "var",
+	"tmp", "data", "v1", "v2", "v3", "pod", "node", "namespace", "container", "image", "tag", "ip",
+)
+
+// fakeWords replace the other words of names and values
+var fakeWords = []string{
+	"acorn", "alder", "amber", "anchor", "apricot", "aspen", "aster", "atlas", "aurora", "badger",
+	"basil", "beacon", "birch", "bison", "bramble", "breeze", "brook", "cactus", "canyon", "cardinal",
+	"cedar", "cobalt", "comet", "copper", "coral", "cosmos", "cricket", "cypress", "dahlia", "delta",
+	"dune", "eagle", "ember", "falcon", "fennel", "fern", "finch", "fjord", "flint", "garnet",
+	"gecko", "ginger", "glacier", "granite", "harbor", "hazel", "heron", "hickory", "indigo", "iris",
+	"ivy", "jade", "jasper", "juniper", "kestrel", "kiwi", "lagoon", "larch", "lark", "lemon",
+	"lilac", "linden", "lotus", "lynx", "magnet", "mango", "maple", "marble", "meadow", "merlin",
+	"mesa", "mint", "mistral", "nectar", "nimbus", "nova", "oak", "obsidian", "olive", "onyx",
+	"opal", "orchid", "osprey", "otter", "pebble", "pepper", "pine", "plover", "poplar", "prairie",
+	"quartz", "quill", "raven", "reef", "ridge", "robin", "rowan", "saffron", "sage", "sequoia",
+	"sierra", "slate", "sparrow", "spruce", "summit", "tamarind", "teal", "thistle", "thunder", "tidal",
+	"topaz", "tulip", "tundra", "umber", "valley", "velvet", "walnut", "willow", "wren", "yarrow",
+	"zephyr", "zinnia",
+}
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
@@ -0,0 +1,125 @@
+package drift
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"sigs.k8s.io/yaml"
+)
+
+// maxRecordsPerKey bounds the history kept for each context, namespace pair and app
+const maxRecordsPerKey = 100
+
+// ScoreRecord is a drift score recorded at a point in time.
+// App is empty for the score of the whole namespace pair.
+type ScoreRecord struct {
+	Context    string    `json:"context,omitempty"` // kube context: the same namespaces of two clusters are not compared
+	NamespaceA string    `json:"namespaceA"`
+	NamespaceB string    `json:"namespaceB"`
+	App        string    `json:"app,omitempty"`
+	Score      int       `json:"score"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// matches returns true if the record belongs to the context, the namespace
+// pair (in either order) and app
+func (r *ScoreRecord) matches(context, nsA, nsB, app string) bool {
+	if r.Context != context || r.App != app {
+		return false
+	}
+	return (r.NamespaceA == nsA && r.NamespaceB == nsB) || (r.NamespaceA == nsB && r.NamespaceB == nsA)
+}
+
+// HistoryStore persists drift scores in a YAML file
+type HistoryStore struct {
+	path    string
+	Records []ScoreRecord `json:"records"`
+}
+
+// DefaultHistoryPath returns the history file path (~/.config/envtop/history.yaml)
+func DefaultHistoryPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.yaml"), nil
+}
+
+// LoadHistory reads the history file. A missing file yields an empty store.
+func LoadHistory(path string) (*HistoryStore, error) {
+	store := &HistoryStore{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// Last returns the most recently recorded score of a context, namespace pair and app
+func (s *HistoryStore) Last(context, nsA, nsB, app string) (int, bool) {
+	for i := len(s.Records) - 1; i >= 0; i-- {
+		if s.Records[i].matches(context, nsA, nsB, app) {
+			return s.Records[i].Score, true
+		}
+	}
+	return 0, false
+}
+
+// Record appends the scores of a summary and saves the store. A partial
+// summary is not recorded: its lower total would read as an improvement.
+func (s *HistoryStore) Record(context, nsA, nsB string, summary ScoreSummary, now time.Time) error {
+	if summary.Total.Partial {
+		return nil
+	}
+	s.add(ScoreRecord{Context: context, NamespaceA: nsA, NamespaceB: nsB, Score: summary.Total.Value, RecordedAt: now})
+	for app, score := range summary.Apps {
+		s.add(ScoreRecord{Context: context, NamespaceA: nsA, NamespaceB: nsB, App: app, Score: score.Value, RecordedAt: now})
+	}
+	return s.Save()
+}
+
+// add appends a record, dropping the oldest one of the same key beyond maxRecordsPerKey
+func (s *HistoryStore) add(record ScoreRecord) {
+	count := 0
+	for i := range s.Records {
+		if s.Records[i].matches(record.Context, record.NamespaceA, record.NamespaceB, record.App) {
+			count++
+		}
+	}
+	if count >= maxRecordsPerKey {
+		for i := range s.Records {
+			if s.Records[i].matches(record.Context, record.NamespaceA, record.NamespaceB, record.App) {
+				s.Records = append(s.Records[:i], s.Records[i+1:]...)
+				break
+			}
+		}
+	}
+	s.Records = append(s.Records, record)
+}
+
+// Save writes the store to disk
+func (s *HistoryStore) Save() error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(s.path), err)
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package drift
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"sigs.k8s.io/yaml"
+)
+
+// Ignore records a drifted variable that has been accepted as expected
+type Ignore struct {
+	NamespaceA    string    `json:"namespaceA"`
+	NamespaceB    string    `json:"namespaceB"`
+	App           string    `json:"app"`
+	Name          string    `json:"name"`
+	Justification string    `json:"justification"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// IgnoreStore persists accepted drift in a YAML file
+type IgnoreStore struct {
+	path    string
+	Ignores []Ignore `json:"ignores"`
+}
+
+// DefaultIgnorePath returns the ignore file path (~/.config/envtop/ignores.yaml)
+func DefaultIgnorePath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ignores.yaml"), nil
+}
+
+// LoadIgnores reads the ignore file. A missing file yields an empty store.
+func LoadIgnores(path string) (*IgnoreStore, error) {
+	store := &IgnoreStore{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// IsIgnored returns true if the variable's drift between the two namespaces was accepted.
+// Namespace order does not matter.
+func (s *IgnoreStore) IsIgnored(nsA, nsB, app, name string) bool {
+	for _, ig := range s.Ignores {
+		if ig.App != app || ig.Name != name {
+			continue
+		}
+		if (ig.NamespaceA == nsA && ig.NamespaceB == nsB) || (ig.NamespaceA == nsB && ig.NamespaceB == nsA) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add appends an ignore and saves the store
+func (s *IgnoreStore) Add(ig Ignore) error {
+	if ig.CreatedAt.IsZero() {
+		ig.CreatedAt = time.Now()
+	}
+	s.Ignores = append(s.Ignores, ig)
+	return s.Save()
+}
+
+// Save writes the store to disk
+func (s *IgnoreStore) Save() error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(s.path), err)
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package drift
+
+import (
+	"github.com/ginbear/k8s-envtop/internal/env"
+)
+
+// Drift score weights. A variable missing on one side counts more than a
+// differing value, and drift of secret or redacted values counts double.
+const (
+	WeightValueDiff = 1
+	WeightMissing   = 2
+	SecretFactor    = 2
+)
+
+// Trend describes how a score moved since the previous recording
+type Trend string
+
+const (
+	TrendNew       Trend = "new"
+	TrendImproving Trend = "improving"
+	TrendWorsening Trend = "worsening"
+	TrendUnchanged Trend = "unchanged"
+	TrendPartial   Trend = "partial" // some apps failed to diff, not comparable
+)
+
+// Symbol returns a one-character representation of the trend
+func (t Trend) Symbol() string {
+	switch t {
+	case TrendImproving:
+		return "↓"
+	case TrendWorsening:
+		return "↑"
+	case TrendUnchanged:
+		return "="
+	case TrendPartial:
+		return "?"
+	default:
+		return "*"
+	}
+}
+
+// Score is a drift score with the previously recorded score, if any.
+// Partial is set on the total of a bulk diff where some apps failed.
+type Score struct {
+	Value       int
+	Previous    int
+	HasPrevious bool
+	Partial     bool
+}
+
+// Trend compares the score with the previous one
+func (s Score) Trend() Trend {
+	switch {
+	case s.Partial:
+		return TrendPartial
+	case !s.HasPrevious:
+		return TrendNew
+	case s.Value < s.Previous:
+		return TrendImproving
+	case s.Value > s.Previous:
+		return TrendWorsening
+	default:
+		return TrendUnchanged
+	}
+}
+
+// ScoreSummary holds the drift score of a namespace pair and of each shared app
+type ScoreSummary struct {
+	Total Score
+	Apps  map[string]Score
+}
+
+// LoadScores scores a bulk diff of a kube context against the default ignore
+// and history files. The new scores are not recorded: callers that keep a
+// history pass them to Record of the returned store.
+func LoadScores(diffs []env.AppDiff, context, nsA, nsB string) (ScoreSummary, *IgnoreStore, *HistoryStore, error) {
+	ignorePath, err := DefaultIgnorePath()
+	if err != nil {
+		return ScoreSummary{}, nil, nil, err
+	}
+	ignores, err := LoadIgnores(ignorePath)
+	if err != nil {
+		return ScoreSummary{}, nil, nil, err
+	}
+	historyPath, err := DefaultHistoryPath()
+	if err != nil {
+		return ScoreSummary{}, nil, nil, err
+	}
+	history, err := LoadHistory(historyPath)
+	if err != nil {
+		return ScoreSummary{}, nil, nil, err
+	}
+
+	return ScoreDiffs(diffs, ignores, history, context, nsA, nsB), ignores, history, nil
+}
+
+// ScoreResults returns the weighted drift count of one app's diff results
+func ScoreResults(results []env.DiffResult) int {
+	score := 0
+	for _, r := range results {
+		weight := 0
+		switch r.Status {
+		case env.DiffStatusValueDiff:
+			weight = WeightValueDiff
+		case env.DiffStatusOnlyInA, env.DiffStatusOnlyInB:
+			weight = WeightMissing
+		}
+		if (r.EnvA != nil && r.EnvA.IsMasked()) || (r.EnvB != nil && r.EnvB.IsMasked()) {
+			weight *= SecretFactor
+		}
+		score += weight
+	}
+	return score
+}
+
+// ScoreDiffs scores a bulk diff, skipping accepted drift, and fills in the
+// previous scores from the history store when one is given. Apps whose diff
+// failed are left out, and make the total partial.
+func ScoreDiffs(diffs []env.AppDiff, ignores *IgnoreStore, history *HistoryStore, context, nsA, nsB string) ScoreSummary {
+	summary := ScoreSummary{Apps: make(map[string]Score, len(diffs))}
+	for _, diff := range diffs {
+		if diff.Err != nil {
+			summary.Total.Partial = true
+			continue
+		}
+		results := make([]env.DiffResult, 0, len(diff.Results))
+		for _, r := range diff.Results {
+			if ignores != nil && ignores.IsIgnored(nsA, nsB, diff.App.Name, r.Name) {
+				continue
+			}
+			results = append(results, r)
+		}
+
+		score := Score{Value: ScoreResults(results)}
+		if history != nil {
+			score.Previous, score.HasPrevious = history.Last(context, nsA, nsB, diff.App.Name)
+		}
+		summary.Apps[diff.App.Name] = score
+		summary.Total.Value += score.Value
+	}
+
+	if history != nil && !summary.Total.Partial {
+		summary.Total.Previous, summary.Total.HasPrevious = history.Last(context, nsA, nsB, "")
+	}
+	return summary
+}
@@ -0,0 +1,68 @@
+package drift
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ginbear/k8s-envtop/internal/env"
+)
+
+// Mark is the triage decision for a worklist item
+type Mark int
+
+const (
+	MarkPending Mark = iota
+	MarkExpected
+	MarkNeedsFix
+)
+
+// Item is a drifted variable awaiting triage, or an app whose diff failed
+// (Err set), which cannot be triaged
+type Item struct {
+	App    string
+	Result env.DiffResult
+	Mark   Mark
+	Err    error
+}
+
+// BuildWorklist collects the drifted variables of a bulk diff, skipping accepted
+// ones. Apps that failed to diff are listed as items with their error, so that
+// an empty worklist means every app was compared.
+func BuildWorklist(diffs []env.AppDiff, ignores *IgnoreStore, nsA, nsB string) []Item {
+	items := make([]Item, 0)
+	for _, diff := range diffs {
+		if diff.Err != nil {
+			items = append(items, Item{App: diff.App.Name, Err: diff.Err})
+			continue
+		}
+		for _, r := range diff.Results {
+			if !r.Status.IsDrift() {
+				continue
+			}
+			if ignores != nil && ignores.IsIgnored(nsA, nsB, diff.App.Name, r.Name) {
+				continue
+			}
+			items = append(items, Item{App: diff.App.Name, Result: r})
+		}
+	}
+	return items
+}
+
+// WriteTaskList writes the items marked "needs fix" as a Markdown task list
+func WriteTaskList(w io.Writer, nsA, nsB string, items []Item) (int, error) {
+	if _, err := fmt.Fprintf(w, "# Env drift to fix: %s vs %s\n\n", nsA, nsB); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, item := range items {
+		if item.Mark != MarkNeedsFix {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "- [ ] %s: `%s` (%s)\n", item.App, item.Result.Name, item.Result.Status); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
@@ -0,0 +1,115 @@
+package env
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// objectCache keeps the ConfigMaps and Secrets a resolver read for a while, so
+// that switching between the apps of a namespace does not read them again.
+// Unlike the fetchCache of a single resolution, it outlives the operations and
+// is shared by every goroutine using the resolver. Failed reads are not kept.
+type objectCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	objects map[string]cachedObject // by objectKey
+}
+
+type cachedObject struct {
+	object    any // *corev1.ConfigMap or *corev1.Secret
+	fetchedAt time.Time
+}
+
+// objectKey identifies a cached object
+func objectKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+func (c *objectCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.objects[key]
+	if !ok || time.Since(cached.fetchedAt) >= c.ttl {
+		return nil, false
+	}
+	return cached.object, true
+}
+
+func (c *objectCache) put(key string, object any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.objects[key] = cachedObject{object: object, fetchedAt: time.Now()}
+}
+
+// SetCacheTTL keeps the ConfigMaps and Secrets read by the resolver for ttl.
+// A ttl of 0 or less disables the cache, which is the default.
+func (r *Resolver) SetCacheTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		r.cache = nil
+		return
+	}
+	r.cache = &objectCache{ttl: ttl, objects: make(map[string]cachedObject)}
+}
+
+// Invalidate drops a ConfigMap or Secret (kind being k8s.WatchKindConfigMap or
+// k8s.WatchKindSecret) from the cache, e.g. when the live watch reports a change
+func (r *Resolver) Invalidate(kind, namespace, name string) {
+	if r.cache == nil {
+		return
+	}
+	r.cache.mu.Lock()
+	defer r.cache.mu.Unlock()
+	delete(r.cache.objects, objectKey(kind, namespace, name))
+}
+
+// InvalidateKind drops every cached object of a kind, or of every kind if kind
+// is empty, e.g. on a manual reload
+func (r *Resolver) InvalidateKind(kind string) {
+	if r.cache == nil {
+		return
+	}
+	r.cache.mu.Lock()
+	defer r.cache.mu.Unlock()
+	for key := range r.cache.objects {
+		if kind == "" || strings.HasPrefix(key, kind+"/") {
+			delete(r.cache.objects, key)
+		}
+	}
+}
+
+// readConfigMap reads a ConfigMap from the cache of the resolver, or the cluster
+func (r *Resolver) readConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	if r.cache == nil {
+		return r.client.GetConfigMap(ctx, namespace, name)
+	}
+	key := objectKey(k8s.WatchKindConfigMap, namespace, name)
+	if cached, ok := r.cache.get(key); ok {
+		return cached.(*corev1.ConfigMap), nil
+	}
+	cm, err := r.client.GetConfigMap(ctx, namespace, name)
+	if err == nil {
+		r.cache.put(key, cm)
+	}
+	return cm, err
+}
+
+// readSecret reads a Secret from the cache of the resolver, or the cluster
+func (r *Resolver) readSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	if r.cache == nil {
+		return r.client.GetSecret(ctx, namespace, name)
+	}
+	key := objectKey(k8s.WatchKindSecret, namespace, name)
+	if cached, ok := r.cache.get(key); ok {
+		return cached.(*corev1.Secret), nil
+	}
+	secret, err := r.client.GetSecret(ctx, namespace, name)
+	if err == nil {
+		r.cache.put(key, secret)
+	}
+	return secret, err
+}
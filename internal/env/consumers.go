@@ -0,0 +1,92 @@
+package env
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SourceConsumer is a reference of an app to a ConfigMap or Secret
+type SourceConsumer struct {
+	App       k8s.App
+	Container string // "" for volumes
+	Via       string // "env FOO", "envFrom" or "volume config"
+	Key       string // key read by an env entry or mounted by a volume ("" for all keys)
+}
+
+// SourceConsumers returns every reference of the given apps to a ConfigMap (or
+// a Secret when secret is set), through env, envFrom and volumes, in the order
+// of the apps. It is the blast radius of changing a value of the object.
+func (r *Resolver) SourceConsumers(ctx context.Context, apps []k8s.App, secret bool, name string) ([]SourceConsumer, error) {
+	var consumers []SourceConsumer
+	for _, app := range apps {
+		podSpec, err := r.appPodSpec(ctx, app)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", app.Name, err)
+		}
+		for _, c := range podSpecConsumers(podSpec, secret, name) {
+			c.App = app
+			consumers = append(consumers, c)
+		}
+	}
+	return consumers, nil
+}
+
+// podSpecConsumers returns the references of a PodSpec to a ConfigMap or Secret
+func podSpecConsumers(podSpec *corev1.PodSpec, secret bool, name string) []SourceConsumer {
+	var consumers []SourceConsumer
+
+	allContainers := append(append([]corev1.Container{}, podSpec.Containers...), podSpec.InitContainers...)
+	for _, container := range allContainers {
+		for _, envFrom := range container.EnvFrom {
+			if secret && envFrom.SecretRef != nil && envFrom.SecretRef.Name == name ||
+				!secret && envFrom.ConfigMapRef != nil && envFrom.ConfigMapRef.Name == name {
+				via := "envFrom"
+				if envFrom.Prefix != "" {
+					via += " prefix " + envFrom.Prefix
+				}
+				consumers = append(consumers, SourceConsumer{Container: container.Name, Via: via})
+			}
+		}
+		for _, env := range container.Env {
+			from := env.ValueFrom
+			switch {
+			case from == nil:
+			case secret && from.SecretKeyRef != nil && from.SecretKeyRef.Name == name:
+				consumers = append(consumers, SourceConsumer{Container: container.Name, Via: "env " + env.Name, Key: from.SecretKeyRef.Key})
+			case !secret && from.ConfigMapKeyRef != nil && from.ConfigMapKeyRef.Name == name:
+				consumers = append(consumers, SourceConsumer{Container: container.Name, Via: "env " + env.Name, Key: from.ConfigMapKeyRef.Key})
+			}
+		}
+	}
+
+	addVolume := func(volume string, items []corev1.KeyToPath) {
+		if len(items) == 0 {
+			consumers = append(consumers, SourceConsumer{Via: "volume " + volume})
+			return
+		}
+		for _, item := range items {
+			consumers = append(consumers, SourceConsumer{Via: "volume " + volume, Key: item.Key})
+		}
+	}
+	for _, volume := range podSpec.Volumes {
+		switch {
+		case secret && volume.Secret != nil && volume.Secret.SecretName == name:
+			addVolume(volume.Name, volume.Secret.Items)
+		case !secret && volume.ConfigMap != nil && volume.ConfigMap.Name == name:
+			addVolume(volume.Name, volume.ConfigMap.Items)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				switch {
+				case secret && source.Secret != nil && source.Secret.Name == name:
+					addVolume(volume.Name, source.Secret.Items)
+				case !secret && source.ConfigMap != nil && source.ConfigMap.Name == name:
+					addVolume(volume.Name, source.ConfigMap.Items)
+				}
+			}
+		}
+	}
+	return consumers
+}
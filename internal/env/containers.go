@@ -0,0 +1,77 @@
+package env
+
+import (
+	"sort"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// MergeContainers merges per-container env into one list by name. The first container
+// defining a variable wins (app containers in spec order, then init containers).
+// The result is sorted by name.
+func MergeContainers(envVars []k8s.EnvVar) []k8s.EnvVar {
+	merged := make([]k8s.EnvVar, 0, len(envVars))
+	seen := make(map[string]bool)
+	for _, ev := range envVars {
+		if key := entryKey(ev); !seen[key] {
+			seen[key] = true
+			merged = append(merged, ev)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Name < merged[j].Name
+	})
+	return merged
+}
+
+// Containers returns the names of the containers in per-container env, in order
+func Containers(envVars []k8s.EnvVar) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, ev := range envVars {
+		if !seen[ev.Container] {
+			seen[ev.Container] = true
+			names = append(names, ev.Container)
+		}
+	}
+	return names
+}
+
+// Conflicts returns, by variable name, the containers defining a variable
+// when they do not all agree on its value
+func Conflicts(envVars []k8s.EnvVar) map[string][]string {
+	byName := make(map[string][]k8s.EnvVar)
+	for _, ev := range envVars {
+		byName[ev.Name] = append(byName[ev.Name], ev)
+	}
+
+	conflicts := make(map[string][]string)
+	for name, defs := range byName {
+		if len(defs) < 2 {
+			continue
+		}
+		differ := false
+		for _, ev := range defs[1:] {
+			if valueKey(&ev) != valueKey(&defs[0]) {
+				differ = true
+				break
+			}
+		}
+		if !differ {
+			continue
+		}
+		for _, ev := range defs {
+			conflicts[name] = append(conflicts[name], ev.Container)
+		}
+	}
+	return conflicts
+}
+
+// valueKey identifies the value of a variable, by hash when it is masked
+func valueKey(ev *k8s.EnvVar) string {
+	if ev.IsMasked() {
+		return ev.Hash
+	}
+	return ev.Value
+}
@@ -0,0 +1,179 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnvVarReference is one place of the pod spec that defines a variable
+type EnvVarReference struct {
+	Container   string
+	Init        bool
+	Field       string // "env[2]" or "envFrom[0]"
+	EnvFrom     bool
+	Prefix      string // envFrom prefix applied to the key
+	Kind        k8s.EnvSourceKind
+	Namespace   string
+	Source      string // ConfigMap/Secret name, field path or resource ("" for inline values)
+	Key         string // key in the ConfigMap/Secret
+	Optional    bool
+	Missing     bool      // the ConfigMap/Secret does not exist
+	Terminating bool      // the ConfigMap/Secret has a deletionTimestamp
+	Modified    time.Time // last modification of the ConfigMap/Secret (zero if unknown)
+}
+
+// EnvVarProvenance lists every reference to a variable in the pod spec
+type EnvVarProvenance struct {
+	Name       string
+	References []EnvVarReference
+}
+
+// AppEnvVarProvenance returns where the variable of an app is defined
+func (r *Resolver) AppEnvVarProvenance(ctx context.Context, app k8s.App, name string) (*EnvVarProvenance, error) {
+	template, err := r.appPodTemplate(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	return r.provenance(ctx, app.Namespace, &template.Spec, name), nil
+}
+
+// PodEnvVarProvenance is AppEnvVarProvenance for a running pod
+func (r *Resolver) PodEnvVarProvenance(ctx context.Context, namespace, podName, name string) (*EnvVarProvenance, error) {
+	pod, err := r.client.GetPod(ctx, namespace, podName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
+	}
+	return r.provenance(ctx, namespace, &pod.Spec, name), nil
+}
+
+// provenance walks the containers in the order the resolver does
+func (r *Resolver) provenance(ctx context.Context, namespace string, podSpec *corev1.PodSpec, name string) *EnvVarProvenance {
+	p := &EnvVarProvenance{Name: name}
+
+	walk := func(container corev1.Container, init bool) {
+		for i, envFrom := range container.EnvFrom {
+			if !strings.HasPrefix(name, envFrom.Prefix) {
+				continue
+			}
+			ref := EnvVarReference{
+				Container: container.Name,
+				Init:      init,
+				Field:     fmt.Sprintf("envFrom[%d]", i),
+				EnvFrom:   true,
+				Prefix:    envFrom.Prefix,
+				Namespace: namespace,
+				Key:       strings.TrimPrefix(name, envFrom.Prefix),
+			}
+			var optional *bool
+			switch {
+			case envFrom.ConfigMapRef != nil:
+				ref.Kind, ref.Source, optional = k8s.EnvSourceConfigMap, envFrom.ConfigMapRef.Name, envFrom.ConfigMapRef.Optional
+			case envFrom.SecretRef != nil:
+				ref.Kind, ref.Source, optional = k8s.EnvSourceSecret, envFrom.SecretRef.Name, envFrom.SecretRef.Optional
+			default:
+				continue
+			}
+			ref.Optional = optional != nil && *optional
+			if r.describeSource(ctx, &ref) {
+				p.References = append(p.References, ref)
+			}
+		}
+
+		for i, env := range container.Env {
+			if env.Name != name {
+				continue
+			}
+			ref := EnvVarReference{
+				Container: container.Name,
+				Init:      init,
+				Field:     fmt.Sprintf("env[%d]", i),
+				Kind:      k8s.EnvSourceInline,
+				Namespace: namespace,
+			}
+			if from := env.ValueFrom; from != nil {
+				var optional *bool
+				switch {
+				case from.ConfigMapKeyRef != nil:
+					ref.Kind, ref.Source, ref.Key, optional = k8s.EnvSourceConfigMap, from.ConfigMapKeyRef.Name, from.ConfigMapKeyRef.Key, from.ConfigMapKeyRef.Optional
+				case from.SecretKeyRef != nil:
+					ref.Kind, ref.Source, ref.Key, optional = k8s.EnvSourceSecret, from.SecretKeyRef.Name, from.SecretKeyRef.Key, from.SecretKeyRef.Optional
+				case from.FieldRef != nil:
+					ref.Kind, ref.Source = k8s.EnvSourceFieldRef, from.FieldRef.FieldPath
+				case from.ResourceFieldRef != nil:
+					ref.Kind, ref.Source = k8s.EnvSourceResourceRef, from.ResourceFieldRef.Resource
+					if from.ResourceFieldRef.ContainerName != "" {
+						ref.Source = from.ResourceFieldRef.ContainerName + "/" + ref.Source
+					}
+				}
+				ref.Optional = optional != nil && *optional
+				if ref.Key != "" {
+					r.describeSource(ctx, &ref)
+				}
+			}
+			p.References = append(p.References, ref)
+		}
+	}
+
+	for _, container := range podSpec.Containers {
+		walk(container, false)
+	}
+	for _, container := range podSpec.InitContainers {
+		walk(container, true)
+	}
+	return p
+}
+
+// describeSource reads the ConfigMap/Secret of a reference and fills in its
+// modification time. It returns false for an envFrom source without the key.
+func (r *Resolver) describeSource(ctx context.Context, ref *EnvVarReference) bool {
+	var meta metav1.ObjectMeta
+	keys := make(map[string]bool)
+	if ref.Kind == k8s.EnvSourceConfigMap {
+		cm, err := r.getConfigMap(ctx, ref.Namespace, ref.Source)
+		if err != nil {
+			ref.Missing = true
+			return !ref.EnvFrom
+		}
+		meta = cm.ObjectMeta
+		for k := range cm.Data {
+			keys[k] = true
+		}
+		for k := range cm.BinaryData {
+			keys[k] = true
+		}
+	} else {
+		secret, err := r.getSecret(ctx, ref.Namespace, ref.Source)
+		if err != nil {
+			ref.Missing = true
+			return !ref.EnvFrom
+		}
+		if isSealedSecret(secret) {
+			ref.Kind = k8s.EnvSourceSealedSecret
+		}
+		meta = secret.ObjectMeta
+		for k := range secret.Data {
+			keys[k] = true
+		}
+	}
+	ref.Modified = lastModified(meta)
+	ref.Terminating = meta.DeletionTimestamp != nil
+	return !ref.EnvFrom || keys[ref.Key]
+}
+
+// lastModified returns the time of the latest write recorded in managedFields,
+// falling back to the creation time
+func lastModified(meta metav1.ObjectMeta) time.Time {
+	modified := meta.CreationTimestamp.Time
+	for _, entry := range meta.ManagedFields {
+		if entry.Time != nil && entry.Time.After(modified) {
+			modified = entry.Time.Time
+		}
+	}
+	return modified
+}
@@ -0,0 +1,313 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ExplainStep is one decision taken while resolving a variable
+type ExplainStep struct {
+	Container string
+	Source    string // where the step happens, e.g. "envFrom[0] ConfigMap app-config" or "env[2]"
+	Detail    string // what was decided
+	Applied   bool   // the step set the value the container ends up with
+}
+
+// Explanation is a human-readable trace of how a variable's value was determined
+type Explanation struct {
+	Name      string
+	Steps     []ExplainStep
+	Container string // container whose value the merged view shows ("" if none defines it)
+}
+
+// refPattern matches $(VAR) references and $$ escapes in env values
+var refPattern = regexp.MustCompile(`\$\$|\$\(([A-Za-z_][A-Za-z0-9_.-]*)\)`)
+
+// ExplainAppEnvVar traces how the value of a variable of an app is determined
+func (r *Resolver) ExplainAppEnvVar(ctx context.Context, app k8s.App, name string) (*Explanation, error) {
+	template, err := r.appPodTemplate(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	return r.explain(ctx, app.Namespace, &template.Spec, templateFields(app.Namespace, template), name), nil
+}
+
+// ExplainPodEnvVar is ExplainAppEnvVar for a running pod
+func (r *Resolver) ExplainPodEnvVar(ctx context.Context, namespace, podName, name string) (*Explanation, error) {
+	pod, err := r.client.GetPod(ctx, namespace, podName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
+	}
+	return r.explain(ctx, namespace, &pod.Spec, livePodFields(pod), name), nil
+}
+
+// explain walks the containers in the order the resolver does, recording every
+// source that defines the variable or that fails in a way that matters for it
+func (r *Resolver) explain(ctx context.Context, namespace string, podSpec *corev1.PodSpec, fields podFields, name string) *Explanation {
+	exp := &Explanation{Name: name}
+
+	containers := make([]corev1.Container, 0, len(podSpec.Containers)+len(podSpec.InitContainers))
+	containers = append(containers, podSpec.Containers...)
+	containers = append(containers, podSpec.InitContainers...)
+
+	for _, container := range containers {
+		fields.container = container.Name
+		c := &containerTrace{
+			exp:       exp,
+			container: container.Name,
+			fields:    fields,
+			defined:   make(map[string]k8s.EnvVar),
+			pending:   make(map[string]corev1.EnvVar),
+		}
+
+		for i, envFrom := range container.EnvFrom {
+			r.explainEnvFrom(ctx, c, namespace, i, envFrom, name)
+		}
+		for i, env := range container.Env {
+			r.explainEnv(ctx, c, namespace, i, env, name)
+		}
+
+		if c.set && exp.Container == "" {
+			exp.Container = container.Name
+			exp.add(container.Name, "merged view", "shows this container's value (first container defining "+name+")", false)
+		} else if c.set {
+			exp.add(container.Name, "merged view", "hidden behind the value of container "+exp.Container, false)
+		}
+	}
+
+	if exp.Container == "" {
+		exp.add("", "result", "no container defines "+name, false)
+	}
+	return exp
+}
+
+// containerTrace is the state of the walk through one container
+type containerTrace struct {
+	exp       *Explanation
+	container string
+	set       bool   // the variable is defined in this container
+	setBy     string // source of the current value
+	fields    podFields
+	// Variables defined so far, for $(VAR) expansion. valueFrom entries are
+	// kept unresolved until an expansion needs them.
+	defined map[string]k8s.EnvVar
+	pending map[string]corev1.EnvVar
+}
+
+func (e *Explanation) add(container, source, detail string, applied bool) {
+	e.Steps = append(e.Steps, ExplainStep{Container: container, Source: source, Detail: detail, Applied: applied})
+}
+
+// apply records that source sets the variable, overriding an earlier source of the container
+func (c *containerTrace) apply(source, detail string) {
+	if c.set {
+		detail += " (overrides " + c.setBy + ")"
+	}
+	// Only the last source setting the variable is applied in the end
+	for i := range c.exp.Steps {
+		if c.exp.Steps[i].Container == c.container {
+			c.exp.Steps[i].Applied = false
+		}
+	}
+	c.exp.add(c.container, source, detail, true)
+	c.set = true
+	c.setBy = source
+}
+
+// define makes a variable visible to the expansion of later entries
+func (c *containerTrace) define(v k8s.EnvVar) {
+	c.defined[v.Name] = v
+	delete(c.pending, v.Name)
+}
+
+func (r *Resolver) explainEnvFrom(ctx context.Context, c *containerTrace, namespace string, i int, envFrom corev1.EnvFromSource, name string) {
+	var kind, sourceName string
+	var optional *bool
+	switch {
+	case envFrom.ConfigMapRef != nil:
+		kind, sourceName, optional = "ConfigMap", envFrom.ConfigMapRef.Name, envFrom.ConfigMapRef.Optional
+	case envFrom.SecretRef != nil:
+		kind, sourceName, optional = "Secret", envFrom.SecretRef.Name, envFrom.SecretRef.Optional
+	default:
+		return
+	}
+	source := fmt.Sprintf("envFrom[%d] %s %s", i, kind, sourceName)
+	if envFrom.Prefix != "" {
+		source += fmt.Sprintf(" (prefix %s)", envFrom.Prefix)
+	}
+
+	if _, err := r.sourceKeys(ctx, namespace, kind, sourceName); err != nil {
+		if optional != nil && *optional {
+			c.exp.add(c.container, source, "optional and not found: skipped", false)
+		} else {
+			c.exp.add(c.container, source, fmt.Sprintf("cannot be read (%v): the container fails to start", err), false)
+		}
+		return
+	}
+
+	vars, err := r.resolveEnvFrom(ctx, namespace, envFrom)
+	if err != nil {
+		c.exp.add(c.container, source, fmt.Sprintf("cannot be read (%v): the container fails to start", err), false)
+		return
+	}
+	for _, v := range vars {
+		c.define(v)
+		if v.Name == name {
+			c.apply(source, "sets "+name+" = "+explainValue(r.masked(v)))
+		}
+	}
+}
+
+func (r *Resolver) explainEnv(ctx context.Context, c *containerTrace, namespace string, i int, env corev1.EnvVar, name string) {
+	if env.Name != name {
+		if env.ValueFrom == nil {
+			c.define(k8s.EnvVar{Name: env.Name, Value: env.Value, SourceKind: k8s.EnvSourceInline})
+		} else {
+			c.pending[env.Name] = env
+		}
+		return
+	}
+	source := fmt.Sprintf("env[%d]", i)
+
+	if env.ValueFrom != nil {
+		if detail, ok := r.explainKeyRef(ctx, namespace, env.ValueFrom); !ok {
+			c.exp.add(c.container, source, detail, false)
+			return
+		}
+	}
+
+	v, err := r.resolveEnvVar(ctx, namespace, env, c.fields)
+	if err != nil {
+		c.exp.add(c.container, source, fmt.Sprintf("cannot be resolved (%v): the container fails to start", err), false)
+		return
+	}
+	masked := r.masked(v)
+
+	switch {
+	case env.ValueFrom == nil:
+		c.apply(source, "sets "+name+" inline = "+explainValue(masked))
+		if !masked.IsMasked() {
+			r.explainExpansion(ctx, c, namespace, source, env.Value)
+		}
+	case env.ValueFrom.ConfigMapKeyRef != nil:
+		ref := env.ValueFrom.ConfigMapKeyRef
+		c.apply(source, fmt.Sprintf("sets %s from ConfigMap %s key %s = %s", name, ref.Name, ref.Key, explainValue(masked)))
+	case env.ValueFrom.SecretKeyRef != nil:
+		ref := env.ValueFrom.SecretKeyRef
+		c.apply(source, fmt.Sprintf("sets %s from Secret %s key %s = %s", name, ref.Name, ref.Key, explainValue(masked)))
+	case env.ValueFrom.FieldRef != nil:
+		if v.Unresolved {
+			c.apply(source, fmt.Sprintf("sets %s from the pod field %s (known only at runtime)", name, env.ValueFrom.FieldRef.FieldPath))
+		} else {
+			c.apply(source, fmt.Sprintf("sets %s from the pod field %s = %s", name, env.ValueFrom.FieldRef.FieldPath, explainValue(masked)))
+		}
+	case env.ValueFrom.ResourceFieldRef != nil:
+		ref := env.ValueFrom.ResourceFieldRef
+		switch {
+		case !v.Unresolved:
+			c.apply(source, fmt.Sprintf("sets %s from the container resource %s = %s (%s, rounded up)", name, ref.Resource, explainValue(masked), v.Detail))
+		case v.Detail != "":
+			c.apply(source, fmt.Sprintf("sets %s from the container resource %s (%s, known only at runtime)", name, ref.Resource, v.Detail))
+		default:
+			c.apply(source, fmt.Sprintf("sets %s from the container resource %s (known only at runtime)", name, ref.Resource))
+		}
+	default:
+		c.apply(source, "sets "+name+" from an unknown source")
+	}
+	c.define(v)
+}
+
+// explainKeyRef checks a ConfigMap/Secret key reference. ok is false when the reference
+// leaves the variable unset (optional and missing) or makes the container fail.
+func (r *Resolver) explainKeyRef(ctx context.Context, namespace string, from *corev1.EnvVarSource) (detail string, ok bool) {
+	var kind, sourceName, key string
+	var optional *bool
+	switch {
+	case from.ConfigMapKeyRef != nil:
+		kind, sourceName, key, optional = "ConfigMap", from.ConfigMapKeyRef.Name, from.ConfigMapKeyRef.Key, from.ConfigMapKeyRef.Optional
+	case from.SecretKeyRef != nil:
+		kind, sourceName, key, optional = "Secret", from.SecretKeyRef.Name, from.SecretKeyRef.Key, from.SecretKeyRef.Optional
+	default:
+		return "", true
+	}
+	isOptional := optional != nil && *optional
+
+	keys, err := r.sourceKeys(ctx, namespace, kind, sourceName)
+	switch {
+	case err != nil && isOptional:
+		return fmt.Sprintf("optional %s %s not found: the variable is not set", kind, sourceName), false
+	case err != nil:
+		return fmt.Sprintf("%s %s cannot be read (%v): the container fails to start", kind, sourceName, err), false
+	case !keys[key] && isOptional:
+		return fmt.Sprintf("optional key %s missing from %s %s: the variable is not set", key, kind, sourceName), false
+	case !keys[key]:
+		return fmt.Sprintf("key %s missing from %s %s: the container fails to start", key, kind, sourceName), false
+	}
+	return "", true
+}
+
+// sourceKeys reads a ConfigMap or Secret and returns its keys
+func (r *Resolver) sourceKeys(ctx context.Context, namespace, kind, name string) (map[string]bool, error) {
+	keys := make(map[string]bool)
+	if kind == "ConfigMap" {
+		cm, err := r.getConfigMap(ctx, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		for k := range cm.Data {
+			keys[k] = true
+		}
+		for k := range cm.BinaryData {
+			keys[k] = true
+		}
+		return keys, nil
+	}
+	secret, err := r.getSecret(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	for k := range secret.Data {
+		keys[k] = true
+	}
+	return keys, nil
+}
+
+// explainExpansion describes how the kubelet expands $(VAR) references of an inline value
+func (r *Resolver) explainExpansion(ctx context.Context, c *containerTrace, namespace, source, value string) {
+	for _, match := range refPattern.FindAllStringSubmatch(value, -1) {
+		if match[0] == "$$" {
+			c.exp.add(c.container, source, "$$ is an escape: it becomes a literal $", false)
+			continue
+		}
+		ref := match[1]
+		if env, ok := c.pending[ref]; ok {
+			if v, err := r.resolveEnvVar(ctx, namespace, env, c.fields); err == nil {
+				c.define(v)
+			}
+		}
+		if v, ok := c.defined[ref]; ok {
+			c.exp.add(c.container, source, fmt.Sprintf("$(%s) expands to %s", ref, explainValue(r.masked(v))), false)
+		} else {
+			c.exp.add(c.container, source, fmt.Sprintf("$(%s) is left as is: %s is not defined earlier in the container", ref, ref), false)
+		}
+	}
+}
+
+// masked applies the redaction rule to a single variable
+func (r *Resolver) masked(v k8s.EnvVar) k8s.EnvVar {
+	vars := []k8s.EnvVar{v}
+	r.applyRedaction(vars)
+	return vars[0]
+}
+
+// explainValue renders a value for the trace, never in clear when it is masked
+func explainValue(v k8s.EnvVar) string {
+	if v.IsMasked() {
+		return "HASH: " + v.Hash
+	}
+	return fmt.Sprintf("%q", v.Value)
+}
@@ -0,0 +1,202 @@
+package env
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// podFields holds the pod metadata a Downward API fieldRef can select. Resolving a
+// workload template only knows what every pod of the workload will share; the
+// fields decided when a pod is created or scheduled are left unknown.
+type podFields struct {
+	live           bool // taken from a running pod
+	namespace      string
+	name           string
+	uid            string
+	labels         map[string]string
+	annotations    map[string]string
+	nodeName       string
+	serviceAccount string
+	hostIPs        []string
+	podIPs         []string
+
+	// Resources of every container, and the container being resolved
+	// (the default of a resourceFieldRef without containerName)
+	resources map[string]corev1.ResourceRequirements
+	container string
+}
+
+// templateFields returns the fields known from a workload's pod template
+func templateFields(namespace string, template *corev1.PodTemplateSpec) podFields {
+	return podFields{
+		namespace:      namespace,
+		labels:         template.Labels,
+		annotations:    template.Annotations,
+		serviceAccount: serviceAccountOf(&template.Spec),
+		resources:      containerResources(&template.Spec),
+	}
+}
+
+// livePodFields returns the fields of a running pod
+func livePodFields(pod *corev1.Pod) podFields {
+	fields := podFields{
+		live:           true,
+		namespace:      pod.Namespace,
+		name:           pod.Name,
+		uid:            string(pod.UID),
+		labels:         pod.Labels,
+		annotations:    pod.Annotations,
+		nodeName:       pod.Spec.NodeName,
+		serviceAccount: serviceAccountOf(&pod.Spec),
+		resources:      containerResources(&pod.Spec),
+	}
+	for _, ip := range pod.Status.HostIPs {
+		fields.hostIPs = append(fields.hostIPs, ip.IP)
+	}
+	if len(fields.hostIPs) == 0 && pod.Status.HostIP != "" {
+		fields.hostIPs = []string{pod.Status.HostIP}
+	}
+	for _, ip := range pod.Status.PodIPs {
+		fields.podIPs = append(fields.podIPs, ip.IP)
+	}
+	if len(fields.podIPs) == 0 && pod.Status.PodIP != "" {
+		fields.podIPs = []string{pod.Status.PodIP}
+	}
+	return fields
+}
+
+// containerResources returns the resources of every container and init container by name
+func containerResources(spec *corev1.PodSpec) map[string]corev1.ResourceRequirements {
+	resources := make(map[string]corev1.ResourceRequirements, len(spec.Containers)+len(spec.InitContainers))
+	for _, c := range spec.Containers {
+		resources[c.Name] = c.Resources
+	}
+	for _, c := range spec.InitContainers {
+		resources[c.Name] = c.Resources
+	}
+	return resources
+}
+
+// serviceAccountOf returns the service account a pod runs as
+func serviceAccountOf(spec *corev1.PodSpec) string {
+	if spec.ServiceAccountName != "" {
+		return spec.ServiceAccountName
+	}
+	return "default"
+}
+
+// resolve returns the value the kubelet gives a fieldRef, and false when it is
+// not known (a runtime field of a template, or an unsupported path)
+func (f podFields) resolve(path string) (string, bool) {
+	if key, ok := subscript(path, "metadata.labels"); ok {
+		return f.mapValue(f.labels, key)
+	}
+	if key, ok := subscript(path, "metadata.annotations"); ok {
+		return f.mapValue(f.annotations, key)
+	}
+
+	switch path {
+	case "metadata.namespace":
+		return f.namespace, f.namespace != ""
+	case "spec.serviceAccountName":
+		return f.serviceAccount, f.serviceAccount != ""
+	}
+	if !f.live {
+		return "", false
+	}
+
+	switch path {
+	case "metadata.name":
+		return f.name, true
+	case "metadata.uid":
+		return f.uid, true
+	case "spec.nodeName":
+		return f.nodeName, true
+	case "status.hostIP":
+		return first(f.hostIPs), true
+	case "status.hostIPs":
+		return strings.Join(f.hostIPs, ","), true
+	case "status.podIP":
+		return first(f.podIPs), true
+	case "status.podIPs":
+		return strings.Join(f.podIPs, ","), true
+	}
+	return "", false
+}
+
+// mapValue looks up a label or annotation. A running pod yields "" for a missing
+// key, as the kubelet does; a template cannot tell, since pods get extra labels
+// (e.g. pod-template-hash) and annotations when they are created.
+func (f podFields) mapValue(m map[string]string, key string) (string, bool) {
+	value, ok := m[key]
+	return value, ok || f.live
+}
+
+// subscript parses a path like metadata.labels['app'] and returns the key
+func subscript(path, field string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, field+"['")
+	if !ok {
+		return "", false
+	}
+	return strings.CutSuffix(rest, "']")
+}
+
+// resolveResource returns the value the kubelet gives a resourceFieldRef: the
+// resource divided by the divisor, rounded up. detail shows the computation.
+// ok is false when the value depends on the node: a limit that is not set
+// defaults to the node allocatable.
+func (f podFields) resolveResource(ref *corev1.ResourceFieldSelector) (value, detail string, ok bool) {
+	container := ref.ContainerName
+	if container == "" {
+		container = f.container
+	}
+	resources, found := f.resources[container]
+	if !found {
+		return "", "", false
+	}
+
+	kind, name, _ := strings.Cut(ref.Resource, ".")
+	list := resources.Limits
+	if kind == "requests" {
+		list = resources.Requests
+		// Requests default to the limits when only limits are set
+		if _, set := list[corev1.ResourceName(name)]; !set {
+			list = resources.Limits
+		}
+	}
+	quantity, set := list[corev1.ResourceName(name)]
+	if !set {
+		if kind == "limits" {
+			return "", "not set: node allocatable", false
+		}
+		quantity = resource.Quantity{}
+	}
+
+	divisor := ref.Divisor
+	if divisor.IsZero() {
+		divisor = resource.MustParse("1")
+	}
+
+	var n int64
+	if name == string(corev1.ResourceCPU) {
+		n = int64(math.Ceil(float64(quantity.MilliValue()) / float64(divisor.MilliValue())))
+	} else {
+		n = int64(math.Ceil(float64(quantity.Value()) / float64(divisor.Value())))
+	}
+	detail = fmt.Sprintf("%s %s / %s", ref.Resource, quantity.String(), divisor.String())
+	if container != f.container {
+		detail = container + " " + detail
+	}
+	return fmt.Sprintf("%d", n), detail, true
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
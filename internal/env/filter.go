@@ -0,0 +1,111 @@
+package env
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// Filter selects variables, like a kubectl label selector. It is shared by the
+// TUI search and `envtop get env --selector`.
+//
+// The expression is a comma-separated list of terms that must all match:
+//
+//	DB          the name contains "DB"
+//	name=DB_*   the name matches the glob
+//	kind!=Secret, source=api-*, container=app
+//
+// Matching is case-insensitive.
+type Filter struct {
+	terms []filterTerm
+}
+
+// filterTerm is one term of a filter expression
+type filterTerm struct {
+	field   string // "" for a bare substring of the name
+	pattern string // lower-cased
+	negate  bool
+}
+
+// filterFields are the fields a term can select on
+var filterFields = map[string]func(k8s.EnvVar) string{
+	"name":      func(ev k8s.EnvVar) string { return ev.Name },
+	"kind":      func(ev k8s.EnvVar) string { return string(ev.SourceKind) },
+	"source":    func(ev k8s.EnvVar) string { return ev.SourceName },
+	"container": func(ev k8s.EnvVar) string { return ev.Container },
+}
+
+// ParseFilter parses a filter expression. An empty expression matches everything.
+func ParseFilter(expr string) (*Filter, error) {
+	f := &Filter{}
+	for _, term := range strings.Split(expr, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		field, pattern, ok := strings.Cut(term, "=")
+		if !ok {
+			f.terms = append(f.terms, filterTerm{pattern: strings.ToLower(term)})
+			continue
+		}
+		negate := strings.HasSuffix(field, "!")
+		field = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(field, "!")))
+		if _, known := filterFields[field]; !known {
+			return nil, fmt.Errorf("unknown filter field %q (name, kind, source or container)", field)
+		}
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		f.terms = append(f.terms, filterTerm{field: field, pattern: pattern, negate: negate})
+	}
+	return f, nil
+}
+
+// Match returns true if the variable matches every term of the filter
+func (f *Filter) Match(ev k8s.EnvVar) bool {
+	for _, t := range f.terms {
+		if t.field == "" {
+			if !strings.Contains(strings.ToLower(ev.Name), t.pattern) {
+				return false
+			}
+			continue
+		}
+		ok, _ := path.Match(t.pattern, strings.ToLower(filterFields[t.field](ev)))
+		if ok == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// SortKeys lists the keys SortEnvVars accepts
+var SortKeys = []string{"name", "kind", "source", "container"}
+
+// SortEnvVars sorts variables by a key of SortKeys. Ties keep their order, and
+// "source" orders by kind then name of the ConfigMap/Secret.
+func SortEnvVars(envVars []k8s.EnvVar, by string) error {
+	var less func(a, b k8s.EnvVar) bool
+	switch by {
+	case "name":
+		less = func(a, b k8s.EnvVar) bool { return a.Name < b.Name }
+	case "kind":
+		less = func(a, b k8s.EnvVar) bool { return a.SourceKind < b.SourceKind }
+	case "source":
+		less = func(a, b k8s.EnvVar) bool {
+			if a.SourceKind != b.SourceKind {
+				return a.SourceKind < b.SourceKind
+			}
+			return a.SourceName < b.SourceName
+		}
+	case "container":
+		less = func(a, b k8s.EnvVar) bool { return a.Container < b.Container }
+	default:
+		return fmt.Errorf("unknown sort key %q (%s)", by, strings.Join(SortKeys, ", "))
+	}
+	sort.SliceStable(envVars, func(i, j int) bool { return less(envVars[i], envVars[j]) })
+	return nil
+}
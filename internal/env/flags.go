@@ -0,0 +1,74 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// FlagState represents the interpreted state of a feature flag value
+type FlagState int
+
+const (
+	FlagUnknown FlagState = iota
+	FlagOn
+	FlagOff
+)
+
+// ParseFlagState interprets a boolean-like env value
+func ParseFlagState(value string) FlagState {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "1", "yes", "y", "on", "enabled", "enable":
+		return FlagOn
+	case "false", "0", "no", "n", "off", "disabled", "disable":
+		return FlagOff
+	default:
+		return FlagUnknown
+	}
+}
+
+// FlagMatrix holds feature flag values for every app in a namespace
+type FlagMatrix struct {
+	Apps   []string
+	Flags  []string
+	Values map[string]map[string]string // app name -> flag name -> value
+}
+
+// ResolveFlagMatrix resolves the feature flags of all given apps
+func (r *Resolver) ResolveFlagMatrix(ctx context.Context, apps []k8s.App, isFlag func(name string) bool) (*FlagMatrix, error) {
+	matrix := &FlagMatrix{
+		Apps:   make([]string, 0, len(apps)),
+		Values: make(map[string]map[string]string),
+	}
+	flagSet := make(map[string]bool)
+
+	ctx = WithFetchCache(ctx)
+	for _, app := range apps {
+		envVars, err := r.ResolveAppEnvVars(ctx, app)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", app.Name, err)
+		}
+
+		values := make(map[string]string)
+		for _, ev := range envVars {
+			if ev.IsMasked() || !isFlag(ev.Name) {
+				continue
+			}
+			values[ev.Name] = ev.Value
+			flagSet[ev.Name] = true
+		}
+		matrix.Apps = append(matrix.Apps, app.Name)
+		matrix.Values[app.Name] = values
+	}
+
+	matrix.Flags = make([]string, 0, len(flagSet))
+	for name := range flagSet {
+		matrix.Flags = append(matrix.Flags, name)
+	}
+	sort.Strings(matrix.Flags)
+
+	return matrix, nil
+}
@@ -0,0 +1,39 @@
+package env
+
+import (
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// ForbiddenValue is the value shown for a variable whose source the user may not read
+const ForbiddenValue = "(forbidden)"
+
+// forbiddenVar returns the entry of a variable whose source the user may not
+// read: the GET of the source was denied with a 403, which resolutions report
+// this way instead of failing
+func forbiddenVar(name string, kind k8s.EnvSourceKind, source string) k8s.EnvVar {
+	return k8s.EnvVar{
+		Name:       name,
+		Value:      ForbiddenValue,
+		SourceName: source,
+		SourceKind: kind,
+		Forbidden:  true,
+	}
+}
+
+// forbiddenFromName names the entry standing for the unknown variables of an
+// envFrom source the user may not read
+func forbiddenFromName(prefix string) string {
+	return prefix + "*"
+}
+
+// entryKey identifies an env entry by name, except for the placeholders of
+// forbidden envFrom sources, which are kept apart per source: two unreadable
+// Secrets without a prefix both stand for "*"
+func entryKey(ev k8s.EnvVar) string {
+	if ev.Forbidden && strings.HasSuffix(ev.Name, "*") {
+		return ev.Name + "/" + string(ev.SourceKind) + "/" + ev.SourceName
+	}
+	return ev.Name
+}
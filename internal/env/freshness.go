@@ -0,0 +1,86 @@
+package env
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StaleSource is a ConfigMap or Secret changed after running pods of an app
+// started. Env variables are read when a container starts, so those pods still
+// hold the previous values until they are restarted.
+type StaleSource struct {
+	Kind       k8s.EnvSourceKind // as shown on the variables (SealedSecret for their Secret)
+	Name       string
+	ModifiedAt time.Time
+	StalePods  []string // running pods started before the change
+	Pods       int      // running pods
+}
+
+// Key identifies the source of a variable, see StaleKey
+func (s StaleSource) Key() string {
+	return StaleKey(s.Kind, s.Name)
+}
+
+// StaleKey identifies the ConfigMap/Secret a variable comes from
+func StaleKey(kind k8s.EnvSourceKind, name string) string {
+	return string(kind) + "/" + name
+}
+
+// StaleSources compares the last change of each ConfigMap/Secret the variables
+// come from with the start time of the running pods. Sources that cannot be
+// read are skipped.
+func (r *Resolver) StaleSources(ctx context.Context, namespace string, envVars []k8s.EnvVar, pods []k8s.Pod) []StaleSource {
+	var running []k8s.Pod
+	for _, pod := range pods {
+		if pod.Phase == "Running" && !pod.StartTime.IsZero() {
+			running = append(running, pod)
+		}
+	}
+	if len(running) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var stale []StaleSource
+	for _, ev := range envVars {
+		if ev.SourceName == "" || seen[StaleKey(ev.SourceKind, ev.SourceName)] {
+			continue
+		}
+		seen[StaleKey(ev.SourceKind, ev.SourceName)] = true
+
+		var meta metav1.ObjectMeta
+		switch ev.SourceKind {
+		case k8s.EnvSourceConfigMap:
+			cm, err := r.getConfigMap(ctx, namespace, ev.SourceName)
+			if err != nil {
+				continue
+			}
+			meta = cm.ObjectMeta
+		case k8s.EnvSourceSecret, k8s.EnvSourceSealedSecret:
+			secret, err := r.getSecret(ctx, namespace, ev.SourceName)
+			if err != nil {
+				continue
+			}
+			meta = secret.ObjectMeta
+		default:
+			continue
+		}
+
+		modified := lastModified(meta)
+		source := StaleSource{Kind: ev.SourceKind, Name: ev.SourceName, ModifiedAt: modified, Pods: len(running)}
+		for _, pod := range running {
+			if pod.StartTime.Before(modified) {
+				source.StalePods = append(source.StalePods, pod.Name)
+			}
+		}
+		if len(source.StalePods) > 0 {
+			sort.Strings(source.StalePods)
+			stale = append(stale, source)
+		}
+	}
+	return stale
+}
@@ -0,0 +1,152 @@
+package env
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Helm marks the objects of a release with this annotation
+const helmReleaseAnnotation = "meta.helm.sh/release-name"
+
+// ConfigGraph shows where the configuration of an app comes from:
+// app -> containers -> ConfigMaps/Secrets -> the objects those are generated from
+type ConfigGraph struct {
+	App        k8s.App
+	Containers []GraphContainer
+}
+
+// GraphContainer lists the env sources of one container
+type GraphContainer struct {
+	Name    string
+	Init    bool
+	Sources []GraphSource
+	Inline  int // variables with an inline value
+	Runtime int // fieldRef/resourceFieldRef variables
+}
+
+// GraphSource is a ConfigMap or Secret a container reads env from
+type GraphSource struct {
+	Kind        k8s.EnvSourceKind // ConfigMap or Secret
+	Name        string
+	EnvFrom     bool     // imported as a whole through envFrom
+	Prefix      string   // envFrom prefix
+	Vars        []string // variables set from single keys through env valueFrom
+	Optional    bool
+	Missing     bool
+	Terminating bool // the object has a deletionTimestamp
+	// Origin is the object the source is generated from, e.g. "SealedSecret db",
+	// "ExternalSecret db" or "Helm release api" ("" if none is known)
+	Origin string
+}
+
+// AppConfigGraph builds the config dependency graph of an app
+func (r *Resolver) AppConfigGraph(ctx context.Context, app k8s.App) (*ConfigGraph, error) {
+	podSpec, err := r.appPodSpec(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &ConfigGraph{App: app}
+	for i, container := range append(append([]corev1.Container{}, podSpec.Containers...), podSpec.InitContainers...) {
+		gc := GraphContainer{Name: container.Name, Init: i >= len(podSpec.Containers)}
+		index := make(map[string]int)
+		source := func(kind k8s.EnvSourceKind, name string, optional *bool) *GraphSource {
+			key := string(kind) + "/" + name
+			if i, ok := index[key]; ok {
+				return &gc.Sources[i]
+			}
+			index[key] = len(gc.Sources)
+			gc.Sources = append(gc.Sources, GraphSource{Kind: kind, Name: name, Optional: optional != nil && *optional})
+			return &gc.Sources[len(gc.Sources)-1]
+		}
+
+		for _, envFrom := range container.EnvFrom {
+			var s *GraphSource
+			switch {
+			case envFrom.ConfigMapRef != nil:
+				s = source(k8s.EnvSourceConfigMap, envFrom.ConfigMapRef.Name, envFrom.ConfigMapRef.Optional)
+			case envFrom.SecretRef != nil:
+				s = source(k8s.EnvSourceSecret, envFrom.SecretRef.Name, envFrom.SecretRef.Optional)
+			default:
+				continue
+			}
+			s.EnvFrom = true
+			s.Prefix = envFrom.Prefix
+		}
+
+		for _, e := range container.Env {
+			switch {
+			case e.ValueFrom == nil:
+				gc.Inline++
+			case e.ValueFrom.ConfigMapKeyRef != nil:
+				ref := e.ValueFrom.ConfigMapKeyRef
+				s := source(k8s.EnvSourceConfigMap, ref.Name, ref.Optional)
+				s.Vars = append(s.Vars, e.Name)
+			case e.ValueFrom.SecretKeyRef != nil:
+				ref := e.ValueFrom.SecretKeyRef
+				s := source(k8s.EnvSourceSecret, ref.Name, ref.Optional)
+				s.Vars = append(s.Vars, e.Name)
+			default:
+				gc.Runtime++
+			}
+		}
+
+		for i := range gc.Sources {
+			r.graphOrigin(ctx, app.Namespace, &gc.Sources[i])
+		}
+		graph.Containers = append(graph.Containers, gc)
+	}
+	return graph, nil
+}
+
+// graphOrigin looks up a source and fills in whether it exists and where it comes from
+func (r *Resolver) graphOrigin(ctx context.Context, namespace string, s *GraphSource) {
+	var meta metav1.ObjectMeta
+	switch s.Kind {
+	case k8s.EnvSourceConfigMap:
+		cm, err := r.getConfigMap(ctx, namespace, s.Name)
+		if err != nil {
+			s.Missing = true
+			return
+		}
+		meta = cm.ObjectMeta
+	case k8s.EnvSourceSecret:
+		secret, err := r.getSecret(ctx, namespace, s.Name)
+		if err != nil {
+			s.Missing = true
+			return
+		}
+		if isSealedSecret(secret) {
+			s.Kind = k8s.EnvSourceSealedSecret
+		}
+		meta = secret.ObjectMeta
+	}
+	s.Origin = originOf(meta)
+	s.Terminating = meta.DeletionTimestamp != nil
+}
+
+// originOf names the object a ConfigMap or Secret is generated from: its controller
+// (SealedSecret, ExternalSecret, ...), or the Helm release it belongs to
+func originOf(meta metav1.ObjectMeta) string {
+	for _, ref := range meta.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			return ref.Kind + " " + ref.Name
+		}
+	}
+	for _, ref := range meta.OwnerReferences {
+		if ref.Kind == "SealedSecret" || (ref.Kind == "ExternalSecret" && strings.HasPrefix(ref.APIVersion, "external-secrets.io/")) {
+			return ref.Kind + " " + ref.Name
+		}
+	}
+	if meta.Annotations[sealedOwnedAnnotation] == "true" || meta.Annotations[sealedManagedAnnotation] == "true" {
+		return "SealedSecret " + meta.Name
+	}
+	if release := meta.Annotations[helmReleaseAnnotation]; release != "" {
+		return "Helm release " + release
+	}
+	return ""
+}
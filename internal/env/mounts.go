@@ -0,0 +1,158 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MountedFile is a file a container reads from a ConfigMap or Secret volume,
+// or that the Vault agent renders into the pod
+type MountedFile struct {
+	Container string // "" for every container
+	Path      string // path of the file in the container
+	Volume    string
+	Kind      k8s.EnvSourceKind // ConfigMap, Secret or Vault
+	Source    string            // ConfigMap/Secret name, or Vault secret path
+	Key       string            // key of the file (Vault: name of the secret); empty when the source is missing
+	Missing   bool              // the ConfigMap/Secret does not exist
+	Optional  bool
+	Template  bool     // Vault: rendered by a custom template
+	Exports   []string // Vault: variables the template exports for the app to source
+}
+
+// String describes the origin of the file, e.g. "Secret tls[tls.crt]"
+func (f MountedFile) String() string {
+	if f.Kind == k8s.EnvSourceVault {
+		s := "Vault " + f.Source
+		if f.Template {
+			s += " (template)"
+		}
+		if len(f.Exports) > 0 {
+			s += " exports " + strings.Join(f.Exports, ", ")
+		}
+		return s
+	}
+	if f.Missing {
+		return fmt.Sprintf("%s %s (not found)", f.Kind, f.Source)
+	}
+	return fmt.Sprintf("%s %s[%s]", f.Kind, f.Source, f.Key)
+}
+
+// ResolveAppMounts lists the files the containers of an app mount from
+// ConfigMap, Secret and projected volumes, followed by the secrets the Vault
+// agent injects
+func (r *Resolver) ResolveAppMounts(ctx context.Context, app k8s.App) ([]MountedFile, error) {
+	template, err := r.appPodTemplate(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	return append(r.resolveMounts(ctx, app.Namespace, &template.Spec), vaultFiles(template.Annotations)...), nil
+}
+
+// ResolvePodMounts is ResolveAppMounts for a running pod
+func (r *Resolver) ResolvePodMounts(ctx context.Context, namespace, podName string) ([]MountedFile, error) {
+	pod, err := r.client.GetPod(ctx, namespace, podName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
+	}
+	return append(r.resolveMounts(ctx, namespace, &pod.Spec), vaultFiles(pod.Annotations)...), nil
+}
+
+// mountSource is a ConfigMap or Secret projected into a volume
+type mountSource struct {
+	secret   bool
+	name     string
+	items    []corev1.KeyToPath
+	optional *bool
+}
+
+// resolveMounts walks the volumeMounts of every container. Without items, a
+// volume holds one file per key of its source, which is read to list them.
+func (r *Resolver) resolveMounts(ctx context.Context, namespace string, podSpec *corev1.PodSpec) []MountedFile {
+	volumes := make(map[string][]mountSource, len(podSpec.Volumes))
+	for _, volume := range podSpec.Volumes {
+		switch {
+		case volume.ConfigMap != nil:
+			volumes[volume.Name] = []mountSource{{name: volume.ConfigMap.Name, items: volume.ConfigMap.Items, optional: volume.ConfigMap.Optional}}
+		case volume.Secret != nil:
+			volumes[volume.Name] = []mountSource{{secret: true, name: volume.Secret.SecretName, items: volume.Secret.Items, optional: volume.Secret.Optional}}
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				switch {
+				case source.ConfigMap != nil:
+					volumes[volume.Name] = append(volumes[volume.Name], mountSource{name: source.ConfigMap.Name, items: source.ConfigMap.Items, optional: source.ConfigMap.Optional})
+				case source.Secret != nil:
+					volumes[volume.Name] = append(volumes[volume.Name], mountSource{secret: true, name: source.Secret.Name, items: source.Secret.Items, optional: source.Secret.Optional})
+				}
+			}
+		}
+	}
+
+	var files []MountedFile
+	allContainers := append(append([]corev1.Container{}, podSpec.Containers...), podSpec.InitContainers...)
+	for _, container := range allContainers {
+		for _, mount := range container.VolumeMounts {
+			for _, source := range volumes[mount.Name] {
+				files = append(files, r.sourceFiles(ctx, namespace, container.Name, mount, source)...)
+			}
+		}
+	}
+	return files
+}
+
+// sourceFiles lists the files of one source of a volume mount. A subPath mount
+// holds the single file at that path.
+func (r *Resolver) sourceFiles(ctx context.Context, namespace, container string, mount corev1.VolumeMount, source mountSource) []MountedFile {
+	base := MountedFile{
+		Container: container,
+		Volume:    mount.Name,
+		Kind:      k8s.EnvSourceConfigMap,
+		Source:    source.name,
+		Optional:  source.optional != nil && *source.optional,
+	}
+	if source.secret {
+		base.Kind = k8s.EnvSourceSecret
+	}
+
+	keys, err := r.sourceKeys(ctx, namespace, string(base.Kind), source.name)
+	if err != nil {
+		base.Missing = true
+		base.Path = mount.MountPath
+		return []MountedFile{base}
+	}
+
+	// File paths relative to the volume, by key
+	paths := make(map[string]string)
+	if len(source.items) > 0 {
+		for _, item := range source.items {
+			paths[item.Key] = item.Path
+		}
+	} else {
+		for key := range keys {
+			paths[key] = key
+		}
+	}
+
+	var files []MountedFile
+	for key, rel := range paths {
+		f := base
+		f.Key = key
+		switch {
+		case mount.SubPath == "":
+			f.Path = path.Join(mount.MountPath, rel)
+		case mount.SubPath == rel:
+			f.Path = mount.MountPath
+		default:
+			continue
+		}
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
@@ -0,0 +1,66 @@
+package env
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// MissingOptional is an optional ConfigMap or Secret (or a key of one) that does not
+// exist, so it contributed nothing to the env of a container
+type MissingOptional struct {
+	Container string
+	Kind      k8s.EnvSourceKind // ConfigMap or Secret
+	Name      string
+	Key       string // referenced key; empty for envFrom
+	Var       string // variable left unset; empty for envFrom
+}
+
+// String describes the missing source, e.g. "Secret app-secrets (envFrom)"
+func (m MissingOptional) String() string {
+	if m.Var == "" {
+		return fmt.Sprintf("%s %s (envFrom)", m.Kind, m.Name)
+	}
+	return fmt.Sprintf("%s %s key %s → %s", m.Kind, m.Name, m.Key, m.Var)
+}
+
+// missingKey is the context key of the missing optional sources recorded during a resolution
+type missingKey struct{}
+
+type missingRecorder struct {
+	missing []MissingOptional
+}
+
+// CollectMissingOptional returns a context under which resolutions record the optional
+// sources that were not found, and a function returning them
+func CollectMissingOptional(ctx context.Context) (context.Context, func() []MissingOptional) {
+	rec := &missingRecorder{}
+	return context.WithValue(ctx, missingKey{}, rec), func() []MissingOptional {
+		return rec.missing
+	}
+}
+
+// recordMissing records a missing optional source, if they are recorded
+func recordMissing(ctx context.Context, miss MissingOptional) {
+	if rec, ok := ctx.Value(missingKey{}).(*missingRecorder); ok {
+		rec.missing = append(rec.missing, miss)
+	}
+}
+
+// missingCount returns the number of missing optional sources recorded so far
+func missingCount(ctx context.Context) int {
+	if rec, ok := ctx.Value(missingKey{}).(*missingRecorder); ok {
+		return len(rec.missing)
+	}
+	return 0
+}
+
+// setMissingContainer attributes the sources recorded since from to a container
+func setMissingContainer(ctx context.Context, from int, container string) {
+	if rec, ok := ctx.Value(missingKey{}).(*missingRecorder); ok {
+		for i := from; i < len(rec.missing); i++ {
+			rec.missing[i].Container = container
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package env
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// fetchWorkers bounds the concurrent ConfigMap and Secret reads of a resolution
+const fetchWorkers = 8
+
+// fetchCache holds the ConfigMaps and Secrets read during a resolution, by
+// namespace and name, so that each one is fetched once however many variables
+// and apps reference it. Failed reads are cached as well.
+type fetchCache struct {
+	mu         sync.Mutex
+	configMaps map[string]fetchedConfigMap
+	secrets    map[string]fetchedSecret
+}
+
+type fetchedConfigMap struct {
+	cm  *corev1.ConfigMap
+	err error
+}
+
+type fetchedSecret struct {
+	secret *corev1.Secret
+	err    error
+}
+
+// fetchCacheKey is the context key of the fetchCache of a resolution
+type fetchCacheKey struct{}
+
+// fetchCacheOf returns the fetchCache of a resolution, if any
+func fetchCacheOf(ctx context.Context) *fetchCache {
+	cache, _ := ctx.Value(fetchCacheKey{}).(*fetchCache)
+	return cache
+}
+
+// WithFetchCache returns a context whose resolutions share the ConfigMaps and
+// Secrets they read, e.g. to resolve every app of a namespace. An object read
+// once is not read again, so the context should not outlive the operation.
+func WithFetchCache(ctx context.Context) context.Context {
+	if fetchCacheOf(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, fetchCacheKey{}, &fetchCache{
+		configMaps: make(map[string]fetchedConfigMap),
+		secrets:    make(map[string]fetchedSecret),
+	})
+}
+
+// fetchConfigMap reads a ConfigMap through the fetchCache of the resolution, if any
+func (r *Resolver) fetchConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	cache := fetchCacheOf(ctx)
+	if cache == nil {
+		return r.readConfigMap(ctx, namespace, name)
+	}
+	key := namespace + "/" + name
+	cache.mu.Lock()
+	fetched, ok := cache.configMaps[key]
+	cache.mu.Unlock()
+	if ok {
+		return fetched.cm, fetched.err
+	}
+
+	cm, err := r.readConfigMap(ctx, namespace, name)
+	cache.mu.Lock()
+	cache.configMaps[key] = fetchedConfigMap{cm: cm, err: err}
+	cache.mu.Unlock()
+	return cm, err
+}
+
+// fetchSecret reads a Secret through the fetchCache of the resolution, if any
+func (r *Resolver) fetchSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	cache := fetchCacheOf(ctx)
+	if cache == nil {
+		return r.readSecret(ctx, namespace, name)
+	}
+	key := namespace + "/" + name
+	cache.mu.Lock()
+	fetched, ok := cache.secrets[key]
+	cache.mu.Unlock()
+	if ok {
+		return fetched.secret, fetched.err
+	}
+
+	secret, err := r.readSecret(ctx, namespace, name)
+	cache.mu.Lock()
+	cache.secrets[key] = fetchedSecret{secret: secret, err: err}
+	cache.mu.Unlock()
+	return secret, err
+}
+
+// prefetch reads every ConfigMap and Secret a pod spec references, at most
+// fetchWorkers at a time, into the fetchCache of the returned context. The
+// resolution that follows reads them from the cache in its usual order.
+func (r *Resolver) prefetch(ctx context.Context, namespace string, podSpec *corev1.PodSpec) context.Context {
+	ctx = WithFetchCache(ctx)
+	overlay := overlayOf(ctx)
+
+	configMaps := make(map[string]bool)
+	secrets := make(map[string]bool)
+	containers := append(append([]corev1.Container{}, podSpec.Containers...), podSpec.InitContainers...)
+	for _, c := range containers {
+		for _, envFrom := range c.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				configMaps[envFrom.ConfigMapRef.Name] = true
+			}
+			if envFrom.SecretRef != nil {
+				secrets[envFrom.SecretRef.Name] = true
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				configMaps[ref.Name] = true
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				secrets[ref.Name] = true
+			}
+		}
+	}
+
+	sem := make(chan struct{}, fetchWorkers)
+	var wg sync.WaitGroup
+	run := func(fetch func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fetch()
+		}()
+	}
+	for name := range configMaps {
+		if overlay == nil || overlay.ConfigMaps[name] == nil {
+			run(func() { _, _ = r.fetchConfigMap(ctx, namespace, name) })
+		}
+	}
+	for name := range secrets {
+		if overlay == nil || overlay.Secrets[name] == nil {
+			run(func() { _, _ = r.fetchSecret(ctx, namespace, name) })
+		}
+	}
+	wg.Wait()
+	return ctx
+}
@@ -0,0 +1,38 @@
+package env
+
+import (
+	"context"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Overlay holds ConfigMaps and Secrets by name that are read in place of the
+// cluster ones, such as the objects of a rendered chart. Names missing from
+// the overlay are still read from the cluster.
+type Overlay struct {
+	ConfigMaps map[string]*corev1.ConfigMap
+	Secrets    map[string]*corev1.Secret
+}
+
+// overlayKey is the context key of the Overlay of a resolution
+type overlayKey struct{}
+
+// overlayOf returns the Overlay of a resolution, if any
+func overlayOf(ctx context.Context) *Overlay {
+	overlay, _ := ctx.Value(overlayKey{}).(*Overlay)
+	return overlay
+}
+
+// ResolveTemplateEnvVars resolves the env of a pod template that is not (yet) in
+// the cluster, reading its ConfigMaps and Secrets from the overlay first.
+// Sidecar containers are left out as in ResolveAppDiffEnvVars, so that the
+// result compares with the env of the live app.
+func (r *Resolver) ResolveTemplateEnvVars(ctx context.Context, namespace string, template *corev1.PodTemplateSpec, overlay *Overlay) []k8s.EnvVar {
+	ctx = context.WithValue(ctx, overlayKey{}, overlay)
+	containerEnv := r.resolveContainers(ctx, namespace, &template.Spec, templateFields(namespace, template))
+	if !r.includeSidecars {
+		containerEnv = WithoutSidecars(containerEnv)
+	}
+	return MergeContainers(containerEnv)
+}
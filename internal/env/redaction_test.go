@@ -0,0 +1,66 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+func TestApplyRedaction(t *testing.T) {
+	cfg := &config.Config{Redact: []string{"*_TOKEN", "DATABASE_URL", "API_*"}}
+	resolver := &Resolver{}
+	resolver.SetRedactRule(cfg.IsRedacted)
+
+	secretHash := k8s.HashValue([]byte("hunter2"))
+	tests := []struct {
+		name       string
+		ev         k8s.EnvVar
+		wantMasked bool
+		wantRedact bool // masked by the rule, not by being a secret
+	}{
+		{"suffix pattern", k8s.EnvVar{Name: "GITHUB_TOKEN", Value: "ghp_x", SourceKind: k8s.EnvSourceConfigMap}, true, true},
+		{"exact pattern", k8s.EnvVar{Name: "DATABASE_URL", Value: "postgres://u:p@db/app", SourceKind: k8s.EnvSourceInline}, true, true},
+		{"prefix pattern", k8s.EnvVar{Name: "API_KEY", Value: "k", SourceKind: k8s.EnvSourceConfigMap}, true, true},
+		{"no match", k8s.EnvVar{Name: "LOG_LEVEL", Value: "debug", SourceKind: k8s.EnvSourceConfigMap}, false, false},
+		{"patterns are case sensitive", k8s.EnvVar{Name: "github_token", Value: "x", SourceKind: k8s.EnvSourceConfigMap}, false, false},
+		{"pattern must match the whole name", k8s.EnvVar{Name: "DATABASE_URL_RO", Value: "x", SourceKind: k8s.EnvSourceConfigMap}, false, false},
+		{"field ref", k8s.EnvVar{Name: "POD_TOKEN", Value: "api-0", SourceKind: k8s.EnvSourceFieldRef}, true, true},
+		{"secret without a match", k8s.EnvVar{Name: "PASSWORD", Value: "HASH: " + secretHash, Hash: secretHash, SourceKind: k8s.EnvSourceSecret}, true, false},
+		{"secret with a match", k8s.EnvVar{Name: "DB_TOKEN", Value: "HASH: " + secretHash, Hash: secretHash, SourceKind: k8s.EnvSourceSecret}, true, false},
+		{"sealed secret", k8s.EnvVar{Name: "PASSWORD", Value: "HASH: " + secretHash, Hash: secretHash, SourceKind: k8s.EnvSourceSealedSecret, IsSealed: true}, true, false},
+		{"forbidden source", forbiddenVar("DB_TOKEN", k8s.EnvSourceSecret, "db"), false, false},
+	}
+
+	for _, tt := range tests {
+		envVars := []k8s.EnvVar{tt.ev}
+		resolver.applyRedaction(envVars)
+		got := envVars[0]
+
+		if got.IsMasked() != tt.wantMasked {
+			t.Errorf("%s: IsMasked() = %v, want %v", tt.name, got.IsMasked(), tt.wantMasked)
+		}
+		if got.Redacted != tt.wantRedact {
+			t.Errorf("%s: Redacted = %v, want %v", tt.name, got.Redacted, tt.wantRedact)
+		}
+		switch {
+		case tt.wantRedact:
+			// The clear value is kept for an explicit reveal only
+			if got.Value == tt.ev.Value || got.Hash != k8s.HashValue([]byte(tt.ev.Value)) || string(got.RawValue) != tt.ev.Value {
+				t.Errorf("%s: got %+v, want the value hashed with the clear value in RawValue", tt.name, got)
+			}
+		case got.Value != tt.ev.Value || got.Hash != tt.ev.Hash:
+			// Secrets are already hashed and must not be hashed again
+			t.Errorf("%s: value changed to %q (hash %q)", tt.name, got.Value, got.Hash)
+		}
+	}
+}
+
+func TestApplyRedactionWithoutRule(t *testing.T) {
+	resolver := &Resolver{}
+	envVars := []k8s.EnvVar{{Name: "GITHUB_TOKEN", Value: "ghp_x", SourceKind: k8s.EnvSourceConfigMap}}
+	resolver.applyRedaction(envVars)
+	if envVars[0].IsMasked() || envVars[0].Value != "ghp_x" {
+		t.Errorf("masked without a redaction rule: %+v", envVars[0])
+	}
+}
@@ -4,14 +4,25 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/ginbear/k8s-envtop/internal/k8s"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Annotations marking a Secret as produced by the sealed-secrets controller
+const (
+	sealedOwnedAnnotation   = "sealedsecrets.bitnami.com/owned"
+	sealedManagedAnnotation = "sealedsecrets.bitnami.com/managed"
 )
 
 // Resolver resolves environment variables from Kubernetes workloads
 type Resolver struct {
-	client *k8s.Client
+	client          *k8s.Client
+	redact          func(name string) bool
+	includeSidecars bool         // compare sidecar variables in app-vs-app diffs
+	cache           *objectCache // nil unless enabled with SetCacheTTL
 }
 
 // NewResolver creates a new env resolver
@@ -19,74 +30,174 @@ func NewResolver(client *k8s.Client) *Resolver {
 	return &Resolver{client: client}
 }
 
+// SetRedactRule sets the rule deciding which non-secret variables are masked like secrets
+func (r *Resolver) SetRedactRule(redact func(name string) bool) {
+	r.redact = redact
+}
+
+// applyRedaction masks the values of non-secret variables matching the redaction rule.
+// The clear value is kept in RawValue so it can still be revealed explicitly.
+func (r *Resolver) applyRedaction(envVars []k8s.EnvVar) {
+	if r.redact == nil {
+		return
+	}
+	for i := range envVars {
+		ev := &envVars[i]
+		if ev.IsSecret() || ev.Forbidden || !r.redact(ev.Name) {
+			continue
+		}
+		ev.RawValue = []byte(ev.Value)
+		ev.Hash = k8s.HashValue(ev.RawValue)
+		ev.Value = fmt.Sprintf("HASH: %s", ev.Hash)
+		ev.Redacted = true
+	}
+}
+
 // ResolveAppEnvVars resolves all environment variables for a given app
 func (r *Resolver) ResolveAppEnvVars(ctx context.Context, app k8s.App) ([]k8s.EnvVar, error) {
-	var podSpec *corev1.PodSpec
+	template, err := r.appPodTemplate(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	return r.resolveFromPodSpec(ctx, app.Namespace, &template.Spec, templateFields(app.Namespace, template))
+}
+
+// appPodSpec returns the pod template spec of an app
+func (r *Resolver) appPodSpec(ctx context.Context, app k8s.App) (*corev1.PodSpec, error) {
+	template, err := r.appPodTemplate(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	return &template.Spec, nil
+}
 
+// appPodTemplate returns the pod template of an app
+func (r *Resolver) appPodTemplate(ctx context.Context, app k8s.App) (*corev1.PodTemplateSpec, error) {
 	switch app.Kind {
 	case k8s.AppKindDeployment:
 		deployment, err := r.client.GetDeployment(ctx, app.Namespace, app.Name)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get deployment %s: %w", app.Name, err)
 		}
-		podSpec = &deployment.Spec.Template.Spec
+		recordVersion(ctx, string(app.Kind), app.Name, deployment.ResourceVersion)
+		return &deployment.Spec.Template, nil
 	case k8s.AppKindStatefulSet:
 		statefulset, err := r.client.GetStatefulSet(ctx, app.Namespace, app.Name)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get statefulset %s: %w", app.Name, err)
 		}
-		podSpec = &statefulset.Spec.Template.Spec
+		recordVersion(ctx, string(app.Kind), app.Name, statefulset.ResourceVersion)
+		return &statefulset.Spec.Template, nil
+	case k8s.AppKindCronJob:
+		cronjob, err := r.client.GetCronJob(ctx, app.Namespace, app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cronjob %s: %w", app.Name, err)
+		}
+		recordVersion(ctx, string(app.Kind), app.Name, cronjob.ResourceVersion)
+		return &cronjob.Spec.JobTemplate.Spec.Template, nil
+	case k8s.AppKindJob:
+		job, err := r.client.GetJob(ctx, app.Namespace, app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job %s: %w", app.Name, err)
+		}
+		recordVersion(ctx, string(app.Kind), app.Name, job.ResourceVersion)
+		return &job.Spec.Template, nil
 	default:
 		return nil, fmt.Errorf("unsupported app kind: %s", app.Kind)
 	}
+}
 
-	return r.resolveFromPodSpec(ctx, app.Namespace, podSpec)
+// ResolveAppContainerEnvVars resolves the environment variables of every container of an
+// app separately; a variable defined by several containers appears once per container
+func (r *Resolver) ResolveAppContainerEnvVars(ctx context.Context, app k8s.App) ([]k8s.EnvVar, error) {
+	template, err := r.appPodTemplate(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	return r.resolveContainers(ctx, app.Namespace, &template.Spec, templateFields(app.Namespace, template)), nil
 }
 
-// resolveFromPodSpec extracts env vars from a PodSpec
-func (r *Resolver) resolveFromPodSpec(ctx context.Context, namespace string, podSpec *corev1.PodSpec) ([]k8s.EnvVar, error) {
-	envVars := make([]k8s.EnvVar, 0)
-	seen := make(map[string]bool)
+// ResolvePodContainerEnvVars is ResolveAppContainerEnvVars for a running pod
+func (r *Resolver) ResolvePodContainerEnvVars(ctx context.Context, namespace, podName string) ([]k8s.EnvVar, error) {
+	pod, err := r.client.GetPod(ctx, namespace, podName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
+	}
+	return r.resolveContainers(ctx, namespace, &pod.Spec, livePodFields(pod)), nil
+}
 
-	// Process all containers (including init containers)
-	allContainers := append(podSpec.Containers, podSpec.InitContainers...)
+// ResolvePodEnvVars resolves all environment variables from a running pod's spec
+func (r *Resolver) ResolvePodEnvVars(ctx context.Context, namespace, podName string) ([]k8s.EnvVar, error) {
+	pod, err := r.client.GetPod(ctx, namespace, podName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
+	}
+	return r.resolveFromPodSpec(ctx, namespace, &pod.Spec, livePodFields(pod))
+}
+
+// resolveFromPodSpec extracts env vars from a PodSpec, merged across containers
+func (r *Resolver) resolveFromPodSpec(ctx context.Context, namespace string, podSpec *corev1.PodSpec, fields podFields) ([]k8s.EnvVar, error) {
+	return MergeContainers(r.resolveContainers(ctx, namespace, podSpec, fields)), nil
+}
+
+// resolveContainers resolves the env of every container and init container separately.
+// Within a container, env entries override envFrom and later envFrom sources override
+// earlier ones, as in the kubelet. The result is ordered by container, then by name.
+// fieldRefs are resolved from fields where their value is known.
+func (r *Resolver) resolveContainers(ctx context.Context, namespace string, podSpec *corev1.PodSpec, fields podFields) []k8s.EnvVar {
+	ctx = r.prefetch(ctx, namespace, podSpec)
+	containers := make([]corev1.Container, 0, len(podSpec.Containers)+len(podSpec.InitContainers))
+	containers = append(containers, podSpec.Containers...)
+	containers = append(containers, podSpec.InitContainers...)
+
+	envVars := make([]k8s.EnvVar, 0)
+	for _, container := range containers {
+		missingFrom := missingCount(ctx)
+		vars := make([]k8s.EnvVar, 0)
+		index := make(map[string]int)
+		set := func(v k8s.EnvVar) {
+			v.Container = container.Name
+			key := entryKey(v)
+			if i, ok := index[key]; ok {
+				vars[i] = v
+				return
+			}
+			index[key] = len(vars)
+			vars = append(vars, v)
+		}
 
-	for _, container := range allContainers {
 		// Process envFrom first
 		for _, envFrom := range container.EnvFrom {
-			vars, err := r.resolveEnvFrom(ctx, namespace, envFrom)
+			resolved, err := r.resolveEnvFrom(ctx, namespace, envFrom)
 			if err != nil {
 				// Log error but continue
 				continue
 			}
-			for _, v := range vars {
-				if !seen[v.Name] {
-					seen[v.Name] = true
-					envVars = append(envVars, v)
-				}
+			for _, v := range resolved {
+				set(v)
 			}
 		}
 
 		// Process env
+		fields.container = container.Name
 		for _, env := range container.Env {
-			v, err := r.resolveEnvVar(ctx, namespace, env)
+			v, err := r.resolveEnvVar(ctx, namespace, env, fields)
 			if err != nil {
 				// Log error but continue
 				continue
 			}
-			if !seen[v.Name] {
-				seen[v.Name] = true
-				envVars = append(envVars, v)
-			}
+			set(v)
 		}
-	}
 
-	// Sort by name for consistent display
-	sort.Slice(envVars, func(i, j int) bool {
-		return envVars[i].Name < envVars[j].Name
-	})
+		sort.Slice(vars, func(i, j int) bool {
+			return vars[i].Name < vars[j].Name
+		})
+		envVars = append(envVars, vars...)
+		setMissingContainer(ctx, missingFrom, container.Name)
+	}
 
-	return envVars, nil
+	r.applyRedaction(envVars)
+	return envVars
 }
 
 // resolveEnvFrom resolves environment variables from envFrom sources
@@ -95,10 +206,14 @@ func (r *Resolver) resolveEnvFrom(ctx context.Context, namespace string, envFrom
 	vars := make([]k8s.EnvVar, 0)
 
 	if envFrom.ConfigMapRef != nil {
-		cm, err := r.client.GetConfigMap(ctx, namespace, envFrom.ConfigMapRef.Name)
+		cm, err := r.getConfigMap(ctx, namespace, envFrom.ConfigMapRef.Name)
+		if apierrors.IsForbidden(err) {
+			return []k8s.EnvVar{forbiddenVar(forbiddenFromName(prefix), k8s.EnvSourceConfigMap, envFrom.ConfigMapRef.Name)}, nil
+		}
 		if err != nil {
 			// Check if optional
 			if envFrom.ConfigMapRef.Optional != nil && *envFrom.ConfigMapRef.Optional {
+				recordMissing(ctx, MissingOptional{Kind: k8s.EnvSourceConfigMap, Name: envFrom.ConfigMapRef.Name})
 				return vars, nil
 			}
 			return nil, err
@@ -106,27 +221,32 @@ func (r *Resolver) resolveEnvFrom(ctx context.Context, namespace string, envFrom
 
 		for key, value := range cm.Data {
 			vars = append(vars, k8s.EnvVar{
-				Name:       prefix + key,
-				Value:      value,
-				SourceName: cm.Name,
-				SourceKind: k8s.EnvSourceConfigMap,
-				ValueLen:   len(value),
+				Name:        prefix + key,
+				Value:       value,
+				SourceName:  cm.Name,
+				SourceKind:  k8s.EnvSourceConfigMap,
+				ValueLen:    len(value),
+				Terminating: cm.DeletionTimestamp != nil,
 			})
 		}
 	}
 
 	if envFrom.SecretRef != nil {
-		secret, err := r.client.GetSecret(ctx, namespace, envFrom.SecretRef.Name)
+		secret, err := r.getSecret(ctx, namespace, envFrom.SecretRef.Name)
+		if apierrors.IsForbidden(err) {
+			return []k8s.EnvVar{forbiddenVar(forbiddenFromName(prefix), k8s.EnvSourceSecret, envFrom.SecretRef.Name)}, nil
+		}
 		if err != nil {
 			// Check if optional
 			if envFrom.SecretRef.Optional != nil && *envFrom.SecretRef.Optional {
+				recordMissing(ctx, MissingOptional{Kind: k8s.EnvSourceSecret, Name: envFrom.SecretRef.Name})
 				return vars, nil
 			}
 			return nil, err
 		}
 
 		// Check if this is a SealedSecret by looking for owner reference
-		isSealed := r.isSealedSecret(ctx, namespace, secret.Name)
+		isSealed := isSealedSecret(secret)
 
 		for key, value := range secret.Data {
 			sourceKind := k8s.EnvSourceSecret
@@ -134,14 +254,15 @@ func (r *Resolver) resolveEnvFrom(ctx context.Context, namespace string, envFrom
 				sourceKind = k8s.EnvSourceSealedSecret
 			}
 			vars = append(vars, k8s.EnvVar{
-				Name:       prefix + key,
-				RawValue:   value,
-				Value:      fmt.Sprintf("HASH: %s", k8s.HashValue(value)),
-				SourceName: secret.Name,
-				SourceKind: sourceKind,
-				IsSealed:   isSealed,
-				ValueLen:   len(value),
-				Hash:       k8s.HashValue(value),
+				Name:        prefix + key,
+				RawValue:    value,
+				Value:       fmt.Sprintf("HASH: %s", k8s.HashValue(value)),
+				SourceName:  secret.Name,
+				SourceKind:  sourceKind,
+				IsSealed:    isSealed,
+				ValueLen:    len(value),
+				Hash:        k8s.HashValue(value),
+				Terminating: secret.DeletionTimestamp != nil,
 			})
 		}
 	}
@@ -150,7 +271,7 @@ func (r *Resolver) resolveEnvFrom(ctx context.Context, namespace string, envFrom
 }
 
 // resolveEnvVar resolves a single environment variable
-func (r *Resolver) resolveEnvVar(ctx context.Context, namespace string, env corev1.EnvVar) (k8s.EnvVar, error) {
+func (r *Resolver) resolveEnvVar(ctx context.Context, namespace string, env corev1.EnvVar, fields podFields) (k8s.EnvVar, error) {
 	// Inline value
 	if env.Value != "" {
 		return k8s.EnvVar{
@@ -172,9 +293,13 @@ func (r *Resolver) resolveEnvVar(ctx context.Context, namespace string, env core
 	// ConfigMap key reference
 	if env.ValueFrom.ConfigMapKeyRef != nil {
 		ref := env.ValueFrom.ConfigMapKeyRef
-		cm, err := r.client.GetConfigMap(ctx, namespace, ref.Name)
+		cm, err := r.getConfigMap(ctx, namespace, ref.Name)
+		if apierrors.IsForbidden(err) {
+			return forbiddenVar(env.Name, k8s.EnvSourceConfigMap, ref.Name), nil
+		}
 		if err != nil {
 			if ref.Optional != nil && *ref.Optional {
+				recordMissing(ctx, MissingOptional{Kind: k8s.EnvSourceConfigMap, Name: ref.Name, Key: ref.Key, Var: env.Name})
 				return k8s.EnvVar{
 					Name:       env.Name,
 					Value:      "(optional, not found)",
@@ -185,22 +310,36 @@ func (r *Resolver) resolveEnvVar(ctx context.Context, namespace string, env core
 			return k8s.EnvVar{}, err
 		}
 
-		value := cm.Data[ref.Key]
+		value, ok := cm.Data[ref.Key]
+		if !ok && ref.Optional != nil && *ref.Optional {
+			recordMissing(ctx, MissingOptional{Kind: k8s.EnvSourceConfigMap, Name: ref.Name, Key: ref.Key, Var: env.Name})
+			return k8s.EnvVar{
+				Name:       env.Name,
+				Value:      "(optional, not found)",
+				SourceName: ref.Name,
+				SourceKind: k8s.EnvSourceConfigMap,
+			}, nil
+		}
 		return k8s.EnvVar{
-			Name:       env.Name,
-			Value:      value,
-			SourceName: cm.Name,
-			SourceKind: k8s.EnvSourceConfigMap,
-			ValueLen:   len(value),
+			Name:        env.Name,
+			Value:       value,
+			SourceName:  cm.Name,
+			SourceKind:  k8s.EnvSourceConfigMap,
+			ValueLen:    len(value),
+			Terminating: cm.DeletionTimestamp != nil,
 		}, nil
 	}
 
 	// Secret key reference
 	if env.ValueFrom.SecretKeyRef != nil {
 		ref := env.ValueFrom.SecretKeyRef
-		secret, err := r.client.GetSecret(ctx, namespace, ref.Name)
+		secret, err := r.getSecret(ctx, namespace, ref.Name)
+		if apierrors.IsForbidden(err) {
+			return forbiddenVar(env.Name, k8s.EnvSourceSecret, ref.Name), nil
+		}
 		if err != nil {
 			if ref.Optional != nil && *ref.Optional {
+				recordMissing(ctx, MissingOptional{Kind: k8s.EnvSourceSecret, Name: ref.Name, Key: ref.Key, Var: env.Name})
 				return k8s.EnvVar{
 					Name:       env.Name,
 					Value:      "(optional, not found)",
@@ -211,40 +350,78 @@ func (r *Resolver) resolveEnvVar(ctx context.Context, namespace string, env core
 			return k8s.EnvVar{}, err
 		}
 
-		value := secret.Data[ref.Key]
-		isSealed := r.isSealedSecret(ctx, namespace, secret.Name)
+		value, ok := secret.Data[ref.Key]
+		if !ok && ref.Optional != nil && *ref.Optional {
+			recordMissing(ctx, MissingOptional{Kind: k8s.EnvSourceSecret, Name: ref.Name, Key: ref.Key, Var: env.Name})
+			return k8s.EnvVar{
+				Name:       env.Name,
+				Value:      "(optional, not found)",
+				SourceName: ref.Name,
+				SourceKind: k8s.EnvSourceSecret,
+			}, nil
+		}
+		isSealed := isSealedSecret(secret)
 		sourceKind := k8s.EnvSourceSecret
 		if isSealed {
 			sourceKind = k8s.EnvSourceSealedSecret
 		}
 
 		return k8s.EnvVar{
-			Name:       env.Name,
-			RawValue:   value,
-			Value:      fmt.Sprintf("HASH: %s", k8s.HashValue(value)),
-			SourceName: secret.Name,
-			SourceKind: sourceKind,
-			IsSealed:   isSealed,
-			ValueLen:   len(value),
-			Hash:       k8s.HashValue(value),
+			Name:        env.Name,
+			RawValue:    value,
+			Value:       fmt.Sprintf("HASH: %s", k8s.HashValue(value)),
+			SourceName:  secret.Name,
+			SourceKind:  sourceKind,
+			IsSealed:    isSealed,
+			ValueLen:    len(value),
+			Hash:        k8s.HashValue(value),
+			Terminating: secret.DeletionTimestamp != nil,
 		}, nil
 	}
 
-	// Field reference (e.g., metadata.name)
+	// Field reference (e.g., metadata.name), resolved when the pod fields are known
 	if env.ValueFrom.FieldRef != nil {
+		path := env.ValueFrom.FieldRef.FieldPath
+		value, ok := fields.resolve(path)
+		if !ok {
+			return k8s.EnvVar{
+				Name:       env.Name,
+				Value:      fmt.Sprintf("fieldRef: %s", path),
+				SourceName: path,
+				SourceKind: k8s.EnvSourceFieldRef,
+				Unresolved: true,
+			}, nil
+		}
 		return k8s.EnvVar{
 			Name:       env.Name,
-			Value:      fmt.Sprintf("fieldRef: %s", env.ValueFrom.FieldRef.FieldPath),
+			Value:      value,
+			SourceName: path,
 			SourceKind: k8s.EnvSourceFieldRef,
+			ValueLen:   len(value),
 		}, nil
 	}
 
-	// Resource field reference (e.g., limits.cpu)
+	// Resource field reference (e.g., limits.cpu), computed from the container resources
 	if env.ValueFrom.ResourceFieldRef != nil {
+		ref := env.ValueFrom.ResourceFieldRef
+		value, detail, ok := fields.resolveResource(ref)
+		if !ok {
+			return k8s.EnvVar{
+				Name:       env.Name,
+				Value:      fmt.Sprintf("resourceFieldRef: %s", ref.Resource),
+				SourceName: ref.Resource,
+				SourceKind: k8s.EnvSourceResourceRef,
+				Unresolved: true,
+				Detail:     detail,
+			}, nil
+		}
 		return k8s.EnvVar{
 			Name:       env.Name,
-			Value:      fmt.Sprintf("resourceFieldRef: %s", env.ValueFrom.ResourceFieldRef.Resource),
+			Value:      value,
+			SourceName: ref.Resource,
 			SourceKind: k8s.EnvSourceResourceRef,
+			ValueLen:   len(value),
+			Detail:     detail,
 		}, nil
 	}
 
@@ -255,19 +432,24 @@ func (r *Resolver) resolveEnvVar(ctx context.Context, namespace string, env core
 	}, nil
 }
 
-// isSealedSecret checks if a secret is managed by SealedSecret controller
-func (r *Resolver) isSealedSecret(ctx context.Context, namespace, secretName string) bool {
-	// Try to get the corresponding SealedSecret
-	_, err := r.client.GetSealedSecret(ctx, namespace, secretName)
-	return err == nil
+// isSealedSecret checks if a secret is managed by the SealedSecret controller,
+// based on its owner references and controller annotations rather than on a
+// SealedSecret with the same name happening to exist
+func isSealedSecret(secret *corev1.Secret) bool {
+	for _, ref := range secret.OwnerReferences {
+		if ref.Kind == "SealedSecret" && strings.HasPrefix(ref.APIVersion, k8s.SealedSecretGVR.Group+"/") {
+			return true
+		}
+	}
+	return secret.Annotations[sealedOwnedAnnotation] == "true" || secret.Annotations[sealedManagedAnnotation] == "true"
 }
 
 // DiffResult represents a comparison result for a single env var
 type DiffResult struct {
-	Name      string
-	EnvA      *k8s.EnvVar // nil if only in B
-	EnvB      *k8s.EnvVar // nil if only in A
-	Status    DiffStatus
+	Name   string
+	EnvA   *k8s.EnvVar // nil if only in B
+	EnvB   *k8s.EnvVar // nil if only in A
+	Status DiffStatus
 }
 
 // DiffStatus represents the comparison status
@@ -278,19 +460,28 @@ const (
 	DiffStatusValueDiff DiffStatus = "VALUE_DIFF"
 	DiffStatusOnlyInA   DiffStatus = "ONLY_IN_A"
 	DiffStatusOnlyInB   DiffStatus = "ONLY_IN_B"
+	DiffStatusForbidden DiffStatus = "FORBIDDEN" // a side may not be read: unknown, not drift
 )
 
+// IsDrift returns true if the status is a difference between the two sides.
+// Forbidden entries are unknown: they are neither the same nor drift.
+func (s DiffStatus) IsDrift() bool {
+	return s != DiffStatusSame && s != DiffStatusForbidden
+}
+
 // CompareEnvVars compares two lists of env vars and returns the diff
 func CompareEnvVars(envsA, envsB []k8s.EnvVar) []DiffResult {
 	results := make([]DiffResult, 0)
 	mapA := make(map[string]*k8s.EnvVar)
 	mapB := make(map[string]*k8s.EnvVar)
 
+	// Keyed like MergeContainers, so the forbidden envFrom placeholders of
+	// different sources stay apart
 	for i := range envsA {
-		mapA[envsA[i].Name] = &envsA[i]
+		mapA[entryKey(envsA[i])] = &envsA[i]
 	}
 	for i := range envsB {
-		mapB[envsB[i].Name] = &envsB[i]
+		mapB[entryKey(envsB[i])] = &envsB[i]
 	}
 
 	// Collect all unique names
@@ -310,19 +501,26 @@ func CompareEnvVars(envsA, envsB []k8s.EnvVar) []DiffResult {
 	sort.Strings(names)
 
 	// Compare
-	for _, name := range names {
-		a, hasA := mapA[name]
-		b, hasB := mapB[name]
+	for _, key := range names {
+		a, hasA := mapA[key]
+		b, hasB := mapB[key]
 
-		result := DiffResult{Name: name, EnvA: a, EnvB: b}
+		result := DiffResult{Name: key, EnvA: a, EnvB: b}
 
 		switch {
+		case (hasA && a.Forbidden) || (hasB && b.Forbidden):
+			// The value of a side is unknown
+			result.Name = a.Name
+			if !hasA {
+				result.Name = b.Name
+			}
+			result.Status = DiffStatusForbidden
 		case !hasA:
 			result.Status = DiffStatusOnlyInB
 		case !hasB:
 			result.Status = DiffStatusOnlyInA
-		case a.IsSecret() || b.IsSecret():
-			// Compare by hash for secrets
+		case a.IsMasked() || b.IsMasked():
+			// Compare by hash for secrets and redacted values
 			if a.Hash == b.Hash {
 				result.Status = DiffStatusSame
 			} else {
@@ -339,3 +537,116 @@ func CompareEnvVars(envsA, envsB []k8s.EnvVar) []DiffResult {
 
 	return results
 }
+
+// CompareConfigMaps compares every key of two ConfigMaps and returns the diff.
+// A ConfigMap that does not exist is treated as empty.
+func (r *Resolver) CompareConfigMaps(ctx context.Context, nsA, nameA, nsB, nameB string) ([]DiffResult, error) {
+	return CompareConfigMapsAcross(ctx, r, nsA, nameA, r, nsB, nameB)
+}
+
+// CompareConfigMapsAcross is CompareConfigMaps for ConfigMaps read through two
+// resolvers, e.g. in the same namespace of two clusters
+func CompareConfigMapsAcross(ctx context.Context, rA *Resolver, nsA, nameA string, rB *Resolver, nsB, nameB string) ([]DiffResult, error) {
+	envsA, err := rA.configMapKeys(ctx, nsA, nameA)
+	if err != nil {
+		return nil, err
+	}
+
+	envsB, err := rB.configMapKeys(ctx, nsB, nameB)
+	if err != nil {
+		return nil, err
+	}
+
+	return CompareEnvVars(envsA, envsB), nil
+}
+
+// configMapKeys returns all keys of a ConfigMap as env vars
+func (r *Resolver) configMapKeys(ctx context.Context, namespace, name string) ([]k8s.EnvVar, error) {
+	cm, err := r.client.GetConfigMap(ctx, namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get configmap %s: %w", name, err)
+	}
+
+	vars := make([]k8s.EnvVar, 0, len(cm.Data))
+	for key, value := range cm.Data {
+		vars = append(vars, k8s.EnvVar{
+			Name:       key,
+			Value:      value,
+			SourceName: cm.Name,
+			SourceKind: k8s.EnvSourceConfigMap,
+			ValueLen:   len(value),
+		})
+	}
+	r.applyRedaction(vars)
+	return vars, nil
+}
+
+// AppDiff is the diff of a single app shared by two namespaces
+type AppDiff struct {
+	App     k8s.App
+	Results []DiffResult
+	Err     error // resolution error for this app, if any
+}
+
+// CompareNamespaces diffs every app that exists (same name and kind) in both namespaces
+func (r *Resolver) CompareNamespaces(ctx context.Context, nsA, nsB string) ([]AppDiff, error) {
+	apps, err := r.SharedApps(ctx, nsA, nsB)
+	if err != nil {
+		return nil, err
+	}
+	ctx = WithFetchCache(ctx)
+	diffs := make([]AppDiff, 0, len(apps))
+	for _, app := range apps {
+		diffs = append(diffs, r.CompareApp(ctx, app, nsB))
+	}
+	return diffs, nil
+}
+
+// SharedApps returns the apps of namespace A that also exist (same name and
+// kind) in namespace B, sorted by name
+func (r *Resolver) SharedApps(ctx context.Context, nsA, nsB string) ([]k8s.App, error) {
+	appsA, err := r.client.ListApps(ctx, nsA)
+	if err != nil {
+		return nil, err
+	}
+	appsB, err := r.client.ListApps(ctx, nsB)
+	if err != nil {
+		return nil, err
+	}
+
+	inB := make(map[string]bool, len(appsB))
+	for _, app := range appsB {
+		inB[string(app.Kind)+"/"+app.Name] = true
+	}
+
+	shared := make([]k8s.App, 0)
+	for _, appA := range appsA {
+		if inB[string(appA.Kind)+"/"+appA.Name] {
+			shared = append(shared, appA)
+		}
+	}
+	sort.SliceStable(shared, func(i, j int) bool {
+		return shared[i].Name < shared[j].Name
+	})
+	return shared, nil
+}
+
+// CompareApp diffs an app against the app of the same name and kind in namespace nsB
+func (r *Resolver) CompareApp(ctx context.Context, appA k8s.App, nsB string) AppDiff {
+	appB := k8s.App{Name: appA.Name, Namespace: nsB, Kind: appA.Kind}
+
+	diff := AppDiff{App: appA}
+	envsA, err := r.ResolveAppDiffEnvVars(ctx, appA)
+	if err == nil {
+		var envsB []k8s.EnvVar
+		envsB, err = r.ResolveAppDiffEnvVars(ctx, appB)
+		if err == nil {
+			diff.Results = CompareEnvVars(envsA, envsB)
+		}
+	}
+	diff.Err = err
+	return diff
+}
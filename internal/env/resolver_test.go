@@ -0,0 +1,157 @@
+package env
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// forbiddenClient returns a client of a Deployment "api" in "dev" reading the
+// ConfigMap "flags" and the Secrets "db" and "keys", where every get of a
+// Secret is denied with a 403
+func forbiddenClient(t *testing.T) (*k8s.Client, *fake.Clientset) {
+	t.Helper()
+	secretRef := func(name string) corev1.EnvFromSource {
+		return corev1.EnvFromSource{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}}}
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "dev"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: "api",
+						EnvFrom: []corev1.EnvFromSource{
+							{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "flags"}}},
+							secretRef("db"),
+							secretRef("keys"),
+						},
+						Env: []corev1.EnvVar{{
+							Name: "PASSWORD",
+							ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "db"},
+								Key:                  "password",
+							}},
+						}},
+					}},
+				},
+			},
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "flags", Namespace: "dev"},
+		Data:       map[string]string{"FOO": "bar"},
+	}
+
+	clientset := fake.NewClientset(deployment, configMap)
+	clientset.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		name := action.(k8stesting.GetAction).GetName()
+		return true, nil, apierrors.NewForbidden(corev1.Resource("secrets"), name, nil)
+	})
+	return k8s.NewClientForClientset("test", clientset), clientset
+}
+
+func TestResolveForbiddenSources(t *testing.T) {
+	client, clientset := forbiddenClient(t)
+	resolver := NewResolver(client)
+
+	envVars, err := resolver.ResolveAppEnvVars(context.Background(), k8s.App{Name: "api", Namespace: "dev", Kind: k8s.AppKindDeployment})
+	if err != nil {
+		t.Fatalf("ResolveAppEnvVars: %v", err)
+	}
+
+	forbidden := make(map[string]bool)
+	for _, ev := range envVars {
+		switch {
+		case ev.Name == "FOO":
+			if ev.Forbidden || ev.Value != "bar" {
+				t.Errorf("FOO = %+v, want the readable value bar", ev)
+			}
+		case ev.Forbidden:
+			if ev.Value != ForbiddenValue {
+				t.Errorf("%s: value %q, want %q", ev.Name, ev.Value, ForbiddenValue)
+			}
+			if ev.IsMasked() {
+				t.Errorf("%s: a forbidden variable must not be masked", ev.Name)
+			}
+			forbidden[ev.Name+" "+ev.SourceName] = true
+		default:
+			t.Errorf("unexpected variable %+v", ev)
+		}
+	}
+	for _, want := range []string{"PASSWORD db", "* db", "* keys"} {
+		if !forbidden[want] {
+			t.Errorf("missing forbidden entry %q, got %v", want, forbidden)
+		}
+	}
+
+	// The 403 comes from the GETs themselves, no access review is made
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "create" {
+			t.Errorf("unexpected %s of %s", action.GetVerb(), action.GetResource().Resource)
+		}
+	}
+}
+
+func TestCompareForbidden(t *testing.T) {
+	client, _ := forbiddenClient(t)
+	resolver := NewResolver(client)
+	app := k8s.App{Name: "api", Namespace: "dev", Kind: k8s.AppKindDeployment}
+
+	envsA, err := resolver.ResolveAppEnvVars(context.Background(), app)
+	if err != nil {
+		t.Fatalf("ResolveAppEnvVars: %v", err)
+	}
+	envsB, err := resolver.ResolveAppEnvVars(context.Background(), app)
+	if err != nil {
+		t.Fatalf("ResolveAppEnvVars: %v", err)
+	}
+
+	results := CompareEnvVars(envsA, envsB)
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want FOO, PASSWORD and one * per Secret: %+v", len(results), results)
+	}
+	placeholders := 0
+	for _, r := range results {
+		if r.Status.IsDrift() {
+			t.Errorf("%s: status %s is drift", r.Name, r.Status)
+		}
+		if r.Name == "FOO" {
+			if r.Status != DiffStatusSame {
+				t.Errorf("FOO: status %s, want %s", r.Status, DiffStatusSame)
+			}
+			continue
+		}
+		if r.Status != DiffStatusForbidden {
+			t.Errorf("%s: status %s, want %s", r.Name, r.Status, DiffStatusForbidden)
+		}
+		if r.Name == "*" {
+			placeholders++
+			if r.EnvA.SourceName != r.EnvB.SourceName {
+				t.Errorf("* of %s compared with * of %s", r.EnvA.SourceName, r.EnvB.SourceName)
+			}
+		}
+	}
+	if placeholders != 2 {
+		t.Errorf("got %d * entries, want one per Secret", placeholders)
+	}
+}
+
+func TestCompareForbiddenOneSide(t *testing.T) {
+	readable := k8s.EnvVar{Name: "PASSWORD", Value: "HASH: abc", Hash: "abc", SourceKind: k8s.EnvSourceSecret, SourceName: "db"}
+	results := CompareEnvVars(
+		[]k8s.EnvVar{forbiddenVar("PASSWORD", k8s.EnvSourceSecret, "db")},
+		[]k8s.EnvVar{readable},
+	)
+	if len(results) != 1 || results[0].Status != DiffStatusForbidden {
+		t.Fatalf("got %+v, want PASSWORD as %s", results, DiffStatusForbidden)
+	}
+}
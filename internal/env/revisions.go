@@ -0,0 +1,23 @@
+package env
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// CompareRevisions resolves the env of two rollouts of an app and compares them.
+// The templates are historical, but the ConfigMaps and Secrets they reference are
+// read as they are now: a changed value of the same key does not show up.
+func (r *Resolver) CompareRevisions(ctx context.Context, namespace string, older, newer k8s.Revision) ([]DiffResult, error) {
+	envsA, err := r.resolveFromPodSpec(ctx, namespace, &older.Template.Spec, templateFields(namespace, &older.Template))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve revision %d: %w", older.Number, err)
+	}
+	envsB, err := r.resolveFromPodSpec(ctx, namespace, &newer.Template.Spec, templateFields(namespace, &newer.Template))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve revision %d: %w", newer.Number, err)
+	}
+	return CompareEnvVars(envsA, envsB), nil
+}
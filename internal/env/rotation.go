@@ -0,0 +1,112 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SecretKeyConsumer is a variable of an app reading a Secret key
+type SecretKeyConsumer struct {
+	App       k8s.App
+	Container string
+	Variable  string
+}
+
+// SecretKeyUsage is a Secret key consumed as env, with what rotating it involves
+type SecretKeyUsage struct {
+	Secret     string
+	Key        string
+	Sealed     bool
+	Missing    bool      // the Secret does not exist
+	CreatedAt  time.Time // creation of the Secret
+	ModifiedAt time.Time // latest write recorded in managedFields
+	Consumers  []SecretKeyConsumer
+}
+
+// SecretKeyUsages lists every Secret key the given apps read as env, through
+// secretKeyRef or envFrom, sorted by Secret and key
+func (r *Resolver) SecretKeyUsages(ctx context.Context, apps []k8s.App) ([]SecretKeyUsage, error) {
+	secrets := make(map[string]*corev1.Secret) // nil for Secrets that cannot be read
+	getSecret := func(namespace, name string) *corev1.Secret {
+		id := namespace + "/" + name
+		if secret, ok := secrets[id]; ok {
+			return secret
+		}
+		secret, err := r.getSecret(ctx, namespace, name)
+		if err != nil {
+			secret = nil
+		}
+		secrets[id] = secret
+		return secret
+	}
+
+	usages := make(map[string]*SecretKeyUsage)
+	add := func(app k8s.App, container, secretName, key, variable string) {
+		id := app.Namespace + "/" + secretName + "/" + key
+		usage, ok := usages[id]
+		if !ok {
+			usage = &SecretKeyUsage{Secret: secretName, Key: key}
+			if secret := getSecret(app.Namespace, secretName); secret != nil {
+				usage.Sealed = isSealedSecret(secret)
+				usage.CreatedAt = secret.CreationTimestamp.Time
+				usage.ModifiedAt = lastModified(secret.ObjectMeta)
+			} else {
+				usage.Missing = true
+			}
+			usages[id] = usage
+		}
+		usage.Consumers = append(usage.Consumers, SecretKeyConsumer{App: app, Container: container, Variable: variable})
+	}
+
+	for _, app := range apps {
+		podSpec, err := r.appPodSpec(ctx, app)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", app.Name, err)
+		}
+		containers := append(append([]corev1.Container{}, podSpec.Containers...), podSpec.InitContainers...)
+		for _, container := range containers {
+			for _, envFrom := range container.EnvFrom {
+				if envFrom.SecretRef == nil {
+					continue
+				}
+				secret := getSecret(app.Namespace, envFrom.SecretRef.Name)
+				if secret == nil {
+					continue
+				}
+				for key := range secret.Data {
+					add(app, container.Name, secret.Name, key, envFrom.Prefix+key)
+				}
+			}
+			for _, env := range container.Env {
+				if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+					ref := env.ValueFrom.SecretKeyRef
+					add(app, container.Name, ref.Name, ref.Key, env.Name)
+				}
+			}
+		}
+	}
+
+	results := make([]SecretKeyUsage, 0, len(usages))
+	for _, usage := range usages {
+		sort.Slice(usage.Consumers, func(i, j int) bool {
+			a, b := usage.Consumers[i], usage.Consumers[j]
+			if a.App.Name != b.App.Name {
+				return a.App.Name < b.App.Name
+			}
+			return a.Variable < b.Variable
+		})
+		results = append(results, *usage)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Secret != results[j].Secret {
+			return results[i].Secret < results[j].Secret
+		}
+		return results[i].Key < results[j].Key
+	})
+	return results, nil
+}
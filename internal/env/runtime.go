@@ -0,0 +1,26 @@
+package env
+
+import "github.com/ginbear/k8s-envtop/internal/k8s"
+
+// RuntimeKey identifies a variable of a container in the result of RuntimeOnly
+func RuntimeKey(container, name string) string {
+	return container + "/" + name
+}
+
+// RuntimeOnly returns the variables of a live pod that its workload template does
+// not define, keyed by RuntimeKey. They were added after admission, usually by
+// mutating webhooks (Istio, Datadog, Vault Agent), including whole injected containers.
+func RuntimeOnly(template, pod []k8s.EnvVar) map[string]bool {
+	defined := make(map[string]bool, len(template))
+	for _, ev := range template {
+		defined[RuntimeKey(ev.Container, ev.Name)] = true
+	}
+
+	injected := make(map[string]bool)
+	for _, ev := range pod {
+		if key := RuntimeKey(ev.Container, ev.Name); !defined[key] {
+			injected[key] = true
+		}
+	}
+	return injected
+}
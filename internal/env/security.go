@@ -0,0 +1,79 @@
+package env
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ResolveAppSecurity resolves the pod security settings of an app
+func (r *Resolver) ResolveAppSecurity(ctx context.Context, app k8s.App) (*k8s.PodSecurity, error) {
+	podSpec, err := r.appPodSpec(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	return r.resolveSecurity(ctx, app.Namespace, podSpec), nil
+}
+
+// ResolvePodSecurity resolves the security settings of a running pod
+func (r *Resolver) ResolvePodSecurity(ctx context.Context, namespace, podName string) (*k8s.PodSecurity, error) {
+	pod, err := r.client.GetPod(ctx, namespace, podName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
+	}
+	return r.resolveSecurity(ctx, namespace, &pod.Spec), nil
+}
+
+// resolveSecurity extracts security settings from a PodSpec. The effective
+// automountServiceAccountToken falls back to the ServiceAccount, then to the
+// Kubernetes default (true). When the ServiceAccount exists but cannot be read,
+// the source is AutomountSourceUnknown rather than presenting the default as fact.
+func (r *Resolver) resolveSecurity(ctx context.Context, namespace string, podSpec *corev1.PodSpec) *k8s.PodSecurity {
+	security := &k8s.PodSecurity{
+		ServiceAccountName: podSpec.ServiceAccountName,
+		AutomountToken:     true,
+		AutomountSource:    "default",
+	}
+	if security.ServiceAccountName == "" {
+		security.ServiceAccountName = "default"
+	}
+
+	if podSpec.AutomountServiceAccountToken != nil {
+		security.AutomountToken = *podSpec.AutomountServiceAccountToken
+		security.AutomountSource = "pod"
+	} else if sa, err := r.client.GetServiceAccount(ctx, namespace, security.ServiceAccountName); err != nil {
+		// A missing ServiceAccount sets nothing, but an unreadable one may
+		if !apierrors.IsNotFound(err) {
+			security.AutomountSource = k8s.AutomountSourceUnknown
+		}
+	} else if sa.AutomountServiceAccountToken != nil {
+		security.AutomountToken = *sa.AutomountServiceAccountToken
+		security.AutomountSource = "serviceaccount"
+	}
+
+	if psc := podSpec.SecurityContext; psc != nil {
+		security.RunAsNonRoot = psc.RunAsNonRoot
+		security.RunAsUser = psc.RunAsUser
+	}
+
+	for _, container := range podSpec.Containers {
+		cs := k8s.ContainerSecurity{Name: container.Name}
+		if sc := container.SecurityContext; sc != nil {
+			cs.Privileged = sc.Privileged != nil && *sc.Privileged
+			cs.AllowPrivilegeEscalation = sc.AllowPrivilegeEscalation
+			cs.ReadOnlyRootFilesystem = sc.ReadOnlyRootFilesystem != nil && *sc.ReadOnlyRootFilesystem
+			cs.RunAsNonRoot = sc.RunAsNonRoot
+			if sc.Capabilities != nil {
+				for _, capability := range sc.Capabilities.Add {
+					cs.AddedCapabilities = append(cs.AddedCapabilities, string(capability))
+				}
+			}
+		}
+		security.Containers = append(security.Containers, cs)
+	}
+
+	return security
+}
@@ -0,0 +1,53 @@
+package env
+
+import (
+	"context"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// sidecarContainers are the containers added by service mesh injectors. Their
+// proxy settings are not the app's own config.
+var sidecarContainers = map[string]bool{
+	"istio-proxy":               true,
+	"istio-init":                true,
+	"istio-validation":          true,
+	"linkerd-proxy":             true,
+	"linkerd-init":              true,
+	"linkerd-network-validator": true,
+}
+
+// IsSidecar returns true if the container is a known service mesh sidecar
+func IsSidecar(container string) bool {
+	return sidecarContainers[container]
+}
+
+// WithoutSidecars drops the variables of sidecar containers from per-container env
+func WithoutSidecars(containerEnv []k8s.EnvVar) []k8s.EnvVar {
+	own := make([]k8s.EnvVar, 0, len(containerEnv))
+	for _, ev := range containerEnv {
+		if !IsSidecar(ev.Container) {
+			own = append(own, ev)
+		}
+	}
+	return own
+}
+
+// SetIncludeSidecars makes app-vs-app diffs compare the variables of sidecar
+// containers too. They are left out by default.
+func (r *Resolver) SetIncludeSidecars(include bool) {
+	r.includeSidecars = include
+}
+
+// ResolveAppDiffEnvVars resolves the env compared by app-vs-app diffs: the
+// merged env of the app without the variables of sidecar containers
+func (r *Resolver) ResolveAppDiffEnvVars(ctx context.Context, app k8s.App) ([]k8s.EnvVar, error) {
+	containerEnv, err := r.ResolveAppContainerEnvVars(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	if !r.includeSidecars {
+		containerEnv = WithoutSidecars(containerEnv)
+	}
+	return MergeContainers(containerEnv), nil
+}
@@ -0,0 +1,58 @@
+package env
+
+import (
+	"fmt"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// MaxValueSize is the largest single NAME=VALUE string the Linux kernel passes
+// to a process (MAX_ARG_STRLEN); a longer one makes the exec of the container
+// fail with E2BIG
+const MaxValueSize = 128 * 1024
+
+// ContainerSize is the size of the environment a container is started with
+type ContainerSize struct {
+	Container string
+	Bytes     int // NAME=VALUE strings with their terminating NUL
+	Vars      int
+	Largest   string // variable with the longest NAME=VALUE string
+	// LargestBytes is the size of the Largest variable's NAME=VALUE string
+	LargestBytes int
+}
+
+// ContainerSizes returns the env size of every container, in the order of the
+// containers. The sizes are those of the resolved values, so variables only
+// known at runtime (fieldRefs) and forbidden sources count for their names only.
+func ContainerSizes(containerEnv []k8s.EnvVar) []ContainerSize {
+	var sizes []ContainerSize
+	index := make(map[string]int)
+	for _, ev := range containerEnv {
+		i, ok := index[ev.Container]
+		if !ok {
+			i = len(sizes)
+			index[ev.Container] = i
+			sizes = append(sizes, ContainerSize{Container: ev.Container})
+		}
+		n := len(ev.Name) + 1 + ev.ValueLen + 1
+		s := &sizes[i]
+		s.Bytes += n
+		s.Vars++
+		if n > s.LargestBytes {
+			s.Largest, s.LargestBytes = ev.Name, n
+		}
+	}
+	return sizes
+}
+
+// FormatSize formats a byte count in KiB or MiB
+func FormatSize(bytes int) string {
+	switch {
+	case bytes < 1024:
+		return fmt.Sprintf("%d B", bytes)
+	case bytes < 1024*1024:
+		return fmt.Sprintf("%.1f KiB", float64(bytes)/1024)
+	default:
+		return fmt.Sprintf("%.1f MiB", float64(bytes)/(1024*1024))
+	}
+}
@@ -0,0 +1,86 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SecretUsage lists the apps referencing a Secret
+type SecretUsage struct {
+	Name string
+	Apps []string
+}
+
+// ResolveSecretUsage counts how many of the given apps reference each Secret,
+// through env, envFrom or volumes. Results are sorted by usage, most used first.
+func (r *Resolver) ResolveSecretUsage(ctx context.Context, apps []k8s.App) ([]SecretUsage, error) {
+	usage := make(map[string][]string)
+
+	for _, app := range apps {
+		podSpec, err := r.appPodSpec(ctx, app)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", app.Name, err)
+		}
+		for _, name := range referencedSecrets(podSpec) {
+			usage[name] = append(usage[name], app.Name)
+		}
+	}
+
+	results := make([]SecretUsage, 0, len(usage))
+	for name, appNames := range usage {
+		sort.Strings(appNames)
+		results = append(results, SecretUsage{Name: name, Apps: appNames})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if len(results[i].Apps) != len(results[j].Apps) {
+			return len(results[i].Apps) > len(results[j].Apps)
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results, nil
+}
+
+// referencedSecrets returns the distinct Secret names referenced by a PodSpec
+func referencedSecrets(podSpec *corev1.PodSpec) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	allContainers := append(podSpec.Containers, podSpec.InitContainers...)
+	for _, container := range allContainers {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil {
+				add(envFrom.SecretRef.Name)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				add(env.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+	}
+
+	for _, volume := range podSpec.Volumes {
+		if volume.Secret != nil {
+			add(volume.Secret.SecretName)
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.Secret != nil {
+					add(source.Secret.Name)
+				}
+			}
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,187 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// EditKind tells whether a run of text is in both values or only in one of them
+type EditKind int
+
+const (
+	EditEqual  EditKind = iota
+	EditDelete          // only in the first value
+	EditInsert          // only in the second value
+)
+
+// Edit is a run of text of a value diff
+type Edit struct {
+	Kind EditKind
+	Text string
+}
+
+// LineEdit is a line of a value diff. Changed lines paired with their
+// counterpart carry a character-level diff in Chars.
+type LineEdit struct {
+	Kind  EditKind
+	Text  string
+	Chars []Edit // nil for equal lines and for unpaired changes
+}
+
+// maxDiffCells bounds the LCS table of a diff. Larger inputs (after trimming
+// the common prefix and suffix) are shown as a whole replacement.
+const maxDiffCells = 1 << 20
+
+// DiffChars compares two values character by character
+func DiffChars(a, b string) []Edit {
+	ra, rb := []rune(a), []rune(b)
+	var edits []Edit
+	for _, op := range diffSeq(ra, rb) {
+		var text string
+		if op.kind == EditInsert {
+			text = string(rb[op.start:op.end])
+		} else {
+			text = string(ra[op.start:op.end])
+		}
+		if n := len(edits); n > 0 && edits[n-1].Kind == op.kind {
+			edits[n-1].Text += text
+			continue
+		}
+		edits = append(edits, Edit{Kind: op.kind, Text: text})
+	}
+	return edits
+}
+
+// DiffLines compares two values line by line, with a character-level diff of
+// the changed lines. JSON values are pretty-printed first, so that a blob on
+// a single line is compared field by field.
+func DiffLines(a, b string) []LineEdit {
+	la := strings.Split(prettyJSON(a), "\n")
+	lb := strings.Split(prettyJSON(b), "\n")
+
+	var lines []LineEdit
+	var deleted, inserted []string
+	flush := func() {
+		// Pair the deleted and inserted lines of a change in order
+		for i := range deleted {
+			line := LineEdit{Kind: EditDelete, Text: deleted[i]}
+			if i < len(inserted) {
+				line.Chars = DiffChars(deleted[i], inserted[i])
+			}
+			lines = append(lines, line)
+		}
+		for i := range inserted {
+			line := LineEdit{Kind: EditInsert, Text: inserted[i]}
+			if i < len(deleted) {
+				line.Chars = DiffChars(deleted[i], inserted[i])
+			}
+			lines = append(lines, line)
+		}
+		deleted, inserted = nil, nil
+	}
+
+	for _, op := range diffSeq(la, lb) {
+		switch op.kind {
+		case EditEqual:
+			flush()
+			for _, text := range la[op.start:op.end] {
+				lines = append(lines, LineEdit{Kind: EditEqual, Text: text})
+			}
+		case EditDelete:
+			deleted = append(deleted, la[op.start:op.end]...)
+		case EditInsert:
+			inserted = append(inserted, lb[op.start:op.end]...)
+		}
+	}
+	flush()
+	return lines
+}
+
+// prettyJSON indents a JSON object or array, leaving other values as they are
+func prettyJSON(value string) string {
+	trimmed := strings.TrimSpace(value)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return value
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(trimmed), "", "  "); err != nil {
+		return value
+	}
+	return buf.String()
+}
+
+// seqOp is a run of a diff: [start, end) of the first sequence for equal and
+// deleted runs, of the second one for inserted runs
+type seqOp struct {
+	kind       EditKind
+	start, end int
+}
+
+// diffSeq computes a shortest edit script between two sequences through their
+// longest common subsequence
+func diffSeq[T comparable](a, b []T) []seqOp {
+	// Trim the common prefix and suffix: values usually differ in one place
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []seqOp
+	add := func(kind EditKind, start, end int) {
+		if start == end {
+			return
+		}
+		if n := len(ops); n > 0 && ops[n-1].kind == kind && ops[n-1].end == start {
+			ops[n-1].end = end
+			return
+		}
+		ops = append(ops, seqOp{kind: kind, start: start, end: end})
+	}
+
+	add(EditEqual, 0, prefix)
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	n, m := len(ma), len(mb)
+
+	if n*m > maxDiffCells {
+		add(EditDelete, prefix, prefix+n)
+		add(EditInsert, prefix, prefix+m)
+	} else {
+		// lcs[i][j] is the LCS length of ma[i:] and mb[j:]
+		lcs := make([][]int32, n+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, m+1)
+		}
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if ma[i] == mb[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < n || j < m {
+			switch {
+			case i < n && j < m && ma[i] == mb[j]:
+				add(EditEqual, prefix+i, prefix+i+1)
+				i++
+				j++
+			case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+				add(EditDelete, prefix+i, prefix+i+1)
+				i++
+			default:
+				add(EditInsert, prefix+j, prefix+j+1)
+				j++
+			}
+		}
+	}
+
+	add(EditEqual, len(a)-suffix, len(a))
+	return ops
+}
@@ -0,0 +1,89 @@
+package env
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// Annotations of the Vault agent injector
+// (https://developer.hashicorp.com/vault/docs/platform/k8s/injector/annotations)
+const (
+	vaultAnnotationPrefix  = "vault.hashicorp.com/"
+	vaultInject            = vaultAnnotationPrefix + "agent-inject"
+	vaultSecretPrefix      = vaultAnnotationPrefix + "agent-inject-secret-"
+	vaultTemplatePrefix    = vaultAnnotationPrefix + "agent-inject-template-"
+	vaultFilePrefix        = vaultAnnotationPrefix + "agent-inject-file-"
+	vaultVolumePath        = vaultAnnotationPrefix + "secret-volume-path"
+	vaultContainers        = vaultAnnotationPrefix + "agent-inject-containers"
+	vaultDefaultVolumePath = "/vault/secrets"
+	vaultVolume            = "vault-secrets"
+)
+
+// exportPattern matches the variable assignments of a template meant to be
+// sourced by the app, e.g. "export DB_PASSWORD={{ .Data.password }}"
+var exportPattern = regexp.MustCompile(`(?m)(?:^|\}\})\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)=`)
+
+// vaultFiles lists the secrets the Vault agent injector renders into a pod
+// annotated with agent-inject: one file per agent-inject-secret-<name>, in
+// the secret volume of each container the agent is injected into
+func vaultFiles(annotations map[string]string) []MountedFile {
+	if annotations[vaultInject] != "true" {
+		return nil
+	}
+
+	containers := []string{""}
+	if list := annotations[vaultContainers]; list != "" {
+		containers = nil
+		for _, c := range strings.Split(list, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				containers = append(containers, c)
+			}
+		}
+	}
+
+	var names []string
+	for k := range annotations {
+		if name, ok := strings.CutPrefix(k, vaultSecretPrefix); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var files []MountedFile
+	for _, name := range names {
+		dir := annotations[vaultVolumePath+"-"+name]
+		if dir == "" {
+			dir = annotations[vaultVolumePath]
+		}
+		if dir == "" {
+			dir = vaultDefaultVolumePath
+		}
+		file := annotations[vaultFilePrefix+name]
+		if file == "" {
+			file = name
+		}
+
+		f := MountedFile{
+			Path:   path.Join(dir, file),
+			Volume: vaultVolume,
+			Kind:   k8s.EnvSourceVault,
+			Source: annotations[vaultSecretPrefix+name],
+			Key:    name,
+		}
+		if template, ok := annotations[vaultTemplatePrefix+name]; ok {
+			f.Template = true
+			for _, match := range exportPattern.FindAllStringSubmatch(template, -1) {
+				f.Exports = append(f.Exports, match[1])
+			}
+		}
+		for _, container := range containers {
+			f.Container = container
+			files = append(files, f)
+		}
+	}
+	return files
+}
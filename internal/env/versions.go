@@ -0,0 +1,68 @@
+package env
+
+import (
+	"context"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Versions maps the objects an env was resolved from (see VersionKey) to their
+// resourceVersion. Objects that could not be read are recorded with an empty version,
+// so their later creation is noticed too.
+type Versions map[string]string
+
+// VersionKey returns the Versions key of an object, kind being an AppKind or a k8s.WatchKind
+func VersionKey(kind, name string) string {
+	return kind + "/" + name
+}
+
+// versionsKey is the context key of the Versions recorded during a resolution
+type versionsKey struct{}
+
+// recordVersion records the resourceVersion of an object read during a resolution, if any is recorded
+func recordVersion(ctx context.Context, kind, name, resourceVersion string) {
+	if versions, ok := ctx.Value(versionsKey{}).(Versions); ok {
+		versions[VersionKey(kind, name)] = resourceVersion
+	}
+}
+
+// ResolveAppContainerEnvVarsVersioned is ResolveAppContainerEnvVars that also returns the
+// resourceVersions of the workload and of every ConfigMap and Secret it read, so the
+// result can be cached until one of them changes
+func (r *Resolver) ResolveAppContainerEnvVarsVersioned(ctx context.Context, app k8s.App) ([]k8s.EnvVar, Versions, error) {
+	versions := make(Versions)
+	envVars, err := r.ResolveAppContainerEnvVars(context.WithValue(ctx, versionsKey{}, versions), app)
+	if err != nil {
+		return nil, nil, err
+	}
+	return envVars, versions, nil
+}
+
+// getConfigMap reads a ConfigMap, recording its resourceVersion
+func (r *Resolver) getConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	if overlay := overlayOf(ctx); overlay != nil && overlay.ConfigMaps[name] != nil {
+		return overlay.ConfigMaps[name], nil
+	}
+	cm, err := r.fetchConfigMap(ctx, namespace, name)
+	if err != nil {
+		recordVersion(ctx, k8s.WatchKindConfigMap, name, "")
+		return nil, err
+	}
+	recordVersion(ctx, k8s.WatchKindConfigMap, name, cm.ResourceVersion)
+	return cm, nil
+}
+
+// getSecret reads a Secret, recording its resourceVersion
+func (r *Resolver) getSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	if overlay := overlayOf(ctx); overlay != nil && overlay.Secrets[name] != nil {
+		return overlay.Secrets[name], nil
+	}
+	secret, err := r.fetchSecret(ctx, namespace, name)
+	if err != nil {
+		recordVersion(ctx, k8s.WatchKindSecret, name, "")
+		return nil, err
+	}
+	recordVersion(ctx, k8s.WatchKindSecret, name, secret.ResourceVersion)
+	return secret, nil
+}
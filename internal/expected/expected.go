@@ -0,0 +1,128 @@
+package expected
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"sigs.k8s.io/yaml"
+)
+
+// SourceKind marks env vars declared by an expected manifest
+const SourceKind k8s.EnvSourceKind = "Expected"
+
+// Manifest declares the env each app is expected to have, e.g. as exported by
+// an IaC pipeline. It is read from YAML or JSON.
+type Manifest struct {
+	Apps []App `json:"apps"`
+}
+
+// App is the expected env of one Deployment/StatefulSet
+type App struct {
+	Name      string         `json:"name"`
+	Kind      k8s.AppKind    `json:"kind,omitempty"`      // optional; matches any kind when empty
+	Namespace string         `json:"namespace,omitempty"` // optional; defaults to the namespace being checked
+	Env       map[string]Var `json:"env"`
+}
+
+// Var is an expected variable. In the manifest it is either a plain string
+// (the expected value) or an object with one of value, hash or present.
+type Var struct {
+	Value   *string `json:"value,omitempty"`
+	Hash    string  `json:"hash,omitempty"`    // SHA256 prefix as shown by envtop, for secrets
+	Present bool    `json:"present,omitempty"` // only the presence of the variable is managed
+}
+
+// UnmarshalJSON accepts a plain string as shorthand for {value: ...}
+func (v *Var) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v.Value = &s
+		return nil
+	}
+	type plain Var
+	return json.Unmarshal(data, (*plain)(v))
+}
+
+// Load reads an expected manifest from a file
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expected manifest %s: %w", path, err)
+	}
+
+	m := &Manifest{}
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse expected manifest %s: %w", path, err)
+	}
+	for i, app := range m.Apps {
+		if app.Name == "" {
+			return nil, fmt.Errorf("%s: apps[%d]: name is required", path, i)
+		}
+		for name, v := range app.Env {
+			if v.Value == nil && v.Hash == "" && !v.Present {
+				return nil, fmt.Errorf("%s: %s: %s: one of value, hash or present is required", path, app.Name, name)
+			}
+		}
+	}
+	return m, nil
+}
+
+// EnvVars returns the expected variables of the app in a form CompareEnvVars accepts
+func (a *App) EnvVars() []k8s.EnvVar {
+	vars := make([]k8s.EnvVar, 0, len(a.Env))
+	for name, v := range a.Env {
+		ev := k8s.EnvVar{Name: name, SourceKind: SourceKind, SourceName: "expected"}
+		switch {
+		case v.Value != nil:
+			ev.Value = *v.Value
+			ev.ValueLen = len(*v.Value)
+			ev.Hash = k8s.HashValue([]byte(*v.Value))
+		case v.Hash != "":
+			ev.Hash = v.Hash
+			ev.Value = "HASH: " + v.Hash
+			ev.Redacted = true
+		}
+		vars = append(vars, ev)
+	}
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i].Name < vars[j].Name
+	})
+	return vars
+}
+
+// Compare compares the expected env (A) with the live env (B).
+// ONLY_IN_B results are variables managed outside of the expected manifest.
+func (a *App) Compare(live []k8s.EnvVar) []env.DiffResult {
+	expected := a.EnvVars()
+
+	// Hash every live value so expected hashes can be compared with plain values
+	hashed := make([]k8s.EnvVar, len(live))
+	copy(hashed, live)
+	for i := range hashed {
+		if hashed[i].Hash == "" {
+			hashed[i].Hash = k8s.HashValue([]byte(hashed[i].Value))
+		}
+	}
+
+	results := env.CompareEnvVars(expected, hashed)
+	for i := range results {
+		r := &results[i]
+		if r.EnvA == nil || r.EnvB == nil {
+			continue
+		}
+		if v := a.Env[r.Name]; v.Value == nil && v.Hash == "" {
+			// present: true manages the name only
+			r.Status = env.DiffStatusSame
+		}
+		// Never print an expected value in clear when the live one is masked
+		if r.EnvB.IsMasked() && !r.EnvA.Redacted {
+			r.EnvA.Redacted = true
+			r.EnvA.Value = "HASH: " + r.EnvA.Hash
+		}
+	}
+	return results
+}
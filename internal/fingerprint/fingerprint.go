@@ -0,0 +1,85 @@
+// Package fingerprint renders the hash of a secret value in a form that people
+// can compare by reading it aloud, e.g. over a screen share
+package fingerprint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Style is a rendering of a hash
+type Style string
+
+const (
+	StyleHex   Style = "hex"   // the hash itself, e.g. 1a2b3c4d
+	StyleWords Style = "words" // four words, e.g. comet-lava-oak-quill
+	StyleEmoji Style = "emoji" // six emoji
+)
+
+// ParseStyle parses a style name as accepted by the config file ("" is hex)
+func ParseStyle(s string) (Style, error) {
+	switch Style(s) {
+	case "", StyleHex:
+		return StyleHex, nil
+	case StyleWords, StyleEmoji:
+		return Style(s), nil
+	}
+	return "", fmt.Errorf("unknown hash display: %s (want hex, words or emoji)", s)
+}
+
+// Format renders a hash as returned by k8s.HashValue (8 hex characters).
+// Every bit of the hash is kept, so two fingerprints match exactly when the
+// hashes do. Anything else is returned unchanged.
+func Format(style Style, hash string) string {
+	if style != StyleWords && style != StyleEmoji || len(hash) != 8 {
+		return hash
+	}
+	n, err := strconv.ParseUint(hash, 16, 32)
+	if err != nil {
+		return hash
+	}
+
+	if style == StyleWords {
+		parts := make([]string, 4)
+		for i := range parts {
+			parts[i] = words[byte(n>>(24-8*i))]
+		}
+		return strings.Join(parts, "-")
+	}
+
+	// 32 bits in base 64: the first emoji carries the 2 leftover bits
+	var b strings.Builder
+	for shift := 30; shift >= 0; shift -= 6 {
+		b.WriteString(emoji[(n>>shift)&63])
+	}
+	return b.String()
+}
+
+// words has one entry per byte value
+var words = [256]string{
+	"acid", "acorn", "actor", "adobe", "agent", "alarm", "album", "alley", "amber", "angel", "ankle", "apple", "april", "arena", "arrow", "atlas",
+	"attic", "audio", "aunt", "axis", "bacon", "badge", "bagel", "baker", "bamboo", "banjo", "barn", "basil", "beach", "beard", "bell", "bench",
+	"berry", "bike", "bird", "blade", "blaze", "bloom", "board", "boat", "bonus", "book", "boot", "brass", "bread", "brick", "bride", "broom",
+	"brush", "buddy", "bugle", "cabin", "cable", "cactus", "camel", "candy", "canoe", "canyon", "cargo", "castle", "cedar", "chalk", "charm", "cheese",
+	"cherry", "chess", "chief", "chili", "cider", "cigar", "circus", "clay", "cliff", "clock", "cloud", "clown", "coast", "cobra", "cocoa", "comet",
+	"coral", "corn", "cotton", "couch", "crab", "crane", "crater", "crown", "cube", "cupid", "curry", "daisy", "dancer", "delta", "denim", "desk",
+	"diesel", "dingo", "disco", "dock", "donkey", "dragon", "drum", "duck", "dune", "eagle", "easel", "echo", "elbow", "elk", "ember", "engine",
+	"falcon", "fern", "ferry", "fiddle", "finch", "flame", "flute", "fog", "forest", "fossil", "fox", "frog", "galaxy", "garlic", "gecko", "geyser",
+	"ghost", "giant", "ginger", "globe", "goat", "gold", "goose", "grape", "gravel", "guitar", "hammer", "harbor", "harp", "hawk", "hazel", "helmet",
+	"hero", "hippo", "honey", "horse", "hotel", "husky", "igloo", "iguana", "indigo", "iris", "island", "ivory", "jacket", "jaguar", "jam", "jelly",
+	"jewel", "jockey", "judge", "juice", "jungle", "kayak", "kettle", "kiwi", "koala", "lagoon", "lamp", "laser", "lava", "lemon", "lens", "lilac",
+	"lily", "lime", "lion", "lizard", "llama", "locket", "lotus", "magnet", "mango", "maple", "marble", "meadow", "melon", "meteor", "mint", "mirror",
+	"monkey", "moose", "motor", "mouse", "muffin", "mule", "nectar", "needle", "nest", "ninja", "noodle", "oak", "oasis", "ocean", "olive", "onion",
+	"opera", "orange", "orbit", "orchid", "otter", "owl", "oyster", "paddle", "panda", "parrot", "peach", "peanut", "pearl", "pebble", "pepper", "piano",
+	"pickle", "pigeon", "pilot", "pine", "pirate", "pizza", "planet", "plum", "pony", "poppy", "potato", "prism", "puma", "puzzle", "quail", "quartz",
+	"queen", "quill", "rabbit", "radar", "radio", "rain", "raven", "reef", "rhino", "ribbon", "river", "robin", "rocket", "rose", "ruby", "saddle",
+}
+
+// emoji has one entry per 6-bit value; all of them are two cells wide
+var emoji = [64]string{
+	"🐶", "🐱", "🐭", "🐹", "🐰", "🦊", "🐻", "🐼", "🐨", "🐯", "🦁", "🐮", "🐷", "🐸", "🐵", "🐔",
+	"🐧", "🐦", "🦆", "🦉", "🐴", "🦄", "🐝", "🐛", "🦋", "🐌", "🐞", "🐢", "🐍", "🐙", "🦀", "🐬",
+	"🐳", "🦈", "🍎", "🍐", "🍊", "🍋", "🍌", "🍉", "🍇", "🍓", "🍒", "🍑", "🍍", "🥝", "🍅", "🥕",
+	"🌽", "🍄", "🌵", "🌲", "🌻", "🌙", "⭐", "🔥", "🌈", "⛄", "🎈", "🎁", "🔔", "🚀", "🚗", "⚓",
+}
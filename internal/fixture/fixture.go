@@ -0,0 +1,219 @@
+// Package fixture records the Kubernetes API responses involved in an env
+// resolution into a sanitized bundle, and replays a bundle as a fake API server
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"k8s.io/client-go/rest"
+)
+
+// bundleVersion is the format version written to new bundles
+const bundleVersion = 1
+
+// Bundle is a recorded set of API responses for one app
+type Bundle struct {
+	Version    int         `json:"version"`
+	Context    string      `json:"context"`
+	Namespace  string      `json:"namespace"`
+	App        string      `json:"app"`
+	Kind       k8s.AppKind `json:"kind,omitempty"`
+	CapturedAt time.Time   `json:"capturedAt"`
+	Entries    []Entry     `json:"entries"`
+}
+
+// Entry is one recorded API response
+type Entry struct {
+	Method string          `json:"method"`
+	URI    string          `json:"uri"`
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	// Text notes a response body that was dropped: only JSON objects can be
+	// sanitized, other bodies are never recorded
+	Text string `json:"text,omitempty"`
+}
+
+// Recorder captures the API responses passing through a client transport
+type Recorder struct {
+	sanitizer *sanitizer
+
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+// NewRecorder creates a recorder. Secret values are always replaced by
+// placeholders; other values are replaced when redact matches their name.
+func NewRecorder(redact func(name string) bool) *Recorder {
+	return &Recorder{sanitizer: newSanitizer(redact), entries: make(map[string]Entry)}
+}
+
+// Wrap returns a transport recording the responses of rt
+func (r *Recorder) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return recordingTransport{next: rt, recorder: r}
+}
+
+// Bundle returns the responses recorded so far, in request order of their URIs
+func (r *Recorder) Bundle(context, namespace string, app k8s.App) *Bundle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]Entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].URI != entries[j].URI {
+			return entries[i].URI < entries[j].URI
+		}
+		return entries[i].Method < entries[j].Method
+	})
+
+	return &Bundle{
+		Version:    bundleVersion,
+		Context:    context,
+		Namespace:  namespace,
+		App:        app.Name,
+		Kind:       app.Kind,
+		CapturedAt: time.Now().UTC(),
+		Entries:    entries,
+	}
+}
+
+// record stores a sanitized copy of a response
+func (r *Recorder) record(req *http.Request, status int, body []byte) {
+	entry := Entry{
+		Method: req.Method,
+		URI:    req.URL.RequestURI(),
+		Status: status,
+	}
+	if sanitized, ok := r.sanitizer.sanitize(body); ok {
+		entry.Body = sanitized
+	} else if len(body) > 0 {
+		entry.Text = fmt.Sprintf("<dropped: %d bytes that could not be sanitized>", len(body))
+	}
+
+	r.mu.Lock()
+	r.entries[entryKey(entry.Method, entry.URI)] = entry
+	r.mu.Unlock()
+}
+
+// recordingTransport passes requests through and records their responses
+type recordingTransport struct {
+	next     http.RoundTripper
+	recorder *Recorder
+}
+
+func (t recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.recorder.record(req, resp.StatusCode, body)
+	return resp, nil
+}
+
+// entryKey identifies a request in a bundle
+func entryKey(method, uri string) string {
+	return method + " " + uri
+}
+
+// Write writes the bundle as indented JSON to a file only the user can read
+func (b *Bundle) Write(path string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(b); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0600)
+}
+
+// Load reads a bundle written by Write
+func Load(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture %s: %w", path, err)
+	}
+	bundle := &Bundle{}
+	if err := json.Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	if bundle.Version > bundleVersion {
+		return nil, fmt.Errorf("fixture %s has unsupported version %d", path, bundle.Version)
+	}
+	return bundle, nil
+}
+
+// Client returns a client answering every request from the bundle.
+// Requests that were not recorded get a 404 NotFound status.
+func (b *Bundle) Client() (*k8s.Client, error) {
+	responses := make(map[string]Entry, len(b.Entries))
+	for _, e := range b.Entries {
+		responses[entryKey(e.Method, e.URI)] = e
+	}
+	config := &rest.Config{
+		Host:      "http://fixture.invalid",
+		Transport: replayTransport{responses: responses},
+	}
+	return k8s.NewClientForConfig(config, b.Context+" (replay)")
+}
+
+// replayTransport serves recorded responses
+type replayTransport struct {
+	responses map[string]Entry
+}
+
+func (t replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	uri := req.URL.RequestURI()
+	entry, ok := t.responses[entryKey(req.Method, uri)]
+	if !ok {
+		entry = Entry{
+			Status: http.StatusNotFound,
+			Body:   notFoundStatus(req.Method + " " + uri),
+		}
+	}
+
+	body := []byte(entry.Body)
+	contentType := "application/json"
+	if entry.Body == nil {
+		body = []byte(entry.Text)
+		contentType = "text/plain"
+	}
+	return &http.Response{
+		StatusCode:    entry.Status,
+		Status:        fmt.Sprintf("%d %s", entry.Status, http.StatusText(entry.Status)),
+		Header:        http.Header{"Content-Type": []string{contentType}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// notFoundStatus returns the API Status body for a request missing from the bundle
+func notFoundStatus(request string) json.RawMessage {
+	data, _ := json.Marshal(map[string]interface{}{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"status":     "Failure",
+		"reason":     "NotFound",
+		"code":       http.StatusNotFound,
+		"message":    "not recorded in fixture: " + request,
+	})
+	return data
+}
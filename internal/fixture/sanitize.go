@@ -0,0 +1,146 @@
+package fixture
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// lastAppliedAnnotation holds the full applied manifest, including Secret data
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// sanitizer removes secret material from the API responses of one bundle
+type sanitizer struct {
+	redact func(name string) bool
+	// key of the HMAC of placeholders, random per bundle: a placeholder cannot
+	// be matched against the hash of a guessed value
+	key []byte
+}
+
+// newSanitizer creates a sanitizer with a new random key
+func newSanitizer(redact func(name string) bool) *sanitizer {
+	key := make([]byte, 32)
+	rand.Read(key) // never fails since Go 1.24
+	return &sanitizer{redact: redact, key: key}
+}
+
+// sanitize removes secret material from a JSON API response: Secret values,
+// values whose name matches redact, managed fields and last-applied manifests.
+// Values are replaced by a placeholder carrying their HMAC, so that equal
+// values still compare equal in a bug report. ok is false for a body that is
+// not a JSON object, which cannot be sanitized and must not be recorded.
+func (s *sanitizer) sanitize(body []byte) (sanitized json.RawMessage, ok bool) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(body, &obj); err != nil || obj == nil {
+		return nil, false
+	}
+
+	kind, _ := obj["kind"].(string)
+	if items, ok := obj["items"].([]interface{}); ok {
+		itemKind := strings.TrimSuffix(kind, "List")
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				s.sanitizeObject(m, itemKind)
+			}
+		}
+	}
+	s.sanitizeObject(obj, kind)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(obj); err != nil {
+		return nil, false
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), true
+}
+
+// sanitizeObject sanitizes one API object in place
+func (s *sanitizer) sanitizeObject(obj map[string]interface{}, kind string) {
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "managedFields")
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, lastAppliedAnnotation)
+		}
+	}
+
+	switch kind {
+	case "Secret":
+		s.redactMap(obj["data"], func(string) bool { return true }, true)
+		s.redactMap(obj["stringData"], func(string) bool { return true }, false)
+	case "ConfigMap":
+		if s.redact != nil {
+			s.redactMap(obj["data"], s.redact, false)
+			s.redactMap(obj["binaryData"], s.redact, true)
+		}
+	}
+	if s.redact != nil {
+		s.redactEnv(obj)
+	}
+}
+
+// redactMap replaces the values of a data map whose key matches
+func (s *sanitizer) redactMap(data interface{}, match func(string) bool, encoded bool) {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for key, v := range m {
+		value, ok := v.(string)
+		if !ok || !match(key) {
+			continue
+		}
+		if !encoded {
+			m[key] = s.placeholder([]byte(value))
+			continue
+		}
+		raw, err := k8s.DecodeBase64(value)
+		if err != nil {
+			raw = []byte(value)
+		}
+		m[key] = k8s.EncodeBase64([]byte(s.placeholder(raw)))
+	}
+}
+
+// redactEnv replaces inline env values whose name matches redact, at any depth
+// (pod specs, workload templates, CronJob job templates)
+func (s *sanitizer) redactEnv(v interface{}) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for key, child := range node {
+			if key == "env" {
+				if list, ok := child.([]interface{}); ok {
+					for _, item := range list {
+						envVar, ok := item.(map[string]interface{})
+						if !ok {
+							continue
+						}
+						name, _ := envVar["name"].(string)
+						if value, ok := envVar["value"].(string); ok && s.redact(name) {
+							envVar["value"] = s.placeholder([]byte(value))
+						}
+					}
+					continue
+				}
+			}
+			s.redactEnv(child)
+		}
+	case []interface{}:
+		for _, child := range node {
+			s.redactEnv(child)
+		}
+	}
+}
+
+// placeholder returns the value recorded in place of a redacted value
+func (s *sanitizer) placeholder(value []byte) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(value)
+	return "<redacted hmac:" + hex.EncodeToString(mac.Sum(nil)[:4]) + ">"
+}
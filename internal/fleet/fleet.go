@@ -0,0 +1,244 @@
+// Package fleet queries env across several clusters (kubeconfig contexts)
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// DefaultConcurrency is the default number of API requests in flight during a query
+const DefaultConcurrency = 8
+
+// NewClients creates one client per configured context
+func NewClients(contexts []config.ContextRef) ([]*k8s.Client, error) {
+	clients := make([]*k8s.Client, 0, len(contexts))
+	seen := make(map[string]bool, len(contexts))
+	for _, ref := range contexts {
+		if seen[ref.Name] {
+			return nil, fmt.Errorf("context %s is listed twice", ref.Name)
+		}
+		seen[ref.Name] = true
+
+		client, err := k8s.NewClientForContext(ref.Kubeconfig, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+	return clients, nil
+}
+
+// Target is a cluster to query
+type Target struct {
+	Client   *k8s.Client
+	Resolver *env.Resolver
+}
+
+// Match is a variable found by Query
+type Match struct {
+	Context   string
+	Namespace string
+	App       k8s.App
+	Var       k8s.EnvVar
+	// Missing is set by QueryVar for an app that exists but does not define the variable
+	Missing bool
+}
+
+// Failure is a context, namespace or app that could not be queried
+type Failure struct {
+	Context   string
+	Namespace string // empty when the namespaces could not be listed
+	App       string // empty when the apps could not be listed
+	Err       error
+}
+
+func (f Failure) Error() string {
+	where := f.Context
+	if f.Namespace != "" {
+		where += "/" + f.Namespace
+	}
+	if f.App != "" {
+		where += "/" + f.App
+	}
+	return fmt.Sprintf("%s: %v", where, f.Err)
+}
+
+// Result holds the matches of a query and the parts of the fleet that failed
+type Result struct {
+	Matches  []Match
+	Failures []Failure
+}
+
+// Query resolves the env of every app in every namespace of the targets (or in
+// namespace only, when set) and returns the variables whose name matches the glob
+// pattern. At most concurrency API requests run at once; failures do not stop the query.
+// Matches are ordered by target, namespace, app and variable name.
+func Query(ctx context.Context, targets []Target, namespace, pattern string, concurrency int) (*Result, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+	}
+	return run(ctx, targets, namespace, &query{pattern: pattern}, concurrency), nil
+}
+
+// QueryVar looks up one variable of an app in every namespace of the targets where
+// an app of the same name and kind exists. Apps without the variable yield a Missing match.
+func QueryVar(ctx context.Context, targets []Target, app k8s.App, name string, concurrency int) *Result {
+	q := &query{
+		pattern: name,
+		exact:   true,
+		app:     &k8s.App{Name: app.Name, Kind: app.Kind},
+	}
+	return run(ctx, targets, "", q, concurrency)
+}
+
+// run fans q out to the targets and orders its result
+func run(ctx context.Context, targets []Target, namespace string, q *query, concurrency int) *Result {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+	q.sem = make(chan struct{}, concurrency)
+	q.order = make(map[string]int, len(targets))
+	for i, target := range targets {
+		q.order[target.Client.GetCurrentContext()] = i
+		q.wg.Add(1)
+		go q.queryTarget(ctx, target, namespace)
+	}
+	q.wg.Wait()
+
+	sort.SliceStable(q.result.Matches, func(i, j int) bool {
+		a, b := q.result.Matches[i], q.result.Matches[j]
+		if a.Context != b.Context {
+			return q.order[a.Context] < q.order[b.Context]
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.App.Name != b.App.Name {
+			return a.App.Name < b.App.Name
+		}
+		return a.Var.Name < b.Var.Name
+	})
+	sort.SliceStable(q.result.Failures, func(i, j int) bool {
+		return q.order[q.result.Failures[i].Context] < q.order[q.result.Failures[j].Context]
+	})
+	return &q.result
+}
+
+// query is the shared state of a running Query
+type query struct {
+	sem     chan struct{}
+	pattern string
+	exact   bool           // pattern is a variable name, not a glob
+	app     *k8s.App       // only query apps of this name and kind (nil for all apps)
+	order   map[string]int // context -> target index
+
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	result Result
+}
+
+// do runs fn while holding a concurrency slot
+func (q *query) do(ctx context.Context, fn func() error) error {
+	select {
+	case q.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-q.sem }()
+	return fn()
+}
+
+func (q *query) fail(f Failure) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.result.Failures = append(q.result.Failures, f)
+}
+
+func (q *query) queryTarget(ctx context.Context, target Target, namespace string) {
+	defer q.wg.Done()
+	kubeContext := target.Client.GetCurrentContext()
+
+	namespaces := []string{namespace}
+	if namespace == "" {
+		err := q.do(ctx, func() (err error) {
+			namespaces, err = target.Client.ListNamespaces(ctx)
+			return err
+		})
+		if err != nil {
+			q.fail(Failure{Context: kubeContext, Err: err})
+			return
+		}
+	}
+
+	for _, ns := range namespaces {
+		q.wg.Add(1)
+		go q.queryNamespace(ctx, target, ns)
+	}
+}
+
+func (q *query) queryNamespace(ctx context.Context, target Target, namespace string) {
+	defer q.wg.Done()
+	kubeContext := target.Client.GetCurrentContext()
+
+	var apps []k8s.App
+	err := q.do(ctx, func() (err error) {
+		apps, err = target.Client.ListApps(ctx, namespace)
+		return err
+	})
+	if err != nil {
+		q.fail(Failure{Context: kubeContext, Namespace: namespace, Err: err})
+		return
+	}
+
+	for _, app := range apps {
+		if q.app != nil && (app.Name != q.app.Name || app.Kind != q.app.Kind) {
+			continue
+		}
+		q.wg.Add(1)
+		go q.queryApp(ctx, target, app)
+	}
+}
+
+func (q *query) queryApp(ctx context.Context, target Target, app k8s.App) {
+	defer q.wg.Done()
+	kubeContext := target.Client.GetCurrentContext()
+
+	var envVars []k8s.EnvVar
+	err := q.do(ctx, func() (err error) {
+		envVars, err = target.Resolver.ResolveAppEnvVars(ctx, app)
+		return err
+	})
+	if err != nil {
+		q.fail(Failure{Context: kubeContext, Namespace: app.Namespace, App: app.Name, Err: err})
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	found := false
+	for _, ev := range envVars {
+		if q.matches(ev.Name) {
+			q.result.Matches = append(q.result.Matches, Match{Context: kubeContext, Namespace: app.Namespace, App: app, Var: ev})
+			found = true
+		}
+	}
+	if !found && q.exact {
+		q.result.Matches = append(q.result.Matches, Match{Context: kubeContext, Namespace: app.Namespace, App: app, Var: k8s.EnvVar{Name: q.pattern}, Missing: true})
+	}
+}
+
+// matches returns true if a variable name is selected by the query
+func (q *query) matches(name string) bool {
+	if q.exact {
+		return name == q.pattern
+	}
+	ok, _ := path.Match(q.pattern, name)
+	return ok
+}
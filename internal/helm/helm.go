@@ -0,0 +1,69 @@
+// Package helm renders a local chart with the Helm SDK, for previewing the env
+// a chart would produce before it is upgraded
+package helm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/manifest"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+)
+
+// Renderer renders charts like `helm template`, without a helm binary
+type Renderer struct{}
+
+// Chart is a local chart to render as a release
+type Chart struct {
+	Path       string   // chart directory or packaged chart
+	Release    string   // release name
+	Namespace  string   // namespace of the release
+	ValueFiles []string // values files, later ones taking precedence
+}
+
+// Template renders a chart. Nothing is sent to the cluster: the chart is
+// rendered client-side only, so lookup functions of the chart return empty
+// results. Objects without a namespace are placed in the release namespace.
+func (r *Renderer) Template(ctx context.Context, chart Chart) (*manifest.Set, error) {
+	chrt, err := loader.Load(chart.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s: %w", chart.Path, err)
+	}
+	if chrt.Metadata.Dependencies != nil {
+		if err := action.CheckDependencies(chrt, chrt.Metadata.Dependencies); err != nil {
+			return nil, fmt.Errorf("chart %s: %w (run helm dependency build)", chart.Path, err)
+		}
+	}
+
+	valueOpts := &values.Options{ValueFiles: chart.ValueFiles}
+	vals, err := valueOpts.MergeValues(getter.All(cli.New()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read values: %w", err)
+	}
+
+	// The same settings as `helm template`
+	install := action.NewInstall(&action.Configuration{Log: func(string, ...interface{}) {}})
+	install.DryRun = true
+	install.DryRunOption = "true"
+	install.ClientOnly = true
+	install.Replace = true
+	install.ReleaseName = chart.Release
+	install.Namespace = chart.Namespace
+
+	rel, err := install.RunWithContext(ctx, chrt, vals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %s: %w", chart.Path, err)
+	}
+
+	var rendered strings.Builder
+	rendered.WriteString(rel.Manifest)
+	for _, hook := range rel.Hooks {
+		fmt.Fprintf(&rendered, "\n---\n# Source: %s\n%s\n", hook.Path, hook.Manifest)
+	}
+	return manifest.Parse(strings.NewReader(rendered.String()), chart.Namespace)
+}
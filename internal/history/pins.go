@@ -0,0 +1,93 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"sigs.k8s.io/yaml"
+)
+
+// PinStore persists the variables pinned to the top of the Env pane, per app
+// (see ViewKey), in a YAML file. It is safe for concurrent use.
+type PinStore struct {
+	mu   sync.Mutex
+	path string
+	Pins map[string][]string `json:"pins"`
+}
+
+// DefaultPinsPath returns the pins path (~/.config/envtop/pins.yaml)
+func DefaultPinsPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pins.yaml"), nil
+}
+
+// LoadPins reads the pins. A missing file yields an empty store.
+func LoadPins(path string) (*PinStore, error) {
+	store := &PinStore{path: path, Pins: make(map[string][]string)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if store.Pins == nil {
+		store.Pins = make(map[string][]string)
+	}
+	return store, nil
+}
+
+// Pinned returns the variables pinned in an app, in the order they were pinned
+func (s *PinStore) Pinned(key string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.Pins[key])
+}
+
+// Toggle pins a variable of an app, or unpins it if it was pinned, and saves
+// the store. It returns whether the variable is now pinned.
+func (s *PinStore) Toggle(key, name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pinned := s.Pins[key]
+	if i := slices.Index(pinned, name); i >= 0 {
+		pinned = slices.Delete(slices.Clone(pinned), i, i+1)
+	} else {
+		pinned = append(slices.Clone(pinned), name)
+	}
+	if len(pinned) == 0 {
+		delete(s.Pins, key)
+	} else {
+		s.Pins[key] = pinned
+	}
+	return slices.Contains(pinned, name), s.save()
+}
+
+// save writes the store to disk; the caller holds the lock
+func (s *PinStore) save() error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(s.path), err)
+	}
+	if err := os.WriteFile(s.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"sigs.k8s.io/yaml"
+)
+
+// View is the env of an app as it was last viewed. Only value hashes are stored.
+type View struct {
+	ViewedAt time.Time         `json:"viewedAt"`
+	Hashes   map[string]string `json:"hashes"`
+}
+
+// Changes lists the variables that differ from the last view of an app
+type Changes struct {
+	Since   time.Time
+	Changed map[string]bool // variables whose value changed or that are new
+	Removed []string        // variables that no longer exist
+}
+
+// Count returns the number of changed, new and removed variables
+func (c *Changes) Count() int {
+	return len(c.Changed) + len(c.Removed)
+}
+
+// ViewStore persists the last viewed env of each app in a YAML file.
+// It is safe for concurrent use.
+type ViewStore struct {
+	mu    sync.Mutex
+	path  string
+	Views map[string]View `json:"views"`
+}
+
+// DefaultViewsPath returns the view history path (~/.config/envtop/views.yaml)
+func DefaultViewsPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "views.yaml"), nil
+}
+
+// LoadViews reads the view history. A missing file yields an empty store.
+func LoadViews(path string) (*ViewStore, error) {
+	store := &ViewStore{path: path, Views: make(map[string]View)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if store.Views == nil {
+		store.Views = make(map[string]View)
+	}
+	return store, nil
+}
+
+// ViewKey identifies an app across clusters
+func ViewKey(context string, app k8s.App) string {
+	return fmt.Sprintf("%s/%s/%s/%s", context, app.Namespace, app.Kind, app.Name)
+}
+
+// valueHash returns the hash identifying a variable's value
+func valueHash(ev *k8s.EnvVar) string {
+	if ev.Hash != "" {
+		return ev.Hash
+	}
+	return k8s.HashValue([]byte(ev.Value))
+}
+
+// Compare returns the changes since the last view of the app, or nil if it was never viewed
+func (s *ViewStore) Compare(key string, envVars []k8s.EnvVar) *Changes {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last, ok := s.Views[key]
+	if !ok {
+		return nil
+	}
+
+	changes := &Changes{Since: last.ViewedAt, Changed: make(map[string]bool)}
+	current := make(map[string]bool, len(envVars))
+	for i := range envVars {
+		ev := &envVars[i]
+		current[ev.Name] = true
+		if hash, ok := last.Hashes[ev.Name]; !ok || hash != valueHash(ev) {
+			changes.Changed[ev.Name] = true
+		}
+	}
+	for name := range last.Hashes {
+		if !current[name] {
+			changes.Removed = append(changes.Removed, name)
+		}
+	}
+	return changes
+}
+
+// Record stores the env as the last view of the app and saves the store
+func (s *ViewStore) Record(key string, envVars []k8s.EnvVar, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hashes := make(map[string]string, len(envVars))
+	for i := range envVars {
+		hashes[envVars[i].Name] = valueHash(&envVars[i])
+	}
+	s.Views[key] = View{ViewedAt: now, Hashes: hashes}
+	return s.save()
+}
+
+// save writes the store to disk; the caller holds the lock
+func (s *ViewStore) save() error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(s.path), err)
+	}
+	if err := os.WriteFile(s.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Package i18n translates UI strings through message catalogs and formats
+// timestamps and numbers for the user's locale.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultLanguage is the language of the built-in strings and the catalog fallback
+const DefaultLanguage = "en"
+
+// catalogs holds one YAML file per language mapping message keys to fmt formats
+//
+//go:embed catalog/*.yaml
+var catalogs embed.FS
+
+// Formats holds the timestamp and number conventions of a language
+type Formats struct {
+	Date      string // Go time layout of a date
+	DateTime  string // Go time layout of a date with time of day
+	Thousands string // digit group separator ("" disables grouping)
+}
+
+// formats are the built-in conventions; languages without an entry use English ones
+var formats = map[string]Formats{
+	"en": {Date: "Jan 2, 2006", DateTime: "Jan 2 15:04", Thousands: ","},
+	"ja": {Date: "2006年1月2日", DateTime: "1月2日 15:04", Thousands: ","},
+}
+
+// Printer translates messages and formats values for one language
+type Printer struct {
+	language string
+	messages map[string]string
+	fallback map[string]string
+	formats  Formats
+}
+
+// New returns a printer for a language ("" detects it from the environment).
+// Empty fields of override keep the language's own formats.
+func New(language string, override Formats) (*Printer, error) {
+	if language == "" {
+		language = Detect()
+	}
+
+	fallback, err := loadCatalog(DefaultLanguage)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := loadCatalog(language)
+	if err != nil {
+		// No catalog for the language: untranslated, but still formatted for it
+		messages = fallback
+	}
+
+	f, ok := formats[language]
+	if !ok {
+		f = formats[DefaultLanguage]
+	}
+	if override.Date != "" {
+		f.Date = override.Date
+	}
+	if override.DateTime != "" {
+		f.DateTime = override.DateTime
+	}
+	if override.Thousands != "" {
+		f.Thousands = override.Thousands
+	}
+
+	return &Printer{language: language, messages: messages, fallback: fallback, formats: f}, nil
+}
+
+// Detect returns the language of the environment from LC_ALL, LC_MESSAGES or LANG
+// (e.g. "ja" for ja_JP.UTF-8), English when unset or "C"
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		fields := strings.FieldsFunc(value, func(r rune) bool {
+			return r == '_' || r == '.' || r == '@' || r == '-'
+		})
+		if len(fields) == 0 {
+			continue
+		}
+		language := strings.ToLower(fields[0])
+		if language == "c" || language == "posix" {
+			return DefaultLanguage
+		}
+		return language
+	}
+	return DefaultLanguage
+}
+
+// loadCatalog reads the message catalog of a language
+func loadCatalog(language string) (map[string]string, error) {
+	data, err := catalogs.ReadFile("catalog/" + language + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("no message catalog for %s", language)
+	}
+	messages := make(map[string]string)
+	if err := yaml.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse message catalog %s: %w", language, err)
+	}
+	return messages, nil
+}
+
+// Language returns the language of the printer
+func (p *Printer) Language() string {
+	return p.language
+}
+
+// T returns the message of a key formatted with args. Keys missing from the catalog
+// of the language fall back to English, then to the key itself.
+func (p *Printer) T(key string, args ...any) string {
+	format, ok := p.messages[key]
+	if !ok {
+		format, ok = p.fallback[key]
+	}
+	if !ok {
+		format = key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Date formats the date of a timestamp in local time
+func (p *Printer) Date(t time.Time) string {
+	return t.Local().Format(p.formats.Date)
+}
+
+// DateTime formats a timestamp with its time of day in local time
+func (p *Printer) DateTime(t time.Time) string {
+	return t.Local().Format(p.formats.DateTime)
+}
+
+// Number formats an integer with digit grouping
+func (p *Printer) Number(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	if p.formats.Thousands == "" || len(digits) <= 3 {
+		return sign + digits
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > len(sign) {
+			b.WriteString(p.formats.Thousands)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
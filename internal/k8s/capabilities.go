@@ -0,0 +1,91 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Capability is an optional cluster feature (a CRD of a controller) that some
+// badges and features of envtop depend on
+type Capability string
+
+const (
+	CapabilitySealedSecrets   Capability = "sealedSecrets"
+	CapabilityExternalSecrets Capability = "externalSecrets"
+	CapabilityFlux            Capability = "flux"
+	CapabilityArgoCD          Capability = "argocd"
+	CapabilityKEDA            Capability = "keda"
+)
+
+// Capabilities lists the capabilities envtop discovers, in display order
+var Capabilities = []Capability{CapabilitySealedSecrets, CapabilityExternalSecrets, CapabilityFlux, CapabilityArgoCD, CapabilityKEDA}
+
+// capabilityResource is the resource whose presence reveals a capability
+type capabilityResource struct {
+	kind string
+	gvr  schema.GroupVersionResource
+}
+
+var capabilityResources = map[Capability]capabilityResource{
+	CapabilitySealedSecrets:   {"SealedSecret", SealedSecretGVR},
+	CapabilityExternalSecrets: {"ExternalSecret", schema.GroupVersionResource{Group: "external-secrets.io", Version: "v1beta1", Resource: "externalsecrets"}},
+	CapabilityFlux:            {"Kustomization", schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}},
+	CapabilityArgoCD:          {"Application", schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}},
+	CapabilityKEDA:            {"ScaledObject", ScaledObjectGVR},
+}
+
+// capabilityNames are the display names of the capabilities
+var capabilityNames = map[Capability]string{
+	CapabilitySealedSecrets:   "SealedSecret",
+	CapabilityExternalSecrets: "External Secrets",
+	CapabilityFlux:            "Flux",
+	CapabilityArgoCD:          "Argo CD",
+	CapabilityKEDA:            "KEDA",
+}
+
+// String returns the display name of the capability
+func (c Capability) String() string {
+	if name, ok := capabilityNames[c]; ok {
+		return name
+	}
+	return string(c)
+}
+
+// ParseCapability parses a capability name of the config file
+func ParseCapability(name string) (Capability, error) {
+	for _, c := range Capabilities {
+		if string(c) == name {
+			return c, nil
+		}
+	}
+	names := make([]string, len(Capabilities))
+	for i, c := range Capabilities {
+		names[i] = string(c)
+	}
+	return "", fmt.Errorf("unknown capability %q (one of %s)", name, strings.Join(names, ", "))
+}
+
+// DiscoverCapability checks whether the CRD of a capability is served by the
+// cluster. A missing CRD is reported as unavailable without an error; other
+// failures (an unresponsive aggregated API, the deadline of ctx, RBAC) return
+// the error, and the capability should be treated as unknown.
+func (c *Client) DiscoverCapability(ctx context.Context, capability Capability) (bool, error) {
+	res, ok := capabilityResources[capability]
+	if !ok {
+		return false, fmt.Errorf("unknown capability %q", capability)
+	}
+	_, err := c.dynamicClient.Resource(res.gvr).List(ctx, metav1.ListOptions{Limit: 1})
+	switch {
+	case err == nil:
+		return true, nil
+	case apierrors.IsNotFound(err):
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to discover %s: %w", capability, err)
+	}
+}
@@ -5,42 +5,114 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/rest"
 )
 
 // Client wraps Kubernetes client operations
 type Client struct {
-	clientset     *kubernetes.Clientset
+	clientset     kubernetes.Interface
 	dynamicClient dynamic.Interface
+	restConfig    *rest.Config
 	context       string
+	kubeconfig    string
 }
 
-// NewClient creates a new Kubernetes client using kubeconfig
+// InClusterContext is the context name shown for the in-cluster configuration
+const InClusterContext = "in-cluster"
+
+// NewClient creates a new Kubernetes client using kubeconfig. Inside a pod
+// without a kubeconfig file, or with $ENVTOP_IN_CLUSTER=1, it uses the
+// in-cluster configuration (the pod's service account) instead. With
+// $ENVTOP_OFFLINE set to a directory, it serves the manifests of the directory
+// from memory (see NewOfflineClient).
 func NewClient() (*Client, error) {
-	kubeconfig := os.Getenv("KUBECONFIG")
-	if kubeconfig == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
+	if dir := os.Getenv("ENVTOP_OFFLINE"); dir != "" {
+		return NewOfflineClient(dir)
+	}
+	if os.Getenv("ENVTOP_IN_CLUSTER") == "1" || inPodWithoutKubeconfig() {
+		return NewInClusterClient()
+	}
+	return NewClientForContext("", "")
+}
+
+// NewInClusterClient creates a client from the service account of the pod envtop
+// runs in, e.g. a debug pod or a kubectl exec session
+func NewInClusterClient() (*Client, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load in-cluster config: %w", err)
+	}
+	return NewClientForConfig(config, InClusterContext)
+}
+
+// inPodWithoutKubeconfig returns true when running in a pod (the API server
+// address is injected in the environment) and no kubeconfig file exists
+func inPodWithoutKubeconfig() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return false
+	}
+	kubeconfig, err := kubeconfigPath("")
+	if err != nil {
+		return true
+	}
+	_, err = os.Stat(kubeconfig)
+	return os.IsNotExist(err)
+}
+
+// NewClientForContext creates a client for a context of a kubeconfig file, or of
+// a kubeconfig read into memory by ReadKubeconfig or FetchKubeconfig (given its
+// source). Empty values select $KUBECONFIG (or ~/.kube/config) and its current context.
+func NewClientForContext(kubeconfig, contextName string) (*Client, error) {
+	kubeconfig, err := kubeconfigPath(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse kubeconfig once for both the REST config and the context name
+	kubeConfig, rawConfig, err := loadKubeconfig(kubeconfig, contextName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get raw config: %w", err)
+	}
+
+	currentContext := rawConfig.CurrentContext
+	if contextName != "" {
+		if _, ok := rawConfig.Contexts[contextName]; !ok {
+			return nil, fmt.Errorf("context %s not found in %s", contextName, kubeconfigSourceName(kubeconfig))
 		}
-		kubeconfig = filepath.Join(home, ".kube", "config")
+		currentContext = contextName
+	}
+
+	config, err := kubeConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config for context %s: %w", currentContext, err)
 	}
 
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	client, err := NewClientForConfig(config, currentContext)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build config: %w", err)
+		return nil, err
 	}
+	client.kubeconfig = kubeconfig
+	return client, nil
+}
 
+// NewClientForConfig creates a client from a REST config, e.g. one serving a
+// recorded fixture. contextName is only used for display.
+func NewClientForConfig(config *rest.Config, contextName string) (*Client, error) {
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create clientset: %w", err)
@@ -51,43 +123,150 @@ func NewClient() (*Client, error) {
 		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
-	// Get current context name
-	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	loadingRules.ExplicitPath = kubeconfig
-	configOverrides := &clientcmd.ConfigOverrides{}
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-	rawConfig, err := kubeConfig.RawConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get raw config: %w", err)
-	}
-
 	return &Client{
 		clientset:     clientset,
 		dynamicClient: dynamicClient,
-		context:       rawConfig.CurrentContext,
+		restConfig:    config,
+		context:       contextName,
 	}, nil
 }
 
+// WithTransport returns a copy of the client whose requests go through wrap,
+// e.g. to record the API responses
+func (c *Client) WithTransport(wrap func(http.RoundTripper) http.RoundTripper) (*Client, error) {
+	config := rest.CopyConfig(c.restConfig)
+	config.Wrap(wrap)
+	client, err := NewClientForConfig(config, c.context)
+	if err != nil {
+		return nil, err
+	}
+	client.kubeconfig = c.kubeconfig
+	return client, nil
+}
+
+// kubeconfigPath returns the kubeconfig file to load: the given path, $KUBECONFIG or ~/.kube/config
+func kubeconfigPath(kubeconfig string) (string, error) {
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+	if kubeconfig == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		kubeconfig = filepath.Join(home, ".kube", "config")
+	}
+	return kubeconfig, nil
+}
+
+// ListContexts returns the sorted context names of a kubeconfig file (or of one
+// read into memory by ReadKubeconfig or FetchKubeconfig) and its current context
+func ListContexts(kubeconfig string) ([]string, string, error) {
+	kubeconfig, err := kubeconfigPath(kubeconfig)
+	if err != nil {
+		return nil, "", err
+	}
+	_, rawConfig, err := loadKubeconfig(kubeconfig, "")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load %s: %w", kubeconfigSourceName(kubeconfig), err)
+	}
+
+	contexts := make([]string, 0, len(rawConfig.Contexts))
+	for name := range rawConfig.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+	return contexts, rawConfig.CurrentContext, nil
+}
+
 // GetCurrentContext returns the current Kubernetes context name
 func (c *Client) GetCurrentContext() string {
 	return c.context
 }
 
+// Kubeconfig returns the path of the kubeconfig file the client was built from
+func (c *Client) Kubeconfig() string {
+	return c.kubeconfig
+}
+
+// NamespacePageSize is the number of namespaces requested per page
+const NamespacePageSize = 500
+
 // ListNamespaces returns a list of all namespaces
 func (c *Client) ListNamespaces(ctx context.Context) ([]string, error) {
-	nsList, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	details, err := c.ListNamespaceDetails(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		return nil, err
 	}
 
-	namespaces := make([]string, 0, len(nsList.Items))
-	for _, ns := range nsList.Items {
+	namespaces := make([]string, 0, len(details))
+	for _, ns := range details {
 		namespaces = append(namespaces, ns.Name)
 	}
 	return namespaces, nil
 }
 
-// ListApps returns a list of Deployments and StatefulSets in the given namespace
+// ListNamespaceDetails returns all namespaces with their labels and creation
+// time, listed page by page
+func (c *Client) ListNamespaceDetails(ctx context.Context) ([]Namespace, error) {
+	var namespaces []Namespace
+	continueToken := ""
+	for {
+		page, next, err := c.ListNamespaceDetailsPage(ctx, NamespacePageSize, continueToken)
+		if err != nil {
+			return nil, err
+		}
+		namespaces = append(namespaces, page...)
+		if next == "" {
+			return namespaces, nil
+		}
+		continueToken = next
+	}
+}
+
+// ListNamespaceDetailsPage returns one page of at most limit namespaces, and the
+// continue token of the next page ("" after the last one). A token expires a
+// few minutes after it was issued (410 Gone); the listing must then restart.
+func (c *Client) ListNamespaceDetailsPage(ctx context.Context, limit int64, continueToken string) ([]Namespace, string, error) {
+	nsList, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{Limit: limit, Continue: continueToken})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	namespaces := make([]Namespace, 0, len(nsList.Items))
+	for _, ns := range nsList.Items {
+		namespaces = append(namespaces, Namespace{
+			Name:      ns.Name,
+			Labels:    ns.Labels,
+			CreatedAt: ns.CreationTimestamp.Time,
+		})
+	}
+	return namespaces, nsList.Continue, nil
+}
+
+// GetNamespaceLimits returns the ResourceQuotas and LimitRanges defined in a namespace
+func (c *Client) GetNamespaceLimits(ctx context.Context, namespace string) (*NamespaceLimits, error) {
+	quotas, err := c.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource quotas: %w", err)
+	}
+	limitRanges, err := c.clientset.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list limit ranges: %w", err)
+	}
+
+	limits := &NamespaceLimits{}
+	for _, q := range quotas.Items {
+		limits.ResourceQuotas = append(limits.ResourceQuotas, q.Name)
+	}
+	for _, lr := range limitRanges.Items {
+		limits.LimitRanges = append(limits.LimitRanges, lr.Name)
+	}
+	return limits, nil
+}
+
+// ListApps returns a list of Deployments, StatefulSets, CronJobs and Jobs in the given namespace.
+// Jobs created by a CronJob are not listed separately.
 func (c *Client) ListApps(ctx context.Context, namespace string) ([]App, error) {
 	apps := make([]App, 0)
 
@@ -117,6 +296,35 @@ func (c *Client) ListApps(ctx context.Context, namespace string) ([]App, error)
 		})
 	}
 
+	// List CronJobs
+	cronjobs, err := c.clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+	for _, cj := range cronjobs.Items {
+		apps = append(apps, App{
+			Name:      cj.Name,
+			Namespace: namespace,
+			Kind:      AppKindCronJob,
+		})
+	}
+
+	// List Jobs not owned by a CronJob
+	jobs, err := c.clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for _, j := range jobs.Items {
+		if owner := metav1.GetControllerOf(&j); owner != nil && owner.Kind == string(AppKindCronJob) {
+			continue
+		}
+		apps = append(apps, App{
+			Name:      j.Name,
+			Namespace: namespace,
+			Kind:      AppKindJob,
+		})
+	}
+
 	return apps, nil
 }
 
@@ -130,6 +338,156 @@ func (c *Client) GetStatefulSet(ctx context.Context, namespace, name string) (*a
 	return c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
+// GetCronJob returns a CronJob by name
+func (c *Client) GetCronJob(ctx context.Context, namespace, name string) (*batchv1.CronJob, error) {
+	return c.clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetJob returns a Job by name
+func (c *Client) GetJob(ctx context.Context, namespace, name string) (*batchv1.Job, error) {
+	return c.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// cronJobSelector selects the pods of the Jobs a CronJob has created.
+// Pods are matched by the job-name label, since a CronJob has no selector of its own.
+func (c *Client) cronJobSelector(ctx context.Context, namespace, name string) (*metav1.LabelSelector, error) {
+	jobs, err := c.clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	var names []string
+	for _, j := range jobs.Items {
+		if owner := metav1.GetControllerOf(&j); owner != nil && owner.Kind == string(AppKindCronJob) && owner.Name == name {
+			names = append(names, j.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      "job-name",
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   names,
+		}},
+	}, nil
+}
+
+// zoneLabel is the well-known node label holding the topology zone
+const zoneLabel = "topology.kubernetes.io/zone"
+
+// ListAppPods returns the pods selected by the given app's label selector
+func (c *Client) ListAppPods(ctx context.Context, app App) ([]Pod, error) {
+	var selector *metav1.LabelSelector
+	switch app.Kind {
+	case AppKindDeployment:
+		d, err := c.GetDeployment(ctx, app.Namespace, app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment %s: %w", app.Name, err)
+		}
+		selector = d.Spec.Selector
+	case AppKindStatefulSet:
+		s, err := c.GetStatefulSet(ctx, app.Namespace, app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get statefulset %s: %w", app.Name, err)
+		}
+		selector = s.Spec.Selector
+	case AppKindCronJob:
+		s, err := c.cronJobSelector(ctx, app.Namespace, app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get jobs of cronjob %s: %w", app.Name, err)
+		}
+		if s == nil {
+			return []Pod{}, nil // no Job has run yet
+		}
+		selector = s
+	case AppKindJob:
+		j, err := c.GetJob(ctx, app.Namespace, app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job %s: %w", app.Name, err)
+		}
+		selector = j.Spec.Selector
+	default:
+		return nil, fmt.Errorf("unsupported app kind: %s", app.Kind)
+	}
+
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector for %s: %w", app.Name, err)
+	}
+
+	podList, err := c.clientset.CoreV1().Pods(app.Namespace).List(ctx, metav1.ListOptions{LabelSelector: sel.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	// Look up each node's zone once; nodes may not be readable, in which case zone stays empty
+	zones := make(map[string]string)
+	pods := make([]Pod, 0, len(podList.Items))
+	for _, p := range podList.Items {
+		zone, ok := zones[p.Spec.NodeName]
+		if !ok && p.Spec.NodeName != "" {
+			if node, err := c.clientset.CoreV1().Nodes().Get(ctx, p.Spec.NodeName, metav1.GetOptions{}); err == nil {
+				zone = node.Labels[zoneLabel]
+			}
+			zones[p.Spec.NodeName] = zone
+		}
+		pod := Pod{
+			Name:      p.Name,
+			Namespace: p.Namespace,
+			NodeName:  p.Spec.NodeName,
+			Zone:      zone,
+			Phase:     string(p.Status.Phase),
+		}
+		if p.Status.StartTime != nil {
+			pod.StartTime = p.Status.StartTime.Time
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// GetPod returns a Pod by name
+func (c *Client) GetPod(ctx context.Context, namespace, name string) (*corev1.Pod, error) {
+	return c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetServiceAccount returns a ServiceAccount by name
+func (c *Client) GetServiceAccount(ctx context.Context, namespace, name string) (*corev1.ServiceAccount, error) {
+	return c.clientset.CoreV1().ServiceAccounts(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetService returns a Service by name
+func (c *Client) GetService(ctx context.Context, namespace, name string) (*corev1.Service, error) {
+	return c.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListServiceNames returns the names of all Services in a namespace
+func (c *Client) ListServiceNames(ctx context.Context, namespace string) ([]string, error) {
+	list, err := c.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services in %s: %w", namespace, err)
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for _, svc := range list.Items {
+		names = append(names, svc.Name)
+	}
+	return names, nil
+}
+
+// ListEndpointSlices returns the EndpointSlices backing a Service
+func (c *Client) ListEndpointSlices(ctx context.Context, namespace, service string) ([]discoveryv1.EndpointSlice, error) {
+	list, err := c.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + service,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoint slices of %s: %w", service, err)
+	}
+	return list.Items, nil
+}
+
 // GetConfigMap returns a ConfigMap by name
 func (c *Client) GetConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
 	return c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
@@ -152,10 +510,42 @@ func (c *Client) GetSealedSecret(ctx context.Context, namespace, name string) (*
 	return c.dynamicClient.Resource(SealedSecretGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
-// IsSealedSecretAvailable checks if SealedSecret CRD is available in the cluster
-func (c *Client) IsSealedSecretAvailable(ctx context.Context) bool {
-	_, err := c.dynamicClient.Resource(SealedSecretGVR).List(ctx, metav1.ListOptions{Limit: 1})
-	return err == nil
+// Default sealed-secrets controller location (same defaults as kubeseal)
+const (
+	DefaultSealedSecretsControllerNamespace = "kube-system"
+	DefaultSealedSecretsControllerName      = "sealed-secrets-controller"
+)
+
+// FetchSealingCert fetches the sealed-secrets controller's public certificate through the service proxy
+func (c *Client) FetchSealingCert(ctx context.Context, namespace, name string) ([]byte, error) {
+	data, err := c.clientset.CoreV1().Services(namespace).ProxyGet("http", name, "", "/v1/cert.pem", nil).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sealing certificate from %s/%s: %w", namespace, name, err)
+	}
+	return data, nil
+}
+
+// VerifySealedSecret asks the sealed-secrets controller whether it can decrypt the given
+// SealedSecret (JSON encoded). It returns false when the controller rejects it.
+func (c *Client) VerifySealedSecret(ctx context.Context, namespace, name string, sealedSecret []byte) (bool, error) {
+	var statusCode int
+	result := c.clientset.CoreV1().RESTClient().Post().
+		Namespace(namespace).
+		Resource("services").
+		SubResource("proxy").
+		Name(utilnet.JoinSchemeNamePort("http", name, "")).
+		Suffix("/v1/verify").
+		Body(sealedSecret).
+		Do(ctx).
+		StatusCode(&statusCode)
+
+	if statusCode == http.StatusConflict {
+		return false, nil
+	}
+	if err := result.Error(); err != nil {
+		return false, fmt.Errorf("failed to verify sealed secret with %s/%s: %w", namespace, name, err)
+	}
+	return true, nil
 }
 
 // HashValue returns a SHA256 hash prefix of the given value
@@ -173,3 +563,17 @@ func DecodeBase64(encoded string) ([]byte, error) {
 func EncodeBase64(data []byte) string {
 	return base64.StdEncoding.EncodeToString(data)
 }
+
+// maskedEdge is the number of characters MaskValue keeps at each end
+const maskedEdge = 4
+
+// MaskValue shows only the first and last characters of a value with its
+// length, e.g. "sk_l…9f3a (32 chars)", enough to tell which credential is
+// deployed. Values too short to hide anything in between show the length only.
+func MaskValue(data []byte) string {
+	runes := []rune(string(data))
+	if len(runes) <= 3*maskedEdge {
+		return fmt.Sprintf("•••• (%d chars)", len(runes))
+	}
+	return fmt.Sprintf("%s…%s (%d chars)", string(runes[:maskedEdge]), string(runes[len(runes)-maskedEdge:]), len(runes))
+}
@@ -0,0 +1,111 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"syscall"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ErrorKind classifies the failures envtop can suggest a remedy for
+type ErrorKind string
+
+const (
+	ErrorAuth       ErrorKind = "Auth"       // the credentials were rejected or expired
+	ErrorForbidden  ErrorKind = "Forbidden"  // RBAC denied the request
+	ErrorNotFound   ErrorKind = "NotFound"   // the object does not exist
+	ErrorTimeout    ErrorKind = "Timeout"    // the API server did not answer
+	ErrorCRDMissing ErrorKind = "CRDMissing" // the resource type is not installed
+)
+
+// Error is a classified failure with a remediation hint for the user. It
+// wraps the original error, so apierrors.IsForbidden and friends still apply.
+type Error struct {
+	Kind ErrorKind
+	Hint string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// forbiddenPattern extracts the verb, resource and namespace of an RBAC denial,
+// e.g. `User "jane" cannot get resource "secrets" in API group "" in the namespace "prod"`
+var forbiddenPattern = regexp.MustCompile(`cannot (\w+) resource "([^"]+)"(?: in API group "[^"]*")?(?: in the namespace "([^"]+)")?`)
+
+// Classify wraps err into an *Error when its kind is recognized, and returns it
+// unchanged otherwise (nil included). Errors that are already classified are
+// returned as they are.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	switch {
+	case apierrors.IsUnauthorized(err):
+		return &Error{Kind: ErrorAuth, Err: err,
+			Hint: "the credentials were rejected: log in again (e.g. aws sso login, gcloud auth login) or check the user of the kubeconfig context"}
+	case apierrors.IsForbidden(err):
+		hint := "ask for the missing permission, or check it with kubectl auth can-i"
+		if m := forbiddenPattern.FindStringSubmatch(err.Error()); m != nil {
+			hint = fmt.Sprintf("run kubectl auth can-i %s %s", m[1], m[2])
+			if m[3] != "" {
+				hint += " -n " + m[3]
+			}
+			hint += " to check the permission, and ask for it if it is missing"
+		}
+		return &Error{Kind: ErrorForbidden, Err: err, Hint: hint}
+	case apierrors.IsNotFound(err):
+		if isMissingResourceType(err) {
+			return &Error{Kind: ErrorCRDMissing, Err: err,
+				Hint: "the resource type is not installed in this cluster: install its CRD (or controller), or turn the feature off"}
+		}
+		return &Error{Kind: ErrorNotFound, Err: err,
+			Hint: "check the namespace and the name; the object may have been deleted, reload with ctrl+r"}
+	case isTimeout(err):
+		return &Error{Kind: ErrorTimeout, Err: err,
+			Hint: "the API server did not answer: check the VPN or proxy, and that the cluster is running"}
+	}
+	return err
+}
+
+// isMissingResourceType returns true for the NotFound the API server answers
+// for a resource it does not serve, as opposed to a missing object
+func isMissingResourceType(err error) bool {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return false
+	}
+	s := status.Status()
+	return s.Details == nil || strings.HasPrefix(s.Message, "the server could not find the requested resource")
+}
+
+// isTimeout returns true for the errors of an unreachable or unresponsive API server
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EHOSTUNREACH) || errors.Is(err, syscall.ENETUNREACH) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
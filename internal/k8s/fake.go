@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+// NewFakeClient creates a client backed by an in-memory fake API server holding
+// the given typed objects, e.g. for the tutorial. The custom resources
+// (SealedSecrets, ScaledObjects, the CRDs of the capabilities) are empty,
+// watches report nothing and port forwarding fails to connect.
+func NewFakeClient(contextName string, objects ...runtime.Object) *Client {
+	return newInMemoryClient(contextName, objects, nil)
+}
+
+// newInMemoryClient creates a client of an in-memory fake API server holding
+// typed objects for the clientset and unstructured custom resources for the
+// dynamic client
+func newInMemoryClient(contextName string, typed, custom []runtime.Object) *Client {
+	listKinds := map[schema.GroupVersionResource]string{
+		SealedSecretGVR: "SealedSecretList",
+	}
+	for _, res := range capabilityResources {
+		listKinds[res.gvr] = res.kind + "List"
+	}
+	for _, res := range watchedResources {
+		listKinds[res.gvr] = res.kind + "List"
+	}
+
+	return &Client{
+		clientset:     fake.NewClientset(typed...),
+		dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, custom...),
+		restConfig:    &rest.Config{},
+		context:       contextName,
+	}
+}
+
+// NewClientForClientset creates a client of the given clientset, e.g. a fake
+// clientset whose reactors inject API errors in tests. The custom resources
+// are empty, as with NewFakeClient.
+func NewClientForClientset(contextName string, clientset kubernetes.Interface) *Client {
+	client := newInMemoryClient(contextName, nil, nil)
+	client.clientset = clientset
+	return client
+}
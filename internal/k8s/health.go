@@ -0,0 +1,166 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// AppHealth summarizes the workload status looked at first during incidents
+type AppHealth struct {
+	FailingPods  int       // pods failed, unknown or stuck in a container error state
+	ConfigEvents int       // Warning events about missing ConfigMaps/Secrets or container config
+	LastDeployed time.Time // creation of the newest ReplicaSet/ControllerRevision
+}
+
+// AppKey returns the key of an app in the map returned by ListAppHealth
+func AppKey(app App) string {
+	return string(app.Kind) + "/" + app.Name
+}
+
+// failingWaitingReasons are container waiting reasons that indicate a broken pod
+var failingWaitingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// ListAppHealth returns the health of every Deployment and StatefulSet of a
+// namespace, keyed by AppKey. Pods, ReplicaSets, ControllerRevisions and Events
+// are each listed once for the whole namespace.
+func (c *Client) ListAppHealth(ctx context.Context, namespace string) (map[string]AppHealth, error) {
+	health := make(map[string]AppHealth)
+	selectors := make(map[string]labels.Selector)
+
+	deployments, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		key := AppKey(App{Name: d.Name, Kind: AppKindDeployment})
+		health[key] = AppHealth{}
+		if sel, err := metav1.LabelSelectorAsSelector(d.Spec.Selector); err == nil {
+			selectors[key] = sel
+		}
+	}
+	statefulsets, err := c.clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, s := range statefulsets.Items {
+		key := AppKey(App{Name: s.Name, Kind: AppKindStatefulSet})
+		health[key] = AppHealth{}
+		if sel, err := metav1.LabelSelectorAsSelector(s.Spec.Selector); err == nil {
+			selectors[key] = sel
+		}
+	}
+
+	// Rollouts: the newest ReplicaSet / ControllerRevision owned by each app
+	owners := make(map[string]string) // "ReplicaSet/<name>" -> app key, for events
+	replicaSets, err := c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicasets: %w", err)
+	}
+	for _, rs := range replicaSets.Items {
+		if owner := metav1.GetControllerOf(&rs); owner != nil && owner.Kind == string(AppKindDeployment) {
+			key := AppKey(App{Name: owner.Name, Kind: AppKindDeployment})
+			owners["ReplicaSet/"+rs.Name] = key
+			updateLastDeployed(health, key, rs.CreationTimestamp.Time)
+		}
+	}
+	revisions, err := c.clientset.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list controllerrevisions: %w", err)
+	}
+	for _, rev := range revisions.Items {
+		if owner := metav1.GetControllerOf(&rev); owner != nil && owner.Kind == string(AppKindStatefulSet) {
+			updateLastDeployed(health, AppKey(App{Name: owner.Name, Kind: AppKindStatefulSet}), rev.CreationTimestamp.Time)
+		}
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		for key, sel := range selectors {
+			if !sel.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			owners["Pod/"+pod.Name] = key
+			if isFailing(&pod) {
+				h := health[key]
+				h.FailingPods++
+				health[key] = h
+			}
+		}
+	}
+
+	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: "type=Warning"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	for _, ev := range events.Items {
+		if !isConfigEvent(&ev) {
+			continue
+		}
+		obj := ev.InvolvedObject.Kind + "/" + ev.InvolvedObject.Name
+		key, ok := owners[obj]
+		if !ok {
+			key = obj // events on the Deployment/StatefulSet itself
+		}
+		if h, ok := health[key]; ok {
+			h.ConfigEvents++
+			health[key] = h
+		}
+	}
+
+	return health, nil
+}
+
+// updateLastDeployed records t as the app's last rollout if it is newer
+func updateLastDeployed(health map[string]AppHealth, key string, t time.Time) {
+	h, ok := health[key]
+	if !ok || !t.After(h.LastDeployed) {
+		return
+	}
+	h.LastDeployed = t
+	health[key] = h
+}
+
+// isFailing returns true if the pod failed or a container is stuck in an error state
+func isFailing(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodUnknown {
+		return true
+	}
+	statuses := append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if cs.State.Waiting != nil && failingWaitingReasons[cs.State.Waiting.Reason] {
+			return true
+		}
+	}
+	return false
+}
+
+// isConfigEvent returns true for Warning events caused by env or volume configuration,
+// e.g. a referenced ConfigMap, Secret or key that does not exist
+func isConfigEvent(ev *corev1.Event) bool {
+	if ev.Type != corev1.EventTypeWarning {
+		return false
+	}
+	if ev.Reason == "CreateContainerConfigError" {
+		return true
+	}
+	message := strings.ToLower(ev.Message)
+	return strings.Contains(message, "configmap") || strings.Contains(message, "secret") ||
+		strings.Contains(message, "couldn't find key")
+}
@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// StdinKubeconfig is the kubeconfig "path" of a kubeconfig read from stdin
+const StdinKubeconfig = "-"
+
+// maxKubeconfigSize bounds the kubeconfig read from stdin or a URL
+const maxKubeconfigSize = 4 << 20
+
+// kubeconfigFetchTimeout bounds the download of a kubeconfig
+const kubeconfigFetchTimeout = 30 * time.Second
+
+// memoryKubeconfigs holds the kubeconfigs read from stdin or a URL, by source
+// ("-" or the URL). The source stands for the path of a kubeconfig file, so
+// that switching contexts reuses the kubeconfig without temp files.
+var memoryKubeconfigs = struct {
+	sync.Mutex
+	configs map[string]*clientcmdapi.Config
+}{configs: make(map[string]*clientcmdapi.Config)}
+
+// ReadKubeconfig reads a kubeconfig from r (e.g. stdin) and keeps it in memory
+// under source, which can then be passed as the kubeconfig to NewClientForContext
+// and ListContexts
+func ReadKubeconfig(source string, r io.Reader) error {
+	data, err := io.ReadAll(io.LimitReader(r, maxKubeconfigSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read kubeconfig from %s: %w", kubeconfigSourceName(source), err)
+	}
+	if len(data) > maxKubeconfigSize {
+		return fmt.Errorf("kubeconfig from %s is larger than %d bytes", kubeconfigSourceName(source), maxKubeconfigSize)
+	}
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse kubeconfig from %s: %w", kubeconfigSourceName(source), err)
+	}
+	if len(config.Contexts) == 0 {
+		return fmt.Errorf("kubeconfig from %s has no contexts", kubeconfigSourceName(source))
+	}
+
+	memoryKubeconfigs.Lock()
+	memoryKubeconfigs.configs[source] = config
+	memoryKubeconfigs.Unlock()
+	return nil
+}
+
+// FetchKubeconfig downloads a kubeconfig, e.g. from the API handing out the
+// kubeconfigs of preview environments, and keeps it in memory under the URL.
+// The token, if any, is sent as a bearer token, which plain http only allows
+// to a loopback address.
+func FetchKubeconfig(ctx context.Context, rawURL, token string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid kubeconfig URL %q: an http(s) URL is expected", rawURL)
+	}
+	if u.Scheme == "http" && token != "" && !isLoopback(u.Hostname()) {
+		return fmt.Errorf("refusing to send the kubeconfig token over plain http to %s: use https", u.Host)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kubeconfigFetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid kubeconfig URL %q: %w", rawURL, err)
+	}
+	req.Header.Set("Accept", "application/yaml, application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch kubeconfig from %s: %w", u.Redacted(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch kubeconfig from %s: %s", u.Redacted(), resp.Status)
+	}
+	return ReadKubeconfig(rawURL, resp.Body)
+}
+
+// isLoopback returns true for localhost and the loopback addresses
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// memoryKubeconfig returns the kubeconfig kept in memory under source, if any
+func memoryKubeconfig(source string) *clientcmdapi.Config {
+	memoryKubeconfigs.Lock()
+	defer memoryKubeconfigs.Unlock()
+	return memoryKubeconfigs.configs[source]
+}
+
+// kubeconfigSourceName names the source of a kubeconfig in messages
+func kubeconfigSourceName(source string) string {
+	if source == StdinKubeconfig {
+		return "stdin"
+	}
+	if u, err := url.Parse(source); err == nil && strings.HasPrefix(u.Scheme, "http") {
+		return u.Redacted()
+	}
+	return source
+}
+
+// loadKubeconfig returns the client config of a kubeconfig, read from memory
+// when it came from stdin or a URL, and its raw config
+func loadKubeconfig(kubeconfig, contextName string) (clientcmd.ClientConfig, clientcmdapi.Config, error) {
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	if config := memoryKubeconfig(kubeconfig); config != nil {
+		return clientcmd.NewNonInteractiveClientConfig(*config, contextName, overrides, nil), *config, nil
+	}
+	if kubeconfig == StdinKubeconfig {
+		return nil, clientcmdapi.Config{}, fmt.Errorf("no kubeconfig was read from stdin")
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+	rawConfig, err := clientConfig.RawConfig()
+	if err != nil {
+		return nil, clientcmdapi.Config{}, err
+	}
+	return clientConfig, rawConfig, nil
+}
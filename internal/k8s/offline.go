@@ -0,0 +1,144 @@
+package k8s
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// OfflineContext is the context name shown for a cluster loaded from manifests
+const OfflineContext = "offline"
+
+// NewOfflineClient creates a client of an in-memory cluster holding the
+// manifests of a directory (*.yaml, *.yml and *.json, multi-document files
+// included), e.g. the e2e fixtures as a development sandbox. Built-in kinds are
+// served by the typed client; SealedSecrets, ScaledObjects and the custom
+// resources of the capabilities by the dynamic client. Other kinds are ignored.
+func NewOfflineClient(dir string) (*Client, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offline manifests: %w", err)
+	}
+
+	var typed, custom []runtime.Object
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || !slices.Contains([]string{".yaml", ".yml", ".json"}, ext) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		objs, err := decodeManifests(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			if _, ok := obj.(*unstructured.Unstructured); ok {
+				custom = append(custom, obj)
+			} else {
+				typed = append(typed, obj)
+			}
+		}
+	}
+
+	return newInMemoryClient(OfflineContext, typed, custom), nil
+}
+
+// offlineCustomKinds are the custom resources an offline cluster can hold
+var offlineCustomKinds = map[string]bool{
+	"SealedSecret": true,
+	"ScaledObject": true,
+}
+
+// decodeManifests reads the objects of a manifest file. Built-in kinds are
+// decoded into their typed objects, the custom kinds envtop reads into
+// unstructured ones.
+func decodeManifests(path string) ([]runtime.Object, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var objs []runtime.Object
+	reader := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		doc := &unstructured.Unstructured{}
+		if err := reader.Decode(&doc.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if len(doc.Object) == 0 {
+			continue
+		}
+
+		if !doc.IsList() {
+			obj, err := decodeObject(doc)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			if obj != nil {
+				objs = append(objs, obj)
+			}
+			continue
+		}
+		err := doc.EachListItem(func(item runtime.Object) error {
+			obj, err := decodeObject(item.(*unstructured.Unstructured))
+			if obj != nil {
+				objs = append(objs, obj)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+}
+
+// decodeObject converts a manifest object of a built-in kind into its typed
+// object and keeps the custom kinds envtop reads as they are. It returns nil
+// for the kinds envtop does not read.
+func decodeObject(u *unstructured.Unstructured) (runtime.Object, error) {
+	gvk := u.GroupVersionKind()
+	if offlineCustomKinds[gvk.Kind] || isCapabilityKind(gvk.Kind) {
+		return u, nil
+	}
+	obj, err := scheme.Scheme.New(gvk)
+	if err != nil {
+		return nil, nil
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		return nil, fmt.Errorf("failed to decode %s %s: %w", gvk.Kind, u.GetName(), err)
+	}
+	if secret, ok := obj.(*corev1.Secret); ok && len(secret.StringData) > 0 {
+		// The API server merges stringData into data on write
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte, len(secret.StringData))
+		}
+		for k, v := range secret.StringData {
+			secret.Data[k] = []byte(v)
+		}
+		secret.StringData = nil
+	}
+	return obj, nil
+}
+
+// isCapabilityKind returns true for the kind of a capability CRD
+func isCapabilityKind(kind string) bool {
+	for _, res := range capabilityResources {
+		if res.kind == kind {
+			return true
+		}
+	}
+	return false
+}
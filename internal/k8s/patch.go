@@ -0,0 +1,154 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// EnvValuePatch sets an inline env value of a container of an app
+type EnvValuePatch struct {
+	App       App
+	Container string
+	Init      bool // the container is an init container
+	Name      string
+	Value     string
+}
+
+// Body returns the strategic-merge patch of the workload. Env entries are
+// merged by name, so the other variables of the container are kept.
+func (p EnvValuePatch) Body() ([]byte, error) {
+	containersField := "containers"
+	if p.Init {
+		containersField = "initContainers"
+	}
+	template := map[string]interface{}{
+		"spec": map[string]interface{}{
+			containersField: []interface{}{map[string]interface{}{
+				"name": p.Container,
+				"env":  []interface{}{map[string]interface{}{"name": p.Name, "value": p.Value}},
+			}},
+		},
+	}
+
+	var patch map[string]interface{}
+	switch p.App.Kind {
+	case AppKindDeployment, AppKindStatefulSet:
+		patch = map[string]interface{}{"spec": map[string]interface{}{"template": template}}
+	case AppKindCronJob:
+		patch = map[string]interface{}{"spec": map[string]interface{}{
+			"jobTemplate": map[string]interface{}{"spec": map[string]interface{}{"template": template}},
+		}}
+	default:
+		return nil, fmt.Errorf("the pod template of a %s cannot be changed", p.App.Kind)
+	}
+	return json.Marshal(patch)
+}
+
+// ConfigMapValuePatch sets the value of a ConfigMap key
+type ConfigMapValuePatch struct {
+	Namespace string
+	Name      string
+	Key       string
+	Value     string
+}
+
+// Body returns the strategic-merge patch of the ConfigMap
+func (p ConfigMapValuePatch) Body() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"data": map[string]string{p.Key: p.Value}})
+}
+
+// SealedValuePatch sets one encrypted value of a SealedSecret
+type SealedValuePatch struct {
+	Namespace      string
+	Name           string
+	Key            string
+	EncryptedValue string
+}
+
+// Body returns the JSON merge patch of the SealedSecret. Custom resources do not
+// support strategic merge, but a merge patch keeps the other encryptedData keys.
+func (p SealedValuePatch) Body() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"encryptedData": map[string]string{p.Key: p.EncryptedValue}},
+	})
+}
+
+// PatchEnvValue applies an env value patch. With dryRun, the API server
+// validates the patch (including admission) without persisting it.
+func (c *Client) PatchEnvValue(ctx context.Context, p EnvValuePatch, dryRun bool) error {
+	body, err := p.Body()
+	if err != nil {
+		return err
+	}
+	opts := patchOptions(dryRun)
+	switch p.App.Kind {
+	case AppKindDeployment:
+		_, err = c.clientset.AppsV1().Deployments(p.App.Namespace).Patch(ctx, p.App.Name, types.StrategicMergePatchType, body, opts)
+	case AppKindStatefulSet:
+		_, err = c.clientset.AppsV1().StatefulSets(p.App.Namespace).Patch(ctx, p.App.Name, types.StrategicMergePatchType, body, opts)
+	case AppKindCronJob:
+		_, err = c.clientset.BatchV1().CronJobs(p.App.Namespace).Patch(ctx, p.App.Name, types.StrategicMergePatchType, body, opts)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to patch %s %s: %w", p.App.Kind, p.App.Name, err)
+	}
+	return nil
+}
+
+// PatchConfigMapValue applies a ConfigMap value patch, like PatchEnvValue
+func (c *Client) PatchConfigMapValue(ctx context.Context, p ConfigMapValuePatch, dryRun bool) error {
+	body, err := p.Body()
+	if err != nil {
+		return err
+	}
+	_, err = c.clientset.CoreV1().ConfigMaps(p.Namespace).Patch(ctx, p.Name, types.StrategicMergePatchType, body, patchOptions(dryRun))
+	if err != nil {
+		return fmt.Errorf("failed to patch ConfigMap %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// ApplySealedValue patches the SealedSecret with the sealed value. When the
+// SealedSecret does not exist, it is created from manifest (JSON encoded).
+func (c *Client) ApplySealedValue(ctx context.Context, p SealedValuePatch, manifest []byte) error {
+	resource := c.dynamicClient.Resource(SealedSecretGVR).Namespace(p.Namespace)
+
+	_, err := resource.Get(ctx, p.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(manifest); err != nil {
+			return fmt.Errorf("invalid SealedSecret manifest: %w", err)
+		}
+		if _, err := resource.Create(ctx, obj, metav1.CreateOptions{FieldManager: "envtop"}); err != nil {
+			return fmt.Errorf("failed to create SealedSecret %s: %w", p.Name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get SealedSecret %s: %w", p.Name, err)
+	}
+
+	body, err := p.Body()
+	if err != nil {
+		return err
+	}
+	if _, err := resource.Patch(ctx, p.Name, types.MergePatchType, body, patchOptions(false)); err != nil {
+		return fmt.Errorf("failed to patch SealedSecret %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// patchOptions identifies envtop as the field manager of the patched fields
+func patchOptions(dryRun bool) metav1.PatchOptions {
+	opts := metav1.PatchOptions{FieldManager: "envtop"}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	return opts
+}
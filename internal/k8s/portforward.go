@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForward is an open port-forward to a pod, like `kubectl port-forward`
+type PortForward struct {
+	LocalPort int
+
+	stopCh chan struct{}
+	mu     sync.Mutex
+	errOut *bytes.Buffer
+}
+
+// Close stops the port-forward
+func (p *PortForward) Close() {
+	close(p.stopCh)
+}
+
+// Errors returns the errors reported by the forwarder so far (e.g. connection refused)
+func (p *PortForward) Errors() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.errOut.String()
+}
+
+// Write collects forwarder error output
+func (p *PortForward) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.errOut.Write(b)
+}
+
+// StartPortForward forwards a random local port on 127.0.0.1 to the given pod port.
+// The caller must Close the returned PortForward.
+func (c *Client) StartPortForward(ctx context.Context, namespace, pod string, port int) (*PortForward, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(c.restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create port-forward transport: %w", err)
+	}
+
+	req := c.clientset.CoreV1().RESTClient().Post().
+		Namespace(namespace).
+		Resource("pods").
+		Name(pod).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	pf := &PortForward{
+		stopCh: make(chan struct{}),
+		errOut: &bytes.Buffer{},
+	}
+	readyCh := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", port)}, pf.stopCh, readyCh, nil, pf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create port-forward: %w", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return nil, fmt.Errorf("failed to port-forward to %s/%s:%d: %w", namespace, pod, port, err)
+	case <-ctx.Done():
+		close(pf.stopCh)
+		return nil, ctx.Err()
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		close(pf.stopCh)
+		return nil, fmt.Errorf("failed to get forwarded port: %v", err)
+	}
+	pf.LocalPort = int(ports[0].Local)
+	return pf, nil
+}
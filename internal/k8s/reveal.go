@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RevealEventReason is the reason of the Events recorded when a Secret value is revealed
+const RevealEventReason = "SecretRevealed"
+
+// WhoAmI returns the user the API server authenticates the client as, with a
+// SelfSubjectReview
+func (c *Client) WhoAmI(ctx context.Context) (string, error) {
+	review, err := c.clientset.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to review the user: %w", err)
+	}
+	return review.Status.UserInfo.Username, nil
+}
+
+// RecordReveal creates an Event on a Secret recording that the value of a
+// variable read from it was revealed (action being e.g. "revealed" or
+// "copied"), by whom and when, so that reveals show in `kubectl describe secret` and in the tooling
+// collecting cluster events. The user is the one the API server knows, or
+// "an unknown user" if it cannot tell: the local user name is not trustworthy.
+func (c *Client) RecordReveal(ctx context.Context, namespace, secretName, variable, action string) error {
+	secret, err := c.clientset.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get secret %s: %w", secretName, err)
+	}
+
+	who, err := c.WhoAmI(ctx)
+	if err != nil || who == "" {
+		who = "an unknown user"
+	}
+	host, _ := os.Hostname()
+	now := metav1.NewTime(time.Now())
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", secretName, now.UnixNano()),
+			Namespace: namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      "v1",
+			Kind:            "Secret",
+			Namespace:       namespace,
+			Name:            secretName,
+			UID:             secret.UID,
+			ResourceVersion: secret.ResourceVersion,
+		},
+		Reason:              RevealEventReason,
+		Message:             fmt.Sprintf("Value of %s %s by %s with envtop", variable, action, who),
+		Type:                corev1.EventTypeNormal,
+		Source:              corev1.EventSource{Component: "envtop", Host: host},
+		ReportingController: "envtop",
+		ReportingInstance:   host,
+		Action:              "Reveal",
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+	}
+	if _, err := c.clientset.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to record the reveal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// deploymentRevisionAnnotation holds the rollout number of a ReplicaSet
+const deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// Revision is a rollout of an app: a ReplicaSet of a Deployment or a
+// ControllerRevision of a StatefulSet, with the pod template it rolled out
+type Revision struct {
+	Number    int64
+	Name      string // ReplicaSet or ControllerRevision name
+	CreatedAt time.Time
+	Template  corev1.PodTemplateSpec
+}
+
+// ListRevisions returns the rollout history of a Deployment or StatefulSet, newest first.
+// The first entry is the current revision.
+func (c *Client) ListRevisions(ctx context.Context, app App) ([]Revision, error) {
+	var revisions []Revision
+	switch app.Kind {
+	case AppKindDeployment:
+		d, err := c.GetDeployment(ctx, app.Namespace, app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment %s: %w", app.Name, err)
+		}
+		selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector of deployment %s: %w", app.Name, err)
+		}
+		list, err := c.clientset.AppsV1().ReplicaSets(app.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list replicasets: %w", err)
+		}
+		for _, rs := range list.Items {
+			if !ownedBy(rs.OwnerReferences, d.UID) {
+				continue
+			}
+			number, err := strconv.ParseInt(rs.Annotations[deploymentRevisionAnnotation], 10, 64)
+			if err != nil {
+				continue
+			}
+			revisions = append(revisions, Revision{Number: number, Name: rs.Name, CreatedAt: rs.CreationTimestamp.Time, Template: rs.Spec.Template})
+		}
+
+	case AppKindStatefulSet:
+		s, err := c.GetStatefulSet(ctx, app.Namespace, app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get statefulset %s: %w", app.Name, err)
+		}
+		selector, err := metav1.LabelSelectorAsSelector(s.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector of statefulset %s: %w", app.Name, err)
+		}
+		list, err := c.clientset.AppsV1().ControllerRevisions(app.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list controllerrevisions: %w", err)
+		}
+		for _, cr := range list.Items {
+			if !ownedBy(cr.OwnerReferences, s.UID) {
+				continue
+			}
+			// The data is a patch replacing the pod template of the StatefulSet
+			var patch struct {
+				Spec struct {
+					Template corev1.PodTemplateSpec `json:"template"`
+				} `json:"spec"`
+			}
+			if err := json.Unmarshal(cr.Data.Raw, &patch); err != nil {
+				continue
+			}
+			revisions = append(revisions, Revision{Number: cr.Revision, Name: cr.Name, CreatedAt: cr.CreationTimestamp.Time, Template: patch.Spec.Template})
+		}
+
+	default:
+		return nil, fmt.Errorf("%s has no rollout history", app.Kind)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Number > revisions[j].Number
+	})
+	return revisions, nil
+}
+
+// ownedBy returns true if an owner reference points to the object with the given UID
+func ownedBy(owners []metav1.OwnerReference, uid types.UID) bool {
+	for _, owner := range owners {
+		if owner.UID == uid {
+			return true
+		}
+	}
+	return false
+}
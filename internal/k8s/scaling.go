@@ -0,0 +1,174 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ScaledObjectGVR is the GroupVersionResource for KEDA ScaledObjects
+var ScaledObjectGVR = schema.GroupVersionResource{
+	Group:    "keda.sh",
+	Version:  "v1alpha1",
+	Resource: "scaledobjects",
+}
+
+// Scaler is an HPA or KEDA ScaledObject targeting an app
+type Scaler struct {
+	Kind        string // "HorizontalPodAutoscaler" or "ScaledObject"
+	Name        string
+	MinReplicas int32
+	MaxReplicas int32
+	Triggers    []ScalerTrigger
+}
+
+// ScalerTrigger is a metric of an HPA or a trigger of a ScaledObject
+type ScalerTrigger struct {
+	Type string
+	// Metadata is the trigger configuration, e.g. queueName. KEDA reads keys
+	// ending in "FromEnv" from the env of the target container.
+	Metadata map[string]string
+}
+
+// FromEnv returns the metadata keys read from the env of the target container,
+// mapped to the variable names
+func (t ScalerTrigger) FromEnv() map[string]string {
+	refs := make(map[string]string)
+	for k, v := range t.Metadata {
+		if name, ok := strings.CutSuffix(k, "FromEnv"); ok && v != "" {
+			refs[name] = v
+		}
+	}
+	return refs
+}
+
+// ListAppScalers returns the HPAs and KEDA ScaledObjects targeting an app.
+// ScaledObjects are only listed with keda, when the KEDA capability was
+// discovered (see DiscoverCapability).
+func (c *Client) ListAppScalers(ctx context.Context, app App, keda bool) ([]Scaler, error) {
+	if app.Kind != AppKindDeployment && app.Kind != AppKindStatefulSet {
+		return nil, nil
+	}
+
+	hpas, err := c.clientset.AutoscalingV2().HorizontalPodAutoscalers(app.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list horizontalpodautoscalers: %w", err)
+	}
+	var scalers []Scaler
+	for _, hpa := range hpas.Items {
+		ref := hpa.Spec.ScaleTargetRef
+		// HPAs created by KEDA are shown through their ScaledObject
+		if ref.Kind != string(app.Kind) || ref.Name != app.Name || hpa.Labels["scaledobject.keda.sh/name"] != "" {
+			continue
+		}
+		scalers = append(scalers, hpaScaler(&hpa))
+	}
+
+	if keda {
+		list, err := c.dynamicClient.Resource(ScaledObjectGVR).Namespace(app.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list scaledobjects: %w", err)
+		}
+		for _, obj := range list.Items {
+			if scaler, ok := scaledObjectScaler(&obj, app); ok {
+				scalers = append(scalers, scaler)
+			}
+		}
+	}
+	return scalers, nil
+}
+
+func hpaScaler(hpa *autoscalingv2.HorizontalPodAutoscaler) Scaler {
+	scaler := Scaler{Kind: "HorizontalPodAutoscaler", Name: hpa.Name, MinReplicas: 1, MaxReplicas: hpa.Spec.MaxReplicas}
+	if hpa.Spec.MinReplicas != nil {
+		scaler.MinReplicas = *hpa.Spec.MinReplicas
+	}
+	for _, metric := range hpa.Spec.Metrics {
+		trigger := ScalerTrigger{Type: string(metric.Type), Metadata: make(map[string]string)}
+		switch {
+		case metric.Resource != nil:
+			trigger.Metadata["resource"] = string(metric.Resource.Name)
+			addTarget(trigger.Metadata, metric.Resource.Target)
+		case metric.ContainerResource != nil:
+			trigger.Metadata["container"] = metric.ContainerResource.Container
+			trigger.Metadata["resource"] = string(metric.ContainerResource.Name)
+			addTarget(trigger.Metadata, metric.ContainerResource.Target)
+		case metric.Pods != nil:
+			trigger.Metadata["metric"] = metric.Pods.Metric.Name
+			addTarget(trigger.Metadata, metric.Pods.Target)
+		case metric.Object != nil:
+			trigger.Metadata["metric"] = metric.Object.Metric.Name
+			trigger.Metadata["object"] = metric.Object.DescribedObject.Kind + "/" + metric.Object.DescribedObject.Name
+			addTarget(trigger.Metadata, metric.Object.Target)
+		case metric.External != nil:
+			trigger.Metadata["metric"] = metric.External.Metric.Name
+			addTarget(trigger.Metadata, metric.External.Target)
+		}
+		scaler.Triggers = append(scaler.Triggers, trigger)
+	}
+	return scaler
+}
+
+// addTarget records the target of an HPA metric
+func addTarget(metadata map[string]string, target autoscalingv2.MetricTarget) {
+	switch {
+	case target.AverageUtilization != nil:
+		metadata["averageUtilization"] = fmt.Sprintf("%d%%", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		metadata["averageValue"] = target.AverageValue.String()
+	case target.Value != nil:
+		metadata["value"] = target.Value.String()
+	}
+}
+
+// scaledObjectScaler reads a ScaledObject, returning false when it targets another app
+func scaledObjectScaler(obj *unstructured.Unstructured, app App) (Scaler, bool) {
+	kind, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "kind")
+	name, _, _ := unstructured.NestedString(obj.Object, "spec", "scaleTargetRef", "name")
+	if kind == "" {
+		kind = string(AppKindDeployment)
+	}
+	if kind != string(app.Kind) || name != app.Name {
+		return Scaler{}, false
+	}
+
+	scaler := Scaler{Kind: "ScaledObject", Name: obj.GetName(), MaxReplicas: 100}
+	if n, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "minReplicaCount"); ok {
+		scaler.MinReplicas = int32(n)
+	}
+	if n, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "maxReplicaCount"); ok {
+		scaler.MaxReplicas = int32(n)
+	}
+
+	triggers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "triggers")
+	for _, t := range triggers {
+		m, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		trigger := ScalerTrigger{Metadata: make(map[string]string)}
+		trigger.Type, _, _ = unstructured.NestedString(m, "type")
+		metadata, _, _ := unstructured.NestedStringMap(m, "metadata")
+		for k, v := range metadata {
+			trigger.Metadata[k] = v
+		}
+		scaler.Triggers = append(scaler.Triggers, trigger)
+	}
+	return scaler, true
+}
+
+// SortedMetadata returns the metadata of a trigger as key=value pairs in key order
+func (t ScalerTrigger) SortedMetadata() []string {
+	pairs := make([]string, 0, len(t.Metadata))
+	for k, v := range t.Metadata {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
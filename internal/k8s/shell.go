@@ -0,0 +1,14 @@
+package k8s
+
+import "strings"
+
+// ShellQuote quotes s for POSIX shells when it contains special characters,
+// for kubectl commands shown to be copied and pasted
+func ShellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:@=", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,91 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Snapshot returns the objects envtop reads from every namespace: namespaces,
+// workloads and their pods, ConfigMaps, Secrets and ServiceAccounts
+func (c *Client) Snapshot(ctx context.Context) ([]runtime.Object, error) {
+	var objs []runtime.Object
+	all := metav1.NamespaceAll
+	opts := metav1.ListOptions{}
+
+	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	for i := range namespaces.Items {
+		objs = append(objs, &namespaces.Items[i])
+	}
+
+	deployments, err := c.clientset.AppsV1().Deployments(all).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		objs = append(objs, &deployments.Items[i])
+	}
+
+	statefulsets, err := c.clientset.AppsV1().StatefulSets(all).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for i := range statefulsets.Items {
+		objs = append(objs, &statefulsets.Items[i])
+	}
+
+	cronjobs, err := c.clientset.BatchV1().CronJobs(all).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+	for i := range cronjobs.Items {
+		objs = append(objs, &cronjobs.Items[i])
+	}
+
+	jobs, err := c.clientset.BatchV1().Jobs(all).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for i := range jobs.Items {
+		objs = append(objs, &jobs.Items[i])
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(all).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for i := range pods.Items {
+		objs = append(objs, &pods.Items[i])
+	}
+
+	configMaps, err := c.clientset.CoreV1().ConfigMaps(all).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list configmaps: %w", err)
+	}
+	for i := range configMaps.Items {
+		objs = append(objs, &configMaps.Items[i])
+	}
+
+	secrets, err := c.clientset.CoreV1().Secrets(all).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	for i := range secrets.Items {
+		objs = append(objs, &secrets.Items[i])
+	}
+
+	serviceAccounts, err := c.clientset.CoreV1().ServiceAccounts(all).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list serviceaccounts: %w", err)
+	}
+	for i := range serviceAccounts.Items {
+		objs = append(objs, &serviceAccounts.Items[i])
+	}
+
+	return objs, nil
+}
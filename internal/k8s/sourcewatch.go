@@ -0,0 +1,144 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// SourceRef names a ConfigMap or Secret (Kind being WatchKindConfigMap or WatchKindSecret)
+type SourceRef struct {
+	Kind string
+	Name string
+}
+
+// Types of SourceEvent
+const (
+	SourceAdded   = "added"
+	SourceUpdated = "updated"
+	SourceDeleted = "deleted"
+)
+
+// SourceEvent reports a change to a watched ConfigMap or Secret
+type SourceEvent struct {
+	SourceRef
+	Type string // SourceAdded, SourceUpdated or SourceDeleted
+	// Time and Manager tell when and by which field manager (e.g. "helm",
+	// "kubectl-edit") the object was last changed, from its managedFields. Time
+	// is the arrival of the event when the object has none.
+	Time    time.Time
+	Manager string
+}
+
+// WatchSources streams the changes to a few ConfigMaps and Secrets of a
+// namespace until ctx is done, then closes the channel. Each object is watched
+// on its own with a metadata.name field selector, which RBAC rules restricted
+// to resourceNames allow, and which is much lighter than watching every Secret
+// of the namespace. Only changes made after the call are reported; objects that
+// may not be watched are skipped silently.
+func (c *Client) WatchSources(ctx context.Context, namespace string, refs []SourceRef) <-chan SourceEvent {
+	events := make(chan SourceEvent, 16)
+	var wg sync.WaitGroup
+	for _, ref := range refs {
+		var ri dynamic.ResourceInterface
+		for _, res := range watchedResources {
+			if res.kind == ref.Kind {
+				ri = c.dynamicClient.Resource(res.gvr).Namespace(namespace)
+			}
+		}
+		if ri == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			watchSource(ctx, ri, ref, events)
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+	return events
+}
+
+// watchSource watches one object, re-establishing the watch when it ends
+func watchSource(ctx context.Context, ri dynamic.ResourceInterface, ref SourceRef, events chan<- SourceEvent) {
+	selector := fields.OneTermEqualSelector("metadata.name", ref.Name).String()
+	resourceVersion := ""
+	for ctx.Err() == nil {
+		if resourceVersion == "" {
+			list, err := ri.List(ctx, metav1.ListOptions{FieldSelector: selector})
+			if err != nil {
+				if apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err) || !sleepCtx(ctx, watchRetryDelay) {
+					return
+				}
+				continue
+			}
+			resourceVersion = list.GetResourceVersion()
+		}
+
+		w, err := ri.Watch(ctx, metav1.ListOptions{FieldSelector: selector, ResourceVersion: resourceVersion, AllowWatchBookmarks: true})
+		if err != nil {
+			if apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err) || !sleepCtx(ctx, watchRetryDelay) {
+				return
+			}
+			continue
+		}
+
+		for ev := range w.ResultChan() {
+			if ev.Type == watch.Error {
+				resourceVersion = ""
+				break
+			}
+			obj, err := meta.Accessor(ev.Object)
+			if err != nil {
+				continue
+			}
+			resourceVersion = obj.GetResourceVersion()
+
+			event := SourceEvent{SourceRef: ref, Time: time.Now()}
+			switch ev.Type {
+			case watch.Added:
+				event.Type = SourceAdded
+			case watch.Modified:
+				event.Type = SourceUpdated
+			case watch.Deleted:
+				event.Type = SourceDeleted
+			default:
+				continue
+			}
+			if changedAt, manager := lastChange(obj); !changedAt.IsZero() && event.Type != SourceDeleted {
+				event.Time, event.Manager = changedAt, manager
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				w.Stop()
+				return
+			}
+		}
+		w.Stop()
+	}
+}
+
+// lastChange returns the time and the field manager of the latest managedFields entry
+func lastChange(obj metav1.Object) (time.Time, string) {
+	var at time.Time
+	manager := ""
+	for _, f := range obj.GetManagedFields() {
+		if f.Time != nil && f.Time.After(at) {
+			at, manager = f.Time.Time, f.Manager
+		}
+	}
+	return at, manager
+}
@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// ParseServiceAccount parses a "namespace/name" service account reference
+func ParseServiceAccount(ref string) (namespace, name string, err error) {
+	namespace, name, ok := strings.Cut(ref, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid service account %q: expected namespace/name", ref)
+	}
+	return namespace, name, nil
+}
+
+// WithServiceAccountToken mints a short-lived token of a service account through
+// the TokenRequest API and returns a client that authenticates with it instead
+// of the user's credentials, which are only used for the request. The token
+// cannot be refreshed: the session loses access when it expires.
+func (c *Client) WithServiceAccountToken(ctx context.Context, namespace, name string, ttl time.Duration) (*Client, time.Time, error) {
+	seconds := int64(ttl.Seconds())
+	request := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &seconds},
+	}
+	token, err := c.clientset.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, name, request, metav1.CreateOptions{})
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to request a token for service account %s/%s: %w", namespace, name, err)
+	}
+
+	// Keep the server and its CA, drop every user credential
+	config := rest.AnonymousClientConfig(c.restConfig)
+	config.BearerToken = token.Status.Token
+
+	client, err := NewClientForConfig(config, c.context)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	client.kubeconfig = c.kubeconfig
+	return client, token.Status.ExpirationTimestamp.Time, nil
+}
@@ -1,45 +1,112 @@
 package k8s
 
+import "time"
+
+// Namespace represents a namespace with the metadata envtop displays
+type Namespace struct {
+	Name      string
+	Labels    map[string]string
+	CreatedAt time.Time
+}
+
+// NamespaceLimits lists the ResourceQuotas and LimitRanges of a namespace
+type NamespaceLimits struct {
+	ResourceQuotas []string
+	LimitRanges    []string
+}
+
 // AppKind represents the type of Kubernetes workload
 type AppKind string
 
 const (
 	AppKindDeployment  AppKind = "Deployment"
 	AppKindStatefulSet AppKind = "StatefulSet"
+	AppKindCronJob     AppKind = "CronJob"
+	AppKindJob         AppKind = "Job"
 )
 
-// App represents a Kubernetes workload (Deployment/StatefulSet)
+// App represents a Kubernetes workload (Deployment/StatefulSet/CronJob/Job)
 type App struct {
 	Name      string
 	Namespace string
 	Kind      AppKind
 }
 
+// Pod represents a running instance of an app with its placement
+type Pod struct {
+	Name      string
+	Namespace string
+	NodeName  string
+	Zone      string // topology.kubernetes.io/zone label of the node
+	Phase     string
+	StartTime time.Time // zero until the kubelet has started the pod
+}
+
 // EnvSourceKind represents the source type of an environment variable
 type EnvSourceKind string
 
 const (
-	EnvSourceConfigMap     EnvSourceKind = "ConfigMap"
-	EnvSourceSecret        EnvSourceKind = "Secret"
-	EnvSourceSealedSecret  EnvSourceKind = "SealedSecret"
-	EnvSourceFieldRef      EnvSourceKind = "FieldRef"
-	EnvSourceResourceRef   EnvSourceKind = "ResourceRef"
-	EnvSourceInline        EnvSourceKind = "Inline"
+	EnvSourceConfigMap    EnvSourceKind = "ConfigMap"
+	EnvSourceSecret       EnvSourceKind = "Secret"
+	EnvSourceSealedSecret EnvSourceKind = "SealedSecret"
+	EnvSourceFieldRef     EnvSourceKind = "FieldRef"
+	EnvSourceResourceRef  EnvSourceKind = "ResourceRef"
+	EnvSourceInline       EnvSourceKind = "Inline"
+	EnvSourceVault        EnvSourceKind = "Vault" // rendered by the Vault agent injector
 )
 
 // EnvVar represents an environment variable with its source information
 type EnvVar struct {
-	Name       string
-	Value      string        // actual value for ConfigMap/Inline, hash for Secret/SealedSecret
-	RawValue   []byte        // raw value (base64 decoded) for secrets
-	SourceName string        // name of the ConfigMap/Secret
-	SourceKind EnvSourceKind
-	IsSealed   bool
-	ValueLen   int
-	Hash       string        // SHA256 hash prefix for secrets
+	Name        string
+	Value       string // actual value for ConfigMap/Inline, hash for Secret/SealedSecret
+	RawValue    []byte // raw value (base64 decoded) for secrets
+	SourceName  string // name of the ConfigMap/Secret
+	SourceKind  EnvSourceKind
+	Container   string // container defining the variable
+	IsSealed    bool
+	Redacted    bool   // masked by a redaction rule although not sourced from a Secret
+	Unresolved  bool   // value only known at runtime: Value is a "fieldRef: ..." placeholder
+	Detail      string // how a computed value was derived, e.g. "limits.cpu 1500m / 1"
+	Terminating bool   // the ConfigMap/Secret has a deletionTimestamp: it is about to disappear
+	Forbidden   bool   // the user may not read the ConfigMap/Secret: Value is "(forbidden)"
+	ValueLen    int
+	Hash        string // SHA256 hash prefix for secrets
 }
 
 // IsSecret returns true if the env var comes from a Secret or SealedSecret
 func (e *EnvVar) IsSecret() bool {
 	return e.SourceKind == EnvSourceSecret || e.SourceKind == EnvSourceSealedSecret
 }
+
+// IsMasked returns true if the value must not be displayed or exported in clear.
+// A forbidden variable has no value to hide.
+func (e *EnvVar) IsMasked() bool {
+	return (e.IsSecret() || e.Redacted) && !e.Forbidden
+}
+
+// AutomountSourceUnknown is the AutomountSource of a pod whose ServiceAccount
+// could not be read: AutomountToken is then only the Kubernetes default
+const AutomountSourceUnknown = "unknown (ServiceAccount unreadable)"
+
+// PodSecurity holds the settings that decide which credentials exist inside a pod
+type PodSecurity struct {
+	ServiceAccountName string
+	// AutomountToken is the effective automountServiceAccountToken value
+	AutomountToken bool
+	// AutomountSource tells where AutomountToken comes from: "pod", "serviceaccount",
+	// "default", or AutomountSourceUnknown when the ServiceAccount could not be read
+	AutomountSource string
+	RunAsNonRoot    *bool
+	RunAsUser       *int64
+	Containers      []ContainerSecurity
+}
+
+// ContainerSecurity holds the securityContext settings of a container
+type ContainerSecurity struct {
+	Name                     string
+	Privileged               bool
+	AllowPrivilegeEscalation *bool
+	ReadOnlyRootFilesystem   bool
+	RunAsNonRoot             *bool
+	AddedCapabilities        []string
+}
@@ -0,0 +1,160 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// Kinds of objects reported by Watch
+const (
+	WatchKindNamespace = "Namespace"
+	WatchKindConfigMap = "ConfigMap"
+	WatchKindSecret    = "Secret"
+)
+
+// watchRetryDelay is the wait before re-establishing a failed watch
+const watchRetryDelay = 5 * time.Second
+
+// WatchEvent reports a change to a watched object
+type WatchEvent struct {
+	Kind            string // an AppKind or one of the WatchKind constants
+	Namespace       string
+	Name            string
+	ResourceVersion string
+
+	// Resync reports that changes to objects of Kind may have been missed (the watch
+	// had to start over); Stopped that Kind is no longer watched at all. Name is empty.
+	Resync  bool
+	Stopped bool
+}
+
+// watchedResource is a resource watched by Watch
+type watchedResource struct {
+	kind       string
+	gvr        schema.GroupVersionResource
+	namespaced bool
+}
+
+var watchedResources = []watchedResource{
+	{WatchKindNamespace, schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, false},
+	{string(AppKindDeployment), schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, true},
+	{string(AppKindStatefulSet), schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, true},
+	{string(AppKindCronJob), schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, true},
+	{string(AppKindJob), schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, true},
+	{WatchKindConfigMap, schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, true},
+	{WatchKindSecret, schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, true},
+}
+
+// Watch streams changes to namespaces and to the workloads, ConfigMaps and Secrets
+// of a namespace until ctx is done, then closes the channel. Only changes made after
+// the call are reported.
+// Resources that may not be watched (e.g. missing RBAC) are skipped silently.
+func (c *Client) Watch(ctx context.Context, namespace string) <-chan WatchEvent {
+	events := make(chan WatchEvent, 64)
+	var wg sync.WaitGroup
+	for _, res := range watchedResources {
+		var ri dynamic.ResourceInterface = c.dynamicClient.Resource(res.gvr)
+		if res.namespaced {
+			ri = c.dynamicClient.Resource(res.gvr).Namespace(namespace)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			watchResource(ctx, ri, res.kind, events)
+		}()
+	}
+
+	// Close the stream once every watch has ended, so readers do not block forever
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+	return events
+}
+
+// watchResource watches one resource, re-establishing the watch when it ends.
+// The watch starts at the resourceVersion of a list, so existing objects are not reported.
+func watchResource(ctx context.Context, ri dynamic.ResourceInterface, kind string, events chan<- WatchEvent) {
+	send := func(ev WatchEvent) bool {
+		select {
+		case events <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	// stop reports that kind is no longer watched, unless the watch was cancelled
+	stop := func() {
+		if ctx.Err() == nil {
+			send(WatchEvent{Kind: kind, Stopped: true})
+		}
+	}
+
+	resourceVersion := ""
+	listed := false
+	for ctx.Err() == nil {
+		if resourceVersion == "" {
+			list, err := ri.List(ctx, metav1.ListOptions{Limit: 1})
+			if err != nil {
+				if apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err) || !sleepCtx(ctx, watchRetryDelay) {
+					stop()
+					return
+				}
+				continue
+			}
+			resourceVersion = list.GetResourceVersion()
+			if listed && !send(WatchEvent{Kind: kind, Resync: true}) {
+				return
+			}
+			listed = true
+		}
+
+		w, err := ri.Watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersion, AllowWatchBookmarks: true})
+		if err != nil {
+			if apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err) || !sleepCtx(ctx, watchRetryDelay) {
+				stop()
+				return
+			}
+			continue
+		}
+
+		for ev := range w.ResultChan() {
+			if ev.Type == watch.Error {
+				// Typically 410 Gone: the resourceVersion is too old, list again
+				resourceVersion = ""
+				break
+			}
+			obj, err := meta.Accessor(ev.Object)
+			if err != nil {
+				continue
+			}
+			resourceVersion = obj.GetResourceVersion()
+			if ev.Type == watch.Bookmark {
+				continue
+			}
+			if !send(WatchEvent{Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), ResourceVersion: resourceVersion}) {
+				w.Stop()
+				return
+			}
+		}
+		w.Stop()
+	}
+}
+
+// sleepCtx waits for d and returns false if ctx is done first
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
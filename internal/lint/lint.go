@@ -0,0 +1,298 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/netcheck"
+	"github.com/ginbear/k8s-envtop/internal/policy"
+)
+
+// Rule names
+const (
+	RuleDanglingService   = "dangling-service"
+	RuleTerminatingSource = "terminating-source"
+	RuleForbiddenSource   = "forbidden-source"
+	RuleEnvSize           = "env-size"
+	RuleValuePolicy       = "value-policy"
+	RuleRego              = "rego"
+)
+
+// Finding is a problem detected in an app's env
+type Finding struct {
+	App      string          `json:"app"`
+	Name     string          `json:"name"`
+	Rule     string          `json:"rule"`
+	Severity policy.Severity `json:"severity"`
+	Message  string          `json:"message"`
+}
+
+// Linter runs lint rules against the resolved env of apps
+type Linter struct {
+	client   *k8s.Client
+	resolver *env.Resolver
+
+	policies *policy.Set
+	rego     *policy.RegoHook
+	tierOf   func(namespace string, labels map[string]string) string
+	budget   int // env size budget per container in bytes, 0 to skip the check
+
+	namespaces map[string]map[string]string // namespace -> labels, loaded lazily
+	services   map[string]map[string]bool   // namespace -> service names, loaded lazily
+}
+
+// NewLinter creates a new Linter resolving env vars with the given resolver
+func NewLinter(client *k8s.Client, resolver *env.Resolver) *Linter {
+	return &Linter{
+		client:   client,
+		resolver: resolver,
+		services: make(map[string]map[string]bool),
+	}
+}
+
+// SetPolicies enables value policies. tierOf resolves the tier of a namespace for
+// policies restricted to tiers.
+func (l *Linter) SetPolicies(policies *policy.Set, tierOf func(namespace string, labels map[string]string) string) {
+	l.policies = policies
+	l.tierOf = tierOf
+}
+
+// SetEnvSizeBudget enables the env size check of every container
+func (l *Linter) SetEnvSizeBudget(bytes int) {
+	l.budget = bytes
+}
+
+// SetRegoHook enables evaluation of Rego policies; denials become findings
+func (l *Linter) SetRegoHook(hook *policy.RegoHook) {
+	l.rego = hook
+}
+
+// LintApps resolves the env of every app and returns the findings sorted by app and name
+func (l *Linter) LintApps(ctx context.Context, apps []k8s.App) ([]Finding, error) {
+	var findings []Finding
+	for _, app := range apps {
+		containerEnv, err := l.resolver.ResolveAppContainerEnvVars(ctx, app)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", app.Name, err)
+		}
+		findings = append(findings, l.checkEnvSize(app, containerEnv)...)
+		envVars := env.MergeContainers(containerEnv)
+		for _, ev := range envVars {
+			if ev.Forbidden {
+				// Nothing to check without the value
+				findings = append(findings, Finding{
+					App:      app.Name,
+					Name:     ev.Name,
+					Rule:     RuleForbiddenSource,
+					Severity: policy.SeverityWarning,
+					Message:  fmt.Sprintf("no permission to get %s %s: not linted", ev.SourceKind, ev.SourceName),
+				})
+				continue
+			}
+			finding, err := l.checkServiceRef(ctx, app, ev)
+			if err != nil {
+				return nil, err
+			}
+			if finding != nil {
+				findings = append(findings, *finding)
+			}
+			if ev.Terminating {
+				findings = append(findings, Finding{
+					App:      app.Name,
+					Name:     ev.Name,
+					Rule:     RuleTerminatingSource,
+					Severity: policy.SeverityWarning,
+					Message:  fmt.Sprintf("%s %s is marked for deletion (deletionTimestamp set)", ev.SourceKind, ev.SourceName),
+				})
+			}
+		}
+
+		policyFindings, err := l.checkPolicies(ctx, app, envVars)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, policyFindings...)
+
+		regoFindings, err := l.checkRego(ctx, app, envVars)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, regoFindings...)
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].App != findings[j].App {
+			return findings[i].App < findings[j].App
+		}
+		return findings[i].Name < findings[j].Name
+	})
+	return findings, nil
+}
+
+// checkEnvSize flags containers whose env exceeds the size budget, and
+// variables too long for the kernel to pass to a process at all
+func (l *Linter) checkEnvSize(app k8s.App, containerEnv []k8s.EnvVar) []Finding {
+	if l.budget <= 0 {
+		return nil
+	}
+	var findings []Finding
+	for _, size := range env.ContainerSizes(containerEnv) {
+		if size.LargestBytes > env.MaxValueSize {
+			findings = append(findings, Finding{
+				App:      app.Name,
+				Name:     size.Largest,
+				Rule:     RuleEnvSize,
+				Severity: policy.SeverityError,
+				Message:  fmt.Sprintf("%s in container %s exceeds the %s a single variable may take: the container will fail to start", env.FormatSize(size.LargestBytes), size.Container, env.FormatSize(env.MaxValueSize)),
+			})
+		}
+		if size.Bytes > l.budget {
+			findings = append(findings, Finding{
+				App:      app.Name,
+				Name:     size.Container,
+				Rule:     RuleEnvSize,
+				Severity: policy.SeverityWarning,
+				Message:  fmt.Sprintf("env of container %s is %s (%d variables), over the budget of %s", size.Container, env.FormatSize(size.Bytes), size.Vars, env.FormatSize(l.budget)),
+			})
+		}
+	}
+	return findings
+}
+
+// checkServiceRef flags values pointing at in-cluster Services that do not exist.
+// Two-label hosts in an unknown namespace (e.g. example.com) are assumed to be external.
+func (l *Linter) checkServiceRef(ctx context.Context, app k8s.App, ev k8s.EnvVar) (*Finding, error) {
+	value := ev.Value
+	if ev.IsMasked() {
+		value = string(ev.RawValue)
+	}
+	ep, ok := netcheck.ParseEndpoint(value, app.Namespace)
+	if !ok {
+		return nil, nil
+	}
+
+	if err := l.loadNamespaces(ctx); err != nil {
+		return nil, err
+	}
+	if _, ok := l.namespaces[ep.Namespace]; !ok {
+		if !ep.Qualified {
+			return nil, nil
+		}
+		return &Finding{
+			App:      app.Name,
+			Name:     ev.Name,
+			Rule:     RuleDanglingService,
+			Severity: policy.SeverityError,
+			Message:  fmt.Sprintf("%s: namespace %s does not exist", ep, ep.Namespace),
+		}, nil
+	}
+
+	services, err := l.serviceNames(ctx, ep.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if services[ep.Service] {
+		return nil, nil
+	}
+	return &Finding{
+		App:      app.Name,
+		Name:     ev.Name,
+		Rule:     RuleDanglingService,
+		Severity: policy.SeverityError,
+		Message:  fmt.Sprintf("%s: service %s/%s not found", ep, ep.Namespace, ep.Service),
+	}, nil
+}
+
+// checkPolicies evaluates the value policies against an app's env
+func (l *Linter) checkPolicies(ctx context.Context, app k8s.App, envVars []k8s.EnvVar) ([]Finding, error) {
+	if l.policies.Len() == 0 {
+		return nil, nil
+	}
+	if err := l.loadNamespaces(ctx); err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, v := range l.policies.Evaluate(app.Namespace, l.namespaceTier(app.Namespace), envVars) {
+		findings = append(findings, Finding{
+			App:      app.Name,
+			Name:     v.Name,
+			Rule:     RuleValuePolicy,
+			Severity: v.Severity,
+			Message:  fmt.Sprintf("%s: %s", v.Policy, v.Message),
+		})
+	}
+	return findings, nil
+}
+
+// checkRego evaluates the Rego policies against an app's env
+func (l *Linter) checkRego(ctx context.Context, app k8s.App, envVars []k8s.EnvVar) ([]Finding, error) {
+	if l.rego == nil || len(l.rego.Policies) == 0 {
+		return nil, nil
+	}
+	if err := l.loadNamespaces(ctx); err != nil {
+		return nil, err
+	}
+
+	input := policy.NewRegoInput(app.Namespace, l.namespaceTier(app.Namespace), app, envVars)
+	violations, err := l.rego.Evaluate(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("rego evaluation of %s failed: %w", app.Name, err)
+	}
+
+	findings := make([]Finding, 0, len(violations))
+	for _, v := range violations {
+		findings = append(findings, Finding{
+			App:      app.Name,
+			Name:     v.Name,
+			Rule:     RuleRego,
+			Severity: v.Severity,
+			Message:  fmt.Sprintf("%s: %s", v.Policy, v.Message),
+		})
+	}
+	return findings, nil
+}
+
+// namespaceTier returns the tier of a loaded namespace ("" if unknown)
+func (l *Linter) namespaceTier(namespace string) string {
+	if l.tierOf == nil {
+		return ""
+	}
+	return l.tierOf(namespace, l.namespaces[namespace])
+}
+
+// loadNamespaces caches the namespace names and labels
+func (l *Linter) loadNamespaces(ctx context.Context) error {
+	if l.namespaces != nil {
+		return nil
+	}
+	namespaces, err := l.client.ListNamespaceDetails(ctx)
+	if err != nil {
+		return err
+	}
+	l.namespaces = make(map[string]map[string]string, len(namespaces))
+	for _, ns := range namespaces {
+		l.namespaces[ns.Name] = ns.Labels
+	}
+	return nil
+}
+
+// serviceNames returns the cached Service names of a namespace
+func (l *Linter) serviceNames(ctx context.Context, namespace string) (map[string]bool, error) {
+	if services, ok := l.services[namespace]; ok {
+		return services, nil
+	}
+	names, err := l.client.ListServiceNames(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	services := make(map[string]bool, len(names))
+	for _, name := range names {
+		services[name] = true
+	}
+	l.services[namespace] = services
+	return services, nil
+}
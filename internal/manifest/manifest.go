@@ -0,0 +1,218 @@
+// Package manifest reads Kubernetes manifests from local files (multi-document
+// YAML or JSON, List objects, rendered charts) into the objects envtop resolves
+// env from, normalized to what the API server would store
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// App is a workload of the manifests with its pod template
+type App struct {
+	App      k8s.App
+	Template corev1.PodTemplateSpec
+}
+
+// Set is the part of a set of manifests envtop reads: the workloads and the
+// ConfigMaps and Secrets they may reference, by name
+type Set struct {
+	Apps       []App
+	ConfigMaps map[string]*corev1.ConfigMap
+	Secrets    map[string]*corev1.Secret
+}
+
+// newSet returns an empty Set
+func newSet() *Set {
+	return &Set{
+		ConfigMaps: make(map[string]*corev1.ConfigMap),
+		Secrets:    make(map[string]*corev1.Secret),
+	}
+}
+
+// Load reads manifest files, or the .yaml, .yml and .json files of directories
+// (not recursively), with "-" for stdin. Objects without a namespace are placed
+// in the given one.
+func Load(paths []string, namespace string) (*Set, error) {
+	set := newSet()
+	for _, p := range paths {
+		files := []string{p}
+		if info, err := os.Stat(p); err == nil && info.IsDir() {
+			entries, err := os.ReadDir(p)
+			if err != nil {
+				return nil, err
+			}
+			files = files[:0]
+			for _, e := range entries {
+				switch filepath.Ext(e.Name()) {
+				case ".yaml", ".yml", ".json":
+					files = append(files, filepath.Join(p, e.Name()))
+				}
+			}
+			sort.Strings(files)
+		}
+		for _, f := range files {
+			if err := set.readFile(f, namespace); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return set, nil
+}
+
+// readFile adds the objects of one file
+func (s *Set) readFile(path, namespace string) error {
+	if path == "-" {
+		return s.read(os.Stdin, namespace)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := s.read(f, namespace); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Parse reads multi-document YAML or JSON. Objects without a namespace are
+// placed in the given one.
+func Parse(r io.Reader, namespace string) (*Set, error) {
+	set := newSet()
+	if err := set.read(r, namespace); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// read adds the objects of a multi-document stream
+func (s *Set) read(r io.Reader, namespace string) error {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read manifests: %w", err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		obj, err := decode(doc)
+		if err != nil {
+			return err
+		}
+		if err := s.add(obj, namespace); err != nil {
+			return err
+		}
+	}
+}
+
+// decode reads a document into a generic object. Numbers are kept as written
+// so that unquoted values are not rounded when they are turned into strings.
+func decode(doc []byte) (map[string]interface{}, error) {
+	data, err := yaml.YAMLToJSON(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	var obj map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return obj, nil
+}
+
+// add normalizes a generic object and adds it, expanding List objects and
+// skipping kinds envtop does not read
+func (s *Set) add(obj map[string]interface{}, namespace string) error {
+	kind, _ := obj["kind"].(string)
+	if kind == "List" || strings.HasSuffix(kind, "List") && obj["items"] != nil {
+		items, _ := obj["items"].([]interface{})
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				if err := s.add(m, namespace); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	var typed interface{}
+	switch kind {
+	case "Deployment":
+		typed = &appsv1.Deployment{}
+	case "StatefulSet":
+		typed = &appsv1.StatefulSet{}
+	case "CronJob":
+		typed = &batchv1.CronJob{}
+	case "Job":
+		typed = &batchv1.Job{}
+	case "ConfigMap":
+		typed = &corev1.ConfigMap{}
+	case "Secret":
+		typed = &corev1.Secret{}
+	default:
+		return nil
+	}
+	normalize(kind, obj)
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, typed); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", kind, err)
+	}
+
+	ns := func(objectNamespace string) string {
+		if objectNamespace == "" {
+			return namespace
+		}
+		return objectNamespace
+	}
+	switch o := typed.(type) {
+	case *appsv1.Deployment:
+		s.addApp(k8s.AppKindDeployment, o.ObjectMeta, ns(o.Namespace), o.Spec.Template)
+	case *appsv1.StatefulSet:
+		s.addApp(k8s.AppKindStatefulSet, o.ObjectMeta, ns(o.Namespace), o.Spec.Template)
+	case *batchv1.CronJob:
+		s.addApp(k8s.AppKindCronJob, o.ObjectMeta, ns(o.Namespace), o.Spec.JobTemplate.Spec.Template)
+	case *batchv1.Job:
+		s.addApp(k8s.AppKindJob, o.ObjectMeta, ns(o.Namespace), o.Spec.Template)
+	case *corev1.ConfigMap:
+		o.Namespace = ns(o.Namespace)
+		s.ConfigMaps[o.Name] = o
+	case *corev1.Secret:
+		mergeStringData(o)
+		o.Namespace = ns(o.Namespace)
+		s.Secrets[o.Name] = o
+	}
+	return nil
+}
+
+func (s *Set) addApp(kind k8s.AppKind, meta metav1.ObjectMeta, namespace string, template corev1.PodTemplateSpec) {
+	s.Apps = append(s.Apps, App{
+		App:      k8s.App{Name: meta.Name, Namespace: namespace, Kind: kind},
+		Template: template,
+	})
+}
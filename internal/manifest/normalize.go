@@ -0,0 +1,116 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The API server never returns a manifest as it was written. Comparing a local
+// manifest with the cluster without the normalization below reports drift that
+// is not there:
+//
+//   - Secret stringData is merged into data on write (stringData wins for a key
+//     in both) and is never returned
+//   - base64 in data may be wrapped over several lines in YAML
+//   - unquoted scalars (PORT: 8080, DEBUG: true) in ConfigMap data, Secret
+//     stringData and env values are read by YAML as numbers and booleans, where
+//     the cluster has strings (kubectl and Helm users usually quote them after
+//     the first rejected apply; rendered charts often do not)
+//   - null maps and lists (data: ~, env: null) are the same as absent ones
+
+// normalize rewrites a generic object of the given kind in place
+func normalize(kind string, obj map[string]interface{}) {
+	switch kind {
+	case "ConfigMap":
+		stringifyValues(obj, "data")
+	case "Secret":
+		stringifyValues(obj, "stringData")
+		if data, ok := obj["data"].(map[string]interface{}); ok {
+			for k, v := range data {
+				if s, ok := v.(string); ok {
+					data[k] = strings.Join(strings.Fields(s), "")
+				}
+			}
+		}
+	case "Deployment", "StatefulSet", "Job":
+		normalizePodSpec(dig(obj, "spec", "template", "spec"))
+	case "CronJob":
+		normalizePodSpec(dig(obj, "spec", "jobTemplate", "spec", "template", "spec"))
+	}
+}
+
+// normalizePodSpec turns the env values of every container into strings
+func normalizePodSpec(spec map[string]interface{}) {
+	if spec == nil {
+		return
+	}
+	for _, field := range []string{"containers", "initContainers"} {
+		containers, _ := spec[field].([]interface{})
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			envs, _ := container["env"].([]interface{})
+			for _, e := range envs {
+				if env, ok := e.(map[string]interface{}); ok {
+					if v, ok := env["value"]; ok {
+						env["value"] = scalarString(v)
+					}
+				}
+			}
+		}
+	}
+}
+
+// stringifyValues turns the values of a map field into strings, dropping a null map
+func stringifyValues(obj map[string]interface{}, field string) {
+	m, ok := obj[field].(map[string]interface{})
+	if !ok {
+		delete(obj, field)
+		return
+	}
+	for k, v := range m {
+		m[k] = scalarString(v)
+	}
+}
+
+// scalarString renders a YAML scalar as the string the author meant
+func scalarString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// dig returns a nested map field, nil if any level is missing
+func dig(obj map[string]interface{}, fields ...string) map[string]interface{} {
+	for _, f := range fields {
+		next, ok := obj[f].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		obj = next
+	}
+	return obj
+}
+
+// mergeStringData merges stringData into data as the API server does on write
+func mergeStringData(secret *corev1.Secret) {
+	for k, v := range secret.StringData {
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		secret.Data[k] = []byte(v)
+	}
+	secret.StringData = nil
+}
@@ -0,0 +1,212 @@
+package netcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// StepStatus is the outcome of a single check step
+type StepStatus int
+
+const (
+	StepOK StepStatus = iota
+	StepFailed
+	StepSkipped
+)
+
+// Step is one stage of a connectivity check
+type Step struct {
+	Name   string
+	Status StepStatus
+	Detail string
+}
+
+// Result is the outcome of a connectivity check
+type Result struct {
+	Endpoint Endpoint
+	Steps    []Step
+}
+
+// OK returns true if no step failed
+func (r *Result) OK() bool {
+	for _, step := range r.Steps {
+		if step.Status == StepFailed {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Result) add(name string, status StepStatus, format string, args ...interface{}) {
+	r.Steps = append(r.Steps, Step{Name: name, Status: status, Detail: fmt.Sprintf(format, args...)})
+}
+
+// Options configures Check
+type Options struct {
+	// PortForward opens a port-forward to a ready backing pod and tries a TCP connect
+	PortForward bool
+	Timeout     time.Duration
+}
+
+// Check verifies that an endpoint refers to an existing Service with ready
+// endpoints, and optionally that its port accepts TCP connections.
+func Check(ctx context.Context, client *k8s.Client, ep Endpoint, opts Options) *Result {
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	result := &Result{Endpoint: ep}
+
+	svc, err := client.GetService(ctx, ep.Namespace, ep.Service)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			result.add("service", StepFailed, "service %s/%s not found", ep.Namespace, ep.Service)
+		} else {
+			result.add("service", StepFailed, "%v", err)
+		}
+		return result
+	}
+	result.add("service", StepOK, "%s/%s (%s)", ep.Namespace, ep.Service, svc.Spec.Type)
+
+	// Cluster DNS is usually not reachable from a workstation, so a failed lookup is not an error
+	if addrs, err := net.DefaultResolver.LookupHost(ctx, ep.FQDN()); err == nil {
+		result.add("dns", StepOK, "%s -> %s", ep.FQDN(), strings.Join(addrs, ", "))
+	} else {
+		result.add("dns", StepSkipped, "%s not resolvable from this machine", ep.FQDN())
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		result.add("endpoints", StepSkipped, "ExternalName service pointing to %s", svc.Spec.ExternalName)
+		return result
+	}
+
+	servicePort, ok := matchServicePort(svc, ep.Port)
+	if !ok {
+		result.add("port", StepFailed, "service does not expose port %d (ports: %s)", ep.Port, servicePorts(svc))
+		return result
+	}
+	result.add("port", StepOK, "%d -> target %s", servicePort.Port, servicePort.TargetPort.String())
+
+	slices, err := client.ListEndpointSlices(ctx, ep.Namespace, ep.Service)
+	if err != nil {
+		result.add("endpoints", StepFailed, "%v", err)
+		return result
+	}
+	pod, targetPort, ready := readyBackend(slices, servicePort)
+	if ready == 0 {
+		result.add("endpoints", StepFailed, "no ready endpoints")
+		return result
+	}
+	result.add("endpoints", StepOK, "%d ready", ready)
+
+	if !opts.PortForward {
+		return result
+	}
+	if pod == "" || targetPort == 0 {
+		result.add("tcp", StepSkipped, "no ready pod to port-forward to")
+		return result
+	}
+	checkTCP(ctx, client, result, ep.Namespace, pod, targetPort)
+	return result
+}
+
+// matchServicePort returns the Service port matching the given port number
+// (the first port when port is 0)
+func matchServicePort(svc *corev1.Service, port int) (corev1.ServicePort, bool) {
+	if len(svc.Spec.Ports) == 0 {
+		return corev1.ServicePort{}, false
+	}
+	if port == 0 {
+		return svc.Spec.Ports[0], true
+	}
+	for _, p := range svc.Spec.Ports {
+		if int(p.Port) == port {
+			return p, true
+		}
+	}
+	return corev1.ServicePort{}, false
+}
+
+// servicePorts lists the ports of a Service for messages
+func servicePorts(svc *corev1.Service) string {
+	ports := make([]string, 0, len(svc.Spec.Ports))
+	for _, p := range svc.Spec.Ports {
+		ports = append(ports, strconv.Itoa(int(p.Port)))
+	}
+	if len(ports) == 0 {
+		return "none"
+	}
+	return strings.Join(ports, ", ")
+}
+
+// readyBackend counts ready endpoints and returns one ready pod with the
+// container port the Service port resolves to
+func readyBackend(slices []discoveryv1.EndpointSlice, servicePort corev1.ServicePort) (pod string, port int, ready int) {
+	for _, slice := range slices {
+		slicePort := 0
+		for _, p := range slice.Ports {
+			if p.Port != nil && (p.Name == nil || *p.Name == servicePort.Name) {
+				slicePort = int(*p.Port)
+				break
+			}
+		}
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			ready++
+			if pod == "" && slicePort != 0 && endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+				pod, port = endpoint.TargetRef.Name, slicePort
+			}
+		}
+	}
+	return pod, port, ready
+}
+
+// checkTCP port-forwards to the pod and checks that the port accepts connections.
+// A port-forward only reports a refused connection once data flows, so the
+// connection is considered refused when the remote side closes it immediately.
+func checkTCP(ctx context.Context, client *k8s.Client, result *Result, namespace, pod string, port int) {
+	pf, err := client.StartPortForward(ctx, namespace, pod, port)
+	if err != nil {
+		result.add("tcp", StepFailed, "%v", err)
+		return
+	}
+	defer pf.Close()
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(pf.LocalPort)), 3*time.Second)
+	if err != nil {
+		result.add("tcp", StepFailed, "%v", err)
+		return
+	}
+	defer conn.Close()
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	switch {
+	case err == nil, errors.Is(err, os.ErrDeadlineExceeded):
+		result.add("tcp", StepOK, "connected to %s:%d via port-forward", pod, port)
+	case errors.Is(err, io.EOF):
+		detail := strings.TrimSpace(pf.Errors())
+		if detail == "" {
+			detail = "connection closed by remote"
+		}
+		result.add("tcp", StepFailed, "%s:%d: %s", pod, port, detail)
+	default:
+		result.add("tcp", StepFailed, "%s:%d: %v", pod, port, err)
+	}
+}
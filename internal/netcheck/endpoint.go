@@ -0,0 +1,142 @@
+package netcheck
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultPorts maps URL schemes to their well-known ports
+var defaultPorts = map[string]int{
+	"http":       80,
+	"https":      443,
+	"ws":         80,
+	"wss":        443,
+	"grpc":       443,
+	"redis":      6379,
+	"rediss":     6379,
+	"postgres":   5432,
+	"postgresql": 5432,
+	"mysql":      3306,
+	"mongodb":    27017,
+	"amqp":       5672,
+	"amqps":      5671,
+	"nats":       4222,
+	"kafka":      9092,
+}
+
+// dnsLabel matches a single RFC 1123 label as used for Service and namespace names
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Endpoint is an in-cluster Service address found in an env value
+type Endpoint struct {
+	Service   string
+	Namespace string
+	Port      int  // 0 when neither given nor implied by the scheme
+	Qualified bool // the host used the <svc>.<ns>.svc form
+}
+
+// FQDN returns the cluster DNS name of the Service
+func (e Endpoint) FQDN() string {
+	return e.Service + "." + e.Namespace + ".svc.cluster.local"
+}
+
+// String returns the endpoint as service.namespace[:port]
+func (e Endpoint) String() string {
+	s := e.Service + "." + e.Namespace
+	if e.Port != 0 {
+		s += ":" + strconv.Itoa(e.Port)
+	}
+	return s
+}
+
+// ParseEndpoint extracts an in-cluster Service address from an env value.
+// Accepted forms are URLs, host:port and *.svc[.cluster.local] hosts whose host
+// is <svc>, <svc>.<ns>, <svc>.<ns>.svc or <svc>.<ns>.svc.<cluster-domain>.
+// Bare names without a port are not treated as hosts since they are ambiguous.
+// Namespace defaults to defaultNamespace for bare service names.
+func ParseEndpoint(value, defaultNamespace string) (Endpoint, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.ContainsAny(value, " \t\n,;") {
+		return Endpoint{}, false
+	}
+
+	var host, port string
+	explicit := false // the value is clearly an address (URL or host:port)
+	switch {
+	case strings.Contains(value, "://"):
+		u, err := url.Parse(value)
+		if err != nil {
+			return Endpoint{}, false
+		}
+		host, port = u.Hostname(), u.Port()
+		if port == "" {
+			if p, ok := defaultPorts[strings.ToLower(u.Scheme)]; ok {
+				port = strconv.Itoa(p)
+			}
+		}
+		explicit = true
+	case strings.Contains(value, "/"):
+		return Endpoint{}, false
+	case strings.Contains(value, ":"):
+		h, p, err := net.SplitHostPort(value)
+		if err != nil {
+			return Endpoint{}, false
+		}
+		host, port = h, p
+		explicit = true
+	default:
+		host = value
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" || host == "localhost" || net.ParseIP(host) != nil {
+		return Endpoint{}, false
+	}
+
+	labels := strings.Split(host, ".")
+	clusterLocal := false
+	if i := indexOf(labels, "svc"); i == 2 {
+		labels = labels[:2]
+		clusterLocal = true
+	} else if i >= 0 {
+		return Endpoint{}, false
+	}
+	if !explicit && !clusterLocal {
+		return Endpoint{}, false
+	}
+
+	ep := Endpoint{Namespace: defaultNamespace, Qualified: clusterLocal}
+	switch len(labels) {
+	case 1:
+		ep.Service = labels[0]
+	case 2:
+		ep.Service, ep.Namespace = labels[0], labels[1]
+	default:
+		return Endpoint{}, false
+	}
+	if !dnsLabel.MatchString(ep.Service) || !dnsLabel.MatchString(ep.Namespace) {
+		return Endpoint{}, false
+	}
+
+	if port != "" {
+		n, err := strconv.Atoi(port)
+		if err != nil || n <= 0 || n > 65535 {
+			return Endpoint{}, false
+		}
+		ep.Port = n
+	}
+	return ep, true
+}
+
+// indexOf returns the index of s in list, or -1
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
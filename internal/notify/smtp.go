@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/config"
+)
+
+// base64LineLen is the maximum encoded line length allowed by RFC 2045
+const base64LineLen = 76
+
+// DefaultSMTPPort is used when smtp.port is not set (submission with STARTTLS)
+const DefaultSMTPPort = 587
+
+// DefaultPasswordEnv is the environment variable holding the SMTP password
+// when smtp.passwordEnv is not set
+const DefaultPasswordEnv = "ENVTOP_SMTP_PASSWORD"
+
+// Mailer sends reports through an SMTP server
+type Mailer struct {
+	cfg      config.SMTPConfig
+	password string
+}
+
+// NewMailer creates a mailer from the smtp section of the config file.
+// The password is read from the environment, never from the config file.
+func NewMailer(cfg config.SMTPConfig) (*Mailer, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("smtp.host is not configured")
+	}
+	if cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("smtp.from and smtp.to are required")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = DefaultSMTPPort
+	}
+
+	passwordEnv := cfg.PasswordEnv
+	if passwordEnv == "" {
+		passwordEnv = DefaultPasswordEnv
+	}
+	return &Mailer{cfg: cfg, password: os.Getenv(passwordEnv)}, nil
+}
+
+// Attachment is a file attached to a report mail
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Send mails an HTML body with optional attachments to the configured recipients
+func (m *Mailer) Send(subject string, htmlBody []byte, attachments ...Attachment) error {
+	msg := m.message(subject, htmlBody, attachments, time.Now())
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.password, m.cfg.Host)
+	}
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	if err := smtp.SendMail(addr, auth, m.cfg.From, m.cfg.To, msg); err != nil {
+		return fmt.Errorf("failed to send mail via %s: %w", addr, err)
+	}
+	return nil
+}
+
+// message builds a MIME multipart/mixed message
+func (m *Mailer) message(subject string, htmlBody []byte, attachments []Attachment, now time.Time) []byte {
+	boundary := fmt.Sprintf("envtop-%d", now.UnixNano())
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", m.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(m.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	b.WriteString("Content-Type: text/html; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	writeBase64(&b, htmlBody)
+
+	for _, a := range attachments {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		fmt.Fprintf(&b, "Content-Type: %s\r\n", a.ContentType)
+		b.WriteString("Content-Transfer-Encoding: base64\r\n")
+		fmt.Fprintf(&b, "Content-Disposition: attachment; filename=%q\r\n\r\n", a.Filename)
+		writeBase64(&b, a.Data)
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return b.Bytes()
+}
+
+// writeBase64 writes data base64 encoded and wrapped at base64LineLen
+func writeBase64(b *bytes.Buffer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > base64LineLen {
+		b.WriteString(encoded[:base64LineLen])
+		b.WriteString("\r\n")
+		encoded = encoded[base64LineLen:]
+	}
+	b.WriteString(encoded)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,198 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"sigs.k8s.io/yaml"
+)
+
+// Severity ranks policy violations
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// rank orders severities from least to most severe
+func (s Severity) rank() int {
+	switch s {
+	case SeverityInfo:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// AtLeast returns true if s is as severe as or more severe than other
+func (s Severity) AtLeast(other Severity) bool {
+	return s.rank() >= other.rank()
+}
+
+// ParseSeverity parses a severity name
+func ParseSeverity(s string) (Severity, error) {
+	switch Severity(s) {
+	case SeverityInfo, SeverityWarning, SeverityError:
+		return Severity(s), nil
+	case "":
+		return SeverityError, nil
+	}
+	return "", fmt.Errorf("unknown severity: %s", s)
+}
+
+// Policy requires the values of matching variables to match a pattern
+type Policy struct {
+	Name string `json:"name"`
+	// Variable is a glob on the variable name
+	Variable string `json:"variable"`
+	// Namespaces and Tiers restrict the policy; empty means everywhere
+	Namespaces []string `json:"namespaces,omitempty"`
+	Tiers      []string `json:"tiers,omitempty"`
+	// Pattern is the regular expression the value must match
+	Pattern  string   `json:"pattern"`
+	Severity Severity `json:"severity,omitempty"`
+	Message  string   `json:"message,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// File is the format of a policy file
+type File struct {
+	Policies []Policy `json:"policies"`
+}
+
+// Violation is a variable whose value does not satisfy a policy
+type Violation struct {
+	Policy   string
+	Name     string
+	Severity Severity
+	Message  string
+}
+
+// Set is a compiled set of policies
+type Set struct {
+	policies []Policy
+}
+
+// LoadFiles loads and compiles policy files. Relative paths are resolved against baseDir.
+func LoadFiles(baseDir string, paths []string) (*Set, error) {
+	set := &Set{}
+	for _, p := range ResolvePaths(baseDir, paths) {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy file %s: %w", p, err)
+		}
+		var file File
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse policy file %s: %w", p, err)
+		}
+		for _, policy := range file.Policies {
+			if err := set.add(policy); err != nil {
+				return nil, fmt.Errorf("%s: %w", p, err)
+			}
+		}
+	}
+	return set, nil
+}
+
+// add validates and compiles a policy
+func (s *Set) add(policy Policy) error {
+	if policy.Name == "" || policy.Variable == "" {
+		return fmt.Errorf("policy requires name and variable")
+	}
+	re, err := regexp.Compile(policy.Pattern)
+	if err != nil {
+		return fmt.Errorf("policy %s: invalid pattern: %w", policy.Name, err)
+	}
+	policy.Severity, err = ParseSeverity(string(policy.Severity))
+	if err != nil {
+		return fmt.Errorf("policy %s: %w", policy.Name, err)
+	}
+	policy.re = re
+	s.policies = append(s.policies, policy)
+	return nil
+}
+
+// Len returns the number of policies
+func (s *Set) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.policies)
+}
+
+// Evaluate checks resolved env vars of an app in a namespace (of the given tier).
+// Masked values are matched on their raw value but never included in messages.
+func (s *Set) Evaluate(namespace, tier string, envVars []k8s.EnvVar) []Violation {
+	if s == nil {
+		return nil
+	}
+
+	var violations []Violation
+	for _, policy := range s.policies {
+		if !policy.appliesTo(namespace, tier) {
+			continue
+		}
+		for _, ev := range envVars {
+			if ok, _ := path.Match(policy.Variable, ev.Name); !ok || ev.Forbidden {
+				continue
+			}
+			value := ev.Value
+			if ev.IsMasked() {
+				value = string(ev.RawValue)
+			}
+			if policy.re.MatchString(value) {
+				continue
+			}
+
+			message := policy.Message
+			if message == "" {
+				message = fmt.Sprintf("value does not match %s", policy.Pattern)
+			}
+			violations = append(violations, Violation{
+				Policy:   policy.Name,
+				Name:     ev.Name,
+				Severity: policy.Severity,
+				Message:  message,
+			})
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Name < violations[j].Name
+	})
+	return violations
+}
+
+// appliesTo returns true if the policy covers the namespace and tier
+func (p *Policy) appliesTo(namespace, tier string) bool {
+	if len(p.Namespaces) > 0 {
+		matched := false
+		for _, pattern := range p.Namespaces {
+			if ok, _ := path.Match(pattern, namespace); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(p.Tiers) > 0 {
+		for _, t := range p.Tiers {
+			if t == tier {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+func TestEvaluate(t *testing.T) {
+	set := &Set{}
+	for _, p := range []Policy{
+		{Name: "db-proxy", Variable: "DATABASE_URL", Tiers: []string{"prod"}, Pattern: `@db-proxy\.`, Message: "use the DB proxy"},
+		{Name: "no-debug", Variable: "*LOG_LEVEL", Namespaces: []string{"shop-*"}, Pattern: `^(info|warn|error)$`, Severity: SeverityWarning},
+		{Name: "api-key", Variable: "API_KEY", Pattern: `^ak_live_`},
+	} {
+		if err := set.add(p); err != nil {
+			t.Fatalf("add %s: %v", p.Name, err)
+		}
+	}
+
+	secret := func(name, value string) k8s.EnvVar {
+		return k8s.EnvVar{Name: name, Value: "HASH: x", RawValue: []byte(value), SourceKind: k8s.EnvSourceSecret}
+	}
+	tests := []struct {
+		name      string
+		namespace string
+		tier      string
+		ev        k8s.EnvVar
+		want      string // violated policy, "" when allowed
+		severity  Severity
+	}{
+		{"allowed value", "shop-prd", "prod", k8s.EnvVar{Name: "DATABASE_URL", Value: "postgres://u@db-proxy.data:5432/app"}, "", ""},
+		{"violating value", "shop-prd", "prod", k8s.EnvVar{Name: "DATABASE_URL", Value: "postgres://u@db.data:5432/app"}, "db-proxy", SeverityError},
+		{"other tier", "shop-stg", "staging", k8s.EnvVar{Name: "DATABASE_URL", Value: "postgres://u@db.data:5432/app"}, "", ""},
+		{"glob on the name", "shop-prd", "prod", k8s.EnvVar{Name: "APP_LOG_LEVEL", Value: "debug"}, "no-debug", SeverityWarning},
+		{"other namespace", "billing", "prod", k8s.EnvVar{Name: "APP_LOG_LEVEL", Value: "debug"}, "", ""},
+		{"no policy for the name", "shop-prd", "prod", k8s.EnvVar{Name: "PORT", Value: "8080"}, "", ""},
+		{"masked value allowed on its raw value", "billing", "", secret("API_KEY", "ak_live_123"), "", ""},
+		{"masked value violating on its raw value", "billing", "", secret("API_KEY", "ak_test_123"), "api-key", SeverityError},
+		{"forbidden value is not evaluated", "billing", "", forbidden("API_KEY"), "", ""},
+	}
+
+	for _, tt := range tests {
+		violations := set.Evaluate(tt.namespace, tt.tier, []k8s.EnvVar{tt.ev})
+		if tt.want == "" {
+			if len(violations) != 0 {
+				t.Errorf("%s: got %+v, want no violation", tt.name, violations)
+			}
+			continue
+		}
+		if len(violations) != 1 {
+			t.Errorf("%s: got %+v, want a violation of %s", tt.name, violations, tt.want)
+			continue
+		}
+		v := violations[0]
+		if v.Policy != tt.want || v.Name != tt.ev.Name || v.Severity != tt.severity {
+			t.Errorf("%s: got %+v, want %s on %s with severity %s", tt.name, v, tt.want, tt.ev.Name, tt.severity)
+		}
+		if len(tt.ev.RawValue) > 0 && strings.Contains(v.Message, string(tt.ev.RawValue)) {
+			t.Errorf("%s: message %q shows the masked value", tt.name, v.Message)
+		}
+	}
+}
+
+// forbidden returns a variable of a Secret the user may not read
+func forbidden(name string) k8s.EnvVar {
+	return k8s.EnvVar{Name: name, Value: "(forbidden)", SourceKind: k8s.EnvSourceSecret, Forbidden: true}
+}
+
+func TestEvaluateMessages(t *testing.T) {
+	set := &Set{}
+	if err := set.add(Policy{Name: "https", Variable: "*_URL", Pattern: `^https://`}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	violations := set.Evaluate("shop", "", []k8s.EnvVar{
+		{Name: "WEBHOOK_URL", Value: "http://hooks"},
+		{Name: "API_URL", Value: "http://api"},
+		{Name: "CDN_URL", Value: "https://cdn"},
+	})
+	if len(violations) != 2 || violations[0].Name != "API_URL" || violations[1].Name != "WEBHOOK_URL" {
+		t.Fatalf("got %+v, want API_URL and WEBHOOK_URL sorted by name", violations)
+	}
+	if violations[0].Message != "value does not match ^https://" {
+		t.Errorf("default message %q", violations[0].Message)
+	}
+
+	var none *Set
+	if got := none.Evaluate("shop", "", []k8s.EnvVar{{Name: "API_URL", Value: "http://api"}}); got != nil {
+		t.Errorf("a nil set reported %+v", got)
+	}
+}
+
+func TestAddValidation(t *testing.T) {
+	tests := []struct {
+		policy  Policy
+		wantErr string
+	}{
+		{Policy{Variable: "X", Pattern: "x"}, "requires name and variable"},
+		{Policy{Name: "p", Pattern: "x"}, "requires name and variable"},
+		{Policy{Name: "p", Variable: "X", Pattern: "("}, "invalid pattern"},
+		{Policy{Name: "p", Variable: "X", Pattern: "x", Severity: "fatal"}, "unknown severity"},
+		{Policy{Name: "p", Variable: "X", Pattern: "x"}, ""},
+	}
+	for _, tt := range tests {
+		set := &Set{}
+		err := set.add(tt.policy)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("add(%+v): %v", tt.policy, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("add(%+v) = %v, want an error containing %q", tt.policy, err, tt.wantErr)
+		}
+	}
+
+	// An empty severity defaults to error
+	set := &Set{}
+	if err := set.add(Policy{Name: "p", Variable: "X", Pattern: "x"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if set.policies[0].Severity != SeverityError {
+		t.Errorf("default severity %s, want %s", set.policies[0].Severity, SeverityError)
+	}
+}
@@ -0,0 +1,176 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// DefaultRegoQuery is the Rego rule evaluated when none is configured
+const DefaultRegoQuery = "data.envtop.deny"
+
+// RegoHook evaluates resolved env sets with Rego policies through the external `opa` binary
+type RegoHook struct {
+	Binary   string   // opa executable, "opa" when empty
+	Policies []string // .rego files or directories
+	Query    string   // rule producing denials, DefaultRegoQuery when empty
+}
+
+// RegoInput is the document passed to Rego as `input`. Masked values are
+// represented by their hash and length only.
+type RegoInput struct {
+	Namespace string         `json:"namespace"`
+	Tier      string         `json:"tier,omitempty"`
+	App       string         `json:"app"`
+	Kind      k8s.AppKind    `json:"kind"`
+	Env       []RegoInputVar `json:"env"`
+}
+
+// RegoInputVar is one resolved variable in RegoInput
+type RegoInputVar struct {
+	Name       string            `json:"name"`
+	Value      string            `json:"value,omitempty"`
+	SourceKind k8s.EnvSourceKind `json:"sourceKind"`
+	SourceName string            `json:"sourceName,omitempty"`
+	Redacted   bool              `json:"redacted"`
+	Hash       string            `json:"hash,omitempty"`
+	Length     int               `json:"length"`
+	Forbidden  bool              `json:"forbidden,omitempty"` // the source may not be read: no value
+}
+
+// NewRegoInput builds the Rego input for an app's resolved env
+func NewRegoInput(namespace, tier string, app k8s.App, envVars []k8s.EnvVar) *RegoInput {
+	input := &RegoInput{
+		Namespace: namespace,
+		Tier:      tier,
+		App:       app.Name,
+		Kind:      app.Kind,
+		Env:       make([]RegoInputVar, 0, len(envVars)),
+	}
+	for _, ev := range envVars {
+		v := RegoInputVar{
+			Name:       ev.Name,
+			SourceKind: ev.SourceKind,
+			SourceName: ev.SourceName,
+			Length:     ev.ValueLen,
+		}
+		switch {
+		case ev.Forbidden:
+			v.Forbidden = true
+		case ev.IsMasked():
+			v.Redacted = true
+			v.Hash = ev.Hash
+		default:
+			v.Value = ev.Value
+		}
+		input.Env = append(input.Env, v)
+	}
+	return input
+}
+
+// Evaluate runs `opa eval` and converts the denials into violations.
+// A denial is either a message string or an object with msg, name and severity.
+func (h *RegoHook) Evaluate(ctx context.Context, input *RegoInput) ([]Violation, error) {
+	binary := h.Binary
+	if binary == "" {
+		binary = "opa"
+	}
+	query := h.Query
+	if query == "" {
+		query = DefaultRegoQuery
+	}
+
+	args := []string{"eval", "--format", "json", "--stdin-input"}
+	for _, p := range h.Policies {
+		args = append(args, "--data", p)
+	}
+	args = append(args, query)
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("opa eval failed: %v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var output struct {
+		Result []struct {
+			Expressions []struct {
+				Value []json.RawMessage `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, fmt.Errorf("failed to parse opa output: %w", err)
+	}
+
+	var violations []Violation
+	for _, result := range output.Result {
+		for _, expr := range result.Expressions {
+			for _, raw := range expr.Value {
+				v, err := parseDenial(raw)
+				if err != nil {
+					return nil, err
+				}
+				violations = append(violations, v)
+			}
+		}
+	}
+	return violations, nil
+}
+
+// parseDenial converts one element of the deny set into a violation
+func parseDenial(raw json.RawMessage) (Violation, error) {
+	v := Violation{Policy: "rego", Severity: SeverityError}
+
+	var msg string
+	if err := json.Unmarshal(raw, &msg); err == nil {
+		v.Message = msg
+		return v, nil
+	}
+
+	var obj struct {
+		Msg      string `json:"msg"`
+		Name     string `json:"name"`
+		Policy   string `json:"policy"`
+		Severity string `json:"severity"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return v, fmt.Errorf("unsupported denial: %s", raw)
+	}
+	severity, err := ParseSeverity(obj.Severity)
+	if err != nil {
+		return v, err
+	}
+	v.Message = obj.Msg
+	v.Name = obj.Name
+	v.Severity = severity
+	if obj.Policy != "" {
+		v.Policy = obj.Policy
+	}
+	return v, nil
+}
+
+// ResolvePaths resolves relative policy paths against baseDir
+func ResolvePaths(baseDir string, paths []string) []string {
+	resolved := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(baseDir, p)
+		}
+		resolved = append(resolved, p)
+	}
+	return resolved
+}
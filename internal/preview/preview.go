@@ -0,0 +1,66 @@
+package preview
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// StaleNamespace is a preview namespace older than the allowed age
+type StaleNamespace struct {
+	Name      string
+	CreatedAt time.Time
+	Apps      []string
+}
+
+// FindStale returns the preview namespaces created before now-maxAge, oldest first
+func FindStale(ctx context.Context, client *k8s.Client, cfg *config.Config, maxAge time.Duration, now time.Time) ([]StaleNamespace, error) {
+	namespaces, err := client.ListNamespaceDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []StaleNamespace
+	for _, ns := range namespaces {
+		if !cfg.IsPreview(ns.Name, ns.Labels) || now.Sub(ns.CreatedAt) < maxAge {
+			continue
+		}
+
+		apps, err := client.ListApps(ctx, ns.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list apps in %s: %w", ns.Name, err)
+		}
+		names := make([]string, 0, len(apps))
+		for _, app := range apps {
+			names = append(names, app.Name)
+		}
+
+		stale = append(stale, StaleNamespace{
+			Name:      ns.Name,
+			CreatedAt: ns.CreatedAt,
+			Apps:      names,
+		})
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].CreatedAt.Before(stale[j].CreatedAt)
+	})
+	return stale, nil
+}
+
+// DeleteCommands returns the kubectl commands deleting the given namespaces
+func DeleteCommands(kubeContext string, stale []StaleNamespace) []string {
+	commands := make([]string, 0, len(stale))
+	for _, ns := range stale {
+		if kubeContext == "" {
+			commands = append(commands, "kubectl delete namespace "+k8s.ShellQuote(ns.Name))
+		} else {
+			commands = append(commands, fmt.Sprintf("kubectl --context %s delete namespace %s", k8s.ShellQuote(kubeContext), k8s.ShellQuote(ns.Name)))
+		}
+	}
+	return commands
+}
@@ -0,0 +1,91 @@
+package report
+
+import (
+	_ "embed"
+
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// DiffSchemaVersion is the version of the diff report JSON schema
+const DiffSchemaVersion = "v1"
+
+//go:embed schema/diff.v1.json
+var diffSchema []byte
+
+// DiffSchema returns the JSON schema describing DiffReport
+func DiffSchema() []byte {
+	return diffSchema
+}
+
+// DiffReport is the stable, machine-readable form of a diff
+type DiffReport struct {
+	SchemaVersion string      `json:"schemaVersion"`
+	Context       string      `json:"context,omitempty"`
+	App           string      `json:"app"`
+	Kind          k8s.AppKind `json:"kind"`
+	NamespaceA    string      `json:"namespaceA"`
+	NamespaceB    string      `json:"namespaceB"`
+	Results       []DiffEntry `json:"results"`
+}
+
+// DiffEntry is a single compared variable
+type DiffEntry struct {
+	Name   string         `json:"name"`
+	Status env.DiffStatus `json:"status"`
+	A      *Value         `json:"a,omitempty"`
+	B      *Value         `json:"b,omitempty"`
+}
+
+// Value is one side of a comparison with secrets redacted
+type Value struct {
+	SourceKind k8s.EnvSourceKind `json:"sourceKind"`
+	SourceName string            `json:"sourceName,omitempty"`
+	Redacted   bool              `json:"redacted"`
+	Value      string            `json:"value,omitempty"`
+	Hash       string            `json:"hash,omitempty"`
+	Length     int               `json:"length"`
+}
+
+// NewDiffReport converts diff results into a report
+func NewDiffReport(context string, app k8s.App, nsA, nsB string, results []env.DiffResult) *DiffReport {
+	entries := make([]DiffEntry, 0, len(results))
+	for _, r := range results {
+		entries = append(entries, DiffEntry{
+			Name:   r.Name,
+			Status: r.Status,
+			A:      newValue(r.EnvA),
+			B:      newValue(r.EnvB),
+		})
+	}
+
+	return &DiffReport{
+		SchemaVersion: DiffSchemaVersion,
+		Context:       context,
+		App:           app.Name,
+		Kind:          app.Kind,
+		NamespaceA:    nsA,
+		NamespaceB:    nsB,
+		Results:       entries,
+	}
+}
+
+// newValue converts an env var into a report value, redacting secrets and masked values
+func newValue(ev *k8s.EnvVar) *Value {
+	if ev == nil {
+		return nil
+	}
+
+	v := &Value{
+		SourceKind: ev.SourceKind,
+		SourceName: ev.SourceName,
+		Length:     ev.ValueLen,
+	}
+	if ev.IsMasked() {
+		v.Redacted = true
+		v.Hash = ev.Hash
+	} else {
+		v.Value = ev.Value
+	}
+	return v
+}
@@ -0,0 +1,166 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/env"
+)
+
+// DiffFormat is a format a diff can be exported in
+type DiffFormat string
+
+const (
+	DiffFormatMarkdown DiffFormat = "markdown"
+	DiffFormatJSON     DiffFormat = "json"
+	DiffFormatUnified  DiffFormat = "unified"
+)
+
+// ParseDiffFormat parses a format name as accepted by --format
+func ParseDiffFormat(s string) (DiffFormat, bool) {
+	switch DiffFormat(s) {
+	case DiffFormatMarkdown, DiffFormatJSON, DiffFormatUnified:
+		return DiffFormat(s), true
+	case "md":
+		return DiffFormatMarkdown, true
+	case "diff", "patch":
+		return DiffFormatUnified, true
+	}
+	return "", false
+}
+
+// DiffFormatOf picks the format from the extension of a path (Markdown by default)
+func DiffFormatOf(path string) DiffFormat {
+	if format, ok := ParseDiffFormat(strings.TrimPrefix(filepath.Ext(path), ".")); ok {
+		return format
+	}
+	return DiffFormatMarkdown
+}
+
+// WriteDiff writes a diff report in an export format. Secret values are
+// written as their hash, as in the JSON report.
+func WriteDiff(w io.Writer, format DiffFormat, rep *DiffReport) error {
+	switch format {
+	case DiffFormatJSON:
+		data, err := json.MarshalIndent(rep, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case DiffFormatUnified:
+		return writeUnifiedDiff(w, rep)
+	default:
+		return writeMarkdownDiff(w, rep)
+	}
+}
+
+// writeMarkdownDiff writes the variables that differ as a Markdown table
+func writeMarkdownDiff(w io.Writer, rep *DiffReport) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s `%s`: `%s` vs `%s`\n\n", rep.Kind, rep.App, rep.NamespaceA, rep.NamespaceB)
+
+	var changed []DiffEntry
+	forbidden := 0
+	for _, e := range rep.Results {
+		if e.Status != env.DiffStatusSame {
+			changed = append(changed, e)
+		}
+		if e.Status == env.DiffStatusForbidden {
+			forbidden++
+		}
+	}
+	if len(changed) == 0 {
+		fmt.Fprintf(&b, "No differences (%d variables compared).\n", len(rep.Results))
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	fmt.Fprintf(&b, "| Name | %s | %s | Status |\n", markdownCell(rep.NamespaceA), markdownCell(rep.NamespaceB))
+	b.WriteString("|------|------|------|--------|\n")
+	for _, e := range changed {
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", e.Name, markdownValue(e.A), markdownValue(e.B), e.Status)
+	}
+	fmt.Fprintf(&b, "\n%d of %d variables differ, %d are the same.\n", len(changed)-forbidden, len(rep.Results), len(rep.Results)-len(changed))
+	if forbidden > 0 {
+		fmt.Fprintf(&b, "%d could not be compared: no permission to read their source.\n", forbidden)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownValue renders a report value as a table cell
+func markdownValue(v *Value) string {
+	switch {
+	case v == nil:
+		return "_(not present)_"
+	case v.Redacted:
+		return fmt.Sprintf("_(secret, sha256 %s)_", v.Hash)
+	case v.Value == "":
+		return "_(empty)_"
+	}
+	if strings.Contains(v.Value, "`") {
+		return "`` " + markdownCell(v.Value) + " ``"
+	}
+	return "`" + markdownCell(v.Value) + "`"
+}
+
+// markdownCell escapes the characters that would break a table row
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+// writeUnifiedDiff writes the env of both sides as a unified diff of
+// NAME=value lines, with every variable as context
+func writeUnifiedDiff(w io.Writer, rep *DiffReport) error {
+	var lines []string
+	countA, countB := 0, 0
+	for _, e := range rep.Results {
+		if e.Status == env.DiffStatusSame {
+			lines = append(lines, " "+unifiedLine(e.Name, e.A))
+			countA++
+			countB++
+			continue
+		}
+		if e.A != nil {
+			lines = append(lines, "-"+unifiedLine(e.Name, e.A))
+			countA++
+		}
+		if e.B != nil {
+			lines = append(lines, "+"+unifiedLine(e.Name, e.B))
+			countB++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s/%s\n", rep.NamespaceA, rep.App)
+	fmt.Fprintf(&b, "+++ %s/%s\n", rep.NamespaceB, rep.App)
+	if len(lines) > 0 {
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", unifiedRange(countA), unifiedRange(countB))
+		for _, line := range lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// unifiedLine renders a variable as a NAME=value line, escaping newlines
+func unifiedLine(name string, v *Value) string {
+	if v.Redacted {
+		return fmt.Sprintf("%s=<secret sha256:%s>", name, v.Hash)
+	}
+	return name + "=" + strings.ReplaceAll(v.Value, "\n", `\n`)
+}
+
+// unifiedRange renders the line range of a hunk covering a whole side
+func unifiedRange(count int) string {
+	if count == 0 {
+		return "0,0"
+	}
+	return fmt.Sprintf("1,%d", count)
+}
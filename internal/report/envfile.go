@@ -0,0 +1,120 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"sigs.k8s.io/yaml"
+)
+
+// EnvFileFormat is the format of a local env file
+type EnvFileFormat string
+
+const (
+	EnvFileDotenv EnvFileFormat = "dotenv"
+	EnvFileJSON   EnvFileFormat = "json"
+	EnvFileYAML   EnvFileFormat = "yaml"
+)
+
+// ParseEnvFileFormat parses a format name as accepted by --format
+func ParseEnvFileFormat(s string) (EnvFileFormat, bool) {
+	switch EnvFileFormat(s) {
+	case EnvFileDotenv, EnvFileJSON, EnvFileYAML:
+		return EnvFileFormat(s), true
+	case "env":
+		return EnvFileDotenv, true
+	case "yml":
+		return EnvFileYAML, true
+	}
+	return "", false
+}
+
+// EnvFileFormatOf picks the format from the extension of a path (dotenv by default)
+func EnvFileFormatOf(path string) EnvFileFormat {
+	if format, ok := ParseEnvFileFormat(strings.TrimPrefix(filepath.Ext(path), ".")); ok {
+		return format
+	}
+	return EnvFileDotenv
+}
+
+// WriteEnvFile writes resolved env vars as a local env file, one entry per variable.
+// Masked values are left out unless includeSecrets is set: dotenv comments them out,
+// JSON and YAML write null. Values only known at runtime (fieldRefs to pod fields
+// that are not known yet, resourceFieldRef) are always left out the same way.
+func WriteEnvFile(w io.Writer, format EnvFileFormat, envVars []k8s.EnvVar, includeSecrets bool) error {
+	switch format {
+	case EnvFileJSON, EnvFileYAML:
+		values := make(map[string]*string, len(envVars))
+		for _, ev := range envVars {
+			value, ok := envFileValue(ev, includeSecrets)
+			if !ok {
+				values[ev.Name] = nil
+				continue
+			}
+			values[ev.Name] = &value
+		}
+		if format == EnvFileYAML {
+			data, err := yaml.Marshal(values)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(data)
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(values)
+
+	case EnvFileDotenv:
+		for _, ev := range envVars {
+			value, ok := envFileValue(ev, includeSecrets)
+			line := ev.Name + "=" + dotenvQuote(value)
+			if !ok {
+				line = fmt.Sprintf("# %s=  (%s)", ev.Name, omittedReason(ev))
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown env file format: %s", format)
+}
+
+// envFileValue returns the value to write, false when it is left out
+func envFileValue(ev k8s.EnvVar, includeSecrets bool) (string, bool) {
+	switch {
+	case ev.Unresolved:
+		return "", false
+	case ev.IsMasked() && !includeSecrets:
+		return "", false
+	case ev.IsMasked():
+		return string(ev.RawValue), true
+	}
+	return ev.Value, true
+}
+
+// omittedReason explains in a dotenv comment why a value was left out
+func omittedReason(ev k8s.EnvVar) string {
+	if ev.IsMasked() {
+		return fmt.Sprintf("redacted, %s %s, HASH: %s", ev.SourceKind, ev.SourceName, ev.Hash)
+	}
+	return ev.Value + ", known only at runtime"
+}
+
+// dotenvPlain matches values that need no quoting in a dotenv file
+var dotenvPlain = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+
+// dotenvQuote double-quotes a value unless it is plain, escaping what dotenv parsers interpret
+func dotenvQuote(value string) string {
+	if dotenvPlain.MatchString(value) {
+		return value
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`)
+	return `"` + r.Replace(value) + `"`
+}
@@ -0,0 +1,109 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// EnvRow is one (app, variable) row of a namespace-wide export
+type EnvRow struct {
+	App        string
+	Kind       k8s.AppKind
+	Container  string
+	Name       string
+	SourceKind k8s.EnvSourceKind
+	SourceName string
+	Redacted   bool
+	Value      string // empty when redacted
+	Hash       string // set when redacted
+	Length     int
+	Warnings   []string
+}
+
+// envRowHeader is the column header of CSV and XLSX exports
+var envRowHeader = []string{"app", "kind", "container", "name", "source_kind", "source_name", "redacted", "value", "hash", "length", "warnings"}
+
+// NewEnvRows converts an app's resolved env into export rows, redacting masked values.
+// warnings maps variable names to messages (e.g. policy violations).
+func NewEnvRows(app k8s.App, envVars []k8s.EnvVar, warnings map[string][]string) []EnvRow {
+	rows := make([]EnvRow, 0, len(envVars))
+	for _, ev := range envVars {
+		v := newValue(&ev)
+		rows = append(rows, EnvRow{
+			App:        app.Name,
+			Kind:       app.Kind,
+			Container:  ev.Container,
+			Name:       ev.Name,
+			SourceKind: v.SourceKind,
+			SourceName: v.SourceName,
+			Redacted:   v.Redacted,
+			Value:      v.Value,
+			Hash:       v.Hash,
+			Length:     v.Length,
+			Warnings:   warnings[ev.Name],
+		})
+	}
+	return rows
+}
+
+// record returns the row as export cells
+func (r EnvRow) record() []string {
+	return []string{
+		r.App,
+		string(r.Kind),
+		r.Container,
+		r.Name,
+		string(r.SourceKind),
+		r.SourceName,
+		strconv.FormatBool(r.Redacted),
+		r.Value,
+		r.Hash,
+		strconv.Itoa(r.Length),
+		strings.Join(r.Warnings, "; "),
+	}
+}
+
+// WriteCSV writes the rows as CSV with a header line
+func WriteCSV(w io.Writer, rows []EnvRow) error {
+	return writeDelimited(w, rows, ',')
+}
+
+// WriteTSV writes the rows as tab-separated values with a header line, the
+// format spreadsheets split into columns on paste
+func WriteTSV(w io.Writer, rows []EnvRow) error {
+	return writeDelimited(w, rows, '\t')
+}
+
+func writeDelimited(w io.Writer, rows []EnvRow, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(envRowHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := row.record()
+		for i := range record {
+			record[i] = escapeFormula(record[i])
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// escapeFormula keeps a spreadsheet from evaluating a cell as a formula when
+// the CSV or TSV is opened or pasted: a value such as =HYPERLINK(...) comes
+// from the cluster, not from the user. A leading ' is shown as text only.
+// XLSX cells are typed as strings and need no escaping.
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
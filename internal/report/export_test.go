@@ -0,0 +1,64 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"testing"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+func TestWriteDelimitedEscapesFormulas(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{`=HYPERLINK("http://evil","x")`, `'=HYPERLINK("http://evil","x")`},
+		{"+1+2", "'+1+2"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1+1", "'\t=1+1"},
+		{"\r=1+1", "'\r=1+1"},
+		{"a=b", "a=b"},
+		{"1-2", "1-2"},
+		{"'quoted", "'quoted"},
+		{"", ""},
+	}
+
+	writers := []struct {
+		name  string
+		write func(io.Writer, []EnvRow) error
+		comma rune
+	}{
+		{"csv", WriteCSV, ','},
+		{"tsv", WriteTSV, '\t'},
+	}
+
+	for _, w := range writers {
+		for _, tt := range tests {
+			row := EnvRow{App: tt.value, Kind: k8s.AppKindDeployment, Name: "X", SourceKind: k8s.EnvSourceInline, Value: tt.value}
+			var buf bytes.Buffer
+			if err := w.write(&buf, []EnvRow{row}); err != nil {
+				t.Fatalf("%s: write %q: %v", w.name, tt.value, err)
+			}
+
+			r := csv.NewReader(&buf)
+			r.Comma = w.comma
+			records, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("%s: read back %q: %v", w.name, tt.value, err)
+			}
+			if len(records) != 2 {
+				t.Fatalf("%s: got %d records, want a header and one row", w.name, len(records))
+			}
+			// Every cell is escaped, not only the value
+			if got := records[1][0]; got != tt.want {
+				t.Errorf("%s: app cell of %q = %q, want %q", w.name, tt.value, got, tt.want)
+			}
+			if got := records[1][7]; got != tt.want {
+				t.Errorf("%s: value cell of %q = %q, want %q", w.name, tt.value, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package report
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/ginbear/k8s-envtop/internal/drift"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+//go:embed template/diff.html
+var diffHTML string
+
+// diffHTMLTemplate renders BulkDiffReport as a self-contained HTML page
+var diffHTMLTemplate = template.Must(template.New("diff").Parse(diffHTML))
+
+// BulkDiffReport is the diff of every app shared by two namespaces
+type BulkDiffReport struct {
+	Context     string
+	NamespaceA  string
+	NamespaceB  string
+	GeneratedAt time.Time
+	Apps        []AppDiffReport
+	Score       *drift.Score // nil when scores were not computed
+}
+
+// AppDiffReport is the diff of one app within a bulk report
+type AppDiffReport struct {
+	App     string
+	Kind    k8s.AppKind
+	Results []DiffEntry
+	Drift   int    // number of variables that differ
+	Error   string // resolution error, if the app could not be compared
+	Score   *drift.Score
+}
+
+// DriftedApps returns the number of apps with at least one differing variable
+func (r *BulkDiffReport) DriftedApps() int {
+	n := 0
+	for _, app := range r.Apps {
+		if app.Drift > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// NewBulkDiffReport converts namespace-wide diff results into a report
+func NewBulkDiffReport(context, nsA, nsB string, diffs []env.AppDiff, now time.Time) *BulkDiffReport {
+	rep := &BulkDiffReport{
+		Context:     context,
+		NamespaceA:  nsA,
+		NamespaceB:  nsB,
+		GeneratedAt: now,
+		Apps:        make([]AppDiffReport, 0, len(diffs)),
+	}
+
+	for _, diff := range diffs {
+		app := AppDiffReport{App: diff.App.Name, Kind: diff.App.Kind}
+		if diff.Err != nil {
+			app.Error = diff.Err.Error()
+		} else {
+			app.Results = NewDiffReport(context, diff.App, nsA, nsB, diff.Results).Results
+			for _, r := range diff.Results {
+				if r.Status.IsDrift() {
+					app.Drift++
+				}
+			}
+		}
+		rep.Apps = append(rep.Apps, app)
+	}
+	return rep
+}
+
+// SetScores attaches drift scores and their trends to the report
+func (r *BulkDiffReport) SetScores(summary drift.ScoreSummary) {
+	total := summary.Total
+	r.Score = &total
+	for i := range r.Apps {
+		if score, ok := summary.Apps[r.Apps[i].App]; ok {
+			r.Apps[i].Score = &score
+		}
+	}
+}
+
+// WriteHTML writes the report as a single HTML file with inline styles and script.
+// Secret and redacted values only appear as hash and length.
+func WriteHTML(w io.Writer, rep *BulkDiffReport) error {
+	return diffHTMLTemplate.Execute(w, rep)
+}
@@ -0,0 +1,38 @@
+package report
+
+import "github.com/ginbear/k8s-envtop/internal/k8s"
+
+// AppEnv is the resolved env of one app, with secrets redacted
+type AppEnv struct {
+	Context   string      `json:"context,omitempty"`
+	Namespace string      `json:"namespace"`
+	App       string      `json:"app"`
+	Kind      k8s.AppKind `json:"kind"`
+	Env       []EnvEntry  `json:"env"`
+}
+
+// EnvEntry is one variable of an app
+type EnvEntry struct {
+	Container string `json:"container,omitempty"`
+	Name      string `json:"name"`
+	Value     *Value `json:"value"`
+}
+
+// NewAppEnv converts the resolved env of an app into its listing
+func NewAppEnv(context string, app k8s.App, envVars []k8s.EnvVar) *AppEnv {
+	entries := make([]EnvEntry, 0, len(envVars))
+	for i := range envVars {
+		entries = append(entries, EnvEntry{
+			Container: envVars[i].Container,
+			Name:      envVars[i].Name,
+			Value:     newValue(&envVars[i]),
+		})
+	}
+	return &AppEnv{
+		Context:   context,
+		Namespace: app.Namespace,
+		App:       app.Name,
+		Kind:      app.Kind,
+		Env:       entries,
+	}
+}
@@ -0,0 +1,27 @@
+package report
+
+import "github.com/ginbear/k8s-envtop/internal/k8s"
+
+// QueryMatch is a variable found by a fleet-wide query, with secrets redacted
+type QueryMatch struct {
+	Context   string      `json:"context,omitempty"`
+	Namespace string      `json:"namespace"`
+	App       string      `json:"app"`
+	Kind      k8s.AppKind `json:"kind"`
+	Container string      `json:"container,omitempty"`
+	Name      string      `json:"name"`
+	Value     *Value      `json:"value"`
+}
+
+// NewQueryMatch converts a variable found in an app into a query match
+func NewQueryMatch(context string, app k8s.App, ev *k8s.EnvVar) QueryMatch {
+	return QueryMatch{
+		Context:   context,
+		Namespace: app.Namespace,
+		App:       app.Name,
+		Kind:      app.Kind,
+		Container: ev.Container,
+		Name:      ev.Name,
+		Value:     newValue(ev),
+	}
+}
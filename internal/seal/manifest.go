@@ -0,0 +1,57 @@
+package seal
+
+// Scope annotations understood by the sealed-secrets controller
+const (
+	annotationNamespaceWide = "sealedsecrets.bitnami.com/namespace-wide"
+	annotationClusterWide   = "sealedsecrets.bitnami.com/cluster-wide"
+)
+
+// Manifest is a SealedSecret manifest in the shape kubeseal produces. Fields
+// are declared in the order of the Kubernetes types, which is the key order
+// of `kubeseal -o json`.
+type Manifest struct {
+	Kind       string       `json:"kind"`
+	APIVersion string       `json:"apiVersion"`
+	Metadata   ObjectMeta   `json:"metadata"`
+	Spec       ManifestSpec `json:"spec"`
+}
+
+// ObjectMeta is the subset of object metadata emitted by kubeseal
+type ObjectMeta struct {
+	Name              string            `json:"name,omitempty"`
+	Namespace         string            `json:"namespace,omitempty"`
+	CreationTimestamp *string           `json:"creationTimestamp"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+}
+
+// ManifestSpec is the SealedSecret spec
+type ManifestSpec struct {
+	Template      ManifestTemplate  `json:"template"`
+	EncryptedData map[string]string `json:"encryptedData"`
+}
+
+// ManifestTemplate is the template of the Secret the controller will create
+type ManifestTemplate struct {
+	Metadata ObjectMeta `json:"metadata"`
+}
+
+// NewManifest builds a SealedSecret manifest from already encrypted values
+func NewManifest(namespace, name string, scope Scope, encryptedData map[string]string) *Manifest {
+	meta := ObjectMeta{Name: name, Namespace: namespace}
+	switch scope {
+	case ScopeNamespaceWide:
+		meta.Annotations = map[string]string{annotationNamespaceWide: "true"}
+	case ScopeClusterWide:
+		meta.Annotations = map[string]string{annotationClusterWide: "true"}
+	}
+
+	return &Manifest{
+		APIVersion: "bitnami.com/v1alpha1",
+		Kind:       "SealedSecret",
+		Metadata:   meta,
+		Spec: ManifestSpec{
+			EncryptedData: encryptedData,
+			Template:      ManifestTemplate{Metadata: meta},
+		},
+	}
+}
@@ -0,0 +1,169 @@
+package seal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// sessionKeyBytes is the AES-256 session key size used by sealed-secrets
+const sessionKeyBytes = 32
+
+// Scope represents the sealing scope of a SealedSecret
+type Scope string
+
+const (
+	ScopeStrict        Scope = "strict"
+	ScopeNamespaceWide Scope = "namespace-wide"
+	ScopeClusterWide   Scope = "cluster-wide"
+)
+
+// ParseScope parses a scope name as accepted by kubeseal --scope
+func ParseScope(s string) (Scope, error) {
+	switch Scope(s) {
+	case ScopeStrict, ScopeNamespaceWide, ScopeClusterWide:
+		return Scope(s), nil
+	case "":
+		return ScopeStrict, nil
+	}
+	return "", fmt.Errorf("unknown scope: %s", s)
+}
+
+// label returns the encryption label binding the ciphertext to its scope
+func (s Scope) label(namespace, name string) []byte {
+	switch s {
+	case ScopeClusterWide:
+		return nil
+	case ScopeNamespaceWide:
+		return []byte(namespace)
+	default:
+		return []byte(namespace + "/" + name)
+	}
+}
+
+// ParsePublicKey extracts the RSA public key from the controller's PEM certificate
+func ParsePublicKey(certPEM []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode certificate PEM")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	pubKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("certificate does not contain an RSA public key")
+	}
+	return pubKey, nil
+}
+
+// EncryptRaw encrypts a plaintext value the same way as `kubeseal --raw`
+// and returns the base64 encoded ciphertext
+func EncryptRaw(pubKey *rsa.PublicKey, namespace, name string, scope Scope, plainText []byte) (string, error) {
+	ciphertext, err := hybridEncrypt(rand.Reader, pubKey, plainText, scope.label(namespace, name))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// ParsePrivateKey parses a PEM encoded RSA private key (PKCS#1 or PKCS#8)
+func ParsePrivateKey(keyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode private key PEM")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// DecryptRaw decrypts a base64 encoded value produced by EncryptRaw (or kubeseal)
+func DecryptRaw(privKey *rsa.PrivateKey, namespace, name string, scope Scope, encoded string) ([]byte, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ciphertext: %w", err)
+	}
+	return hybridDecrypt(rand.Reader, privKey, ciphertext, scope.label(namespace, name))
+}
+
+// hybridEncrypt performs AES-GCM + RSA-OAEP encryption compatible with sealed-secrets.
+// The output is: RSA ciphertext length (2 bytes) || RSA ciphertext || AES ciphertext
+func hybridEncrypt(rnd io.Reader, pubKey *rsa.PublicKey, plainText, label []byte) ([]byte, error) {
+	sessionKey := make([]byte, sessionKeyBytes)
+	if _, err := io.ReadFull(rnd, sessionKey); err != nil {
+		return nil, fmt.Errorf("failed to generate session key: %w", err)
+	}
+
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaCiphertext, err := rsa.EncryptOAEP(sha256.New(), rnd, pubKey, sessionKey, label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt session key: %w", err)
+	}
+
+	ciphertext := make([]byte, 2, 2+len(rsaCiphertext)+len(plainText)+aead.Overhead())
+	binary.BigEndian.PutUint16(ciphertext, uint16(len(rsaCiphertext)))
+	ciphertext = append(ciphertext, rsaCiphertext...)
+
+	// The session key is used only once, so a zero nonce is safe
+	zeroNonce := make([]byte, aead.NonceSize())
+	return aead.Seal(ciphertext, zeroNonce, plainText, nil), nil
+}
+
+// hybridDecrypt reverses hybridEncrypt
+func hybridDecrypt(rnd io.Reader, privKey *rsa.PrivateKey, ciphertext, label []byte) ([]byte, error) {
+	if len(ciphertext) < 2 {
+		return nil, errors.New("ciphertext too short")
+	}
+	rsaLen := int(binary.BigEndian.Uint16(ciphertext))
+	if len(ciphertext) < 2+rsaLen {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), rnd, privKey, ciphertext[2:2+rsaLen], label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt session key: %w", err)
+	}
+
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	zeroNonce := make([]byte, aead.NonceSize())
+	return aead.Open(nil, zeroNonce, ciphertext[2+rsaLen:], nil)
+}
@@ -0,0 +1,105 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSealRoundTrip(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	plainText := []byte("s3cr3t\nwith a line break")
+
+	tests := []struct {
+		scope         Scope
+		namespace     string
+		name          string
+		wantDecrypted bool
+	}{
+		{ScopeStrict, "foo", "app", true},
+		{ScopeStrict, "foo", "other", false},
+		{ScopeStrict, "bar", "app", false},
+		{ScopeNamespaceWide, "foo", "other", true},
+		{ScopeNamespaceWide, "bar", "app", false},
+		{ScopeClusterWide, "bar", "other", true},
+	}
+	for _, tt := range tests {
+		// Sealed for foo/app, opened as namespace/name
+		sealed, err := EncryptRaw(&key.PublicKey, "foo", "app", tt.scope, plainText)
+		if err != nil {
+			t.Fatalf("EncryptRaw(%s): %v", tt.scope, err)
+		}
+
+		got, err := DecryptRaw(key, tt.namespace, tt.name, tt.scope, sealed)
+		if !tt.wantDecrypted {
+			if err == nil {
+				t.Errorf("%s: a value sealed for foo/app opened as %s/%s", tt.scope, tt.namespace, tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: DecryptRaw as %s/%s: %v", tt.scope, tt.namespace, tt.name, err)
+			continue
+		}
+		if !bytes.Equal(got, plainText) {
+			t.Errorf("%s: got %q, want %q", tt.scope, got, plainText)
+		}
+	}
+}
+
+func TestSealTamperedCiphertext(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	sealed, err := EncryptRaw(&key.PublicKey, "foo", "app", ScopeStrict, []byte("value"))
+	if err != nil {
+		t.Fatalf("EncryptRaw: %v", err)
+	}
+	if _, err := DecryptRaw(other, "foo", "app", ScopeStrict, sealed); err == nil {
+		t.Error("decrypted with another controller key")
+	}
+
+	tampered, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	tampered[len(tampered)-1] ^= 1
+	if _, err := DecryptRaw(key, "foo", "app", ScopeStrict, base64.StdEncoding.EncodeToString(tampered)); err == nil {
+		t.Error("decrypted a tampered ciphertext")
+	}
+	if _, err := DecryptRaw(key, "foo", "app", ScopeStrict, "AA=="); err == nil {
+		t.Error("decrypted a truncated ciphertext")
+	}
+}
+
+func TestManifestKeyOrder(t *testing.T) {
+	manifest := NewManifest("foo", "app", ScopeNamespaceWide, map[string]string{"password": "AgB..."})
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	// The key order of `kubeseal -o json`
+	want := []string{`"kind"`, `"apiVersion"`, `"metadata"`, `"name"`, `"namespace"`, `"creationTimestamp"`, `"annotations"`, `"spec"`, `"template"`, `"encryptedData"`}
+	rest := string(data)
+	for _, key := range want {
+		i := strings.Index(rest, key)
+		if i < 0 {
+			t.Fatalf("%s missing or out of order in %s", key, data)
+		}
+		rest = rest[i+len(key):]
+	}
+}
@@ -0,0 +1,178 @@
+package seal
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"sort"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// VerifyOptions configures Verify
+type VerifyOptions struct {
+	ControllerNamespace string
+	ControllerName      string
+	PrivateKey          *rsa.PrivateKey // optional; enables per-value comparison
+}
+
+// VerifyResult describes whether a SealedSecret still corresponds to the live Secret
+type VerifyResult struct {
+	Namespace      string
+	Name           string   // SealedSecret name
+	SecretName     string   // name of the Secret the controller produces
+	Decryptable    *bool    // controller answer, nil if the controller could not be asked
+	MissingKeys    []string // sealed keys absent from the live Secret
+	ExtraKeys      []string // live Secret keys absent from the sealed manifest
+	MismatchedKeys []string // keys whose decrypted value differs from the live value
+	MatchedKeys    []string // keys whose decrypted value matches the live value
+	UncomparedKeys []string // keys on both sides whose values were not compared (no private key)
+	Warnings       []string
+}
+
+// Stale returns true if the sealed manifest would not reproduce the live Secret
+func (r *VerifyResult) Stale() bool {
+	if r.Decryptable != nil && !*r.Decryptable {
+		return true
+	}
+	return len(r.MissingKeys) > 0 || len(r.ExtraKeys) > 0 || len(r.MismatchedKeys) > 0
+}
+
+// Unverified returns true if the keys match but some values could not be
+// compared, so the manifest may still carry outdated values
+func (r *VerifyResult) Unverified() bool {
+	return !r.Stale() && len(r.UncomparedKeys) > 0
+}
+
+// ParseManifest parses a SealedSecret manifest (YAML or JSON), e.g. from Git
+func ParseManifest(data []byte) (*unstructured.Unstructured, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(jsonData); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if obj.GetKind() != "SealedSecret" {
+		return nil, fmt.Errorf("manifest is a %s, not a SealedSecret", obj.GetKind())
+	}
+	return obj, nil
+}
+
+// Verify checks a SealedSecret against the live Secret it is supposed to produce.
+// Decryptability is asked from the controller; values are compared by hash only
+// when a controller private key is supplied.
+func Verify(ctx context.Context, client *k8s.Client, sealed *unstructured.Unstructured, opts VerifyOptions) (*VerifyResult, error) {
+	// The controller always names the Secret after the SealedSecret
+	result := &VerifyResult{
+		Namespace:  sealed.GetNamespace(),
+		Name:       sealed.GetName(),
+		SecretName: sealed.GetName(),
+	}
+
+	encryptedData, _, err := unstructured.NestedStringMap(sealed.Object, "spec", "encryptedData")
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec.encryptedData: %w", err)
+	}
+
+	// Ask the controller whether it can still decrypt the manifest
+	body, err := sealed.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	ok, err := client.VerifySealedSecret(ctx, opts.ControllerNamespace, opts.ControllerName, body)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("controller verification unavailable: %v", err))
+	} else {
+		result.Decryptable = &ok
+	}
+
+	result.Warnings = append(result.Warnings, syncWarnings(sealed)...)
+
+	secret, err := client.GetSecret(ctx, result.Namespace, result.SecretName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get secret %s: %w", result.SecretName, err)
+		}
+		result.Warnings = append(result.Warnings, "live Secret not found")
+		for key := range encryptedData {
+			result.MissingKeys = append(result.MissingKeys, key)
+		}
+		sort.Strings(result.MissingKeys)
+		return result, nil
+	}
+
+	for key := range secret.Data {
+		if _, ok := encryptedData[key]; !ok {
+			result.ExtraKeys = append(result.ExtraKeys, key)
+		}
+	}
+
+	scope := scopeOf(sealed)
+	for key, encoded := range encryptedData {
+		live, ok := secret.Data[key]
+		if !ok {
+			result.MissingKeys = append(result.MissingKeys, key)
+			continue
+		}
+		if opts.PrivateKey == nil {
+			result.UncomparedKeys = append(result.UncomparedKeys, key)
+			continue
+		}
+
+		plain, err := DecryptRaw(opts.PrivateKey, result.Namespace, result.Name, scope, encoded)
+		if err != nil {
+			result.MismatchedKeys = append(result.MismatchedKeys, key)
+			result.Warnings = append(result.Warnings, fmt.Sprintf("cannot decrypt %s with the given key: %v", key, err))
+			continue
+		}
+		if k8s.HashValue(plain) == k8s.HashValue(live) {
+			result.MatchedKeys = append(result.MatchedKeys, key)
+		} else {
+			result.MismatchedKeys = append(result.MismatchedKeys, key)
+		}
+	}
+
+	sort.Strings(result.MissingKeys)
+	sort.Strings(result.ExtraKeys)
+	sort.Strings(result.MismatchedKeys)
+	sort.Strings(result.MatchedKeys)
+	sort.Strings(result.UncomparedKeys)
+
+	return result, nil
+}
+
+// scopeOf returns the sealing scope declared by the SealedSecret annotations
+func scopeOf(sealed *unstructured.Unstructured) Scope {
+	annotations := sealed.GetAnnotations()
+	switch {
+	case annotations[annotationClusterWide] == "true":
+		return ScopeClusterWide
+	case annotations[annotationNamespaceWide] == "true":
+		return ScopeNamespaceWide
+	default:
+		return ScopeStrict
+	}
+}
+
+// syncWarnings returns warnings for failed Synced conditions reported by the controller
+func syncWarnings(sealed *unstructured.Unstructured) []string {
+	conditions, _, _ := unstructured.NestedSlice(sealed.Object, "status", "conditions")
+
+	var warnings []string
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if cond["type"] == "Synced" && cond["status"] == "False" {
+			warnings = append(warnings, fmt.Sprintf("controller reports Synced=False: %v", cond["message"]))
+		}
+	}
+	return warnings
+}
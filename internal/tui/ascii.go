@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// asciiGlyphs replaces the symbols of the UI with ASCII of the same width
+var asciiGlyphs = strings.NewReplacer(
+	"⚠️", "!",
+	"↑", "^", "↓", "v", "←", "<", "→", ">",
+	"✓", "+", "✗", "x", "×", "x", "⟳", "~",
+	"•", "*", "·", ".", "…", ".", "≠", "!",
+	"▶", ">", "▸", ">", "▾", "v", "█", "#", "⚠", "!", "⌫", "<", "★", "*",
+	"─", "-", "│", "|", "├", "+", "└", "`",
+)
+
+// asciiOnly returns true if the terminal is not expected to render box-drawing
+// characters and symbols correctly: Windows consoles (ConPTY) and the terminal
+// types without Unicode fonts. $ENVTOP_ASCII=1 or 0 overrides the detection.
+func asciiOnly() bool {
+	switch os.Getenv("ENVTOP_ASCII") {
+	case "1":
+		return true
+	case "0":
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return noAltScreenTerms[os.Getenv("TERM")] && os.Getenv("TERM") != ""
+}
+
+// WithASCII draws the UI with ASCII borders and symbols, e.g. from the --ascii flag
+func (m Model) WithASCII() Model {
+	m.ascii = true
+	return m
+}
@@ -0,0 +1,265 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ginbear/k8s-envtop/internal/drift"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// bulkDiffWorkers is the number of apps a bulk diff resolves at once
+const bulkDiffWorkers = 4
+
+// bulkState is the progress of one app of a bulk diff
+type bulkState int
+
+const (
+	bulkPending bulkState = iota
+	bulkRunning
+	bulkDone
+	bulkFailed
+)
+
+// bulkProgress is a running bulk diff of every app shared by two namespaces
+type bulkProgress struct {
+	nsA, nsB string
+	apps     []k8s.App
+	states   []bulkState
+	diffs    []env.AppDiff
+	finished int
+	events   <-chan bulkEvent
+	cancel   context.CancelFunc
+}
+
+// bulkEvent reports the progress of one app, by index into the apps
+type bulkEvent struct {
+	idx   int
+	state bulkState
+	diff  env.AppDiff
+}
+
+// bulkStartedMsg carries the apps of a bulk diff once its workers run
+type bulkStartedMsg struct {
+	nsA, nsB string
+	apps     []k8s.App
+	events   <-chan bulkEvent
+	cancel   context.CancelFunc
+	err      error
+}
+
+// bulkEventMsg carries the next progress event of a bulk diff
+type bulkEventMsg struct {
+	events <-chan bulkEvent
+	event  bulkEvent
+	closed bool
+}
+
+// loadWorklist starts a bulk diff of all shared apps, shown as a progress list.
+// The drift worklist is built once every app is compared.
+func (m Model) loadWorklist(nsA, nsB string) (tea.Model, tea.Cmd) {
+	m.loading = false
+	m.viewMode = ViewModeBulkProgress
+	m.bulk = bulkProgress{nsA: nsA, nsB: nsB}
+	resolver := m.resolver
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		apps, err := resolver.SharedApps(ctx, nsA, nsB)
+		if err != nil {
+			cancel()
+			return bulkStartedMsg{nsA: nsA, nsB: nsB, err: err}
+		}
+		events := make(chan bulkEvent, 2*len(apps))
+		go runBulkDiff(ctx, resolver, apps, nsB, events)
+		return bulkStartedMsg{nsA: nsA, nsB: nsB, apps: apps, events: events, cancel: cancel}
+	}
+}
+
+// runBulkDiff compares the apps with a pool of workers, reporting when each
+// app starts and ends. Cancelling ctx skips the apps not started yet and
+// aborts the API calls in flight. The events channel is closed at the end.
+func runBulkDiff(ctx context.Context, resolver *env.Resolver, apps []k8s.App, nsB string, events chan<- bulkEvent) {
+	defer close(events)
+
+	// The apps of a namespace share most of their ConfigMaps and Secrets
+	ctx = env.WithFetchCache(ctx)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < bulkDiffWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				events <- bulkEvent{idx: i, state: bulkRunning}
+				diff := resolver.CompareApp(ctx, apps[i], nsB)
+				state := bulkDone
+				if diff.Err != nil {
+					state = bulkFailed
+				}
+				events <- bulkEvent{idx: i, state: state, diff: diff}
+			}
+		}()
+	}
+
+feed:
+	for i := range apps {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// waitForBulkEvent returns a command delivering the next progress event of a bulk diff
+func waitForBulkEvent(events <-chan bulkEvent) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-events
+		return bulkEventMsg{events: events, event: event, closed: !ok}
+	}
+}
+
+// handleBulkStarted shows the apps of a bulk diff, unless it was cancelled meanwhile
+func (m Model) handleBulkStarted(msg bulkStartedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		if m.viewMode == ViewModeBulkProgress {
+			m.viewMode = ViewModeNormal
+			m.bulk = bulkProgress{}
+			m.err = msg.err
+		}
+		return m, nil
+	}
+	if m.viewMode != ViewModeBulkProgress || msg.nsA != m.bulk.nsA || msg.nsB != m.bulk.nsB || m.bulk.events != nil {
+		msg.cancel()
+		return m, nil
+	}
+	m.bulk.apps = msg.apps
+	m.bulk.states = make([]bulkState, len(msg.apps))
+	m.bulk.diffs = make([]env.AppDiff, len(msg.apps))
+	m.bulk.events = msg.events
+	m.bulk.cancel = msg.cancel
+	return m, waitForBulkEvent(msg.events)
+}
+
+// handleBulkEvent records the progress of an app. Once every app is compared,
+// the drift is scored and the worklist shown.
+func (m Model) handleBulkEvent(msg bulkEventMsg) (tea.Model, tea.Cmd) {
+	if msg.events != m.bulk.events {
+		return m, nil
+	}
+	if !msg.closed {
+		// The slices are shared with previous copies of the model
+		states := append([]bulkState(nil), m.bulk.states...)
+		states[msg.event.idx] = msg.event.state
+		m.bulk.states = states
+		if msg.event.state == bulkDone || msg.event.state == bulkFailed {
+			diffs := append([]env.AppDiff(nil), m.bulk.diffs...)
+			diffs[msg.event.idx] = msg.event.diff
+			m.bulk.diffs = diffs
+			m.bulk.finished++
+		}
+		return m, waitForBulkEvent(msg.events)
+	}
+
+	bulk := m.bulk
+	bulk.cancel()
+	m.bulk = bulkProgress{}
+	m.loading = true
+	context := m.context
+	return m, func() tea.Msg {
+		scores, ignores, history, err := drift.LoadScores(bulk.diffs, context, bulk.nsA, bulk.nsB)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return worklistMsg{
+			items:     drift.BuildWorklist(bulk.diffs, ignores, bulk.nsA, bulk.nsB),
+			ignores:   ignores,
+			scores:    scores,
+			nsA:       bulk.nsA,
+			nsB:       bulk.nsB,
+			recordErr: history.Record(context, bulk.nsA, bulk.nsB, scores, time.Now()),
+		}
+	}
+}
+
+// cancelBulkDiff stops a running bulk diff and returns to the main view
+func (m Model) cancelBulkDiff() (tea.Model, tea.Cmd) {
+	if m.bulk.cancel != nil {
+		m.bulk.cancel()
+	}
+	done := m.bulk.finished
+	m.bulk = bulkProgress{}
+	m.viewMode = ViewModeNormal
+	m.statusMessage = fmt.Sprintf("Bulk diff cancelled after %d apps", done)
+	return m, m.clearStatusAfter(3 * time.Second)
+}
+
+// renderBulkProgress renders the per-app progress of a running bulk diff
+func (m Model) renderBulkProgress() string {
+	bulk := m.bulk
+	title := titleStyle.Render(fmt.Sprintf("Bulk diff: %s vs %s", bulk.nsA, bulk.nsB))
+	content := []string{title, ""}
+
+	if bulk.events == nil {
+		content = append(content, mutedStyle.Render("  Listing the apps of both namespaces..."))
+	} else {
+		failed := 0
+		for _, state := range bulk.states {
+			if state == bulkFailed {
+				failed++
+			}
+		}
+		summary := fmt.Sprintf("%d/%d apps compared", bulk.finished, len(bulk.apps))
+		if failed > 0 {
+			summary += fmt.Sprintf(", %d failed", failed)
+		}
+		content = append(content, helpStyle.Render(summary), "")
+
+		// Keep the apps in progress in view: scroll past the finished ones
+		maxItems := m.height - 8
+		if maxItems < 1 {
+			maxItems = 1
+		}
+		start := 0
+		for start < len(bulk.states) && bulk.states[start] >= bulkDone && len(bulk.apps)-start > maxItems {
+			start++
+		}
+		for i := start; i < len(bulk.apps) && i < start+maxItems; i++ {
+			content = append(content, renderBulkRow(bulk.apps[i], bulk.states[i], bulk.diffs[i]))
+		}
+	}
+
+	content = append(content, "", helpStyle.Render("Esc: cancel the remaining apps"))
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// renderBulkRow renders the state of one app of a bulk diff
+func renderBulkRow(app k8s.App, state bulkState, diff env.AppDiff) string {
+	name := fmt.Sprintf("%-12s %-40s", app.Kind, truncate(app.Name, 40))
+	switch state {
+	case bulkRunning:
+		return warningStyle.Render("  ⟳ " + name + " running")
+	case bulkDone:
+		changed := 0
+		for _, r := range diff.Results {
+			if r.Status.IsDrift() {
+				changed++
+			}
+		}
+		if changed == 0 {
+			return diffSameStyle.Render("  ✓ " + name + " same")
+		}
+		return diffChangedStyle.Render(fmt.Sprintf("  ✓ %s %d differ", name, changed))
+	case bulkFailed:
+		return errorStyle.Render("  ✗ " + name + " " + diff.Err.Error())
+	default:
+		return mutedStyle.Render("  · " + name + " pending")
+	}
+}
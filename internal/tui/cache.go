@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// envCacheEntry is the resolved env of an app with the resourceVersions it was resolved from
+type envCacheEntry struct {
+	containerEnv []k8s.EnvVar
+	missing      []env.MissingOptional
+	versions     env.Versions
+}
+
+// cachedEnv returns the cached env of an app of the watched namespace
+func (m Model) cachedEnv(app k8s.App) (envCacheEntry, bool) {
+	if m.envCacheOff || !m.isWatched(app.Namespace) {
+		return envCacheEntry{}, false
+	}
+	entry, ok := m.envCache[k8s.AppKey(app)]
+	return entry, ok
+}
+
+// isWatched returns true if the live watch covers the namespace of the active context
+func (m Model) isWatched(namespace string) bool {
+	return m.watchKey != "" && m.watchKey == m.context+"/"+namespace
+}
+
+// cacheEnv caches the env of an app of the watched namespace. Results read before a
+// change the watch has already reported are not cached.
+func (m *Model) cacheEnv(app k8s.App, containerEnv []k8s.EnvVar, missing []env.MissingOptional, versions env.Versions) {
+	if m.envCacheOff || versions == nil || !m.isWatched(app.Namespace) {
+		return
+	}
+	for key, version := range versions {
+		if seen, ok := m.watchSeen[key]; ok && seen != version {
+			return
+		}
+	}
+	if m.envCache == nil {
+		m.envCache = make(map[string]envCacheEntry)
+	}
+	m.envCache[k8s.AppKey(app)] = envCacheEntry{containerEnv: containerEnv, missing: missing, versions: versions}
+}
+
+// invalidateEnvCache drops the cached env resolved from an object the watch reports
+// a new resourceVersion for, and the object itself from the resolver cache. When the
+// watch of a kind restarts or stops, changes may have been missed and the whole
+// cache is dropped.
+func (m *Model) invalidateEnvCache(event k8s.WatchEvent) {
+	if event.Kind == k8s.WatchKindNamespace {
+		return
+	}
+	if event.Kind == k8s.WatchKindConfigMap || event.Kind == k8s.WatchKindSecret {
+		if event.Resync || event.Stopped {
+			m.resolver.InvalidateKind(event.Kind)
+		} else {
+			m.resolver.Invalidate(event.Kind, event.Namespace, event.Name)
+		}
+	}
+	if event.Resync || event.Stopped {
+		m.envCache = nil
+		m.envCacheOff = m.envCacheOff || event.Stopped
+		return
+	}
+
+	key := env.VersionKey(event.Kind, event.Name)
+	if m.watchSeen == nil {
+		m.watchSeen = make(map[string]string)
+	}
+	m.watchSeen[key] = event.ResourceVersion
+	for appKey, entry := range m.envCache {
+		if version, ok := entry.versions[key]; ok && version != event.ResourceVersion {
+			delete(m.envCache, appKey)
+		}
+	}
+}
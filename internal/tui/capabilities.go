@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// capabilityState is the discovery state of an optional cluster feature.
+// Capabilities absent from Model.capabilities are still being discovered.
+type capabilityState int
+
+const (
+	capabilityPending capabilityState = iota
+	capabilityAvailable
+	capabilityMissing
+	capabilityUnknown // the check failed or ran out of time
+)
+
+// capabilityMsg carries the result of one capability check
+type capabilityMsg struct {
+	context    string
+	capability k8s.Capability
+	available  bool
+	err        error
+}
+
+// enabledCapabilities returns the capabilities to discover, without those
+// skipped in the config
+func (m Model) enabledCapabilities() []k8s.Capability {
+	var enabled []k8s.Capability
+	for _, c := range k8s.Capabilities {
+		if !slices.Contains(m.cfg.Startup.Skip, string(c)) {
+			enabled = append(enabled, c)
+		}
+	}
+	return enabled
+}
+
+// discoverCapabilities checks the optional cluster features in the background,
+// each on its own with the deadline of the config, so that a slow aggregated API
+// delays nothing but the features depending on it
+func (m Model) discoverCapabilities() tea.Cmd {
+	client := m.client
+	kubeContext := m.context
+	timeout := m.cfg.CheckTimeout()
+
+	var cmds []tea.Cmd
+	for _, capability := range m.enabledCapabilities() {
+		cmds = append(cmds, func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			available, err := client.DiscoverCapability(ctx, capability)
+			return capabilityMsg{context: kubeContext, capability: capability, available: available, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleCapability records the result of a capability check. Results for a
+// context that is no longer shown are dropped.
+func (m *Model) handleCapability(msg capabilityMsg) {
+	if msg.context != m.context {
+		return
+	}
+	if m.capabilities == nil {
+		m.capabilities = make(map[k8s.Capability]capabilityState)
+	}
+	switch {
+	case msg.err != nil:
+		m.capabilities[msg.capability] = capabilityUnknown
+	case msg.available:
+		m.capabilities[msg.capability] = capabilityAvailable
+	default:
+		m.capabilities[msg.capability] = capabilityMissing
+	}
+}
+
+// renderCapabilityLines renders the progress of the capability checks for the splash screen
+func (m Model) renderCapabilityLines() []string {
+	var lines []string
+	for _, c := range m.enabledCapabilities() {
+		switch m.capabilities[c] {
+		case capabilityAvailable:
+			lines = append(lines, diffAddedStyle.Render("✓")+" "+m.t("splash.capability_available", c))
+		case capabilityMissing:
+			lines = append(lines, mutedStyle.Render("-")+" "+m.t("splash.capability_missing", c))
+		case capabilityUnknown:
+			lines = append(lines, mutedStyle.Render("?")+" "+m.t("splash.capability_unknown", c))
+		default:
+			lines = append(lines, warningStyle.Render("…")+" "+m.t("splash.capability_discovering", c))
+		}
+	}
+	return lines
+}
+
+// renderCapabilityBadges lists the capabilities discovered so far in the header
+func (m Model) renderCapabilityBadges() string {
+	var names []string
+	for _, c := range m.enabledCapabilities() {
+		if m.capabilities[c] == capabilityAvailable {
+			names = append(names, c.String())
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return mutedStyle.Render("[" + strings.Join(names, ", ") + "]")
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// isChanged returns true if the variable changed since the previous view of the app
+func (m Model) isChanged(name string) bool {
+	return m.envChanges != nil && m.envChanges.Changed[name]
+}
+
+// handleChangedFilter toggles showing only the variables changed since the previous view
+func (m Model) handleChangedFilter() (tea.Model, tea.Cmd) {
+	if m.envChanges == nil {
+		m.statusMessage = m.t("changes.first_view")
+		return m, m.clearStatusAfter(2 * time.Second)
+	}
+	if len(m.envChanges.Changed) == 0 && !m.changedOnly {
+		m.statusMessage = m.t("changes.none", m.printer.DateTime(m.envChanges.Since))
+		return m, m.clearStatusAfter(2 * time.Second)
+	}
+	m.changedOnly = !m.changedOnly
+	m.activePane = PaneEnv
+	m.envCursor = 0
+	return m, nil
+}
+
+// changesTitle summarizes the changes since the previous view for the Env pane title
+func (m Model) changesTitle() string {
+	c := m.envChanges
+	if c == nil || c.Count() == 0 {
+		return ""
+	}
+	text := m.t("changes.changed", m.printer.Number(len(c.Changed)))
+	if len(c.Removed) > 0 {
+		text += m.t("changes.removed", m.printer.Number(len(c.Removed)))
+	}
+	text += m.t("changes.since", m.printer.DateTime(c.Since))
+	if m.changedOnly {
+		text += m.t("changes.only")
+	}
+	return warningStyle.Render(text)
+}
+
+// renderChangedBadge marks a variable changed since the previous view
+func (m Model) renderChangedBadge(ev k8s.EnvVar) string {
+	if !m.isChanged(ev.Name) {
+		return ""
+	}
+	return " " + warningStyle.Render("~changed")
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// consumersMsg carries the apps referencing the source of the selected variable
+type consumersMsg struct {
+	kind      string // "ConfigMap" or "Secret"
+	name      string
+	consumers []env.SourceConsumer
+}
+
+// handleConsumersStart lists every app of the namespace referencing the
+// ConfigMap or Secret the selected variable comes from
+func (m Model) handleConsumersStart() (tea.Model, tea.Cmd) {
+	if m.activePane != PaneEnv {
+		return m, nil
+	}
+	envVar, ok := m.selectedEnvVar()
+	if !ok {
+		return m, nil
+	}
+
+	var secret bool
+	switch envVar.SourceKind {
+	case k8s.EnvSourceConfigMap:
+	case k8s.EnvSourceSecret, k8s.EnvSourceSealedSecret:
+		secret = true
+	default:
+		m.statusMessage = "Selected variable is not sourced from a ConfigMap or Secret"
+		return m, m.clearStatusAfter(2 * time.Second)
+	}
+
+	apps := m.apps
+	name := envVar.SourceName
+	m.loading = true
+	return m, func() tea.Msg {
+		consumers, err := m.resolver.SourceConsumers(context.Background(), apps, secret, name)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		kind := "ConfigMap"
+		if secret {
+			kind = "Secret"
+		}
+		return consumersMsg{kind: kind, name: name, consumers: consumers}
+	}
+}
+
+// handleConsumers handles key press in the consumers view
+func (m Model) handleConsumers(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.consumersCursor > 0 {
+			m.consumersCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.consumersCursor < len(m.consumers)-1 {
+			m.consumersCursor++
+		}
+	}
+	return m, nil
+}
+
+// renderConsumers renders the apps referencing a ConfigMap or Secret
+func (m Model) renderConsumers() string {
+	apps := make(map[string]bool)
+	for _, c := range m.consumers {
+		apps[string(c.App.Kind)+"/"+c.App.Name] = true
+	}
+	title := titleStyle.Render(fmt.Sprintf("Used by: %s %s (%d apps, %d references)", m.consumersKind, m.consumersName, len(apps), len(m.consumers)))
+	content := []string{title, ""}
+
+	if len(m.consumers) == 0 {
+		content = append(content, mutedStyle.Render("  No app of this namespace references it"))
+	} else {
+		header := fmt.Sprintf("  %-36s %-20s %-28s %s", "APP", "CONTAINER", "VIA", "KEY")
+		content = append(content, helpStyle.Render(header))
+	}
+
+	maxItems := m.height - 6
+	if maxItems < 1 {
+		maxItems = 1
+	}
+	startIdx := 0
+	if m.consumersCursor >= maxItems {
+		startIdx = m.consumersCursor - maxItems + 1
+	}
+	for i := startIdx; i < len(m.consumers) && i < startIdx+maxItems; i++ {
+		c := m.consumers[i]
+		prefix := "  "
+		style := itemStyle
+		if i == m.consumersCursor {
+			prefix = "> "
+			style = selectedItemStyle
+		}
+		keyText := c.Key
+		if keyText == "" {
+			keyText = "(all keys)"
+		}
+		container := c.Container
+		if container == "" {
+			container = "-"
+		}
+		app := fmt.Sprintf("%s/%s", c.App.Kind, c.App.Name)
+		content = append(content, style.Render(fmt.Sprintf("%s%-36s %-20s %-28s %s",
+			prefix, truncate(app, 36), truncate(container, 20), truncate(c.Via, 28), keyText)))
+	}
+
+	content = append(content, "", helpStyle.Render("↑↓: scroll  Esc: back to main view"))
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
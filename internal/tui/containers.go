@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// setContainerEnv stores the per-container env of the selected app and shows the merged list
+func (m *Model) setContainerEnv(containerEnv []k8s.EnvVar) {
+	m.allContainerEnv = containerEnv
+	m.containerEnv = m.visibleEnv(containerEnv)
+	m.containers = env.Containers(m.containerEnv)
+	m.envConflicts = env.Conflicts(m.containerEnv)
+	m.containerIdx = 0
+	m.envVars = env.MergeContainers(m.containerEnv)
+}
+
+// visibleEnv drops the variables of sidecar containers unless they are shown
+func (m Model) visibleEnv(containerEnv []k8s.EnvVar) []k8s.EnvVar {
+	if m.showSidecars {
+		return containerEnv
+	}
+	return env.WithoutSidecars(containerEnv)
+}
+
+// handleSidecarToggle shows or hides the variables of Istio/Linkerd sidecar containers
+func (m Model) handleSidecarToggle() (tea.Model, tea.Cmd) {
+	m.showSidecars = !m.showSidecars
+	m.setContainerEnv(m.allContainerEnv)
+	m.envIdx = 0
+	m.envCursor = 0
+	if m.showSidecars {
+		m.statusMessage = "Showing sidecar env"
+	} else {
+		m.statusMessage = "Hiding sidecar env"
+	}
+	return m, m.clearStatusAfter(2 * time.Second)
+}
+
+// handleContainerSelect cycles the Env pane through all containers merged, then each container
+func (m Model) handleContainerSelect() (tea.Model, tea.Cmd) {
+	if len(m.containers) < 2 {
+		m.statusMessage = "Single container: nothing to select"
+		return m, m.clearStatusAfter(2 * time.Second)
+	}
+
+	m.showContainer((m.containerIdx + 1) % (len(m.containers) + 1))
+	m.activePane = PaneEnv
+	m.envIdx = 0
+	m.envCursor = 0
+	return m, nil
+}
+
+// showContainer shows the env of container idx-1 in the Env pane, or all containers for 0
+func (m *Model) showContainer(idx int) {
+	m.containerIdx = idx
+	if idx == 0 {
+		m.envVars = env.MergeContainers(m.containerEnv)
+		return
+	}
+	container := m.containers[idx-1]
+	m.envVars = nil
+	for _, ev := range m.containerEnv {
+		if ev.Container == container {
+			m.envVars = append(m.envVars, ev)
+		}
+	}
+}
+
+// containerTitle names the container shown in the Env pane, when the app has several,
+// and counts the hidden sidecar variables
+func (m Model) containerTitle() string {
+	hidden := ""
+	if n := len(m.allContainerEnv) - len(m.containerEnv); n > 0 {
+		hidden = mutedStyle.Render(fmt.Sprintf(" [%d sidecar vars hidden]", n))
+	}
+	if len(m.containers) < 2 {
+		return hidden
+	}
+	if m.containerIdx == 0 {
+		return mutedStyle.Render(fmt.Sprintf(" [all %d containers]", len(m.containers))) + hidden
+	}
+	return mutedStyle.Render(fmt.Sprintf(" [container: %s]", m.containers[m.containerIdx-1])) + hidden
+}
+
+// renderConflictBadge marks a variable that containers define with different values
+func (m Model) renderConflictBadge(ev k8s.EnvVar) string {
+	containers := m.envConflicts[ev.Name]
+	if len(containers) == 0 {
+		return ""
+	}
+	return " " + warningStyle.Render("≠"+strings.Join(containers, ","))
+}
@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// contextsMsg carries the contexts of the kubeconfig for the context picker
+type contextsMsg struct {
+	contexts []string
+	current  string
+}
+
+// contextSwitchedMsg carries the client built for the picked context, and the
+// expiry of its service account token in a service account session
+type contextSwitchedMsg struct {
+	client  *k8s.Client
+	expires time.Time
+}
+
+// handleContextSelectStart lists the contexts of the kubeconfig the session was started with
+func (m Model) handleContextSelectStart() (tea.Model, tea.Cmd) {
+	if m.fleet != nil {
+		m.statusMessage = "Multi-cluster session: contexts are set in the config"
+		return m, m.clearStatusAfter(2 * time.Second)
+	}
+
+	kubeconfig := m.client.Kubeconfig()
+	return m, func() tea.Msg {
+		contexts, current, err := k8s.ListContexts(kubeconfig)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return contextsMsg{contexts: contexts, current: current}
+	}
+}
+
+// handleContextSelect handles key press in the context picker
+func (m Model) handleContextSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.contextCursor > 0 {
+			m.contextCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.contextCursor < len(m.contextList)-1 {
+			m.contextCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		picked := m.contextList[m.contextCursor]
+		m.viewMode = ViewModeNormal
+		m.contextList = nil
+		if m.contextForDiff {
+			m.contextForDiff = false
+			m.loading = true
+			return m, m.loadDiffContext(picked)
+		}
+		if picked == m.context {
+			return m, nil
+		}
+		m.loading = true
+		kubeconfig := m.client.Kubeconfig()
+		return m, func() tea.Msg {
+			client, expires, err := m.newContextClient(kubeconfig, picked)
+			if err != nil {
+				return errorMsg{err: err}
+			}
+			return contextSwitchedMsg{client: client, expires: expires}
+		}
+	}
+
+	return m, nil
+}
+
+// newContextClient creates the client of a kubeconfig context. In a service
+// account session, it authenticates with a token of that account as well, and
+// returns when the token expires.
+func (m Model) newContextClient(kubeconfig, contextName string) (*k8s.Client, time.Time, error) {
+	client, err := k8s.NewClientForContext(kubeconfig, contextName)
+	if err != nil || m.cfg.Session.ServiceAccount == "" {
+		return client, time.Time{}, err
+	}
+	namespace, name, err := k8s.ParseServiceAccount(m.cfg.Session.ServiceAccount)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return client.WithServiceAccountToken(context.Background(), namespace, name, m.cfg.SessionTTL())
+}
+
+// switchContext replaces the client and drops everything loaded from the previous cluster
+func (m Model) switchContext(client *k8s.Client, expires time.Time) (tea.Model, tea.Cmd) {
+	if m.watchCancel != nil {
+		m.watchCancel()
+		m.watchCancel = nil
+	}
+	m.watchEvents = nil
+	m.watchKey = ""
+	m.stopTicker()
+	m.watchPending = watchPending{}
+	m.envCache = nil
+	m.watchSeen = nil
+
+	m.client = client
+	m.resolver = newResolver(client, m.cfg)
+	m.context = client.GetCurrentContext()
+	m.sessionExpires = expires
+
+	// The splash screen is shown again until the namespaces of the new cluster arrive
+	m.namespacesLoaded = false
+	m.namespaces = nil
+	m.namespacesMore = ""
+	m.namespaceIdx = 0
+	m.namespaceCursor = 0
+	m.namespaceTiers = nil
+	m.namespaceCreated = nil
+	m.namespaceLimits = nil
+	m.namespaceLimitsFor = ""
+	m.apps = nil
+	m.appIdx = 0
+	m.appCursor = 0
+	m.appHealth = nil
+	m.appHealthFor = ""
+	m.envVars = nil
+	m.envIdx = 0
+	m.envCursor = 0
+	m.setContainerEnv(nil)
+	m.missingOptional = nil
+	m.selectedPod = nil
+	m.security = nil
+	m.scalers = nil
+	m.mounts = nil
+	m.staleSources = nil
+	m.violations = nil
+	m.envChanges = nil
+	m.liveChanged = nil
+	m.filteredNamespaces = nil
+	m.filteredApps = nil
+	m.filteredEnvVars = nil
+	m.activePane = PaneNamespaces
+	m.err = nil
+
+	m.capabilities = nil
+	m.statusMessage = "Switched to context " + m.context
+	return m, tea.Batch(m.loadNamespaces(), m.discoverCapabilities(), m.updateTitle(), m.clearStatusAfter(2*time.Second))
+}
+
+// renderContextSelect renders the context picker
+func (m Model) renderContextSelect() string {
+	dialog := dialogStyle.Width(60)
+
+	content := []string{
+		dialogTitleStyle.Render("Switch context"),
+		"",
+		dialogTextStyle.Render(fmt.Sprintf("Current: %s", m.context)),
+		"",
+	}
+
+	maxItems := 15
+	startIdx := 0
+	if m.contextCursor >= maxItems {
+		startIdx = m.contextCursor - maxItems + 1
+	}
+
+	for i := startIdx; i < len(m.contextList) && i < startIdx+maxItems; i++ {
+		prefix := "  "
+		style := dialogTextStyle
+		if i == m.contextCursor {
+			prefix = "> "
+			style = selectedItemStyle
+		}
+		name := m.contextList[i]
+		if name == m.context {
+			name += " (current)"
+		}
+		content = append(content, style.Render(prefix+truncate(name, 54)))
+	}
+
+	content = append(content, "", helpStyle.Render("↑↓: select  Enter: switch  Esc: cancel"))
+
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
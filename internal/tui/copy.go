@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/report"
+)
+
+// copyField selects what the copy menu puts on the clipboard
+type copyField int
+
+const (
+	copyNone copyField = iota
+	copyName
+	copyValue
+	copyPair // NAME=VALUE
+	copyRowsTSV
+	copyRowsCSV
+)
+
+// copyOptions are the entries of the copy menu, in display order
+var copyOptions = []struct {
+	field copyField
+	label string
+}{
+	{copyName, "Name"},
+	{copyValue, "Value"},
+	{copyPair, "NAME=VALUE"},
+	{copyRowsTSV, "Visible rows (TSV)"},
+	{copyRowsCSV, "Visible rows (CSV)"},
+}
+
+// handleCopyStart opens the copy menu for the selected variable
+func (m Model) handleCopyStart() (tea.Model, tea.Cmd) {
+	if m.activePane != PaneEnv {
+		return m, nil
+	}
+	envVar, ok := m.selectedEnvVar()
+	if !ok {
+		return m, nil
+	}
+	m.viewMode = ViewModeCopyMenu
+	m.copyMenuIdx = 0
+	m.revealedEnvName = envVar.Name
+	m.revealNamespace = m.apps[m.appIdx].Namespace
+	return m, nil
+}
+
+// handleCopyMenu handles key press in the copy menu. Copying a secret value
+// requires the same OK confirmation as revealing it.
+func (m Model) handleCopyMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.copyMenuIdx > 0 {
+			m.copyMenuIdx--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.copyMenuIdx < len(copyOptions)-1 {
+			m.copyMenuIdx++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		field := copyOptions[m.copyMenuIdx].field
+		envVar, ok := m.selectedEnvVar()
+		if field == copyRowsTSV || field == copyRowsCSV {
+			m.revealInput.Reset()
+			model, cmd := m.closeReveal()
+			model, copyCmd := model.(Model).copyVisibleRows(field == copyRowsCSV)
+			return model, tea.Batch(cmd, copyCmd)
+		}
+		if !ok || field == copyName || !envVar.IsMasked() {
+			return m.copySelected(field)
+		}
+
+		if os.Getenv("ENVTOP_DISABLE_REVEAL") == "1" {
+			m.err = &revealDisabledError{}
+			return m.closeReveal()
+		}
+		if m.requireAltScreen() && !m.altScreen {
+			m.err = &revealNoAltScreenError{term: os.Getenv("TERM")}
+			return m.closeReveal()
+		}
+		m.copyField = field
+		m.viewMode = ViewModeRevealConfirm
+		m.revealInput.Reset()
+		m.revealInput.Focus()
+		return m, textinput.Blink
+	}
+
+	return m, nil
+}
+
+// copySelected copies the chosen field of the selected variable to the clipboard
+// and returns to normal mode. Secret values are copied without being displayed.
+func (m Model) copySelected(field copyField) (tea.Model, tea.Cmd) {
+	envVar, ok := m.selectedEnvVar()
+	m.revealInput.Reset()
+	model, cmd := m.closeReveal()
+	m = model.(Model)
+	if !ok {
+		return m, cmd
+	}
+
+	value := envVar.Value
+	if envVar.IsMasked() {
+		value = string(envVar.RawValue)
+	}
+	var text, what string
+	switch field {
+	case copyName:
+		text, what = envVar.Name, "name"
+	case copyValue:
+		text, what = value, "value"
+	default:
+		text, what = envVar.Name+"="+value, "NAME=VALUE"
+	}
+
+	if err := copyToClipboard(text); err != nil {
+		m.statusMessage = fmt.Sprintf("Copy failed: %v", err)
+	} else {
+		m.statusMessage = fmt.Sprintf("Copied %s of %s to clipboard", what, envVar.Name)
+	}
+	return m, tea.Batch(cmd, m.clearStatusAfter(3*time.Second))
+}
+
+// renderCopyMenu renders the copy menu
+func (m Model) renderCopyMenu() string {
+	dialog := dialogStyle.Width(50)
+
+	content := []string{dialogTitleStyle.Render("Copy: " + m.revealedEnvName), "", "Select what to copy:"}
+	for i, opt := range copyOptions {
+		prefix := "  "
+		style := dialogTextStyle
+		if i == m.copyMenuIdx {
+			prefix = "> "
+			style = selectedItemStyle
+		}
+		content = append(content, style.Render(prefix+opt.label))
+	}
+	content = append(content, "", helpStyle.Render("↑↓: select  Enter: copy  Esc: cancel"))
+
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
+
+// copyVisibleRows copies the rows the Env pane shows (search, filters and
+// collapsed groups applied) to the clipboard as TSV or CSV. Secret values are
+// redacted to their hash.
+func (m Model) copyVisibleRows(csv bool) (tea.Model, tea.Cmd) {
+	if m.activePane != PaneEnv || len(m.apps) == 0 || m.appIdx >= len(m.apps) {
+		return m, nil
+	}
+	var envVars []k8s.EnvVar
+	for _, row := range m.envRows() {
+		if row.index >= 0 {
+			envVars = append(envVars, m.envVars[row.index])
+		}
+	}
+	if len(envVars) == 0 {
+		m.statusMessage = "No rows to copy"
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+
+	rows := report.NewEnvRows(m.apps[m.appIdx], envVars, nil)
+	var buf bytes.Buffer
+	write, format := report.WriteTSV, "TSV"
+	if csv {
+		write, format = report.WriteCSV, "CSV"
+	}
+	if err := write(&buf, rows); err != nil {
+		m.statusMessage = fmt.Sprintf("Copy failed: %v", err)
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+
+	if err := copyToClipboard(buf.String()); err != nil {
+		m.statusMessage = fmt.Sprintf("Copy failed: %v", err)
+	} else {
+		m.statusMessage = fmt.Sprintf("Copied %d rows as %s to clipboard (secrets redacted)", len(rows), format)
+	}
+	return m, m.clearStatusAfter(3 * time.Second)
+}
@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// diffSide is one side of a diff: a namespace read through the resolver of a context
+type diffSide struct {
+	resolver  *env.Resolver
+	context   string
+	namespace string
+}
+
+// diffContextMsg carries the client and namespaces of the context picked for diff side B
+type diffContextMsg struct {
+	client     *k8s.Client
+	resolver   *env.Resolver // set when the client belongs to the fleet session
+	namespaces []string
+}
+
+// diffSides returns both sides of the diff being shown. Side B is read through
+// another context when a cross-cluster diff was selected.
+func (m Model) diffSides() (diffSide, diffSide) {
+	a := diffSide{resolver: m.resolver, context: m.context, namespace: m.diffNsA}
+	b := diffSide{resolver: m.resolver, context: m.context, namespace: m.diffNsB}
+	if m.diffResolverB != nil {
+		b.resolver = m.diffResolverB
+		b.context = m.diffContextB
+	}
+	return a, b
+}
+
+// crossCluster returns true when diff side B is read from another context
+func (m Model) crossCluster() bool {
+	return m.diffResolverB != nil
+}
+
+// diffNamespaceLabelB labels a namespace of diff side B, qualified by its context across clusters
+func (m Model) diffNamespaceLabelB(ns string) string {
+	if m.crossCluster() {
+		return m.diffContextB + "/" + ns
+	}
+	return m.namespaceLabel(ns)
+}
+
+// handleDiffContextStart opens the context picker to choose the cluster of diff side B
+func (m Model) handleDiffContextStart() (tea.Model, tea.Cmd) {
+	if m.diffBulk {
+		m.statusMessage = "Bulk diffs compare namespaces of the same cluster"
+		return m, m.clearStatusAfter(2 * time.Second)
+	}
+	m.contextForDiff = true
+
+	if m.fleet != nil {
+		contexts := make([]string, 0, len(m.fleet))
+		for _, client := range m.fleet {
+			contexts = append(contexts, client.GetCurrentContext())
+		}
+		return m, func() tea.Msg { return contextsMsg{contexts: contexts} }
+	}
+	return m.handleContextSelectStart()
+}
+
+// loadDiffContext connects to the context picked for diff side B and lists its namespaces
+func (m Model) loadDiffContext(picked string) tea.Cmd {
+	kubeconfig := m.client.Kubeconfig()
+	fleet := m.fleet
+	resolvers := m.resolvers
+	return func() tea.Msg {
+		var client *k8s.Client
+		var resolver *env.Resolver
+		for _, c := range fleet {
+			if c.GetCurrentContext() == picked {
+				client, resolver = c, resolvers[picked]
+			}
+		}
+		if client == nil {
+			var err error
+			client, _, err = m.newContextClient(kubeconfig, picked)
+			if err != nil {
+				return errorMsg{err: err}
+			}
+		}
+
+		namespaces, err := client.ListNamespaces(context.Background())
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return diffContextMsg{client: client, resolver: resolver, namespaces: namespaces}
+	}
+}
+
+// applyDiffContext switches diff side B to the picked context, preselecting the
+// namespace with the same name as the current one
+func (m Model) applyDiffContext(msg diffContextMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	m.viewMode = ViewModeDiffSelect
+	m.diffNsIdx = 0
+
+	kubeContext := msg.client.GetCurrentContext()
+	if kubeContext == m.context {
+		m.diffResolverB = nil
+		m.diffContextB = ""
+		m.diffNamespaces = m.otherNamespaces()
+		m.diffNsIdx = m.promotionTargetIdx()
+		return m, nil
+	}
+
+	m.diffResolverB = msg.resolver
+	if m.diffResolverB == nil {
+		m.diffResolverB = newResolver(msg.client, m.cfg)
+	}
+	m.diffContextB = kubeContext
+	m.diffNamespaces = msg.namespaces
+	current := m.namespaces[m.namespaceIdx]
+	for i, ns := range m.diffNamespaces {
+		if ns == current {
+			m.diffNsIdx = i
+		}
+	}
+	return m, nil
+}
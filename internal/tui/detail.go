@@ -0,0 +1,190 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// provenanceMsg carries the references to the variable shown in the detail view
+type provenanceMsg struct {
+	envVar     k8s.EnvVar
+	provenance *env.EnvVarProvenance
+}
+
+// handleDetailStart opens the detail view of the selected variable
+func (m Model) handleDetailStart() (tea.Model, tea.Cmd) {
+	if len(m.apps) == 0 || m.appIdx >= len(m.apps) {
+		return m, nil
+	}
+	envVar, ok := m.selectedEnvVar()
+	if !ok {
+		return m, nil
+	}
+
+	app := m.apps[m.appIdx]
+	pod := m.selectedPod
+	m.loading = true
+	return m, func() tea.Msg {
+		ctx := context.Background()
+		var provenance *env.EnvVarProvenance
+		var err error
+		if pod != nil {
+			provenance, err = m.resolver.PodEnvVarProvenance(ctx, pod.Namespace, pod.Name, envVar.Name)
+		} else {
+			provenance, err = m.resolver.AppEnvVarProvenance(ctx, app, envVar.Name)
+		}
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return provenanceMsg{envVar: envVar, provenance: provenance}
+	}
+}
+
+// handleDetail handles key press in the detail view
+func (m Model) handleDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.detailOffset > 0 {
+			m.detailOffset--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.detailOffset < len(m.detailLines())-1 {
+			m.detailOffset++
+		}
+	}
+	return m, nil
+}
+
+// detailLines lays out the detail view: the full value, then every reference
+func (m Model) detailLines() []graphLine {
+	ev := m.detailVar
+	width := m.width - 4
+	if width < 20 {
+		width = 20
+	}
+
+	lines := []graphLine{{text: "Value", style: dialogTitleStyle}}
+	if ev.IsMasked() {
+		note := fmt.Sprintf("%s  len=%d", m.hashLabel(ev.Hash), ev.ValueLen)
+		if ev.IsSealed {
+			note += " sealed"
+		}
+		if ev.Redacted {
+			note += " redacted"
+		}
+		lines = append(lines, graphLine{text: "  " + note, style: envHashStyle})
+	} else {
+		for _, line := range wrapValue(ev.Value, width) {
+			lines = append(lines, graphLine{text: "  " + line, style: envValueStyle})
+		}
+		if ev.Detail != "" {
+			lines = append(lines, graphLine{text: "  (" + ev.Detail + ")", style: mutedStyle})
+		}
+	}
+	if ev.Unresolved {
+		lines = append(lines, graphLine{text: "  known only at runtime", style: mutedStyle})
+	}
+	if ev.Forbidden {
+		lines = append(lines, graphLine{text: fmt.Sprintf("  no permission to get %s %s", ev.SourceKind, ev.SourceName), style: envForbiddenStyle})
+	}
+
+	lines = append(lines, graphLine{}, graphLine{text: "Defined in", style: dialogTitleStyle})
+	if len(m.provenance.References) == 0 {
+		lines = append(lines, graphLine{text: "  (no reference found in the pod spec)", style: mutedStyle})
+	}
+	for _, ref := range m.provenance.References {
+		container := "container " + ref.Container
+		if ref.Init {
+			container = "init container " + ref.Container
+		}
+		lines = append(lines, graphLine{text: fmt.Sprintf("  %s  %s", container, ref.Field), style: itemStyle})
+		for _, detail := range referenceDetails(ref, m.printer.DateTime) {
+			lines = append(lines, graphLine{text: "    " + detail, style: GetSourceKindStyle(string(ref.Kind))})
+		}
+		if source, ok := m.staleSources[env.StaleKey(ref.Kind, ref.Source)]; ok {
+			text := fmt.Sprintf("    changed after %d of %d running pods started, restart them to pick it up: %s",
+				len(source.StalePods), source.Pods, strings.Join(source.StalePods, ", "))
+			lines = append(lines, graphLine{text: text, style: warningStyle})
+		}
+	}
+	return lines
+}
+
+// referenceDetails describes the source of one reference
+func referenceDetails(ref env.EnvVarReference, dateTime func(t time.Time) string) []string {
+	var details []string
+	switch ref.Kind {
+	case k8s.EnvSourceInline:
+		return []string{"inline value"}
+	case k8s.EnvSourceFieldRef:
+		return []string{"pod field " + ref.Source}
+	case k8s.EnvSourceResourceRef:
+		return []string{"container resource " + ref.Source}
+	}
+
+	source := fmt.Sprintf("%s %s/%s", ref.Kind, ref.Namespace, ref.Source)
+	if ref.EnvFrom {
+		source += " (envFrom)"
+	} else {
+		source += " (env)"
+	}
+	details = append(details, source)
+
+	keyText := "key " + ref.Key
+	if ref.Prefix != "" {
+		keyText += fmt.Sprintf(" with prefix %s", ref.Prefix)
+	}
+	details = append(details, keyText)
+
+	switch {
+	case ref.Missing && ref.Optional:
+		details = append(details, "optional, not found")
+	case ref.Missing:
+		details = append(details, "not found")
+	case !ref.Modified.IsZero():
+		details = append(details, "last modified "+dateTime(ref.Modified))
+	}
+	if ref.Terminating {
+		details = append(details, "marked for deletion: pods keep the values, new pods fail to start once it is gone")
+	}
+	return details
+}
+
+// wrapValue splits a value into lines of at most width characters
+func wrapValue(value string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		for len(line) > width {
+			lines = append(lines, line[:width])
+			line = line[width:]
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// renderDetail renders the detail view of a variable
+func (m Model) renderDetail() string {
+	title := titleStyle.Render(fmt.Sprintf("%s  %s", m.detailVar.Name, m.detailVar.SourceKind))
+	content := []string{title, ""}
+
+	maxItems := m.height - 5
+	if maxItems < 1 {
+		maxItems = 1
+	}
+	lines := m.detailLines()
+	for i := m.detailOffset; i < len(lines) && i < m.detailOffset+maxItems; i++ {
+		content = append(content, lines[i].style.Render(lines[i].text))
+	}
+
+	content = append(content, "", helpStyle.Render("↑↓: scroll  Esc: back to main view"))
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
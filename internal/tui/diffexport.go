@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/report"
+)
+
+// handleDiffExportStart opens the output path prompt for the diff shown in diff mode
+func (m Model) handleDiffExportStart() (tea.Model, tea.Cmd) {
+	if len(m.diffResults) == 0 {
+		return m, nil
+	}
+	m.exportInput.SetValue(fmt.Sprintf("envtop-diff-%s-%s-%s.md", m.diffAppName, m.diffNsA, m.diffNsB))
+	m.exportInput.CursorEnd()
+	m.exportInput.Focus()
+	m.exportDiff = true
+	m.viewMode = ViewModeExportInput
+	return m, nil
+}
+
+// writeDiffFile writes the diff in the format of the path extension and goes back to the diff
+func (m Model) writeDiffFile(path string) (tea.Model, tea.Cmd) {
+	m.viewMode = ViewModeDiffShow
+
+	app := k8s.App{Name: m.diffAppName, Namespace: m.diffNsA, Kind: m.diffAppKind}
+	rep := report.NewDiffReport(m.context, app, m.diffNsA, m.diffNsB, m.diffResults)
+	format := report.DiffFormatOf(path)
+
+	f, err := os.Create(path)
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("Export failed: %v", err)
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+	defer f.Close()
+	if err := report.WriteDiff(f, format, rep); err != nil {
+		m.statusMessage = fmt.Sprintf("Export failed: %v", err)
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+	if err := f.Close(); err != nil {
+		m.statusMessage = fmt.Sprintf("Export failed: %v", err)
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+
+	m.statusMessage = fmt.Sprintf("Exported diff of %d variables to %s (%s)", len(m.diffResults), path, format)
+	return m, m.clearStatusAfter(3 * time.Second)
+}
+
+// renderDiffExportInput renders the export prompt of the diff
+func (m Model) renderDiffExportInput() string {
+	dialog := dialogStyle.Width(60)
+	content := []string{
+		dialogTitleStyle.Render("Export diff"),
+		"",
+		dialogTextStyle.Render(fmt.Sprintf("%s: %s vs %s. Format from the extension (.md, .json, .diff):", m.diffAppName, m.diffNsA, m.diffNsB)),
+		m.exportInput.View(),
+		"",
+		mutedStyle.Render("Secret values are written as their hash"),
+		"",
+		helpStyle.Render("Enter: write  Esc: cancel"),
+	}
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// editState is the variable being edited and the patch changing it: either
+// envPatch (an inline value of the workload) or cmPatch (a ConfigMap key)
+type editState struct {
+	name     string
+	oldValue string
+	ref      env.EnvVarReference
+	envPatch *k8s.EnvValuePatch
+	cmPatch  *k8s.ConfigMapValuePatch
+
+	body      string // indented patch, for the preview
+	checking  bool   // the dry run is in flight
+	dryRunErr error
+}
+
+// editTargetMsg carries the definition of the variable to edit
+type editTargetMsg struct {
+	edit editState
+}
+
+// editPreviewMsg carries the result of the server-side dry run of the patch
+type editPreviewMsg struct {
+	err error
+}
+
+// editAppliedMsg reports that the patch was applied
+type editAppliedMsg struct {
+	err error
+}
+
+// handleEditStart looks up where the selected variable is defined and opens the
+// edit prompt. Only inline values and ConfigMap keys of a workload can be edited.
+func (m Model) handleEditStart() (tea.Model, tea.Cmd) {
+	if m.activePane != PaneEnv || len(m.apps) == 0 || m.appIdx >= len(m.apps) {
+		return m, nil
+	}
+	ev, ok := m.selectedEnvVar()
+	if !ok {
+		return m, nil
+	}
+
+	app := m.apps[m.appIdx]
+	refusal := ""
+	switch {
+	case m.selectedPod != nil:
+		refusal = "Select the app instead of a pod to edit its env"
+	case ev.IsMasked():
+		refusal = "Secret and redacted values cannot be edited"
+	case ev.Unresolved || ev.SourceKind != k8s.EnvSourceInline && ev.SourceKind != k8s.EnvSourceConfigMap:
+		refusal = "Only inline values and ConfigMap keys can be edited"
+	case ev.SourceKind == k8s.EnvSourceInline && app.Kind == k8s.AppKindJob:
+		refusal = "The pod template of a Job cannot be changed"
+	}
+	if refusal != "" {
+		m.statusMessage = refusal
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+
+	m.loading = true
+	return m, func() tea.Msg {
+		provenance, err := m.resolver.AppEnvVarProvenance(context.Background(), app, ev.Name)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		ref, ok := effectiveReference(provenance, ev)
+		if !ok {
+			return errorMsg{err: fmt.Errorf("could not find where %s is defined", ev.Name)}
+		}
+		return editTargetMsg{edit: editState{name: ev.Name, oldValue: ev.Value, ref: ref}}
+	}
+}
+
+// effectiveReference returns the reference the shown value comes from: the
+// last one of the variable's container and source, since later entries win
+func effectiveReference(p *env.EnvVarProvenance, ev k8s.EnvVar) (env.EnvVarReference, bool) {
+	var found env.EnvVarReference
+	ok := false
+	for _, ref := range p.References {
+		if ev.Container != "" && ref.Container != ev.Container || ref.Kind != ev.SourceKind {
+			continue
+		}
+		if ref.Kind == k8s.EnvSourceConfigMap && (ref.Source != ev.SourceName || ref.Missing) {
+			continue
+		}
+		found, ok = ref, true
+	}
+	return found, ok
+}
+
+// handleEditInput handles key press in the edit prompt. Enter prepares the
+// patch and checks it with a server-side dry run before asking to apply it.
+func (m Model) handleEditInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.viewMode = ViewModeNormal
+		m.edit = editState{}
+		return m, nil
+
+	case tea.KeyEnter:
+		value := m.editInput.Value()
+		if value == m.edit.oldValue {
+			m.viewMode = ViewModeNormal
+			m.statusMessage = "Value unchanged"
+			return m, m.clearStatusAfter(3 * time.Second)
+		}
+
+		ref := m.edit.ref
+		var body []byte
+		var err error
+		if ref.Kind == k8s.EnvSourceConfigMap {
+			p := k8s.ConfigMapValuePatch{Namespace: ref.Namespace, Name: ref.Source, Key: ref.Key, Value: value}
+			m.edit.envPatch, m.edit.cmPatch = nil, &p
+			body, err = p.Body()
+		} else {
+			p := k8s.EnvValuePatch{App: m.apps[m.appIdx], Container: ref.Container, Init: ref.Init, Name: m.edit.name, Value: value}
+			m.edit.envPatch, m.edit.cmPatch = &p, nil
+			body, err = p.Body()
+		}
+		if err != nil {
+			m.viewMode = ViewModeNormal
+			m.err = err
+			return m, nil
+		}
+
+		var indented bytes.Buffer
+		if json.Indent(&indented, body, "", "  ") == nil {
+			body = indented.Bytes()
+		}
+		m.edit.body = string(body)
+		m.edit.checking = true
+		m.edit.dryRunErr = nil
+		m.viewMode = ViewModeEditConfirm
+		return m, m.applyEdit(true)
+	}
+
+	var cmd tea.Cmd
+	m.editInput, cmd = m.editInput.Update(msg)
+	return m, cmd
+}
+
+// handleEditConfirm handles key press in the edit confirmation dialog. The
+// patch can only be applied once its dry run succeeded.
+func (m Model) handleEditConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.Confirm) && !m.edit.checking && m.edit.dryRunErr == nil {
+		m.loading = true
+		return m, m.applyEdit(false)
+	}
+	return m, nil
+}
+
+// applyEdit applies the prepared patch, or only checks it with dryRun
+func (m Model) applyEdit(dryRun bool) tea.Cmd {
+	edit := m.edit
+	return func() tea.Msg {
+		ctx := context.Background()
+		var err error
+		if edit.cmPatch != nil {
+			err = m.client.PatchConfigMapValue(ctx, *edit.cmPatch, dryRun)
+		} else {
+			err = m.client.PatchEnvValue(ctx, *edit.envPatch, dryRun)
+		}
+		if dryRun {
+			return editPreviewMsg{err: err}
+		}
+		return editAppliedMsg{err: err}
+	}
+}
+
+// handleEditApplied reports the applied patch and reloads the env
+func (m Model) handleEditApplied(msg editAppliedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	m.viewMode = ViewModeNormal
+	edit := m.edit
+	m.edit = editState{}
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+
+	if edit.cmPatch != nil {
+		m.statusMessage = fmt.Sprintf("Patched ConfigMap %s key %s", edit.cmPatch.Name, edit.cmPatch.Key)
+	} else {
+		m.statusMessage = fmt.Sprintf("Patched %s in %s %s", edit.name, edit.envPatch.App.Kind, edit.envPatch.App.Name)
+	}
+	return m, tea.Batch(m.clearStatusAfter(5*time.Second), m.resolveEnvVars(true))
+}
+
+// editTarget describes what the edit changes
+func (m Model) editTarget() string {
+	ref := m.edit.ref
+	if ref.Kind == k8s.EnvSourceConfigMap {
+		return fmt.Sprintf("ConfigMap %s, key %s", ref.Source, ref.Key)
+	}
+	app := m.apps[m.appIdx]
+	container := "container"
+	if ref.Init {
+		container = "init container"
+	}
+	return fmt.Sprintf("%s %s, %s %s (%s)", app.Kind, app.Name, container, ref.Container, ref.Field)
+}
+
+// renderEditInput renders the edit prompt
+func (m Model) renderEditInput() string {
+	dialog := dialogStyle.Width(70)
+	content := []string{
+		dialogTitleStyle.Render("Edit " + m.edit.name),
+		"",
+		dialogTextStyle.Render(m.editTarget()),
+		"",
+		m.editInput.View(),
+		"",
+		helpStyle.Render("Enter: preview patch  Esc: cancel"),
+	}
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
+
+// renderEditConfirm renders the patch, its dry-run result and the consequences of applying it
+func (m Model) renderEditConfirm() string {
+	dialog := dialogStyle.Width(70)
+	content := []string{
+		dialogTitleStyle.Render("Apply patch to " + m.edit.name + "?"),
+		"",
+		dialogTextStyle.Render(m.editTarget()),
+		diffRemovedStyle.Render("- " + truncate(m.edit.oldValue, 64)),
+		diffAddedStyle.Render("+ " + truncate(m.editInput.Value(), 64)),
+		"",
+		mutedStyle.Render("Strategic merge patch:"),
+		m.edit.body,
+		"",
+	}
+
+	switch {
+	case m.edit.checking:
+		content = append(content, mutedStyle.Render("Dry run: checking..."))
+	case m.edit.dryRunErr != nil:
+		content = append(content, errorStyle.Render("Dry run failed: "+m.edit.dryRunErr.Error()))
+	default:
+		content = append(content, diffSameStyle.Render("Dry run: OK"))
+	}
+
+	app := m.apps[m.appIdx]
+	switch {
+	case m.edit.cmPatch != nil:
+		content = append(content, warningStyle.Render("Every app reading the ConfigMap is affected; running pods keep the old value until they restart."))
+	case app.Kind == k8s.AppKindCronJob:
+		content = append(content, warningStyle.Render(fmt.Sprintf("Only the next Jobs of %s get the new value.", app.Name)))
+	default:
+		content = append(content, warningStyle.Render(fmt.Sprintf("Applying changes the pod template: %s rolls out new pods.", app.Name)))
+	}
+
+	help := "y: apply  n/Esc: cancel"
+	if m.edit.checking || m.edit.dryRunErr != nil {
+		help = "n/Esc: cancel"
+	}
+	content = append(content, "", helpStyle.Render(help))
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
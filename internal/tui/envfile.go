@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/report"
+)
+
+// handleExportStart opens the output path prompt of the env file export
+func (m Model) handleExportStart() (tea.Model, tea.Cmd) {
+	if len(m.apps) == 0 || len(m.envVars) == 0 {
+		return m, nil
+	}
+	m.exportInput.SetValue(m.apps[m.appIdx].Name + ".env")
+	m.exportInput.CursorEnd()
+	m.exportInput.Focus()
+	m.exportSecrets = false
+	m.exportDiff = false
+	m.viewMode = ViewModeExportInput
+	return m, nil
+}
+
+// exportSecretsAllowed returns true if secret values may be written in clear,
+// under the same restrictions as reveal
+func (m Model) exportSecretsAllowed() bool {
+	if os.Getenv("ENVTOP_DISABLE_REVEAL") == "1" {
+		return false
+	}
+	return !m.requireAltScreen() || m.altScreen
+}
+
+// handleExportInput handles key press in the export prompt
+func (m Model) handleExportInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.viewMode = ViewModeNormal
+		if m.exportDiff {
+			m.viewMode = ViewModeDiffShow
+		}
+		return m, nil
+
+	case tea.KeyTab:
+		if !m.exportDiff && m.exportSecretsAllowed() {
+			m.exportSecrets = !m.exportSecrets
+		}
+		return m, nil
+
+	case tea.KeyEnter:
+		path := strings.TrimSpace(m.exportInput.Value())
+		if path == "" {
+			return m, nil
+		}
+		if m.exportDiff {
+			return m.writeDiffFile(path)
+		}
+		m.viewMode = ViewModeNormal
+		if err := m.writeEnvFile(path); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.statusMessage = fmt.Sprintf("Exported %d variables to %s", len(m.envVars), path)
+		if m.exportSecrets {
+			m.statusMessage += " (with secret values)"
+		}
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+
+	var cmd tea.Cmd
+	m.exportInput, cmd = m.exportInput.Update(msg)
+	return m, cmd
+}
+
+// writeEnvFile writes the env shown in the Env pane, in the format of the path extension
+func (m Model) writeEnvFile(path string) error {
+	// The file may hold secret values: keep it private to the user
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+	defer f.Close()
+
+	if err := report.WriteEnvFile(f, report.EnvFileFormatOf(path), m.envVars, m.exportSecrets); err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+	return f.Close()
+}
+
+// renderExportInput renders the export prompt
+func (m Model) renderExportInput() string {
+	if m.exportDiff {
+		return m.renderDiffExportInput()
+	}
+	dialog := dialogStyle.Width(60)
+
+	secrets := "[ ] include secret values (Tab)"
+	switch {
+	case !m.exportSecretsAllowed():
+		secrets = mutedStyle.Render("secret values: disabled (reveal is restricted)")
+	case m.exportSecrets:
+		secrets = warningStyle.Render("[x] include secret values (Tab)")
+	}
+
+	content := []string{
+		dialogTitleStyle.Render("Export env file"),
+		"",
+		dialogTextStyle.Render(fmt.Sprintf("%d variables of %s. Format from the extension (.env, .json, .yaml):", len(m.envVars), m.apps[m.appIdx].Name)),
+		m.exportInput.View(),
+		"",
+		secrets,
+		"",
+		helpStyle.Render("Enter: write  Tab: toggle secrets  Esc: cancel"),
+	}
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
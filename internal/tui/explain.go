@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ginbear/k8s-envtop/internal/env"
+)
+
+// explainMsg carries the resolution trace of a variable
+type explainMsg struct {
+	explanation *env.Explanation
+}
+
+// handleExplainStart traces how the selected variable got its value
+func (m Model) handleExplainStart() (tea.Model, tea.Cmd) {
+	if m.activePane != PaneEnv || len(m.apps) == 0 {
+		return m, nil
+	}
+	envVar, ok := m.selectedEnvVar()
+	if !ok {
+		return m, nil
+	}
+
+	m.loading = true
+	return m, m.loadExplanation(envVar.Name)
+}
+
+// loadExplanation resolves the trace for the selected app (or pod)
+func (m Model) loadExplanation(name string) tea.Cmd {
+	app := m.apps[m.appIdx]
+	pod := m.selectedPod
+	return func() tea.Msg {
+		ctx := context.Background()
+		var explanation *env.Explanation
+		var err error
+		if pod != nil {
+			explanation, err = m.resolver.ExplainPodEnvVar(ctx, pod.Namespace, pod.Name, name)
+		} else {
+			explanation, err = m.resolver.ExplainAppEnvVar(ctx, app, name)
+		}
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return explainMsg{explanation: explanation}
+	}
+}
+
+// handleExplain handles key press in the explain view
+func (m Model) handleExplain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.explainOffset > 0 {
+			m.explainOffset--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.explainOffset < len(m.explanation.Steps)-1 {
+			m.explainOffset++
+		}
+	}
+	return m, nil
+}
+
+// renderExplain renders the resolution trace, one step per line grouped by container
+func (m Model) renderExplain() string {
+	exp := m.explanation
+	title := titleStyle.Render("Explain: " + exp.Name)
+	content := []string{title, ""}
+
+	maxItems := m.height - 6
+	if maxItems < 1 {
+		maxItems = 1
+	}
+
+	container := "\x00"
+	for i := m.explainOffset; i < len(exp.Steps) && len(content) < maxItems+2; i++ {
+		step := exp.Steps[i]
+		if step.Container != container {
+			container = step.Container
+			if container != "" {
+				content = append(content, dialogTitleStyle.Render("container "+container))
+			}
+		}
+
+		marker := "  "
+		style := itemStyle
+		if step.Applied {
+			marker = "✓ "
+			style = selectedItemStyle
+		}
+		line := fmt.Sprintf("%s%-36s %s", marker, truncate(step.Source, 36), step.Detail)
+		content = append(content, style.Render(truncate(line, m.width-2)))
+	}
+
+	content = append(content, "",
+		mutedStyle.Render("✓ marks the source each container ends up with; env entries override envFrom, later envFrom override earlier ones"),
+		helpStyle.Render("↑↓: scroll  Esc: back to main view"),
+	)
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// WithFleet aggregates several clients (one per kubeconfig context) into one session.
+// The Namespaces pane lists the namespaces of every context grouped by context, and
+// selecting a namespace switches to the client of its context.
+func (m Model) WithFleet(clients []*k8s.Client) Model {
+	if len(clients) == 0 {
+		return m
+	}
+	m.fleet = clients
+	m.resolvers = make(map[string]*env.Resolver, len(clients))
+	for _, client := range clients {
+		m.resolvers[client.GetCurrentContext()] = newResolver(client, m.cfg)
+	}
+	m.client = clients[0]
+	m.context = clients[0].GetCurrentContext()
+	m.resolver = m.resolvers[m.context]
+	return m
+}
+
+// nsKey returns the key of a namespace in the namespace maps (tiers, creation
+// times). Namespaces are qualified by their context only in a fleet session.
+func (m Model) nsKey(kubeContext, ns string) string {
+	if m.fleet == nil {
+		return ns
+	}
+	return kubeContext + "/" + ns
+}
+
+// namespaceContext returns the context of the namespace at index i
+func (m Model) namespaceContext(i int) string {
+	if i < len(m.namespaceContexts) {
+		return m.namespaceContexts[i]
+	}
+	return m.context
+}
+
+// tierOf returns the tier of a namespace of the active context ("" if unknown)
+func (m Model) tierOf(ns string) string {
+	return m.namespaceTiers[m.nsKey(m.context, ns)]
+}
+
+// namespaceSearchNames returns the strings namespace search matches against;
+// in a fleet session they include the context, so a cluster can be searched for
+func (m Model) namespaceSearchNames() []string {
+	if m.fleet == nil {
+		return m.namespaces
+	}
+	names := make([]string, len(m.namespaces))
+	for i, ns := range m.namespaces {
+		names[i] = m.nsKey(m.namespaceContext(i), ns)
+	}
+	return names
+}
+
+// activateNamespaceContext switches the client to the context of the selected
+// namespace. State cached for the previous context is dropped.
+func (m *Model) activateNamespaceContext() tea.Cmd {
+	kubeContext := m.namespaceContext(m.namespaceIdx)
+	if m.fleet == nil || kubeContext == m.context {
+		return nil
+	}
+	for _, client := range m.fleet {
+		if client.GetCurrentContext() != kubeContext {
+			continue
+		}
+		m.client = client
+		m.resolver = m.resolvers[kubeContext]
+		m.context = kubeContext
+		m.appHealth = nil
+		m.appHealthFor = ""
+		m.namespaceLimits = nil
+		m.namespaceLimitsFor = ""
+		m.capabilities = nil
+		return m.discoverCapabilities()
+	}
+	return nil
+}
+
+// loadFleetNamespaces lists the namespaces of every context concurrently.
+// Unreachable contexts are reported in the status line instead of failing the session.
+func (m Model) loadFleetNamespaces() tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		details := make([][]k8s.Namespace, len(m.fleet))
+		errs := make([]error, len(m.fleet))
+		var wg sync.WaitGroup
+		for i, client := range m.fleet {
+			wg.Add(1)
+			go func(i int, client *k8s.Client) {
+				defer wg.Done()
+				details[i], errs[i] = client.ListNamespaceDetails(ctx)
+			}(i, client)
+		}
+		wg.Wait()
+
+		msg := namespacesLoadedMsg{
+			tiers:     make(map[string]string),
+			createdAt: make(map[string]time.Time),
+		}
+		var failed []string
+		for i, client := range m.fleet {
+			kubeContext := client.GetCurrentContext()
+			if errs[i] != nil {
+				failed = append(failed, kubeContext)
+				continue
+			}
+			for _, ns := range details[i] {
+				key := m.nsKey(kubeContext, ns.Name)
+				msg.namespaces = append(msg.namespaces, ns.Name)
+				msg.contexts = append(msg.contexts, kubeContext)
+				msg.createdAt[key] = ns.CreatedAt
+				if tier := m.cfg.TierOf(ns.Name, ns.Labels); tier != "" {
+					msg.tiers[key] = tier
+				}
+			}
+		}
+
+		if len(failed) == len(m.fleet) {
+			return errorMsg{err: fmt.Errorf("failed to list namespaces in every context: %w", errs[0])}
+		}
+		if len(failed) > 0 {
+			msg.warning = "Unreachable contexts: " + strings.Join(failed, ", ")
+		}
+		return msg
+	}
+}
@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// graphMsg carries the config dependency graph of the selected app
+type graphMsg struct {
+	graph *env.ConfigGraph
+}
+
+// handleGraphStart loads the config dependency graph of the selected app
+func (m Model) handleGraphStart() (tea.Model, tea.Cmd) {
+	if len(m.apps) == 0 || m.appIdx >= len(m.apps) {
+		return m, nil
+	}
+	app := m.apps[m.appIdx]
+	m.loading = true
+	return m, func() tea.Msg {
+		graph, err := m.resolver.AppConfigGraph(context.Background(), app)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return graphMsg{graph: graph}
+	}
+}
+
+// handleGraph handles key press in the graph view
+func (m Model) handleGraph(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.graphOffset > 0 {
+			m.graphOffset--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.graphOffset < len(graphLines(m.graph))-1 {
+			m.graphOffset++
+		}
+	}
+	return m, nil
+}
+
+// graphLine is a rendered line of the graph with its style
+type graphLine struct {
+	text  string
+	style lipgloss.Style
+}
+
+// graphLines draws the graph as an ASCII tree:
+// app -> containers -> sources -> origins
+func graphLines(graph *env.ConfigGraph) []graphLine {
+	lines := []graphLine{{text: fmt.Sprintf("%s %s", graph.App.Kind, graph.App.Name), style: dialogTitleStyle}}
+
+	for i, c := range graph.Containers {
+		branch, indent := "├─ ", "│  "
+		if i == len(graph.Containers)-1 {
+			branch, indent = "└─ ", "   "
+		}
+		name := "container " + c.Name
+		if c.Init {
+			name = "init container " + c.Name
+		}
+		lines = append(lines, graphLine{text: branch + name, style: itemStyle})
+
+		children := make([]graphLine, 0, len(c.Sources)+1)
+		for _, s := range c.Sources {
+			children = append(children, graphSourceLine(s))
+		}
+		if c.Inline > 0 || c.Runtime > 0 {
+			children = append(children, graphLine{text: fmt.Sprintf("env: %d inline, %d from pod fields/resources", c.Inline, c.Runtime), style: mutedStyle})
+		}
+		if len(children) == 0 {
+			children = append(children, graphLine{text: "(no env)", style: mutedStyle})
+		}
+
+		for j, child := range children {
+			childBranch := "├─ "
+			if j == len(children)-1 {
+				childBranch = "└─ "
+			}
+			child.text = indent + childBranch + child.text
+			lines = append(lines, child)
+		}
+	}
+	return lines
+}
+
+// graphSourceLine describes one ConfigMap/Secret of a container and its origin
+func graphSourceLine(s env.GraphSource) graphLine {
+	var via []string
+	if s.EnvFrom {
+		from := "envFrom"
+		if s.Prefix != "" {
+			from += " (prefix " + s.Prefix + ")"
+		}
+		via = append(via, from)
+	}
+	if len(s.Vars) > 0 {
+		via = append(via, "env "+strings.Join(s.Vars, ", "))
+	}
+
+	text := fmt.Sprintf("%s → %s %s", strings.Join(via, " + "), s.Kind, s.Name)
+	style := GetSourceKindStyle(string(s.Kind))
+	switch {
+	case s.Missing && s.Optional:
+		text += "  (optional, not found)"
+		style = mutedStyle
+	case s.Missing:
+		text += "  (not found)"
+		style = diffRemovedStyle
+	case s.Origin != "":
+		text += " ← " + s.Origin
+	}
+	if s.Terminating {
+		text += "  (terminating)"
+		style = warningStyle
+	}
+	if s.Kind == k8s.EnvSourceSecret && s.Origin == "" && !s.Missing {
+		text += "  (created directly)"
+	}
+	return graphLine{text: text, style: style}
+}
+
+// renderGraph renders the config dependency graph of the selected app
+func (m Model) renderGraph() string {
+	title := titleStyle.Render("Config Graph: " + m.graph.App.Name)
+	content := []string{title, ""}
+
+	maxItems := m.height - 6
+	if maxItems < 1 {
+		maxItems = 1
+	}
+	lines := graphLines(m.graph)
+	for i := m.graphOffset; i < len(lines) && i < m.graphOffset+maxItems; i++ {
+		content = append(content, lines[i].style.Render(truncate(lines[i].text, m.width-2)))
+	}
+
+	content = append(content, "",
+		mutedStyle.Render("→ where a container reads env from  ← what the ConfigMap/Secret is generated from"),
+		helpStyle.Render("↑↓: scroll  Esc: back to main view"),
+	)
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
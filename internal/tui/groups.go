@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// minGroupSize is the number of variables sharing a prefix that makes a group
+const minGroupSize = 3
+
+// envRow is one row of the Env pane: a variable, or the header of a prefix group
+// in the tree view
+type envRow struct {
+	index int    // index into envVars, -1 for a group header
+	group string // prefix of the group the row belongs to ("" when ungrouped)
+	count int    // number of variables in the group (group headers only)
+}
+
+// envPrefix returns the grouping prefix of a variable name, up to and including
+// its first underscore (SPRING_DATASOURCE_URL -> SPRING_), or "" if it has none
+func envPrefix(name string) string {
+	i := strings.Index(name, "_")
+	if i <= 0 || i == len(name)-1 {
+		return ""
+	}
+	return name[:i+1]
+}
+
+// envRows returns the rows of the Env pane. In the tree view, variables sharing a
+// prefix are gathered under a group header at the position of their first member,
+// and the members of collapsed groups are hidden. Searching always lists flat.
+// Pinned variables come first, outside of any group.
+func (m *Model) envRows() []envRow {
+	pinned, indices := m.splitPinned(m.GetFilteredEnvVars())
+	rows := make([]envRow, 0, len(pinned)+len(indices))
+	for _, i := range pinned {
+		rows = append(rows, envRow{index: i})
+	}
+	if !m.groupByPrefix || m.IsSearchingPane(PaneEnv) {
+		for _, i := range indices {
+			rows = append(rows, envRow{index: i})
+		}
+		return rows
+	}
+
+	members := make(map[string][]int)
+	for _, i := range indices {
+		if p := envPrefix(m.envVars[i].Name); p != "" {
+			members[p] = append(members[p], i)
+		}
+	}
+
+	emitted := make(map[string]bool)
+	for _, i := range indices {
+		p := envPrefix(m.envVars[i].Name)
+		if len(members[p]) < minGroupSize {
+			rows = append(rows, envRow{index: i})
+			continue
+		}
+		if emitted[p] {
+			continue
+		}
+		emitted[p] = true
+		rows = append(rows, envRow{index: -1, group: p, count: len(members[p])})
+		if m.expandedGroups[p] {
+			for _, j := range members[p] {
+				rows = append(rows, envRow{index: j, group: p})
+			}
+		}
+	}
+	return rows
+}
+
+// handleGroupToggle switches the Env pane between the flat list and the prefix tree,
+// keeping the selected variable under the cursor
+func (m Model) handleGroupToggle() (tea.Model, tea.Cmd) {
+	selected, ok := m.selectedEnvVar()
+	m.groupByPrefix = !m.groupByPrefix
+	m.envCursor = 0
+	if ok {
+		m.focusEnvVar(selected.Name)
+	}
+
+	m.statusMessage = "Env: flat list"
+	if m.groupByPrefix {
+		m.statusMessage = "Env: grouped by prefix (Enter expands/collapses)"
+	}
+	return m, m.clearStatusAfter(2 * time.Second)
+}
+
+// toggleGroup expands or collapses the group under the cursor. It returns false
+// if the cursor is not on a group header.
+func (m *Model) toggleGroup() bool {
+	rows := m.envRows()
+	if m.envCursor >= len(rows) || rows[m.envCursor].index >= 0 {
+		return false
+	}
+	m.setGroupExpanded(rows[m.envCursor].group, !m.expandedGroups[rows[m.envCursor].group])
+	return true
+}
+
+// setGroupExpanded records whether a group is expanded. The map is copied since
+// it is shared by the copies of the model.
+func (m *Model) setGroupExpanded(group string, expanded bool) {
+	groups := make(map[string]bool, len(m.expandedGroups)+1)
+	for g, e := range m.expandedGroups {
+		groups[g] = e
+	}
+	groups[group] = expanded
+	m.expandedGroups = groups
+}
+
+// focusEnvVar moves the cursor to the named variable, expanding its group if needed
+func (m *Model) focusEnvVar(name string) {
+	if p := envPrefix(name); m.groupByPrefix && p != "" && !m.expandedGroups[p] && !slices.Contains(m.pinned, name) {
+		m.setGroupExpanded(p, true)
+	}
+	for pos, row := range m.envRows() {
+		if row.index >= 0 && m.envVars[row.index].Name == name {
+			m.envCursor = pos
+			return
+		}
+	}
+}
+
+// renderGroupRow renders the header of a prefix group
+func (m Model) renderGroupRow(row envRow, selected bool) string {
+	prefix := "  "
+	style := titleStyle
+	if selected {
+		prefix = "> "
+		style = selectedItemStyle
+	}
+	arrow := "▸"
+	if m.expandedGroups[row.group] {
+		arrow = "▾"
+	}
+	return style.Render(fmt.Sprintf("%s%s %s* ", prefix, arrow, row.group)) + mutedStyle.Render(fmt.Sprintf("(%d)", row.count))
+}
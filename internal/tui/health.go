@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// recentDeployWindow is how recent a rollout must be for the "recently deployed" filter
+const recentDeployWindow = 24 * time.Hour
+
+// appFilter is a quick filter of the Apps pane by workload health
+type appFilter int
+
+const (
+	appFilterNone appFilter = iota
+	appFilterFailing
+	appFilterConfigEvents
+	appFilterRecent
+	appFilterCount
+)
+
+// String returns the label shown in the Apps pane title
+func (f appFilter) String() string {
+	switch f {
+	case appFilterFailing:
+		return "failing pods"
+	case appFilterConfigEvents:
+		return "config events"
+	case appFilterRecent:
+		return "deployed <24h"
+	default:
+		return ""
+	}
+}
+
+// next returns the filter that follows f in the cycle
+func (f appFilter) next() appFilter {
+	return (f + 1) % appFilterCount
+}
+
+// loadAppHealth loads the health of every app in a namespace.
+// Failures (e.g. events not readable) are not fatal: badges and filters stay empty.
+func (m Model) loadAppHealth(namespace string) tea.Cmd {
+	return func() tea.Msg {
+		health, _ := m.client.ListAppHealth(context.Background(), namespace)
+		return appHealthMsg{namespace: namespace, health: health}
+	}
+}
+
+// healthOf returns the health of an app of the selected namespace
+func (m Model) healthOf(app k8s.App) (k8s.AppHealth, bool) {
+	if m.appHealth == nil || m.appHealthFor != app.Namespace {
+		return k8s.AppHealth{}, false
+	}
+	h, ok := m.appHealth[k8s.AppKey(app)]
+	return h, ok
+}
+
+// isRecentlyDeployed returns true if the app rolled out within recentDeployWindow
+func isRecentlyDeployed(h k8s.AppHealth) bool {
+	return !h.LastDeployed.IsZero() && time.Since(h.LastDeployed) < recentDeployWindow
+}
+
+// matchesAppFilter returns true if the app passes the active health filter
+func (m Model) matchesAppFilter(app k8s.App) bool {
+	if m.appFilter == appFilterNone {
+		return true
+	}
+	h, _ := m.healthOf(app)
+	switch m.appFilter {
+	case appFilterFailing:
+		return h.FailingPods > 0
+	case appFilterConfigEvents:
+		return h.ConfigEvents > 0
+	case appFilterRecent:
+		return isRecentlyDeployed(h)
+	}
+	return true
+}
+
+// handleAppFilter cycles the health filter of the Apps pane
+func (m Model) handleAppFilter() (tea.Model, tea.Cmd) {
+	m.appFilter = m.appFilter.next()
+	m.appCursor = 0
+	m.activePane = PaneApps
+	if m.appFilter != appFilterNone && m.appHealth == nil {
+		m.statusMessage = "Workload health not available (check RBAC for events, replicasets and controllerrevisions)"
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+	return m, nil
+}
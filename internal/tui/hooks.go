@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/config"
+)
+
+// hookDoneMsg reports the end of a hook command
+type hookDoneMsg struct {
+	name string
+	err  error
+}
+
+// hookBindings returns the key bindings of the key hooks, for the command palette
+func hookBindings(hooks []config.HookConfig) []key.Binding {
+	var bindings []key.Binding
+	for _, hook := range hooks {
+		if hook.On == config.HookOnKey && hook.Key != "" {
+			bindings = append(bindings, key.NewBinding(key.WithKeys(hook.Key), key.WithHelp(hook.Key, "hook: "+hook.Name)))
+		}
+	}
+	return bindings
+}
+
+// CheckHooks validates the hooks of the config against the key map: events
+// must be known, and the key of a hook must not be taken by an action
+func CheckHooks(hooks []config.HookConfig, keys KeyMap) error {
+	bound := make(map[string]string)
+	for action, binding := range keys.bindings() {
+		if binding.Enabled() {
+			for _, k := range binding.Keys() {
+				bound[k] = action
+			}
+		}
+	}
+
+	for i, hook := range hooks {
+		name := hook.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if strings.TrimSpace(hook.Command) == "" {
+			return fmt.Errorf("hook %s has no command", name)
+		}
+		switch hook.On {
+		case config.HookOnNamespace, config.HookOnApp:
+			if hook.Key != "" {
+				return fmt.Errorf("hook %s: key is only used with on: %s", name, config.HookOnKey)
+			}
+		case config.HookOnKey:
+			if hook.Key == "" {
+				return fmt.Errorf("hook %s has no key", name)
+			}
+			if action, ok := bound[hook.Key]; ok {
+				return fmt.Errorf("hook %s: key %q is bound to %s (remap it under keys)", name, hook.Key, action)
+			}
+			bound[hook.Key] = "hook " + name
+		default:
+			return fmt.Errorf("hook %s: unknown event %q (one of %s, %s, %s)", name, hook.On, config.HookOnNamespace, config.HookOnApp, config.HookOnKey)
+		}
+	}
+	return nil
+}
+
+// fireHooks runs the hooks of an event
+func (m Model) fireHooks(on string) tea.Cmd {
+	if m.cfg == nil || m.tutorial {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, hook := range m.cfg.Hooks {
+		if hook.On == on {
+			cmds = append(cmds, m.runHook(hook))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleHookKey runs the key hook bound to msg, if any
+func (m Model) handleHookKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.cfg == nil || m.tutorial {
+		return nil, false
+	}
+	for _, hook := range m.cfg.Hooks {
+		if hook.On == config.HookOnKey && msg.String() == hook.Key {
+			return m.runHook(hook), true
+		}
+	}
+	return nil, false
+}
+
+// hookEnv returns the environment of hook commands: the selection, on top of
+// the environment of envtop. Values are never interpolated into the command,
+// so names cannot inject shell syntax. Only key hooks get the selected variable.
+func (m Model) hookEnv(hook config.HookConfig) []string {
+	vars := map[string]string{"CONTEXT": m.context}
+	if len(m.namespaces) > 0 && m.namespaceIdx < len(m.namespaces) {
+		vars["NAMESPACE"] = m.namespaces[m.namespaceIdx]
+	}
+	if len(m.apps) > 0 && m.appIdx < len(m.apps) {
+		app := m.apps[m.appIdx]
+		vars["NAMESPACE"] = app.Namespace
+		vars["APP"] = app.Name
+		vars["APP_KIND"] = string(app.Kind)
+	}
+	if m.selectedPod != nil {
+		vars["POD"] = m.selectedPod.Name
+	}
+	if hook.On == config.HookOnKey && m.activePane == PaneEnv {
+		if ev, ok := m.selectedEnvVar(); ok {
+			vars["ENV_NAME"] = ev.Name
+			if ev.SourceName != "" {
+				vars["ENV_SOURCE"] = string(ev.SourceKind) + "/" + ev.SourceName
+			}
+		}
+	}
+
+	env := os.Environ()
+	for _, name := range []string{"CONTEXT", "NAMESPACE", "APP", "APP_KIND", "POD", "ENV_NAME", "ENV_SOURCE"} {
+		env = append(env, name+"="+vars[name])
+	}
+	return env
+}
+
+// runHook runs the command of a hook: in the foreground with the terminal when
+// it is interactive, in the background otherwise. Failures are reported in the
+// status line with the first line of the error output.
+func (m Model) runHook(hook config.HookConfig) tea.Cmd {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.Command(shell, flag, hook.Command)
+	cmd.Env = m.hookEnv(hook)
+
+	if hook.Interactive {
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return hookDoneMsg{name: hook.Name, err: err}
+		})
+	}
+	return func() tea.Msg {
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		if err != nil {
+			if line, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); line != "" {
+				err = fmt.Errorf("%w: %s", err, line)
+			}
+		}
+		return hookDoneMsg{name: hook.Name, err: err}
+	}
+}
+
+// handleHookDone reports a failed hook in the status line
+func (m Model) handleHookDone(msg hookDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err == nil {
+		return m, nil
+	}
+	m.statusMessage = fmt.Sprintf("Hook %s failed: %v", msg.name, msg.err)
+	return m, m.clearStatusAfter(5 * time.Second)
+}
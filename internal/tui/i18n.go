@@ -0,0 +1,26 @@
+package tui
+
+import (
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"github.com/ginbear/k8s-envtop/internal/i18n"
+)
+
+// newPrinter returns the message printer of the configured (or detected) locale.
+// A broken catalog is not worth failing the UI for: English is used instead.
+func newPrinter(cfg *config.Config) *i18n.Printer {
+	formats := i18n.Formats{
+		Date:      cfg.Locale.DateFormat,
+		DateTime:  cfg.Locale.DateTimeFormat,
+		Thousands: cfg.Locale.Thousands,
+	}
+	printer, err := i18n.New(cfg.Locale.Language, formats)
+	if err != nil {
+		printer, _ = i18n.New(i18n.DefaultLanguage, formats)
+	}
+	return printer
+}
+
+// t returns the translation of a UI message
+func (m Model) t(key string, args ...any) string {
+	return m.printer.T(key, args...)
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// idleCheckInterval is how often the idle time is checked
+const idleCheckInterval = 10 * time.Second
+
+// idleCheckMsg triggers an idle time check
+type idleCheckMsg struct{}
+
+// idleTimeout returns the configured idle lock timeout (0 if disabled)
+func (m Model) idleTimeout() time.Duration {
+	if m.cfg == nil || m.cfg.IdleLock.TimeoutMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(m.cfg.IdleLock.TimeoutMinutes) * time.Minute
+}
+
+// scheduleIdleCheck schedules the next idle check when the idle lock is enabled
+func (m Model) scheduleIdleCheck() tea.Cmd {
+	if m.idleTimeout() == 0 {
+		return nil
+	}
+	return tea.Tick(idleCheckInterval, func(time.Time) tea.Msg {
+		return idleCheckMsg{}
+	})
+}
+
+// handleIdleCheck locks the UI once the idle timeout has passed
+func (m Model) handleIdleCheck() (tea.Model, tea.Cmd) {
+	next := m.scheduleIdleCheck()
+	if m.locked || time.Since(m.lastActivity) < m.idleTimeout() {
+		return m, next
+	}
+
+	m.locked = true
+	// A revealed value must not come back after unlocking
+	m.revealedValue = ""
+	m.revealedEnvName = ""
+	m.revealCopied = false
+	m.revealedSecret = nil
+	m.revealedSecretName = ""
+	m.revealNamespace = ""
+	if m.viewMode == ViewModeRevealMenu || m.viewMode == ViewModeRevealConfirm || m.viewMode == ViewModeRevealShow {
+		m.viewMode = ViewModeNormal
+		m.revealInput.Reset()
+	}
+
+	m.unlockInput = textinput.New()
+	m.unlockInput.Placeholder = "Type OK to unlock"
+	m.unlockInput.CharLimit = 10
+	m.unlockInput.Width = 20
+	m.unlockInput.Focus()
+
+	return m, tea.Batch(next, tea.ClearScreen, tea.SetWindowTitle("envtop (locked)"))
+}
+
+// handleLocked handles key presses on the lock screen
+func (m Model) handleLocked(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.cfg != nil && m.cfg.IdleLock.Confirm {
+		if msg.Type != tea.KeyEnter {
+			var cmd tea.Cmd
+			m.unlockInput, cmd = m.unlockInput.Update(msg)
+			return m, cmd
+		}
+		if m.unlockInput.Value() != "OK" {
+			m.unlockInput.Reset()
+			return m, nil
+		}
+	}
+
+	m.locked = false
+	return m, m.updateTitle()
+}
+
+// renderLockScreen renders the idle lock screen, which shows no cluster content
+func (m Model) renderLockScreen() string {
+	content := []string{
+		dialogTitleStyle.Render("envtop is locked"),
+		"",
+		dialogTextStyle.Render("The screen was locked after inactivity."),
+		"",
+	}
+	if m.cfg != nil && m.cfg.IdleLock.Confirm {
+		content = append(content, m.unlockInput.View(), "", helpStyle.Render("Enter: unlock"))
+	} else {
+		content = append(content, helpStyle.Render("Press any key to unlock"))
+	}
+
+	return m.centerDialog(dialogStyle.Width(50).Render(strings.Join(content, "\n")))
+}
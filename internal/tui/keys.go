@@ -1,25 +1,58 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 // KeyMap defines all key bindings for the application
 type KeyMap struct {
-	Up       key.Binding
-	Down     key.Binding
-	Left     key.Binding
-	Right    key.Binding
-	Tab      key.Binding
-	ShiftTab key.Binding
-	Enter    key.Binding
-	Back     key.Binding
-	Reveal   key.Binding
-	Diff     key.Binding
-	Search   key.Binding
-	Seal     key.Binding
-	Quit     key.Binding
-	Help     key.Binding
-	Confirm  key.Binding
-	Cancel   key.Binding
+	Up           key.Binding
+	Down         key.Binding
+	Left         key.Binding
+	Right        key.Binding
+	Tab          key.Binding
+	ShiftTab     key.Binding
+	Enter        key.Binding
+	Back         key.Binding
+	Reveal       key.Binding
+	Diff         key.Binding
+	Search       key.Binding
+	Seal         key.Binding
+	Flags        key.Binding
+	Verify       key.Binding
+	Pods         key.Binding
+	Worklist     key.Binding
+	Usage        key.Binding
+	Connect      key.Binding
+	Kubectl      key.Binding
+	Cleanup      key.Binding
+	HealthFilter key.Binding
+	Changed      key.Binding
+	Query        key.Binding
+	Container    key.Binding
+	Explain      key.Binding
+	Context      key.Binding
+	Export       key.Binding
+	Copy         key.Binding
+	CopyRows     key.Binding
+	Edit         key.Binding
+	Group        key.Binding
+	Across       key.Binding
+	Graph        key.Binding
+	History      key.Binding
+	Sidecars     key.Binding
+	Consumers    key.Binding
+	Pin          key.Binding
+	Reload       key.Binding
+	Palette      key.Binding
+	Quit         key.Binding
+	Help         key.Binding
+	Confirm      key.Binding
+	Cancel       key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings
@@ -73,6 +106,114 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("s"),
 			key.WithHelp("s", "seal value"),
 		),
+		Flags: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "feature flags"),
+		),
+		Verify: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "verify sealed secret"),
+		),
+		Pods: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pick pod"),
+		),
+		Worklist: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "drift worklist"),
+		),
+		Usage: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "secret usage"),
+		),
+		Connect: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "test connectivity"),
+		),
+		Kubectl: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "kubectl commands"),
+		),
+		Cleanup: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "stale previews"),
+		),
+		HealthFilter: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "filter apps by health"),
+		),
+		Changed: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "changed since last view"),
+		),
+		Query: key.NewBinding(
+			key.WithKeys("Q"),
+			key.WithHelp("Q", "fleet query"),
+		),
+		Container: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "select container"),
+		),
+		Explain: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "explain value"),
+		),
+		Context: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "switch context"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "export env file"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy name/value"),
+		),
+		CopyRows: key.NewBinding(
+			key.WithKeys("Y"),
+			key.WithHelp("Y", "copy visible rows (TSV)"),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "modify value"),
+		),
+		Group: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "group by prefix"),
+		),
+		Across: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "variable across namespaces"),
+		),
+		Graph: key.NewBinding(
+			key.WithKeys("G"),
+			key.WithHelp("G", "config graph"),
+		),
+		History: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "diff against a past rollout"),
+		),
+		Sidecars: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "show/hide sidecar env"),
+		),
+		Consumers: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "apps using the source"),
+		),
+		Pin: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "pin/unpin variable"),
+		),
+		Reload: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "reload"),
+		),
+		Palette: key.NewBinding(
+			key.WithKeys("ctrl+k"),
+			key.WithHelp("ctrl+k", "command palette"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c"),
 			key.WithHelp("q", "quit"),
@@ -102,6 +243,86 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Left, k.Right},
 		{k.Tab, k.ShiftTab, k.Enter, k.Back},
-		{k.Search, k.Reveal, k.Seal, k.Diff, k.Quit},
+		{k.Search, k.Reveal, k.Seal, k.Verify, k.Diff, k.Flags, k.Pods, k.Worklist, k.Usage, k.Connect, k.Kubectl, k.Cleanup, k.HealthFilter, k.Changed, k.Query, k.Container, k.Explain, k.Context, k.Export, k.Copy, k.CopyRows, k.Edit, k.Group, k.Across, k.Graph, k.History, k.Sidecars, k.Consumers, k.Pin, k.Reload, k.Palette, k.Quit},
+	}
+}
+
+// bindings returns the bindings of the key map by action name, as used by the
+// keys section of the config file
+func (k *KeyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":           &k.Up,
+		"down":         &k.Down,
+		"left":         &k.Left,
+		"right":        &k.Right,
+		"tab":          &k.Tab,
+		"shiftTab":     &k.ShiftTab,
+		"enter":        &k.Enter,
+		"back":         &k.Back,
+		"reveal":       &k.Reveal,
+		"diff":         &k.Diff,
+		"search":       &k.Search,
+		"seal":         &k.Seal,
+		"flags":        &k.Flags,
+		"verify":       &k.Verify,
+		"pods":         &k.Pods,
+		"worklist":     &k.Worklist,
+		"usage":        &k.Usage,
+		"connect":      &k.Connect,
+		"kubectl":      &k.Kubectl,
+		"cleanup":      &k.Cleanup,
+		"healthFilter": &k.HealthFilter,
+		"changed":      &k.Changed,
+		"query":        &k.Query,
+		"container":    &k.Container,
+		"explain":      &k.Explain,
+		"context":      &k.Context,
+		"export":       &k.Export,
+		"copy":         &k.Copy,
+		"copyRows":     &k.CopyRows,
+		"edit":         &k.Edit,
+		"group":        &k.Group,
+		"across":       &k.Across,
+		"graph":        &k.Graph,
+		"history":      &k.History,
+		"sidecars":     &k.Sidecars,
+		"consumers":    &k.Consumers,
+		"pin":          &k.Pin,
+		"reload":       &k.Reload,
+		"palette":      &k.Palette,
+		"quit":         &k.Quit,
+		"help":         &k.Help,
+		"confirm":      &k.Confirm,
+		"cancel":       &k.Cancel,
+	}
+}
+
+// Remap returns the key map with the keys of some actions replaced, from the
+// keys section of the config file. An action mapped to no key is disabled.
+func (k KeyMap) Remap(keys map[string][]string) (KeyMap, error) {
+	bindings := k.bindings()
+	for action, remapped := range keys {
+		binding, ok := bindings[action]
+		if !ok {
+			return k, fmt.Errorf("unknown key action %q (one of %s)", action, strings.Join(KeyActions(), ", "))
+		}
+		if len(remapped) == 0 {
+			binding.SetEnabled(false)
+			continue
+		}
+		binding.SetKeys(remapped...)
+		binding.SetHelp(strings.Join(remapped, "/"), binding.Help().Desc)
+	}
+	return k, nil
+}
+
+// KeyActions returns the sorted names of the actions that can be remapped
+func KeyActions() []string {
+	var k KeyMap
+	actions := make([]string, 0, len(k.bindings()))
+	for action := range k.bindings() {
+		actions = append(actions, action)
 	}
+	sort.Strings(actions)
+	return actions
 }
@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// envJSONPath selects the env and envFrom of every container of a pod template
+const envJSONPath = `{range .spec.template.spec.containers[*]}{.name}{"\n  env: "}{.env}{"\n  envFrom: "}{.envFrom}{"\n"}{end}`
+
+// cronJobEnvJSONPath is envJSONPath for the job template of a CronJob
+const cronJobEnvJSONPath = `{range .spec.jobTemplate.spec.template.spec.containers[*]}{.name}{"\n  env: "}{.env}{"\n  envFrom: "}{.envFrom}{"\n"}{end}`
+
+// kindEnvJSONPath returns the env JSONPath of an app kind
+func kindEnvJSONPath(kind k8s.AppKind) string {
+	if kind == k8s.AppKindCronJob {
+		return cronJobEnvJSONPath
+	}
+	return envJSONPath
+}
+
+// equivalentKubectl returns the kubectl commands equivalent to the reads behind the given view
+func (m Model) equivalentKubectl(view ViewMode) []string {
+	if len(m.namespaces) == 0 {
+		return []string{m.kubectl("get namespaces")}
+	}
+	ns := m.namespaces[m.namespaceIdx]
+
+	if view == ViewModeDiffShow && len(m.apps) > 0 {
+		kind := kindResource(m.apps[m.appIdx].Kind)
+		jsonPath := k8s.ShellQuote(kindEnvJSONPath(m.apps[m.appIdx].Kind))
+		getA := m.kubectl(fmt.Sprintf("get %s %s -n %s -o jsonpath=%s", kind, m.diffAppName, m.diffNsA, jsonPath))
+		_, b := m.diffSides()
+		getB := kubectlIn(b.context, fmt.Sprintf("get %s %s -n %s -o jsonpath=%s", kind, m.diffAppName, m.diffNsB, jsonPath))
+		return []string{getA, getB, fmt.Sprintf("diff <(%s) <(%s)", getA, getB)}
+	}
+
+	commands := []string{
+		m.kubectl("get namespaces"),
+		m.kubectl("get deployments,statefulsets,cronjobs,jobs -n " + ns),
+	}
+	if len(m.apps) == 0 || m.appIdx >= len(m.apps) {
+		return commands
+	}
+	app := m.apps[m.appIdx]
+
+	if m.selectedPod != nil {
+		commands = append(commands, m.kubectl(fmt.Sprintf("get pod %s -n %s -o yaml", m.selectedPod.Name, ns)))
+	} else {
+		commands = append(commands, m.kubectl(fmt.Sprintf("get %s %s -n %s -o jsonpath=%s", kindResource(app.Kind), app.Name, ns, k8s.ShellQuote(kindEnvJSONPath(app.Kind)))))
+	}
+
+	// One read per referenced ConfigMap/Secret, in a stable order
+	sources := make(map[string]k8s.EnvSourceKind)
+	for _, ev := range m.envVars {
+		if ev.SourceName != "" {
+			sources[ev.SourceName+"\x00"+string(ev.SourceKind)] = ev.SourceKind
+		}
+	}
+	keys := make([]string, 0, len(sources))
+	for k := range sources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		name := strings.SplitN(k, "\x00", 2)[0]
+		switch sources[k] {
+		case k8s.EnvSourceConfigMap:
+			commands = append(commands, m.kubectl(fmt.Sprintf("get configmap %s -n %s -o yaml", name, ns)))
+		case k8s.EnvSourceSecret:
+			commands = append(commands, m.kubectl(fmt.Sprintf("get secret %s -n %s -o jsonpath='{.data}'", name, ns)))
+		case k8s.EnvSourceSealedSecret:
+			commands = append(commands,
+				m.kubectl(fmt.Sprintf("get secret %s -n %s -o jsonpath='{.data}'", name, ns)),
+				m.kubectl(fmt.Sprintf("get sealedsecret %s -n %s -o yaml", name, ns)))
+		}
+	}
+	return commands
+}
+
+// kubectl prefixes a kubectl command with the current context
+func (m Model) kubectl(args string) string {
+	return kubectlIn(m.context, args)
+}
+
+// kubectlIn returns a kubectl command line pinned to the given context
+func kubectlIn(kubeContext, args string) string {
+	if kubeContext == "" {
+		return "kubectl " + args
+	}
+	return fmt.Sprintf("kubectl --context %s %s", k8s.ShellQuote(kubeContext), args)
+}
+
+// kindResource returns the kubectl resource name of an app kind
+func kindResource(kind k8s.AppKind) string {
+	switch kind {
+	case k8s.AppKindStatefulSet:
+		return "statefulset"
+	case k8s.AppKindCronJob:
+		return "cronjob"
+	case k8s.AppKindJob:
+		return "job"
+	default:
+		return "deployment"
+	}
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,10 +11,22 @@ import (
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"github.com/ginbear/k8s-envtop/internal/drift"
 	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/fingerprint"
+	"github.com/ginbear/k8s-envtop/internal/fleet"
+	"github.com/ginbear/k8s-envtop/internal/history"
+	"github.com/ginbear/k8s-envtop/internal/i18n"
 	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/netcheck"
+	"github.com/ginbear/k8s-envtop/internal/policy"
+	"github.com/ginbear/k8s-envtop/internal/preview"
+	"github.com/ginbear/k8s-envtop/internal/seal"
+	"sigs.k8s.io/yaml"
 )
 
 // Pane represents the active pane
@@ -36,8 +49,35 @@ const (
 	ViewModeRevealShow
 	ViewModeDiffSelect
 	ViewModeDiffShow
+	ViewModeConfigMapDiff
+	ViewModeFlagMatrix
 	ViewModeSealInput
 	ViewModeSealResult
+	ViewModeVerifyResult
+	ViewModePodSelect
+	ViewModeWorklist
+	ViewModeWorklistJustify
+	ViewModeSecretUsage
+	ViewModeConnectivity
+	ViewModeKubectl
+	ViewModePreviewCleanup
+	ViewModeQueryInput
+	ViewModeQueryResult
+	ViewModeExplain
+	ViewModeContextSelect
+	ViewModeExportInput
+	ViewModeCopyMenu
+	ViewModeVarAcross
+	ViewModeGraph
+	ViewModeDetail
+	ViewModeRevisionSelect
+	ViewModeRevisionDiff
+	ViewModeValueDiff
+	ViewModeConsumers
+	ViewModeEditInput
+	ViewModeEditConfirm
+	ViewModeBulkProgress
+	ViewModePalette
 )
 
 // RevealMode represents how to display the revealed secret
@@ -46,6 +86,8 @@ type RevealMode int
 const (
 	RevealModeBase64 RevealMode = iota
 	RevealModePlain
+	RevealModeMasked // first and last characters with the length
+	RevealModeSecret // every key of the source Secret, in plain text
 )
 
 // Model is the main TUI model
@@ -54,6 +96,14 @@ type Model struct {
 	client   *k8s.Client
 	resolver *env.Resolver
 
+	// Fleet session: one client and resolver per context (nil for a single context)
+	fleet     []*k8s.Client
+	resolvers map[string]*env.Resolver
+
+	// User configuration
+	cfg      *config.Config
+	policies *policy.Set
+
 	// Window dimensions
 	width  int
 	height int
@@ -63,20 +113,71 @@ type Model struct {
 	viewMode   ViewMode
 
 	// Namespace pane
-	namespaces      []string
-	namespaceIdx    int
-	namespaceCursor int
+	namespaces        []string
+	namespaceContexts []string // context of each namespace in a fleet session
+	namespaceIdx      int
+	namespaceCursor   int
 
 	// Apps pane
 	apps      []k8s.App
 	appIdx    int
 	appCursor int
 
+	// Expiry of the service account token the session uses (cfg.Session), if any
+	sessionExpires time.Time
+
+	// Selection requested at startup, applied once the lists are loaded
+	initialNamespace string
+	initialApp       string
+
 	// Env pane
 	envVars   []k8s.EnvVar
 	envIdx    int
 	envCursor int
 
+	// Env pane tree view: variables grouped by name prefix; groups are collapsed
+	// unless expanded
+	groupByPrefix  bool
+	expandedGroups map[string]bool
+	security       *k8s.PodSecurity  // pod security summary of the selected app, nil if unknown
+	scalers        []k8s.Scaler      // HPAs and KEDA ScaledObjects targeting the selected app
+	mounts         []env.MountedFile // files mounted from ConfigMap/Secret volumes
+
+	// ConfigMaps/Secrets changed after running pods of the selected app started,
+	// by env.StaleKey
+	staleSources map[string]env.StaleSource
+
+	// Value policy violations of the selected app, by variable name
+	violations map[string][]policy.Violation
+
+	// Per-container env of the selected app; containerIdx 0 shows all containers merged
+	containerEnv []k8s.EnvVar
+	containers   []string
+	containerIdx int
+	envConflicts map[string][]string // containers defining a variable with different values
+
+	// Sidecar (Istio/Linkerd) variables are hidden unless showSidecars is set;
+	// allContainerEnv keeps them so the toggle needs no reload
+	showSidecars    bool
+	allContainerEnv []k8s.EnvVar
+
+	// Optional ConfigMaps/Secrets (or keys) of the selected app that do not exist
+	missingOptional []env.MissingOptional
+
+	// Variables of the selected pod its workload template does not define, keyed by
+	// env.RuntimeKey (nil for the workload template)
+	injectedVars map[string]bool
+
+	// Pod selection state (pod-level resolution)
+	pods           []k8s.Pod
+	podCursor      int
+	podFilterInput textinput.Model
+	selectedPod    *k8s.Pod // nil when resolving from the workload template
+
+	// Command palette: every action of the key map, fuzzy-searched by name
+	paletteInput  textinput.Model
+	paletteCursor int
+
 	// Search state
 	searchInput        textinput.Model
 	searchPane         Pane
@@ -93,6 +194,19 @@ type Model struct {
 	revealExpiry    time.Time
 	revealCopied    bool
 
+	// Keys of the source Secret when it is revealed as a whole, and the scroll
+	// position of their table. The Secret and its namespace are captured when
+	// the reveal starts, so a selection moved by a refresh is not revealed.
+	revealedSecret     []revealedKey
+	revealedSecretName string
+	revealNamespace    string
+	revealScroll       int
+
+	// Clipboard copy of the selected variable; a secret value goes through the
+	// reveal confirmation first (copyField is set while it is pending)
+	copyMenuIdx int
+	copyField   copyField
+
 	// Diff state
 	diffNamespaces []string
 	diffNsIdx      int
@@ -100,16 +214,162 @@ type Model struct {
 	diffNsA        string
 	diffNsB        string
 	diffAppName    string
+	diffAppKind    k8s.AppKind
 	diffCursor     int
 
+	diffBulk bool // namespace selection leads to a bulk (all apps) diff
+
+	// Cross-cluster diff: side B is read through the resolver of another context
+	// (nil when both sides are in the active context)
+	diffResolverB  *env.Resolver
+	diffContextB   string
+	contextForDiff bool // the context picker selects the context of diff side B
+
+	// Environment tiers by namespace key (see nsKey); namespaces without a tier are absent
+	namespaceTiers map[string]string
+
+	// Namespace detail: creation time of every namespace, quotas of the selected one
+	namespaceCreated   map[string]time.Time
+	namespaceLimits    *k8s.NamespaceLimits
+	namespaceLimitsFor string
+
+	// Variables changed since the previous view of the selected app (nil on first view)
+	views     *history.ViewStore
+	pins      *history.PinStore // nil when pins cannot be saved
+	pinned    []string          // variables pinned in the selected app, in pin order
+	altScreen bool              // terminal supports the alternate screen; see altScreenCapable
+	ascii     bool              // draw borders and symbols in ASCII; see asciiOnly
+
+	// Idle lock state
+	lastActivity time.Time
+	locked       bool
+	unlockInput  textinput.Model
+	envChanges   *history.Changes
+	changedOnly  bool
+
+	// Workload health of the apps in the selected namespace, keyed by k8s.AppKey
+	appHealth    map[string]k8s.AppHealth
+	appHealthFor string
+	appFilter    appFilter
+
+	// Drift worklist state
+	worklist       []drift.Item
+	worklistCursor int
+	worklistNsA    string
+	worklistNsB    string
+	worklistScores drift.ScoreSummary
+	ignores        *drift.IgnoreStore
+	justifyInput   textinput.Model
+
+	// ConfigMap diff state (drill-down from diff view)
+	cmDiffResults []env.DiffResult
+	cmDiffNameA   string
+	cmDiffNameB   string
+	cmDiffCursor  int
+
+	// Feature flag matrix state
+	flagMatrix    *env.FlagMatrix
+	flagCursor    int
+	flagColOffset int
+
+	// kubectl command echo state
+	kubectlCommands []string
+	kubectlReturn   ViewMode // view to return to when the dialog closes
+	kubectlCopied   bool
+
+	// Stale preview namespace state
+	staleNamespaces []preview.StaleNamespace
+	staleCursor     int
+	staleCopied     bool
+
+	// Connectivity check state
+	connEnvName string
+	connResult  *netcheck.Result
+
+	// Secret usage heatmap state
+	secretUsage       []env.SecretUsage
+	secretUsageCursor int
+
+	// Fleet query state
+	queryInput   textinput.Model
+	queryPattern string
+	queryResult  *fleet.Result
+	queryCursor  int
+
+	// Quick-diff of one variable across namespaces
+	varAcrossRef    k8s.EnvVar
+	varAcrossResult *fleet.Result
+	varAcrossCursor int
+
+	// Context picker state
+	contextList   []string
+	contextCursor int
+
+	// Env file export state
+	exportInput   textinput.Model
+	exportSecrets bool // write secret values in clear
+	exportDiff    bool // the prompt exports the diff shown in diff mode instead of the env
+
+	// Running bulk diff of the drift worklist
+	bulk bulkProgress
+
+	// Env value edit state
+	editInput textinput.Model
+	edit      editState
+
+	// Resolution explainer state
+	explanation   *env.Explanation
+	explainOffset int
+
+	// Config dependency graph state
+	graph       *env.ConfigGraph
+	graphOffset int
+
+	// Rollout history diff state
+	revisions          []k8s.Revision // newest first, the first one is current
+	revisionCursor     int
+	revisionOlder      k8s.Revision
+	revisionDiff       []env.DiffResult
+	revisionDiffCursor int
+
+	// Value diff state (drill-down of a VALUE_DIFF row)
+	valueDiff       env.DiffResult
+	valueDiffLines  []env.LineEdit
+	valueDiffLabels [2]string
+	valueDiffReturn ViewMode // diff view to go back to
+	valueDiffOffset int
+
+	// Apps referencing the ConfigMap/Secret of the selected variable
+	consumers       []env.SourceConsumer
+	consumersKind   string
+	consumersName   string
+	consumersCursor int
+
+	// Env var detail view state
+	detailVar    k8s.EnvVar
+	provenance   *env.EnvVarProvenance
+	detailOffset int
+
 	// Seal state
 	sealSecretInput textinput.Model // Secret name input
-	sealValueInput  textinput.Model // Plain text value input
-	sealFocusField  int             // 0: secret name, 1: value
+	sealKeyInput    textinput.Model // Secret key input (optional, for the manifest)
+	sealValueInput  textarea.Model  // Plain text value input (masked, multi-line)
+	sealFocusField  int             // 0: secret name, 1: key, 2: value
 	sealSecretName  string
+	sealKey         string
 	sealResult      string
+	sealManifest    string // SealedSecret manifest (YAML), when a key was given
 	sealError       string
 	sealCopied      bool
+	sealApplied     bool
+
+	// SealedSecret verification state
+	verifyResult *seal.VerifyResult
+
+	// Startup state (shown on the splash screen until namespaces arrive)
+	namespacesLoaded bool
+	namespacesMore   string                             // continue token of the namespace page being loaded, "" once complete
+	capabilities     map[k8s.Capability]capabilityState // discovered so far; see capabilities.go
 
 	// Error state
 	err           error
@@ -119,41 +379,164 @@ type Model struct {
 	// Key bindings
 	keys KeyMap
 
+	// Translated UI strings and locale-aware formats
+	printer *i18n.Printer
+
+	hashStyle fingerprint.Style // rendering of the hashes of masked values
+
+	// Live watch of the selected namespace
+	watchCancel  context.CancelFunc
+	watchEvents  <-chan k8s.WatchEvent
+	watchKey     string // context/namespace being watched
+	watchPending watchPending
+	liveChanged  map[string]bool // variables updated by the watch since the app was selected
+
+	// Ticker of the changes to the env sources of the selected app; see ticker.go
+	tickerCancel context.CancelFunc
+	tickerEvents <-chan k8s.SourceEvent
+	tickerKey    string            // context/namespace/app/sources being watched
+	ticker       []k8s.SourceEvent // newest first
+
+	// Resolved env of the apps of the watched namespace, valid until the watch reports
+	// a new resourceVersion of an object it was resolved from
+	envCache    map[string]envCacheEntry // keyed by k8s.AppKey
+	envCacheOff bool                     // some kind cannot be watched: never serve from the cache
+	watchSeen   map[string]string        // last resourceVersion reported per env.VersionKey
+
 	// Context
-	context       string
-	cancelFunc    context.CancelFunc
+	context    string
+	cancelFunc context.CancelFunc
+
+	// Guided tutorial (--tutorial); tutorialStep indexes tutorialSteps
+	tutorial     bool
+	tutorialStep int
 }
 
 // Messages
 type (
 	namespacesLoadedMsg struct {
 		namespaces []string
+		contexts   []string // fleet session only
+		tiers      map[string]string
+		createdAt  map[string]time.Time
+		warning    string
+		refresh    bool   // reloaded by the live watch: keep the selection
+		token      string // continue token the page was requested with ("" for the first page)
+		more       string // continue token of the next page ("" after the last one)
+	}
+	namespaceLimitsMsg struct {
+		namespace string
+		limits    *k8s.NamespaceLimits
+	}
+	appHealthMsg struct {
+		namespace string
+		health    map[string]k8s.AppHealth
 	}
 	appsLoadedMsg struct {
-		apps []k8s.App
+		apps    []k8s.App
+		refresh bool // reloaded by the live watch: keep the selection
+	}
+	podsLoadedMsg struct {
+		pods []k8s.Pod
 	}
 	envVarsLoadedMsg struct {
-		envVars []k8s.EnvVar
+		envVars      []k8s.EnvVar // merged across containers
+		containerEnv []k8s.EnvVar
+		changes      *history.Changes
+		refresh      bool // reloaded by the live watch: keep the selection
+		app          k8s.App
+		versions     env.Versions // nil for pods and cached results
+		missing      []env.MissingOptional
+		injected     map[string]bool // pod variables absent from the workload template (see env.RuntimeOnly)
+	}
+	securityLoadedMsg struct {
+		security *k8s.PodSecurity
+	}
+	scalersLoadedMsg struct {
+		scalers []k8s.Scaler
+	}
+	mountsLoadedMsg struct {
+		mounts []env.MountedFile
+	}
+	staleSourcesMsg struct {
+		app     k8s.App
+		sources []env.StaleSource
 	}
 	diffResultsMsg struct {
 		results []env.DiffResult
 		nsA     string
 		nsB     string
 		appName string
+		appKind k8s.AppKind
+	}
+	worklistMsg struct {
+		items     []drift.Item
+		ignores   *drift.IgnoreStore
+		scores    drift.ScoreSummary
+		nsA       string
+		nsB       string
+		recordErr error // the scores could not be saved in the history
+	}
+	configMapDiffMsg struct {
+		results []env.DiffResult
+		nameA   string
+		nameB   string
+	}
+	flagMatrixMsg struct {
+		matrix *env.FlagMatrix
+	}
+	secretUsageMsg struct {
+		usage []env.SecretUsage
+	}
+	connectivityMsg struct {
+		result *netcheck.Result
+	}
+	staleNamespacesMsg struct {
+		stale []preview.StaleNamespace
 	}
 	sealResultMsg struct {
-		result string
-		err    string
+		result   string
+		manifest string
+		err      string
+	}
+	sealAppliedMsg struct {
+		err error
+	}
+	verifyResultMsg struct {
+		result *seal.VerifyResult
 	}
 	errorMsg struct {
 		err error
 	}
-	revealTimeoutMsg  struct{}
-	clearStatusMsg    struct{}
+	revealTimeoutMsg struct{}
+	clearStatusMsg   struct{}
 )
 
+// newResolver creates an env resolver applying the configured redaction rules
+func newResolver(client *k8s.Client, cfg *config.Config) *env.Resolver {
+	resolver := env.NewResolver(client)
+	resolver.SetRedactRule(cfg.IsRedacted)
+	resolver.SetCacheTTL(cfg.CacheTTL())
+	return resolver
+}
+
+// hashStyle returns the configured fingerprint style of hashes, hex if it is unknown
+func hashStyle(cfg *config.Config) fingerprint.Style {
+	style, err := fingerprint.ParseStyle(cfg.HashDisplay)
+	if err != nil {
+		return fingerprint.StyleHex
+	}
+	return style
+}
+
 // NewModel creates a new TUI model
-func NewModel(client *k8s.Client) Model {
+func NewModel(client *k8s.Client, cfg *config.Config, policies *policy.Set) Model {
+	// Invalid remappings are reported at startup (see KeyMap.Remap): keep the defaults
+	keys, err := DefaultKeyMap().Remap(cfg.Keys)
+	if err != nil {
+		keys = DefaultKeyMap()
+	}
+
 	ti := textinput.New()
 	ti.Placeholder = "Type OK to confirm"
 	ti.CharLimit = 10
@@ -164,48 +547,125 @@ func NewModel(client *k8s.Client) Model {
 	si.CharLimit = 50
 	si.Width = 30
 
+	podIn := textinput.New()
+	podIn.Placeholder = "Filter by pod, node or zone..."
+	podIn.CharLimit = 100
+	podIn.Width = 40
+
+	justifyIn := textinput.New()
+	justifyIn.Placeholder = "Why is this drift expected?"
+	justifyIn.CharLimit = 200
+	justifyIn.Width = 60
+
 	sealSecretIn := textinput.New()
 	sealSecretIn.Placeholder = "Secret name..."
 	sealSecretIn.CharLimit = 253
 	sealSecretIn.Width = 40
 
-	sealValueIn := textinput.New()
+	sealKeyIn := textinput.New()
+	sealKeyIn.Placeholder = "Key (optional)..."
+	sealKeyIn.CharLimit = 253
+	sealKeyIn.Width = 40
+
+	sealValueIn := textarea.New()
 	sealValueIn.Placeholder = "Plain text value..."
-	sealValueIn.CharLimit = 500
-	sealValueIn.Width = 40
+	sealValueIn.CharLimit = 0
+	sealValueIn.MaxHeight = 0
+	sealValueIn.SetWidth(40)
+	sealValueIn.KeyMap.InsertNewline = key.NewBinding(key.WithKeys("ctrl+j", "alt+enter"))
+
+	queryIn := textinput.New()
+	queryIn.Placeholder = "IMAGE_PULL_POLICY, *_URL..."
+	queryIn.CharLimit = 253
+	queryIn.Width = 40
+
+	editIn := textinput.New()
+	editIn.CharLimit = 0
+	editIn.Width = 60
+
+	paletteIn := textinput.New()
+	paletteIn.Placeholder = "Type a command..."
+	paletteIn.CharLimit = 64
+	paletteIn.Width = 40
+
+	exportIn := textinput.New()
+	exportIn.Placeholder = "app.env"
+	exportIn.CharLimit = 4096
+	exportIn.Width = 50
+
+	// The view history is optional; without it nothing is highlighted
+	var views *history.ViewStore
+	if path, err := history.DefaultViewsPath(); err == nil {
+		views, _ = history.LoadViews(path)
+	}
+	// Likewise for pins: without the store nothing can be pinned
+	var pins *history.PinStore
+	if path, err := history.DefaultPinsPath(); err == nil {
+		pins, _ = history.LoadPins(path)
+	}
 
 	return Model{
 		client:          client,
-		resolver:        env.NewResolver(client),
-		keys:            DefaultKeyMap(),
+		resolver:        newResolver(client, cfg),
+		cfg:             cfg,
+		policies:        policies,
+		views:           views,
+		pins:            pins,
+		altScreen:       altScreenCapable(os.Stdout),
+		lastActivity:    time.Now(),
+		keys:            keys,
+		printer:         newPrinter(cfg),
+		hashStyle:       hashStyle(cfg),
 		activePane:      PaneNamespaces,
 		viewMode:        ViewModeNormal,
 		revealInput:     ti,
 		searchInput:     si,
+		podFilterInput:  podIn,
+		paletteInput:    paletteIn,
+		justifyInput:    justifyIn,
 		sealSecretInput: sealSecretIn,
+		sealKeyInput:    sealKeyIn,
 		sealValueInput:  sealValueIn,
+		queryInput:      queryIn,
+		exportInput:     exportIn,
+		editInput:       editIn,
 		context:         client.GetCurrentContext(),
 	}
 }
 
-// Init initializes the model
+// WithSessionExpiry records when the service account token of the session
+// (session.serviceAccount of the config) expires
+func (m Model) WithSessionExpiry(expires time.Time) Model {
+	m.sessionExpires = expires
+	return m
+}
+
+// WithoutViewHistory neither records the apps viewed nor highlights changes
+// since the last view, e.g. for a cluster that only exists for this session.
+// Variables cannot be pinned either.
+func (m Model) WithoutViewHistory() Model {
+	m.views = nil
+	m.pins = nil
+	return m
+}
+
+// Init initializes the model.
+// Namespace listing and capability discovery run concurrently.
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.loadNamespaces(),
+		m.discoverCapabilities(),
 		tea.EnterAltScreen,
+		m.scheduleIdleCheck(),
 	)
 }
 
-// loadNamespaces loads the namespace list
+// loadNamespaces loads the namespace list, page by page (see loadNamespacePage)
 func (m Model) loadNamespaces() tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
-		namespaces, err := m.client.ListNamespaces(ctx)
-		if err != nil {
-			return errorMsg{err: err}
-		}
-		return namespacesLoadedMsg{namespaces: namespaces}
+	if m.fleet != nil {
+		return m.loadFleetNamespaces()
 	}
+	return m.loadNamespacePage("")
 }
 
 // loadApps loads the apps for the selected namespace
@@ -214,48 +674,193 @@ func (m Model) loadApps() tea.Cmd {
 		return nil
 	}
 	namespace := m.namespaces[m.namespaceIdx]
-	return func() tea.Msg {
+	return tea.Batch(func() tea.Msg {
 		ctx := context.Background()
 		apps, err := m.client.ListApps(ctx, namespace)
 		if err != nil {
 			return errorMsg{err: err}
 		}
 		return appsLoadedMsg{apps: apps}
+	}, m.loadNamespaceLimits(namespace), m.loadAppHealth(namespace))
+}
+
+// loadNamespaceLimits loads the ResourceQuotas and LimitRanges of a namespace.
+// Failures (e.g. missing RBAC) are not fatal: the detail is simply not shown.
+func (m Model) loadNamespaceLimits(namespace string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		limits, _ := m.client.GetNamespaceLimits(ctx, namespace)
+		return namespaceLimitsMsg{namespace: namespace, limits: limits}
 	}
 }
 
 // loadEnvVars loads the env vars for the selected app
 func (m Model) loadEnvVars() tea.Cmd {
+	return m.resolveEnvVars(false)
+}
+
+// resolveEnvVars resolves the env of the selected app (or pod). A refresh by the live
+// watch leaves the view history alone, so "changed since last view" keeps its baseline.
+func (m Model) resolveEnvVars(refresh bool) tea.Cmd {
 	if len(m.apps) == 0 {
 		return nil
 	}
+	app := m.apps[m.appIdx]
+	pod := m.selectedPod
+	// Re-selecting an unchanged app is served from the cache without API calls
+	cached, fromCache := m.cachedEnv(app)
+	fromCache = fromCache && !refresh
+	return tea.Batch(func() tea.Msg {
+		ctx, missing := env.CollectMissingOptional(context.Background())
+		if pod != nil {
+			containerEnv, err := m.resolver.ResolvePodContainerEnvVars(ctx, pod.Namespace, pod.Name)
+			if err != nil {
+				return errorMsg{err: err}
+			}
+			msg := envVarsLoadedMsg{envVars: env.MergeContainers(containerEnv), containerEnv: containerEnv, refresh: refresh, missing: missing()}
+
+			// Compare with the workload template to reveal runtime-injected variables.
+			// Without the template nothing is marked.
+			template := cached.containerEnv
+			if !fromCache {
+				template, err = m.resolver.ResolveAppContainerEnvVars(context.Background(), app)
+			}
+			if err == nil {
+				msg.injected = env.RuntimeOnly(template, containerEnv)
+			}
+			return msg
+		}
+
+		var versions env.Versions
+		containerEnv, missed := cached.containerEnv, cached.missing
+		if !fromCache {
+			var err error
+			containerEnv, versions, err = m.resolver.ResolveAppContainerEnvVarsVersioned(ctx, app)
+			if err != nil {
+				return errorMsg{err: err}
+			}
+			missed = missing()
+		}
+		envVars := env.MergeContainers(containerEnv)
+		msg := envVarsLoadedMsg{envVars: envVars, containerEnv: containerEnv, refresh: refresh, app: app, versions: versions, missing: missed}
+		if refresh {
+			return msg
+		}
+
+		// Compare with the previous view of the app, then remember this one.
+		// The history is best effort: a failed write only loses the next comparison.
+		if m.views != nil {
+			key := history.ViewKey(m.context, app)
+			msg.changes = m.views.Compare(key, envVars)
+			_ = m.views.Record(key, envVars, time.Now())
+		}
+		return msg
+	}, m.loadSecurity(), m.loadScalers(), m.loadMounts())
+}
+
+// evaluatePolicies evaluates the value policies against the env of the selected namespace
+func (m Model) evaluatePolicies(envVars []k8s.EnvVar) map[string][]policy.Violation {
+	if m.policies.Len() == 0 || len(m.namespaces) == 0 {
+		return nil
+	}
+	ns := m.namespaces[m.namespaceIdx]
+	violations := make(map[string][]policy.Violation)
+	for _, v := range m.policies.Evaluate(ns, m.tierOf(ns), envVars) {
+		violations[v.Name] = append(violations[v.Name], v)
+	}
+	return violations
+}
+
+// loadSecurity loads the pod security settings of the selected app (or pod).
+// Failures are not fatal: the summary is simply not shown.
+func (m Model) loadSecurity() tea.Cmd {
+	app := m.apps[m.appIdx]
+	pod := m.selectedPod
+	return func() tea.Msg {
+		ctx := context.Background()
+		var security *k8s.PodSecurity
+		if pod != nil {
+			security, _ = m.resolver.ResolvePodSecurity(ctx, pod.Namespace, pod.Name)
+		} else {
+			security, _ = m.resolver.ResolveAppSecurity(ctx, app)
+		}
+		return securityLoadedMsg{security: security}
+	}
+}
+
+// loadScalers loads the HPAs and KEDA ScaledObjects targeting the selected app;
+// ScaledObjects once KEDA was discovered. Failures are not fatal: the scalers
+// are simply not shown.
+func (m Model) loadScalers() tea.Cmd {
+	app := m.apps[m.appIdx]
+	keda := m.capabilities[k8s.CapabilityKEDA] == capabilityAvailable
+	return func() tea.Msg {
+		scalers, _ := m.client.ListAppScalers(context.Background(), app, keda)
+		return scalersLoadedMsg{scalers: scalers}
+	}
+}
+
+// loadStaleSources compares the ConfigMaps/Secrets of the variables with the
+// start time of the running pods of the app (or of the selected pod). Failures
+// are not fatal: the variables are simply not marked.
+func (m Model) loadStaleSources(app k8s.App, envVars []k8s.EnvVar) tea.Cmd {
+	pod := m.selectedPod
+	return func() tea.Msg {
+		ctx := context.Background()
+		var pods []k8s.Pod
+		if pod != nil {
+			pods = []k8s.Pod{*pod}
+		} else {
+			pods, _ = m.client.ListAppPods(ctx, app)
+		}
+		return staleSourcesMsg{app: app, sources: m.resolver.StaleSources(ctx, app.Namespace, envVars, pods)}
+	}
+}
+
+// loadMounts loads the files the selected app (or pod) mounts from ConfigMaps
+// and Secrets. Failures are not fatal: the files are simply not shown.
+func (m Model) loadMounts() tea.Cmd {
+	app := m.apps[m.appIdx]
+	pod := m.selectedPod
+	return func() tea.Msg {
+		ctx := context.Background()
+		var mounts []env.MountedFile
+		if pod != nil {
+			mounts, _ = m.resolver.ResolvePodMounts(ctx, pod.Namespace, pod.Name)
+		} else {
+			mounts, _ = m.resolver.ResolveAppMounts(ctx, app)
+		}
+		return mountsLoadedMsg{mounts: mounts}
+	}
+}
+
+// loadPods loads the pods of the selected app
+func (m Model) loadPods() tea.Cmd {
 	app := m.apps[m.appIdx]
 	return func() tea.Msg {
 		ctx := context.Background()
-		envVars, err := m.resolver.ResolveAppEnvVars(ctx, app)
+		pods, err := m.client.ListAppPods(ctx, app)
 		if err != nil {
 			return errorMsg{err: err}
 		}
-		return envVarsLoadedMsg{envVars: envVars}
+		return podsLoadedMsg{pods: pods}
 	}
 }
 
 // loadDiff loads the diff between two namespaces
-func (m Model) loadDiff(nsA, nsB, appName string, appKind k8s.AppKind) tea.Cmd {
+func (m Model) loadDiff(a, b diffSide, appName string, appKind k8s.AppKind) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
 
-		appA := k8s.App{Name: appName, Namespace: nsA, Kind: appKind}
-		appB := k8s.App{Name: appName, Namespace: nsB, Kind: appKind}
+		appA := k8s.App{Name: appName, Namespace: a.namespace, Kind: appKind}
+		appB := k8s.App{Name: appName, Namespace: b.namespace, Kind: appKind}
 
-		resolver := env.NewResolver(m.client)
-
-		envsA, err := resolver.ResolveAppEnvVars(ctx, appA)
+		envsA, err := a.resolver.ResolveAppDiffEnvVars(ctx, appA)
 		if err != nil {
 			return errorMsg{err: err}
 		}
 
-		envsB, err := resolver.ResolveAppEnvVars(ctx, appB)
+		envsB, err := b.resolver.ResolveAppDiffEnvVars(ctx, appB)
 		if err != nil {
 			return errorMsg{err: err}
 		}
@@ -263,15 +868,59 @@ func (m Model) loadDiff(nsA, nsB, appName string, appKind k8s.AppKind) tea.Cmd {
 		results := env.CompareEnvVars(envsA, envsB)
 		return diffResultsMsg{
 			results: results,
-			nsA:     nsA,
-			nsB:     nsB,
+			nsA:     a.namespace,
+			nsB:     b.namespace,
 			appName: appName,
+			appKind: appKind,
+		}
+	}
+}
+
+// loadConfigMapDiff loads the key-level diff between two ConfigMaps
+func (m Model) loadConfigMapDiff(a diffSide, nameA string, b diffSide, nameB string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		results, err := env.CompareConfigMapsAcross(ctx, a.resolver, a.namespace, nameA, b.resolver, b.namespace, nameB)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return configMapDiffMsg{
+			results: results,
+			nameA:   nameA,
+			nameB:   nameB,
+		}
+	}
+}
+
+// loadFlagMatrix resolves the feature flags of every app in the selected namespace
+func (m Model) loadFlagMatrix() tea.Cmd {
+	apps := m.apps
+	cfg := m.cfg
+	return func() tea.Msg {
+		ctx := context.Background()
+		matrix, err := m.resolver.ResolveFlagMatrix(ctx, apps, cfg.IsFeatureFlag)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return flagMatrixMsg{matrix: matrix}
+	}
+}
+
+// loadSecretUsage counts the Secret references of every app in the selected namespace
+func (m Model) loadSecretUsage() tea.Cmd {
+	apps := m.apps
+	return func() tea.Msg {
+		ctx := context.Background()
+		usage, err := m.resolver.ResolveSecretUsage(ctx, apps)
+		if err != nil {
+			return errorMsg{err: err}
 		}
+		return secretUsageMsg{usage: usage}
 	}
 }
 
-// Update handles messages
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+// update handles messages
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -279,63 +928,365 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case namespacesLoadedMsg:
+		if msg.token != "" {
+			return m.appendNamespacePage(msg)
+		}
+		// A refresh or a new listing supersedes the pages still loading
+		m.namespacesMore = msg.more
+		if msg.refresh {
+			if m.applyNamespaceRefresh(msg) {
+				return m, nil
+			}
+		}
 		m.namespaces = msg.namespaces
+		m.namespaceContexts = msg.contexts
+		m.namespaceTiers = msg.tiers
+		m.namespaceCreated = msg.createdAt
+		m.namespacesLoaded = true
 		m.loading = false
+		var cmds []tea.Cmd
+		if msg.warning != "" {
+			m.statusMessage = msg.warning
+			cmds = append(cmds, m.clearStatusAfter(5*time.Second))
+		}
 		if len(m.namespaces) > 0 {
-			return m, m.loadApps()
+			cmds = append(cmds, m.selectInitialNamespace(), m.activateNamespaceContext(), m.loadApps())
+		}
+		if msg.more != "" {
+			cmds = append(cmds, m.loadNamespacePage(msg.more))
+		}
+		return m, tea.Batch(cmds...)
+
+	case capabilityMsg:
+		m.handleCapability(msg)
+		if msg.capability == k8s.CapabilityKEDA && msg.available && msg.context == m.context && m.envVars != nil && m.appIdx < len(m.apps) {
+			// The scalers of the shown app were loaded without ScaledObjects
+			return m, m.loadScalers()
 		}
 		return m, nil
 
 	case appsLoadedMsg:
+		if msg.refresh {
+			if m.applyAppsRefresh(msg.apps) {
+				return m, nil
+			}
+		}
 		m.apps = msg.apps
 		m.appIdx = 0
 		m.appCursor = 0
+		m.selectedPod = nil
+		m.security = nil
+		m.scalers = nil
+		m.mounts = nil
+		m.staleSources = nil
+		m.stopTicker()
 		m.loading = false
+		cmds := []tea.Cmd{m.selectInitialApp(), m.updateTitle()}
+		if key := m.context + "/" + m.namespaces[m.namespaceIdx]; key != m.watchKey {
+			cmds = append(cmds, m.startWatch())
+		}
 		if len(m.apps) > 0 {
-			return m, m.loadEnvVars()
+			cmds = append(cmds, m.loadEnvVars())
 		}
-		return m, nil
+		cmds = append(cmds, m.fireHooks(config.HookOnNamespace))
+		return m, tea.Batch(cmds...)
+
+	case podsLoadedMsg:
+		m.pods = msg.pods
+		m.podCursor = 0
+		m.podFilterInput.Reset()
+		m.podFilterInput.Focus()
+		m.viewMode = ViewModePodSelect
+		m.loading = false
+		return m, textinput.Blink
 
 	case envVarsLoadedMsg:
-		m.envVars = msg.envVars
+		m.cacheEnv(msg.app, msg.containerEnv, msg.missing, msg.versions)
+		if msg.refresh {
+			m.applyEnvRefresh(msg)
+			return m, tea.Batch(m.loadStaleSources(msg.app, msg.envVars), m.startTicker(msg.app))
+		}
+		m.liveChanged = nil
+		m.setContainerEnv(msg.containerEnv)
+		m.missingOptional = msg.missing
+		m.loadPinned(msg.app)
+		m.injectedVars = msg.injected
+		m.envChanges = msg.changes
+		if msg.changes == nil || len(msg.changes.Changed) == 0 {
+			m.changedOnly = false
+		}
+		m.violations = m.evaluatePolicies(msg.envVars)
 		m.envIdx = 0
 		m.envCursor = 0
 		m.loading = false
+		return m, tea.Batch(m.updateTitle(), m.loadStaleSources(msg.app, msg.envVars), m.startTicker(msg.app), m.fireHooks(config.HookOnApp))
+
+	case watchEventMsg:
+		return m.handleWatchEvent(msg)
+
+	case watchFlushMsg:
+		return m.handleWatchFlush(msg)
+
+	case tickerEventMsg:
+		return m.handleTickerEvent(msg)
+
+	case tickerRefreshMsg:
+		return m.handleTickerRefresh(msg)
+
+	case namespaceLimitsMsg:
+		m.namespaceLimits = msg.limits
+		m.namespaceLimitsFor = msg.namespace
+		return m, nil
+
+	case appHealthMsg:
+		m.appHealth = msg.health
+		m.appHealthFor = msg.namespace
+		return m, nil
+
+	case securityLoadedMsg:
+		m.security = msg.security
+		return m, nil
+
+	case scalersLoadedMsg:
+		m.scalers = msg.scalers
+		return m, nil
+
+	case mountsLoadedMsg:
+		m.mounts = msg.mounts
+		return m, nil
+
+	case staleSourcesMsg:
+		if len(m.apps) == 0 || m.apps[m.appIdx].Name != msg.app.Name || m.apps[m.appIdx].Kind != msg.app.Kind {
+			return m, nil
+		}
+		m.staleSources = make(map[string]env.StaleSource, len(msg.sources))
+		for _, source := range msg.sources {
+			m.staleSources[source.Key()] = source
+		}
+		return m, nil
+
+	case editTargetMsg:
+		m.loading = false
+		m.edit = msg.edit
+		m.editInput.SetValue(msg.edit.oldValue)
+		m.editInput.CursorEnd()
+		m.editInput.Focus()
+		m.viewMode = ViewModeEditInput
+		return m, textinput.Blink
+
+	case editPreviewMsg:
+		m.edit.checking = false
+		m.edit.dryRunErr = msg.err
 		return m, nil
 
+	case editAppliedMsg:
+		return m.handleEditApplied(msg)
+
+	case bulkStartedMsg:
+		return m.handleBulkStarted(msg)
+
+	case bulkEventMsg:
+		return m.handleBulkEvent(msg)
+
 	case diffResultsMsg:
 		m.diffResults = msg.results
 		m.diffNsA = msg.nsA
 		m.diffNsB = msg.nsB
 		m.diffAppName = msg.appName
+		m.diffAppKind = msg.appKind
 		m.diffCursor = 0
 		m.viewMode = ViewModeDiffShow
 		m.loading = false
 		return m, nil
 
+	case worklistMsg:
+		m.worklist = msg.items
+		m.ignores = msg.ignores
+		m.worklistNsA = msg.nsA
+		m.worklistNsB = msg.nsB
+		m.worklistScores = msg.scores
+		m.worklistCursor = 0
+		m.viewMode = ViewModeWorklist
+		m.loading = false
+		if msg.recordErr != nil {
+			// The worklist does not need the history
+			m.statusMessage = fmt.Sprintf("Drift scores not recorded: %v", msg.recordErr)
+			return m, m.clearStatusAfter(5 * time.Second)
+		}
+		return m, nil
+
+	case configMapDiffMsg:
+		m.cmDiffResults = msg.results
+		m.cmDiffNameA = msg.nameA
+		m.cmDiffNameB = msg.nameB
+		m.cmDiffCursor = 0
+		m.viewMode = ViewModeConfigMapDiff
+		m.loading = false
+		return m, nil
+
+	case flagMatrixMsg:
+		m.flagMatrix = msg.matrix
+		m.flagCursor = 0
+		m.flagColOffset = 0
+		m.viewMode = ViewModeFlagMatrix
+		m.loading = false
+		return m, nil
+
+	case queryMsg:
+		m.queryPattern = msg.pattern
+		m.queryResult = msg.result
+		m.queryCursor = 0
+		m.viewMode = ViewModeQueryResult
+		m.loading = false
+		return m, nil
+
+	case contextsMsg:
+		m.contextList = msg.contexts
+		m.contextCursor = 0
+		for i, name := range msg.contexts {
+			if name == m.context {
+				m.contextCursor = i
+			}
+		}
+		m.viewMode = ViewModeContextSelect
+		return m, nil
+
+	case contextSwitchedMsg:
+		return m.switchContext(msg.client, msg.expires)
+
+	case diffContextMsg:
+		return m.applyDiffContext(msg)
+
+	case varAcrossMsg:
+		m.varAcrossRef = msg.ref
+		m.varAcrossResult = msg.result
+		m.varAcrossCursor = 0
+		m.viewMode = ViewModeVarAcross
+		m.loading = false
+		return m, nil
+
+	case graphMsg:
+		m.graph = msg.graph
+		m.graphOffset = 0
+		m.viewMode = ViewModeGraph
+		m.loading = false
+		return m, nil
+
+	case revisionsMsg:
+		m.revisions = msg.revisions
+		m.revisionCursor = 1
+		m.viewMode = ViewModeRevisionSelect
+		m.loading = false
+		return m, nil
+
+	case consumersMsg:
+		m.consumers = msg.consumers
+		m.consumersKind = msg.kind
+		m.consumersName = msg.name
+		m.consumersCursor = 0
+		m.viewMode = ViewModeConsumers
+		m.loading = false
+		return m, nil
+
+	case revisionDiffMsg:
+		m.revisionDiff = msg.results
+		m.revisionOlder = msg.older
+		m.revisionDiffCursor = 0
+		m.viewMode = ViewModeRevisionDiff
+		m.loading = false
+		return m, nil
+
+	case provenanceMsg:
+		m.detailVar = msg.envVar
+		m.provenance = msg.provenance
+		m.detailOffset = 0
+		m.viewMode = ViewModeDetail
+		m.loading = false
+		return m, nil
+
+	case explainMsg:
+		m.explanation = msg.explanation
+		m.explainOffset = 0
+		m.viewMode = ViewModeExplain
+		m.loading = false
+		return m, nil
+
+	case secretUsageMsg:
+		m.secretUsage = msg.usage
+		m.secretUsageCursor = 0
+		m.viewMode = ViewModeSecretUsage
+		m.loading = false
+		return m, nil
+
+	case staleNamespacesMsg:
+		m.staleNamespaces = msg.stale
+		m.staleCursor = 0
+		m.staleCopied = false
+		m.viewMode = ViewModePreviewCleanup
+		m.loading = false
+		return m, nil
+
+	case connectivityMsg:
+		m.connResult = msg.result
+		m.viewMode = ViewModeConnectivity
+		m.loading = false
+		return m, nil
+
 	case errorMsg:
 		m.err = msg.err
 		m.loading = false
 		return m, nil
 
+	case hookDoneMsg:
+		return m.handleHookDone(msg)
+
+	case revealSecretMsg:
+		return m.handleRevealSecret(msg)
+
+	case revealRecordedMsg:
+		return m.handleRevealRecorded(msg)
+
 	case revealTimeoutMsg:
-		m.revealedValue = ""
-		m.revealedEnvName = ""
-		m.viewMode = ViewModeNormal
-		return m, nil
+		if m.viewMode != ViewModeRevealShow {
+			return m, nil
+		}
+		return m.closeReveal()
 
 	case sealResultMsg:
 		m.sealResult = msg.result
+		m.sealManifest = msg.manifest
 		m.sealError = msg.err
 		m.viewMode = ViewModeSealResult
 		m.loading = false
 		return m, nil
 
+	case sealAppliedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Apply failed: %v", msg.err)
+			return m, m.clearStatusAfter(5 * time.Second)
+		}
+		m.sealApplied = true
+		return m, nil
+
+	case verifyResultMsg:
+		m.verifyResult = msg.result
+		m.viewMode = ViewModeVerifyResult
+		m.loading = false
+		return m, nil
+
 	case clearStatusMsg:
 		m.statusMessage = ""
 		return m, nil
 
+	case idleCheckMsg:
+		return m.handleIdleCheck()
+
 	case tea.KeyMsg:
+		m.lastActivity = time.Now()
+		if m.locked {
+			return m.handleLocked(msg)
+		}
 		return m.handleKeyPress(msg)
 	}
 
@@ -348,13 +1299,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Update text input if in seal input mode
 	if m.viewMode == ViewModeSealInput {
-		var cmd tea.Cmd
-		if m.sealFocusField == 0 {
-			m.sealSecretInput, cmd = m.sealSecretInput.Update(msg)
-		} else {
-			m.sealValueInput, cmd = m.sealValueInput.Update(msg)
-		}
-		return m, cmd
+		return m.updateSealField(msg)
 	}
 
 	return m, nil
@@ -384,11 +1329,9 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Handle escape in special modes
 	if key.Matches(msg, m.keys.Back) || key.Matches(msg, m.keys.Cancel) {
 		switch m.viewMode {
-		case ViewModeRevealMenu, ViewModeRevealConfirm, ViewModeRevealShow:
-			m.viewMode = ViewModeNormal
+		case ViewModeRevealMenu, ViewModeRevealConfirm, ViewModeRevealShow, ViewModeCopyMenu:
 			m.revealInput.Reset()
-			m.revealedValue = ""
-			return m, nil
+			return m.closeReveal()
 		case ViewModeDiffSelect:
 			m.viewMode = ViewModeNormal
 			return m, nil
@@ -396,18 +1339,84 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.viewMode = ViewModeNormal
 			m.diffResults = nil
 			return m, nil
-		case ViewModeSealInput:
+		case ViewModeConfigMapDiff:
+			m.viewMode = ViewModeDiffShow
+			m.cmDiffResults = nil
+			return m, nil
+		case ViewModeFlagMatrix:
 			m.viewMode = ViewModeNormal
-			m.sealSecretInput.Reset()
-			m.sealValueInput.Reset()
+			m.flagMatrix = nil
 			return m, nil
-		case ViewModeSealResult:
+		case ViewModeWorklist:
 			m.viewMode = ViewModeNormal
-			m.sealResult = ""
-			m.sealError = ""
+			m.worklist = nil
 			return m, nil
-		}
-	}
+		case ViewModeSecretUsage:
+			m.viewMode = ViewModeNormal
+			m.secretUsage = nil
+			return m, nil
+		case ViewModeQueryResult:
+			m.viewMode = ViewModeNormal
+			m.queryResult = nil
+			return m, nil
+		case ViewModeVarAcross:
+			m.viewMode = ViewModeNormal
+			m.varAcrossResult = nil
+			return m, nil
+		case ViewModeGraph:
+			m.viewMode = ViewModeNormal
+			m.graph = nil
+			return m, nil
+		case ViewModeDetail:
+			m.viewMode = ViewModeNormal
+			m.provenance = nil
+			return m, nil
+		case ViewModeRevisionSelect:
+			m.viewMode = ViewModeNormal
+			m.revisions = nil
+			return m, nil
+		case ViewModeRevisionDiff:
+			m.viewMode = ViewModeRevisionSelect
+			m.revisionDiff = nil
+			return m, nil
+		case ViewModeValueDiff:
+			m.viewMode = m.valueDiffReturn
+			m.valueDiffLines = nil
+			return m, nil
+		case ViewModeConsumers:
+			m.viewMode = ViewModeNormal
+			m.consumers = nil
+			return m, nil
+		case ViewModePreviewCleanup:
+			m.viewMode = ViewModeNormal
+			m.staleNamespaces = nil
+			return m, nil
+		case ViewModeExplain:
+			m.viewMode = ViewModeNormal
+			m.explanation = nil
+			return m, nil
+		case ViewModeContextSelect:
+			m.viewMode = ViewModeNormal
+			if m.contextForDiff {
+				m.viewMode = ViewModeDiffSelect
+				m.contextForDiff = false
+			}
+			m.contextList = nil
+			return m, nil
+		case ViewModeSealResult:
+			m.viewMode = ViewModeNormal
+			m.sealResult = ""
+			m.sealManifest = ""
+			m.sealError = ""
+			return m, nil
+		case ViewModeEditConfirm:
+			m.viewMode = ViewModeNormal
+			m.edit = editState{}
+			return m, nil
+		case ViewModeBulkProgress:
+			return m.cancelBulkDiff()
+		}
+	}
 
 	// Mode-specific handling
 	switch m.viewMode {
@@ -423,10 +1432,62 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleDiffSelect(msg)
 	case ViewModeDiffShow:
 		return m.handleDiffShow(msg)
+	case ViewModeConfigMapDiff:
+		return m.handleConfigMapDiff(msg)
+	case ViewModeFlagMatrix:
+		return m.handleFlagMatrix(msg)
 	case ViewModeSealInput:
 		return m.handleSealInput(msg)
 	case ViewModeSealResult:
 		return m.handleSealResult(msg)
+	case ViewModeEditInput:
+		return m.handleEditInput(msg)
+	case ViewModeEditConfirm:
+		return m.handleEditConfirm(msg)
+	case ViewModeVerifyResult:
+		return m.handleVerifyResult(msg)
+	case ViewModePodSelect:
+		return m.handlePodSelect(msg)
+	case ViewModeWorklist:
+		return m.handleWorklist(msg)
+	case ViewModeWorklistJustify:
+		return m.handleWorklistJustify(msg)
+	case ViewModeSecretUsage:
+		return m.handleSecretUsage(msg)
+	case ViewModeConnectivity:
+		return m.handleConnectivity(msg)
+	case ViewModeKubectl:
+		return m.handleKubectl(msg)
+	case ViewModePreviewCleanup:
+		return m.handlePreviewCleanup(msg)
+	case ViewModeQueryInput:
+		return m.handleQueryInput(msg)
+	case ViewModeQueryResult:
+		return m.handleQueryResult(msg)
+	case ViewModeExplain:
+		return m.handleExplain(msg)
+	case ViewModeContextSelect:
+		return m.handleContextSelect(msg)
+	case ViewModeExportInput:
+		return m.handleExportInput(msg)
+	case ViewModeCopyMenu:
+		return m.handleCopyMenu(msg)
+	case ViewModeVarAcross:
+		return m.handleVarAcross(msg)
+	case ViewModeGraph:
+		return m.handleGraph(msg)
+	case ViewModeDetail:
+		return m.handleDetail(msg)
+	case ViewModeRevisionSelect:
+		return m.handleRevisionSelect(msg)
+	case ViewModeRevisionDiff:
+		return m.handleRevisionDiff(msg)
+	case ViewModeValueDiff:
+		return m.handleValueDiff(msg)
+	case ViewModeConsumers:
+		return m.handleConsumers(msg)
+	case ViewModePalette:
+		return m.handlePalette(msg)
 	}
 
 	return m, nil
@@ -475,8 +1536,95 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case key.Matches(msg, m.keys.Seal):
 		return m.handleSealStart()
+
+	case key.Matches(msg, m.keys.Flags):
+		return m.handleFlagMatrixStart()
+
+	case key.Matches(msg, m.keys.Verify):
+		return m.handleVerifyStart()
+
+	case key.Matches(msg, m.keys.Pods):
+		return m.handlePodSelectStart()
+
+	case key.Matches(msg, m.keys.Worklist):
+		return m.handleWorklistStart()
+
+	case key.Matches(msg, m.keys.Usage):
+		return m.handleSecretUsageStart()
+
+	case key.Matches(msg, m.keys.Connect):
+		return m.handleConnectivityStart()
+
+	case key.Matches(msg, m.keys.Kubectl):
+		return m.handleKubectlStart()
+
+	case key.Matches(msg, m.keys.Cleanup):
+		m.loading = true
+		return m, m.loadStaleNamespaces()
+
+	case key.Matches(msg, m.keys.HealthFilter):
+		return m.handleAppFilter()
+
+	case key.Matches(msg, m.keys.Changed):
+		return m.handleChangedFilter()
+
+	case key.Matches(msg, m.keys.Query):
+		return m.handleQueryStart()
+
+	case key.Matches(msg, m.keys.Container):
+		return m.handleContainerSelect()
+
+	case key.Matches(msg, m.keys.Explain):
+		return m.handleExplainStart()
+
+	case key.Matches(msg, m.keys.Context):
+		return m.handleContextSelectStart()
+
+	case key.Matches(msg, m.keys.Export):
+		return m.handleExportStart()
+
+	case key.Matches(msg, m.keys.Copy):
+		return m.handleCopyStart()
+
+	case key.Matches(msg, m.keys.CopyRows):
+		return m.copyVisibleRows(false)
+
+	case key.Matches(msg, m.keys.Edit):
+		return m.handleEditStart()
+
+	case key.Matches(msg, m.keys.Graph):
+		return m.handleGraphStart()
+
+	case key.Matches(msg, m.keys.History):
+		return m.handleRevisionsStart()
+
+	case key.Matches(msg, m.keys.Sidecars):
+		return m.handleSidecarToggle()
+
+	case key.Matches(msg, m.keys.Consumers):
+		return m.handleConsumersStart()
+
+	case key.Matches(msg, m.keys.Reload):
+		return m.handleReload()
+
+	case key.Matches(msg, m.keys.Pin):
+		return m.handlePinToggle()
+
+	case key.Matches(msg, m.keys.Palette):
+		return m.handlePaletteStart()
+
+	case key.Matches(msg, m.keys.Across):
+		return m.handleVarAcrossStart()
+
+	case key.Matches(msg, m.keys.Group):
+		if m.activePane == PaneEnv {
+			return m.handleGroupToggle()
+		}
 	}
 
+	if cmd, ok := m.handleHookKey(msg); ok {
+		return m, cmd
+	}
 	return m, nil
 }
 
@@ -507,11 +1655,11 @@ func (m Model) handleDown() (tea.Model, tea.Cmd) {
 			m.namespaceCursor++
 		}
 	case PaneApps:
-		if m.appCursor < len(m.apps)-1 {
+		if m.appCursor < len(m.GetFilteredApps())-1 {
 			m.appCursor++
 		}
 	case PaneEnv:
-		if m.envCursor < len(m.envVars)-1 {
+		if m.envCursor < len(m.envRows())-1 {
 			m.envCursor++
 		}
 	}
@@ -526,15 +1674,22 @@ func (m Model) handleEnter() (tea.Model, tea.Cmd) {
 			m.namespaceIdx = m.namespaceCursor
 			m.activePane = PaneApps // Move to Apps pane
 			m.loading = true
-			return m, m.loadApps()
+			cmd := m.activateNamespaceContext()
+			return m, tea.Batch(cmd, m.loadApps())
 		}
 	case PaneApps:
-		if m.appCursor < len(m.apps) {
-			m.appIdx = m.appCursor
+		if apps := m.GetFilteredApps(); m.appCursor < len(apps) {
+			m.appIdx = apps[m.appCursor]
+			m.selectedPod = nil
 			m.activePane = PaneEnv // Move to Env pane
 			m.loading = true
 			return m, m.loadEnvVars()
 		}
+	case PaneEnv:
+		// Enter expands or collapses a prefix group, and opens the detail of a variable
+		if !m.toggleGroup() {
+			return m.handleDetailStart()
+		}
 	}
 	return m, nil
 }
@@ -546,24 +1701,29 @@ func (m Model) handleRevealStart() (tea.Model, tea.Cmd) {
 		m.err = &revealDisabledError{}
 		return m, nil
 	}
-
-	// Only work in env pane with a secret selected
-	if m.activePane != PaneEnv {
+	if m.requireAltScreen() && !m.altScreen {
+		m.err = &revealNoAltScreenError{term: os.Getenv("TERM")}
 		return m, nil
 	}
 
-	if len(m.envVars) == 0 || m.envCursor >= len(m.envVars) {
+	// Only work in env pane with a secret selected
+	if m.activePane != PaneEnv {
 		return m, nil
 	}
 
-	envVar := m.envVars[m.envCursor]
-	if !envVar.IsSecret() {
+	envVar, ok := m.selectedEnvVar()
+	if !ok || !envVar.IsMasked() {
 		return m, nil
 	}
 
 	m.viewMode = ViewModeRevealMenu
 	m.revealMenuIdx = 0
 	m.revealedEnvName = envVar.Name
+	m.revealedSecretName = ""
+	if envVar.IsSecret() {
+		m.revealedSecretName = envVar.SourceName
+	}
+	m.revealNamespace = m.apps[m.appIdx].Namespace
 	return m, nil
 }
 
@@ -577,17 +1737,13 @@ func (m Model) handleRevealMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case key.Matches(msg, m.keys.Down):
-		if m.revealMenuIdx < 1 {
+		if m.revealMenuIdx < len(m.revealMenuOptions())-1 {
 			m.revealMenuIdx++
 		}
 		return m, nil
 
 	case key.Matches(msg, m.keys.Enter):
-		if m.revealMenuIdx == 0 {
-			m.revealMode = RevealModeBase64
-		} else {
-			m.revealMode = RevealModePlain
-		}
+		m.revealMode = RevealMode(m.revealMenuIdx)
 		m.viewMode = ViewModeRevealConfirm
 		m.revealInput.Reset()
 		m.revealInput.Focus()
@@ -601,120 +1757,557 @@ func (m Model) handleRevealMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) handleRevealConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, m.keys.Enter):
+		if m.loading {
+			// Waiting for the Event or the Secret
+			return m, nil
+		}
 		if m.revealInput.Value() == "OK" {
-			// Find the env var and reveal it
-			for _, ev := range m.envVars {
-				if ev.Name == m.revealedEnvName {
-					if m.revealMode == RevealModeBase64 {
-						m.revealedValue = k8s.EncodeBase64(ev.RawValue)
-					} else {
-						m.revealedValue = string(ev.RawValue)
-					}
-					break
-				}
+			// The Event goes first: a reveal that cannot be recorded is refused
+			if record := m.recordConfirmedReveal(); record != nil {
+				m.loading = true
+				return m, record
+			}
+			return m.confirmReveal()
+		}
+		return m, nil
+	}
+
+	// Handle text input
+	var cmd tea.Cmd
+	m.revealInput, cmd = m.revealInput.Update(msg)
+	return m, cmd
+}
+
+// confirmReveal reveals (or copies) the value confirmed with OK
+func (m Model) confirmReveal() (tea.Model, tea.Cmd) {
+	if m.copyField != copyNone {
+		return m.copySelected(m.copyField)
+	}
+	if m.revealMode == RevealModeSecret {
+		if m.revealedSecretName == "" {
+			return m.closeReveal()
+		}
+		m.loading = true
+		return m, m.loadRevealSecret(m.revealNamespace, m.revealedSecretName)
+	}
+	// Find the env var and reveal it
+	for _, ev := range m.envVars {
+		if ev.Name == m.revealedEnvName {
+			switch m.revealMode {
+			case RevealModeBase64:
+				m.revealedValue = k8s.EncodeBase64(ev.RawValue)
+			case RevealModeMasked:
+				m.revealedValue = k8s.MaskValue(ev.RawValue)
+			default:
+				m.revealedValue = string(ev.RawValue)
 			}
-			m.viewMode = ViewModeRevealShow
-			m.revealExpiry = time.Now().Add(30 * time.Second)
-			return m, tea.Tick(30*time.Second, func(t time.Time) tea.Msg {
-				return revealTimeoutMsg{}
-			})
+			break
+		}
+	}
+	m.viewMode = ViewModeRevealShow
+	m.revealExpiry = time.Now().Add(30 * time.Second)
+	return m, tea.Tick(30*time.Second, func(t time.Time) tea.Msg {
+		return revealTimeoutMsg{}
+	})
+}
+
+// handleRevealShow handles key press in reveal show mode
+func (m Model) handleRevealShow(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.revealedSecret != nil && m.scrollRevealedSecret(msg) {
+		return m, nil
+	}
+	// Handle copy to clipboard
+	if msg.String() == "c" && m.revealedValue != "" && !m.revealCopied && m.revealMode != RevealModeMasked {
+		err := copyToClipboard(m.revealedValue)
+		if err != nil {
+			m.statusMessage = fmt.Sprintf("Copy failed: %v", err)
+			return m, m.clearStatusAfter(3 * time.Second)
+		}
+		m.revealCopied = true
+		return m, nil
+	}
+
+	// Any other key returns to normal mode
+	return m.closeReveal()
+}
+
+// closeReveal forgets the revealed value and repaints the whole screen, so no
+// cell of the dialog survives in terminals that keep alt-screen content around
+func (m Model) closeReveal() (tea.Model, tea.Cmd) {
+	wasShown := m.revealedValue != "" || m.revealedSecret != nil
+	m.viewMode = ViewModeNormal
+	m.revealedValue = ""
+	m.revealedEnvName = ""
+	m.revealedSecret = nil
+	m.revealedSecretName = ""
+	m.revealNamespace = ""
+	m.revealScroll = 0
+	m.revealCopied = false
+	m.copyField = copyNone
+	if !wasShown {
+		return m, nil
+	}
+	return m, tea.ClearScreen
+}
+
+// handleDiffStart starts the diff flow
+func (m Model) handleDiffStart() (tea.Model, tea.Cmd) {
+	if len(m.apps) == 0 || m.appCursor >= len(m.apps) {
+		return m, nil
+	}
+
+	// Other namespaces of the same cluster first; another cluster can be picked with c
+	m.diffNamespaces = m.otherNamespaces()
+	m.diffResolverB = nil
+	m.diffContextB = ""
+
+	m.viewMode = ViewModeDiffSelect
+	m.diffBulk = false
+	m.diffNsIdx = m.promotionTargetIdx()
+	return m, nil
+}
+
+// otherNamespaces returns all namespaces of the active context except the selected one
+func (m Model) otherNamespaces() []string {
+	if len(m.namespaces) == 0 {
+		return nil
+	}
+	others := make([]string, 0, len(m.namespaces))
+	currentNs := m.namespaces[m.namespaceIdx]
+	for i, ns := range m.namespaces {
+		if ns != currentNs && m.namespaceContext(i) == m.context {
+			others = append(others, ns)
+		}
+	}
+	return others
+}
+
+// promotionTargetIdx returns the index in diffNamespaces of the namespace on the
+// next tier of the promotion path, preferring the one whose name shares the
+// longest prefix with the current namespace (e.g. shop-dev -> shop-staging)
+func (m Model) promotionTargetIdx() int {
+	currentNs := m.namespaces[m.namespaceIdx]
+	next := m.cfg.NextTier(m.tierOf(currentNs))
+	if next == "" {
+		return 0
+	}
+
+	best, bestLen := 0, -1
+	for i, ns := range m.diffNamespaces {
+		if m.tierOf(ns) != next {
+			continue
+		}
+		if n := commonPrefixLen(currentNs, ns); n > bestLen {
+			best, bestLen = i, n
+		}
+	}
+	return best
+}
+
+// isPromotion returns true if nsB is the next tier after nsA on the promotion path
+func (m Model) isPromotion(nsA, nsB string) bool {
+	next := m.cfg.NextTier(m.tierOf(nsA))
+	return next != "" && m.tierOf(nsB) == next
+}
+
+// commonPrefixLen returns the length of the common prefix of a and b
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// handleDiffSelect handles key press in diff select mode
+func (m Model) handleDiffSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.diffNsIdx > 0 {
+			m.diffNsIdx--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.diffNsIdx < len(m.diffNamespaces)-1 {
+			m.diffNsIdx++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		if len(m.diffNamespaces) == 0 {
+			return m, nil
+		}
+		nsA := m.namespaces[m.namespaceIdx]
+		nsB := m.diffNamespaces[m.diffNsIdx]
+		if m.diffBulk {
+			return m.loadWorklist(nsA, nsB)
+		}
+		m.loading = true
+		m.diffNsA, m.diffNsB = nsA, nsB
+		a, b := m.diffSides()
+		app := m.apps[m.appIdx]
+		return m, m.loadDiff(a, b, app.Name, app.Kind)
+
+	case key.Matches(msg, m.keys.Context):
+		return m.handleDiffContextStart()
+	}
+
+	return m, nil
+}
+
+// handleDiffShow handles key press in diff show mode
+func (m Model) handleDiffShow(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.diffCursor > 0 {
+			m.diffCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.diffCursor < len(m.diffResults)-1 {
+			m.diffCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		return m.handleDiffRowEnter()
+
+	case key.Matches(msg, m.keys.Kubectl):
+		return m.handleKubectlStart()
+
+	case key.Matches(msg, m.keys.Export):
+		return m.handleDiffExportStart()
+	}
+
+	return m, nil
+}
+
+// handleConfigMapDiffStart drills down into the ConfigMaps backing the selected diff row
+func (m Model) handleConfigMapDiffStart() (tea.Model, tea.Cmd) {
+	if m.diffCursor >= len(m.diffResults) {
+		return m, nil
+	}
+	result := m.diffResults[m.diffCursor]
+
+	nameA := ""
+	if result.EnvA != nil && result.EnvA.SourceKind == k8s.EnvSourceConfigMap {
+		nameA = result.EnvA.SourceName
+	}
+	nameB := ""
+	if result.EnvB != nil && result.EnvB.SourceKind == k8s.EnvSourceConfigMap {
+		nameB = result.EnvB.SourceName
+	}
+
+	if nameA == "" && nameB == "" {
+		m.statusMessage = "Selected variable is not sourced from a ConfigMap"
+		return m, m.clearStatusAfter(2 * time.Second)
+	}
+
+	// Assume the same ConfigMap name on the side where the variable is missing
+	if nameA == "" {
+		nameA = nameB
+	}
+	if nameB == "" {
+		nameB = nameA
+	}
+
+	a, b := m.diffSides()
+	m.loading = true
+	return m, m.loadConfigMapDiff(a, nameA, b, nameB)
+}
+
+// handleConfigMapDiff handles key press in ConfigMap diff mode
+func (m Model) handleConfigMapDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.cmDiffCursor > 0 {
+			m.cmDiffCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.cmDiffCursor < len(m.cmDiffResults)-1 {
+			m.cmDiffCursor++
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleFlagMatrixStart opens the namespace-wide feature flag matrix
+func (m Model) handleFlagMatrixStart() (tea.Model, tea.Cmd) {
+	if len(m.cfg.FeatureFlags) == 0 {
+		m.statusMessage = "No feature flag patterns configured (featureFlags in config file)"
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+	if len(m.apps) == 0 {
+		return m, nil
+	}
+
+	m.loading = true
+	return m, m.loadFlagMatrix()
+}
+
+// handleFlagMatrix handles key press in feature flag matrix mode
+func (m Model) handleFlagMatrix(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.flagMatrix == nil {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.flagCursor > 0 {
+			m.flagCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.flagCursor < len(m.flagMatrix.Apps)-1 {
+			m.flagCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Left):
+		if m.flagColOffset > 0 {
+			m.flagColOffset--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Right):
+		if m.flagColOffset < len(m.flagMatrix.Flags)-1 {
+			m.flagColOffset++
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleSecretUsageStart opens the Secret usage heatmap of the selected namespace
+func (m Model) handleSecretUsageStart() (tea.Model, tea.Cmd) {
+	if len(m.apps) == 0 {
+		return m, nil
+	}
+	m.loading = true
+	return m, m.loadSecretUsage()
+}
+
+// handleSecretUsage handles key press in the Secret usage heatmap
+func (m Model) handleSecretUsage(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.secretUsageCursor > 0 {
+			m.secretUsageCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.secretUsageCursor < len(m.secretUsage)-1 {
+			m.secretUsageCursor++
 		}
 		return m, nil
 	}
 
-	// Handle text input
-	var cmd tea.Cmd
-	m.revealInput, cmd = m.revealInput.Update(msg)
-	return m, cmd
+	return m, nil
 }
 
-// handleRevealShow handles key press in reveal show mode
-func (m Model) handleRevealShow(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Handle copy to clipboard
-	if msg.String() == "c" && m.revealedValue != "" && !m.revealCopied {
-		err := copyToClipboard(m.revealedValue)
-		if err != nil {
-			m.statusMessage = fmt.Sprintf("Copy failed: %v", err)
-			return m, m.clearStatusAfter(3 * time.Second)
-		}
-		m.revealCopied = true
+// handlePodSelectStart opens the pod picker for pod-level resolution
+func (m Model) handlePodSelectStart() (tea.Model, tea.Cmd) {
+	if len(m.apps) == 0 || m.appIdx >= len(m.apps) {
 		return m, nil
 	}
+	m.loading = true
+	return m, m.loadPods()
+}
 
-	// Any other key returns to normal mode
-	m.viewMode = ViewModeNormal
-	m.revealedValue = ""
-	m.revealedEnvName = ""
-	m.revealCopied = false
-	return m, nil
+// filteredPods returns the pods matching the pod filter (pod name, node or zone)
+func (m Model) filteredPods() []k8s.Pod {
+	query := strings.ToLower(m.podFilterInput.Value())
+	if query == "" {
+		return m.pods
+	}
+	result := make([]k8s.Pod, 0, len(m.pods))
+	for _, pod := range m.pods {
+		if strings.Contains(strings.ToLower(pod.Name), query) ||
+			strings.Contains(strings.ToLower(pod.NodeName), query) ||
+			strings.Contains(strings.ToLower(pod.Zone), query) {
+			result = append(result, pod)
+		}
+	}
+	return result
 }
 
-// handleDiffStart starts the diff flow
-func (m Model) handleDiffStart() (tea.Model, tea.Cmd) {
-	if len(m.apps) == 0 || m.appCursor >= len(m.apps) {
+// handlePodSelect handles key press in pod select mode.
+// Cursor 0 is the workload template; pods follow from index 1.
+func (m Model) handlePodSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	pods := m.filteredPods()
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.viewMode = ViewModeNormal
+		m.podFilterInput.Reset()
 		return m, nil
-	}
 
-	m.diffNamespaces = make([]string, 0, len(m.namespaces))
-	currentNs := m.namespaces[m.namespaceIdx]
-	for _, ns := range m.namespaces {
-		if ns != currentNs {
-			m.diffNamespaces = append(m.diffNamespaces, ns)
+	case tea.KeyUp, tea.KeyCtrlP:
+		if m.podCursor > 0 {
+			m.podCursor--
+		}
+		return m, nil
+
+	case tea.KeyDown, tea.KeyCtrlN:
+		if m.podCursor < len(pods) {
+			m.podCursor++
+		}
+		return m, nil
+
+	case tea.KeyEnter:
+		if m.podCursor == 0 {
+			m.selectedPod = nil
+		} else {
+			pod := pods[m.podCursor-1]
+			m.selectedPod = &pod
 		}
+		m.viewMode = ViewModeNormal
+		m.podFilterInput.Reset()
+		m.activePane = PaneEnv
+		m.loading = true
+		return m, m.loadEnvVars()
 	}
 
+	var cmd tea.Cmd
+	m.podFilterInput, cmd = m.podFilterInput.Update(msg)
+	m.podCursor = 0
+	return m, cmd
+}
+
+// handleWorklistStart asks for a namespace to bulk-diff against
+func (m Model) handleWorklistStart() (tea.Model, tea.Cmd) {
+	m.diffNamespaces = m.otherNamespaces()
 	if len(m.diffNamespaces) == 0 {
 		return m, nil
 	}
+	m.diffResolverB = nil
+	m.diffContextB = ""
 
 	m.viewMode = ViewModeDiffSelect
-	m.diffNsIdx = 0
+	m.diffBulk = true
+	m.diffNsIdx = m.promotionTargetIdx()
 	return m, nil
 }
 
-// handleDiffSelect handles key press in diff select mode
-func (m Model) handleDiffSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+// handleWorklist handles key press in the drift worklist
+func (m Model) handleWorklist(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, m.keys.Up):
-		if m.diffNsIdx > 0 {
-			m.diffNsIdx--
+		if m.worklistCursor > 0 {
+			m.worklistCursor--
 		}
 		return m, nil
 
 	case key.Matches(msg, m.keys.Down):
-		if m.diffNsIdx < len(m.diffNamespaces)-1 {
-			m.diffNsIdx++
+		if m.worklistCursor < len(m.worklist)-1 {
+			m.worklistCursor++
 		}
 		return m, nil
+	}
 
-	case key.Matches(msg, m.keys.Enter):
-		nsA := m.namespaces[m.namespaceIdx]
-		nsB := m.diffNamespaces[m.diffNsIdx]
-		app := m.apps[m.appIdx]
-		m.loading = true
-		return m, m.loadDiff(nsA, nsB, app.Name, app.Kind)
+	if len(m.worklist) == 0 {
+		return m, nil
+	}
+	if item := m.worklist[m.worklistCursor]; item.Err != nil && msg.String() != "x" {
+		m.statusMessage = fmt.Sprintf("%s was not compared: %v", item.App, item.Err)
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+
+	switch msg.String() {
+	case "e":
+		// Mark as expected: ask for a justification before persisting
+		m.justifyInput.Reset()
+		m.justifyInput.Focus()
+		m.viewMode = ViewModeWorklistJustify
+		return m, textinput.Blink
+
+	case "f":
+		m.setWorklistMark(drift.MarkNeedsFix)
+		return m, nil
+
+	case "u":
+		m.setWorklistMark(drift.MarkPending)
+		return m, nil
+
+	case "x":
+		return m.exportWorklist()
 	}
 
 	return m, nil
 }
 
-// handleDiffShow handles key press in diff show mode
-func (m Model) handleDiffShow(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch {
-	case key.Matches(msg, m.keys.Up):
-		if m.diffCursor > 0 {
-			m.diffCursor--
-		}
+// setWorklistMark sets the mark of the item under the cursor (copy-on-write)
+func (m *Model) setWorklistMark(mark drift.Mark) {
+	items := make([]drift.Item, len(m.worklist))
+	copy(items, m.worklist)
+	items[m.worklistCursor].Mark = mark
+	m.worklist = items
+}
+
+// handleWorklistJustify handles key press while entering a justification
+func (m Model) handleWorklistJustify(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.viewMode = ViewModeWorklist
 		return m, nil
 
-	case key.Matches(msg, m.keys.Down):
-		if m.diffCursor < len(m.diffResults)-1 {
-			m.diffCursor++
+	case tea.KeyEnter:
+		justification := strings.TrimSpace(m.justifyInput.Value())
+		if justification == "" {
+			m.statusMessage = "Justification is required"
+			return m, m.clearStatusAfter(2 * time.Second)
+		}
+
+		item := m.worklist[m.worklistCursor]
+		err := m.ignores.Add(drift.Ignore{
+			NamespaceA:    m.worklistNsA,
+			NamespaceB:    m.worklistNsB,
+			App:           item.App,
+			Name:          item.Result.Name,
+			Justification: justification,
+		})
+		if err != nil {
+			m.statusMessage = fmt.Sprintf("Failed to save ignore: %v", err)
+			return m, m.clearStatusAfter(3 * time.Second)
 		}
+
+		m.setWorklistMark(drift.MarkExpected)
+		m.viewMode = ViewModeWorklist
 		return m, nil
 	}
 
-	return m, nil
+	var cmd tea.Cmd
+	m.justifyInput, cmd = m.justifyInput.Update(msg)
+	return m, cmd
+}
+
+// exportWorklist writes the "needs fix" items as a Markdown task list in the current directory
+func (m Model) exportWorklist() (tea.Model, tea.Cmd) {
+	path := fmt.Sprintf("envtop-drift-%s-%s.md", m.worklistNsA, m.worklistNsB)
+	f, err := os.Create(path)
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("Export failed: %v", err)
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+	defer f.Close()
+
+	count, err := drift.WriteTaskList(f, m.worklistNsA, m.worklistNsB, m.worklist)
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("Export failed: %v", err)
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+
+	m.statusMessage = fmt.Sprintf("Wrote %d task(s) to %s", count, path)
+	return m, m.clearStatusAfter(3 * time.Second)
 }
 
 // handleSearchStart starts the search mode
@@ -739,8 +2332,10 @@ func (m Model) handleSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		switch m.searchPane {
 		case PaneNamespaces:
 			m.loading = true
-			return m, m.loadApps()
+			cmd := m.activateNamespaceContext()
+			return m, tea.Batch(cmd, m.loadApps())
 		case PaneApps:
+			m.selectedPod = nil
 			m.loading = true
 			return m, m.loadEnvVars()
 		}
@@ -779,13 +2374,16 @@ func (m *Model) updateFilter(query string) {
 
 	switch m.searchPane {
 	case PaneNamespaces:
-		m.filteredNamespaces = m.filterStrings(m.namespaces, query)
+		m.filteredNamespaces = m.filterStrings(m.namespaceSearchNames(), query)
 		if len(m.filteredNamespaces) > 0 {
 			m.namespaceCursor = 0
 		}
 	case PaneApps:
 		m.filteredApps = nil
 		for i, app := range m.apps {
+			if !m.matchesAppFilter(app) {
+				continue
+			}
 			if query == "" || strings.Contains(strings.ToLower(app.Name), query) {
 				m.filteredApps = append(m.filteredApps, i)
 			}
@@ -794,9 +2392,15 @@ func (m *Model) updateFilter(query string) {
 			m.appCursor = 0
 		}
 	case PaneEnv:
+		// Env search takes filter expressions (name=DB_*,kind=Secret); while one
+		// is incomplete, it falls back to a plain substring of the name
+		filter, err := env.ParseFilter(query)
 		m.filteredEnvVars = nil
 		for i, ev := range m.envVars {
-			if query == "" || strings.Contains(strings.ToLower(ev.Name), query) {
+			if m.changedOnly && !m.isChanged(ev.Name) {
+				continue
+			}
+			if err == nil && filter.Match(ev) || err != nil && strings.Contains(strings.ToLower(ev.Name), query) {
 				m.filteredEnvVars = append(m.filteredEnvVars, i)
 			}
 		}
@@ -871,6 +2475,9 @@ func (m *Model) applySearchSelection() {
 			m.envIdx = m.filteredEnvVars[m.envCursor]
 		}
 		m.filteredEnvVars = nil
+		if m.groupByPrefix && m.envIdx < len(m.envVars) {
+			m.focusEnvVar(m.envVars[m.envIdx].Name)
+		}
 	}
 }
 
@@ -892,10 +2499,12 @@ func (m *Model) GetFilteredApps() []int {
 	if m.viewMode == ViewModeSearch && m.searchPane == PaneApps && m.filteredApps != nil {
 		return m.filteredApps
 	}
-	// Return all indices
-	result := make([]int, len(m.apps))
-	for i := range m.apps {
-		result[i] = i
+	// Return all indices passing the health filter
+	result := make([]int, 0, len(m.apps))
+	for i, app := range m.apps {
+		if m.matchesAppFilter(app) {
+			result = append(result, i)
+		}
 	}
 	return result
 }
@@ -905,10 +2514,12 @@ func (m *Model) GetFilteredEnvVars() []int {
 	if m.viewMode == ViewModeSearch && m.searchPane == PaneEnv && m.filteredEnvVars != nil {
 		return m.filteredEnvVars
 	}
-	// Return all indices
-	result := make([]int, len(m.envVars))
-	for i := range m.envVars {
-		result[i] = i
+	// Return all indices, or only changed variables when filtering by change
+	result := make([]int, 0, len(m.envVars))
+	for i, ev := range m.envVars {
+		if !m.changedOnly || m.isChanged(ev.Name) {
+			result = append(result, i)
+		}
 	}
 	return result
 }
@@ -925,51 +2536,80 @@ func (m Model) handleSealStart() (tea.Model, tea.Cmd) {
 		m.statusMessage = "No namespace selected"
 		return m, m.clearStatusAfter(2 * time.Second)
 	}
+	if m.capabilities[k8s.CapabilitySealedSecrets] == capabilityMissing {
+		m.statusMessage = "SealedSecret CRD is not installed in this cluster"
+		return m, m.clearStatusAfter(2 * time.Second)
+	}
 
 	// Reset inputs
 	m.sealSecretInput.Reset()
+	m.sealKeyInput.Reset()
 	m.sealValueInput.Reset()
 
-	// Try to pre-fill secret name if a Secret/SealedSecret is selected in Env pane
+	// Try to pre-fill secret name and key if a Secret/SealedSecret is selected in Env pane.
+	// The key defaults to the variable name, which holds for envFrom and most secretKeyRefs.
 	if m.activePane == PaneEnv && len(m.envVars) > 0 {
-		filteredIndices := m.GetFilteredEnvVars()
-		if m.envCursor < len(filteredIndices) {
-			envVar := m.envVars[filteredIndices[m.envCursor]]
+		if envVar, ok := m.selectedEnvVar(); ok {
 			if envVar.IsSecret() {
-				// Pre-fill secret name from selected env var
 				m.sealSecretInput.SetValue(envVar.SourceName)
-				m.sealFocusField = 1 // Focus on value input
-				m.sealSecretInput.Blur()
-				m.sealValueInput.Focus()
+				m.sealKeyInput.SetValue(envVar.Name)
 				m.viewMode = ViewModeSealInput
-				return m, textinput.Blink
+				return m, m.setSealFocus(2) // Focus on value input
 			}
 		}
 	}
 
 	// No pre-fill, focus on secret name input
-	m.sealFocusField = 0
-	m.sealSecretInput.Focus()
-	m.sealValueInput.Blur()
 	m.viewMode = ViewModeSealInput
-	return m, textinput.Blink
+	return m, m.setSealFocus(0)
+}
+
+// setSealFocus focuses one field of the seal dialog: 0 secret name, 1 key, 2 value
+func (m *Model) setSealFocus(field int) tea.Cmd {
+	m.sealFocusField = field
+	m.sealSecretInput.Blur()
+	m.sealKeyInput.Blur()
+	m.sealValueInput.Blur()
+	switch field {
+	case 0:
+		return m.sealSecretInput.Focus()
+	case 1:
+		return m.sealKeyInput.Focus()
+	default:
+		return m.sealValueInput.Focus()
+	}
+}
+
+// updateSealField passes a message to the focused field of the seal dialog
+func (m Model) updateSealField(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch m.sealFocusField {
+	case 0:
+		m.sealSecretInput, cmd = m.sealSecretInput.Update(msg)
+	case 1:
+		m.sealKeyInput, cmd = m.sealKeyInput.Update(msg)
+	default:
+		m.sealValueInput, cmd = m.sealValueInput.Update(msg)
+	}
+	return m, cmd
 }
 
 // handleSealInput handles key press in seal input mode
 func (m Model) handleSealInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
-	case tea.KeyTab, tea.KeyShiftTab:
-		// Toggle between fields
-		if m.sealFocusField == 0 {
-			m.sealFocusField = 1
-			m.sealSecretInput.Blur()
-			m.sealValueInput.Focus()
-		} else {
-			m.sealFocusField = 0
-			m.sealValueInput.Blur()
-			m.sealSecretInput.Focus()
-		}
-		return m, textinput.Blink
+	case tea.KeyEsc:
+		// Only Esc cancels (other cancel keys are valid input here)
+		m.viewMode = ViewModeNormal
+		m.sealSecretInput.Reset()
+		m.sealKeyInput.Reset()
+		m.sealValueInput.Reset()
+		return m, nil
+
+	case tea.KeyTab:
+		return m, m.setSealFocus((m.sealFocusField + 1) % 3)
+
+	case tea.KeyShiftTab:
+		return m, m.setSealFocus((m.sealFocusField + 2) % 3)
 
 	case tea.KeyEnter:
 		secretName := m.sealSecretInput.Value()
@@ -983,18 +2623,15 @@ func (m Model) handleSealInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, m.clearStatusAfter(2 * time.Second)
 		}
 		m.sealSecretName = secretName
+		m.sealKey = strings.TrimSpace(m.sealKeyInput.Value())
+		m.sealCopied = false
+		m.sealApplied = false
 		m.loading = true
 		return m, m.executeSeal(value)
 	}
 
 	// Handle text input for the focused field
-	var cmd tea.Cmd
-	if m.sealFocusField == 0 {
-		m.sealSecretInput, cmd = m.sealSecretInput.Update(msg)
-	} else {
-		m.sealValueInput, cmd = m.sealValueInput.Update(msg)
-	}
-	return m, cmd
+	return m.updateSealField(msg)
 }
 
 // handleSealResult handles key press in seal result mode
@@ -1004,9 +2641,13 @@ func (m Model) handleSealResult(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Handle copy to clipboard
+	// Handle copy to clipboard: the manifest when there is one, else the raw value
 	if msg.String() == "c" && m.sealResult != "" && !m.sealCopied {
-		err := copyToClipboard(m.sealResult)
+		text := m.sealResult
+		if m.sealManifest != "" {
+			text = m.sealManifest
+		}
+		err := copyToClipboard(text)
 		if err != nil {
 			m.statusMessage = fmt.Sprintf("Copy failed: %v", err)
 			return m, m.clearStatusAfter(3 * time.Second)
@@ -1015,11 +2656,188 @@ func (m Model) handleSealResult(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Apply the sealed value to the SealedSecret in the cluster
+	if msg.String() == "a" && m.sealManifest != "" && !m.sealApplied {
+		m.loading = true
+		return m, m.applySealedValue()
+	}
+
 	// Any other key returns to normal mode
 	m.viewMode = ViewModeNormal
 	m.sealResult = ""
+	m.sealManifest = ""
 	m.sealError = ""
 	m.sealCopied = false
+	m.sealApplied = false
+	return m, nil
+}
+
+// selectedEnvVar returns the env var under the cursor in the Env pane
+func (m Model) selectedEnvVar() (k8s.EnvVar, bool) {
+	rows := m.envRows()
+	if m.envCursor >= len(rows) || rows[m.envCursor].index < 0 {
+		return k8s.EnvVar{}, false
+	}
+	return m.envVars[rows[m.envCursor].index], true
+}
+
+// handleVerifyStart verifies the SealedSecret behind the selected env var
+func (m Model) handleVerifyStart() (tea.Model, tea.Cmd) {
+	if m.activePane != PaneEnv {
+		return m, nil
+	}
+	envVar, ok := m.selectedEnvVar()
+	if !ok {
+		return m, nil
+	}
+	if !envVar.IsSealed {
+		m.statusMessage = "Selected variable is not from a SealedSecret"
+		return m, m.clearStatusAfter(2 * time.Second)
+	}
+
+	m.loading = true
+	return m, m.verifySealedSecret(m.namespaces[m.namespaceIdx], envVar.SourceName)
+}
+
+// verifySealedSecret checks the SealedSecret against its live Secret
+func (m Model) verifySealedSecret(namespace, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		sealed, err := m.client.GetSealedSecret(ctx, namespace, name)
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to get sealedsecret %s: %w", name, err)}
+		}
+		result, err := seal.Verify(ctx, m.client, sealed, seal.VerifyOptions{
+			ControllerNamespace: k8s.DefaultSealedSecretsControllerNamespace,
+			ControllerName:      k8s.DefaultSealedSecretsControllerName,
+		})
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return verifyResultMsg{result: result}
+	}
+}
+
+// handleVerifyResult handles key press in verify result mode
+func (m Model) handleVerifyResult(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Any key returns to normal mode
+	m.viewMode = ViewModeNormal
+	m.verifyResult = nil
+	return m, nil
+}
+
+// handleConnectivityStart checks the in-cluster endpoint referenced by the selected env var
+func (m Model) handleConnectivityStart() (tea.Model, tea.Cmd) {
+	if m.activePane != PaneEnv {
+		return m, nil
+	}
+	envVar, ok := m.selectedEnvVar()
+	if !ok {
+		return m, nil
+	}
+
+	value := envVar.Value
+	if envVar.IsMasked() {
+		value = string(envVar.RawValue)
+	}
+	ep, ok := netcheck.ParseEndpoint(value, m.namespaces[m.namespaceIdx])
+	if !ok {
+		m.statusMessage = "Value does not look like an in-cluster host or URL"
+		return m, m.clearStatusAfter(2 * time.Second)
+	}
+
+	m.connEnvName = envVar.Name
+	m.loading = true
+	return m, m.checkConnectivity(ep, false)
+}
+
+// checkConnectivity runs a connectivity check, optionally with a port-forward TCP connect
+func (m Model) checkConnectivity(ep netcheck.Endpoint, portForward bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		result := netcheck.Check(ctx, m.client, ep, netcheck.Options{PortForward: portForward})
+		return connectivityMsg{result: result}
+	}
+}
+
+// handleConnectivity handles key press in the connectivity result dialog
+func (m Model) handleConnectivity(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "f" && m.connResult != nil {
+		m.loading = true
+		return m, m.checkConnectivity(m.connResult.Endpoint, true)
+	}
+
+	// Any other key returns to normal mode
+	m.viewMode = ViewModeNormal
+	m.connResult = nil
+	return m, nil
+}
+
+// handleKubectlStart shows the kubectl commands equivalent to the current view
+func (m Model) handleKubectlStart() (tea.Model, tea.Cmd) {
+	m.kubectlCommands = m.equivalentKubectl(m.viewMode)
+	m.kubectlReturn = m.viewMode
+	m.kubectlCopied = false
+	m.viewMode = ViewModeKubectl
+	return m, nil
+}
+
+// handleKubectl handles key press in the kubectl command dialog
+func (m Model) handleKubectl(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "c" && !m.kubectlCopied {
+		if err := copyToClipboard(strings.Join(m.kubectlCommands, "\n")); err != nil {
+			m.statusMessage = fmt.Sprintf("Copy failed: %v", err)
+			return m, m.clearStatusAfter(3 * time.Second)
+		}
+		m.kubectlCopied = true
+		return m, nil
+	}
+
+	// Any other key returns to the previous view
+	m.viewMode = m.kubectlReturn
+	m.kubectlCommands = nil
+	return m, nil
+}
+
+// loadStaleNamespaces finds preview namespaces older than the configured max age
+func (m Model) loadStaleNamespaces() tea.Cmd {
+	cfg := m.cfg
+	return func() tea.Msg {
+		ctx := context.Background()
+		maxAge := time.Duration(cfg.PreviewMaxAgeDays()) * 24 * time.Hour
+		stale, err := preview.FindStale(ctx, m.client, cfg, maxAge, time.Now())
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return staleNamespacesMsg{stale: stale}
+	}
+}
+
+// handlePreviewCleanup handles key press in the stale preview namespace report
+func (m Model) handlePreviewCleanup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.staleCursor > 0 {
+			m.staleCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.staleCursor < len(m.staleNamespaces)-1 {
+			m.staleCursor++
+		}
+		return m, nil
+
+	case msg.String() == "c" && len(m.staleNamespaces) > 0:
+		commands := preview.DeleteCommands(m.context, m.staleNamespaces)
+		if err := copyToClipboard(strings.Join(commands, "\n")); err != nil {
+			m.statusMessage = fmt.Sprintf("Copy failed: %v", err)
+			return m, m.clearStatusAfter(3 * time.Second)
+		}
+		m.staleCopied = true
+		return m, nil
+	}
+
 	return m, nil
 }
 
@@ -1030,34 +2848,61 @@ func (m Model) clearStatusAfter(d time.Duration) tea.Cmd {
 	})
 }
 
-// executeSeal runs kubeseal to encrypt the value
+// executeSeal encrypts the value with the sealed-secrets controller certificate.
+// With a key, the SealedSecret manifest holding the value is built as well.
 func (m Model) executeSeal(plainText string) tea.Cmd {
 	namespace := m.namespaces[m.namespaceIdx]
 	secretName := m.sealSecretName
+	secretKey := m.sealKey
 
 	return func() tea.Msg {
-		result, err := runKubeseal(namespace, secretName, plainText)
-		if err != "" {
-			return sealResultMsg{result: "", err: err}
+		result, err := m.sealRaw(namespace, secretName, []byte(plainText))
+		if err != nil {
+			return sealResultMsg{result: "", err: err.Error()}
+		}
+		if secretKey == "" {
+			return sealResultMsg{result: result, err: ""}
 		}
-		return sealResultMsg{result: result, err: ""}
+		manifest := seal.NewManifest(namespace, secretName, seal.ScopeStrict, map[string]string{secretKey: result})
+		data, err := yaml.Marshal(manifest)
+		if err != nil {
+			return sealResultMsg{result: "", err: err.Error()}
+		}
+		return sealResultMsg{result: result, manifest: string(data), err: ""}
 	}
 }
 
-// runKubeseal executes the kubeseal command
-func runKubeseal(namespace, secretName, plainText string) (string, string) {
-	cmd := exec.Command("kubeseal", "--raw", "--from-file=/dev/stdin", "--namespace", namespace, "--name", secretName)
-	cmd.Stdin = strings.NewReader(plainText)
-
-	output, err := cmd.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Sprintf("kubeseal error: %s", string(exitErr.Stderr))
+// applySealedValue sets the sealed value in the SealedSecret, creating it from
+// the manifest when it does not exist yet. The other encrypted keys are kept.
+func (m Model) applySealedValue() tea.Cmd {
+	p := k8s.SealedValuePatch{
+		Namespace:      m.namespaces[m.namespaceIdx],
+		Name:           m.sealSecretName,
+		Key:            m.sealKey,
+		EncryptedValue: m.sealResult,
+	}
+	return func() tea.Msg {
+		manifest := seal.NewManifest(p.Namespace, p.Name, seal.ScopeStrict, map[string]string{p.Key: p.EncryptedValue})
+		data, err := json.Marshal(manifest)
+		if err != nil {
+			return sealAppliedMsg{err: err}
 		}
-		return "", fmt.Sprintf("kubeseal error: %v", err)
+		return sealAppliedMsg{err: m.client.ApplySealedValue(context.Background(), p, data)}
 	}
+}
 
-	return strings.TrimSpace(string(output)), ""
+// sealRaw fetches the controller certificate and encrypts the value like `kubeseal --raw`
+func (m Model) sealRaw(namespace, secretName string, plainText []byte) (string, error) {
+	ctx := context.Background()
+	certPEM, err := m.client.FetchSealingCert(ctx, k8s.DefaultSealedSecretsControllerNamespace, k8s.DefaultSealedSecretsControllerName)
+	if err != nil {
+		return "", err
+	}
+	pubKey, err := seal.ParsePublicKey(certPEM)
+	if err != nil {
+		return "", err
+	}
+	return seal.EncryptRaw(pubKey, namespace, secretName, seal.ScopeStrict, plainText)
 }
 
 // copyToClipboard copies text to the system clipboard
@@ -1092,3 +2937,11 @@ type revealDisabledError struct{}
 func (e *revealDisabledError) Error() string {
 	return "Reveal is disabled (ENVTOP_DISABLE_REVEAL=1)"
 }
+
+type revealNoAltScreenError struct {
+	term string
+}
+
+func (e *revealNoAltScreenError) Error() string {
+	return fmt.Sprintf("Reveal is disabled: terminal %q has no alternate screen (reveal.requireAltScreen)", e.term)
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// loadNamespacePage loads one page of the namespace list. The first page is
+// shown as soon as it arrives; the next ones are appended in the background so
+// that clusters with thousands of namespaces do not hold up startup.
+func (m Model) loadNamespacePage(continueToken string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		details, next, err := m.client.ListNamespaceDetailsPage(ctx, k8s.NamespacePageSize, continueToken)
+		if err != nil {
+			if continueToken == "" {
+				return errorMsg{err: err}
+			}
+			return namespacesLoadedMsg{token: continueToken, warning: "Namespace list incomplete: " + err.Error()}
+		}
+		msg := m.newNamespacesMsg(details)
+		msg.token = continueToken
+		msg.more = next
+		return msg
+	}
+}
+
+// loadAllNamespaces loads the whole namespace list at once, for refreshes
+func (m Model) loadAllNamespaces() tea.Cmd {
+	return func() tea.Msg {
+		details, err := m.client.ListNamespaceDetails(context.Background())
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return m.newNamespacesMsg(details)
+	}
+}
+
+// newNamespacesMsg builds the message of loaded namespaces with their tiers
+func (m Model) newNamespacesMsg(details []k8s.Namespace) namespacesLoadedMsg {
+	namespaces := make([]string, 0, len(details))
+	tiers := make(map[string]string)
+	createdAt := make(map[string]time.Time, len(details))
+	for _, ns := range details {
+		namespaces = append(namespaces, ns.Name)
+		createdAt[ns.Name] = ns.CreatedAt
+		if tier := m.cfg.TierOf(ns.Name, ns.Labels); tier != "" {
+			tiers[ns.Name] = tier
+		}
+	}
+	return namespacesLoadedMsg{namespaces: namespaces, tiers: tiers, createdAt: createdAt}
+}
+
+// appendNamespacePage adds a further page to the namespace list and requests
+// the next one. Pages of a listing superseded by a refresh or a context switch
+// are dropped.
+func (m Model) appendNamespacePage(msg namespacesLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.token != m.namespacesMore {
+		return m, nil
+	}
+	m.namespacesMore = msg.more
+
+	var cmds []tea.Cmd
+	if msg.warning != "" {
+		m.statusMessage = msg.warning
+		cmds = append(cmds, m.clearStatusAfter(5*time.Second))
+	}
+
+	known := make(map[string]bool, len(m.namespaces))
+	for _, ns := range m.namespaces {
+		known[ns] = true
+	}
+	if m.namespaceTiers == nil {
+		m.namespaceTiers = make(map[string]string)
+	}
+	if m.namespaceCreated == nil {
+		m.namespaceCreated = make(map[string]time.Time)
+	}
+	for _, ns := range msg.namespaces {
+		if known[ns] {
+			continue
+		}
+		m.namespaces = append(m.namespaces, ns)
+		m.namespaceCreated[ns] = msg.createdAt[ns]
+		if tier := msg.tiers[ns]; tier != "" {
+			m.namespaceTiers[ns] = tier
+		}
+	}
+
+	// The namespace requested at startup may be on this page
+	if m.initialNamespace != "" {
+		selected := m.namespaceIdx
+		cmds = append(cmds, m.selectInitialNamespace())
+		if m.namespaceIdx != selected {
+			cmds = append(cmds, m.loadApps())
+		}
+	}
+	if msg.more != "" {
+		cmds = append(cmds, m.loadNamespacePage(msg.more))
+	}
+	return m, tea.Batch(cmds...)
+}
@@ -0,0 +1,199 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteAction is an entry of the command palette: an action of the key map,
+// run by replaying its first key in normal mode
+type paletteAction struct {
+	binding key.Binding
+}
+
+// paletteActions returns the enabled actions of the key map in help order,
+// without the palette itself
+func (m Model) paletteActions() []paletteAction {
+	bindings := append(m.keys.FullHelp()[2], m.keys.Help)
+	if m.cfg != nil && !m.tutorial {
+		bindings = append(bindings, hookBindings(m.cfg.Hooks)...)
+	}
+	actions := make([]paletteAction, 0, len(bindings))
+	for _, b := range bindings {
+		if b.Enabled() && len(b.Keys()) > 0 && b.Help() != m.keys.Palette.Help() {
+			actions = append(actions, paletteAction{binding: b})
+		}
+	}
+	return actions
+}
+
+// filteredPaletteActions returns the actions matching the palette input, best
+// match first
+func (m Model) filteredPaletteActions() []paletteAction {
+	actions := m.paletteActions()
+	query := strings.TrimSpace(m.paletteInput.Value())
+	if query == "" {
+		return actions
+	}
+
+	type scored struct {
+		action paletteAction
+		score  int
+	}
+	var matches []scored
+	for _, a := range actions {
+		help := a.binding.Help()
+		score, ok := fuzzyScore(query, help.Desc)
+		if keyScore, keyOK := fuzzyScore(query, help.Key); keyOK && (!ok || keyScore > score) {
+			score, ok = keyScore, true
+		}
+		if ok {
+			matches = append(matches, scored{action: a, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	result := make([]paletteAction, len(matches))
+	for i, match := range matches {
+		result[i] = match.action
+	}
+	return result
+}
+
+// fuzzyScore matches the characters of query, in order, anywhere in text
+// (case-insensitive). Consecutive characters and characters starting a word
+// score higher; gaps cost a little.
+func fuzzyScore(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(text))
+	score, qi, last := 0, 0, -1
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		switch {
+		case last >= 0 && ti == last+1:
+			score += 5
+		case ti == 0 || !unicode.IsLetter(t[ti-1]):
+			score += 3
+		default:
+			score++
+		}
+		if last >= 0 {
+			score -= min(ti-last-1, 3)
+		}
+		last = ti
+		qi++
+	}
+	return score, qi == len(q)
+}
+
+// handlePaletteStart opens the command palette
+func (m Model) handlePaletteStart() (tea.Model, tea.Cmd) {
+	m.paletteInput.Reset()
+	m.paletteCursor = 0
+	m.viewMode = ViewModePalette
+	return m, m.paletteInput.Focus()
+}
+
+// handlePalette handles key press in the command palette
+func (m Model) handlePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	actions := m.filteredPaletteActions()
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.viewMode = ViewModeNormal
+		m.paletteInput.Blur()
+		return m, nil
+
+	case tea.KeyUp, tea.KeyCtrlP:
+		if m.paletteCursor > 0 {
+			m.paletteCursor--
+		}
+		return m, nil
+
+	case tea.KeyDown, tea.KeyCtrlN:
+		if m.paletteCursor < len(actions)-1 {
+			m.paletteCursor++
+		}
+		return m, nil
+
+	case tea.KeyEnter:
+		if len(actions) == 0 {
+			return m, nil
+		}
+		m.viewMode = ViewModeNormal
+		m.paletteInput.Blur()
+		return m.handleKeyPress(keyMsgFor(actions[m.paletteCursor].binding.Keys()[0]))
+	}
+
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	m.paletteCursor = 0
+	return m, cmd
+}
+
+// keyMsgFor builds the key press a key of a binding matches, e.g. "E" or "ctrl+r"
+func keyMsgFor(k string) tea.KeyMsg {
+	var msg tea.KeyMsg
+	if rest, ok := strings.CutPrefix(k, "alt+"); ok && rest != "" {
+		msg.Alt = true
+		k = rest
+	}
+	if runes := []rune(k); len(runes) == 1 {
+		msg.Type = tea.KeyRunes
+		msg.Runes = runes
+		return msg
+	}
+	// Named keys: look the name up among the key types bubbletea knows
+	for t := tea.KeyType(-128); t < 128; t++ {
+		if t != tea.KeyRunes && (tea.Key{Type: t}).String() == k {
+			msg.Type = t
+			return msg
+		}
+	}
+	msg.Type = tea.KeyRunes
+	msg.Runes = []rune(k)
+	return msg
+}
+
+// renderPalette renders the command palette
+func (m Model) renderPalette() string {
+	dialog := dialogStyle.Width(60)
+
+	content := []string{
+		dialogTitleStyle.Render("Commands"),
+		"",
+		m.paletteInput.View(),
+		"",
+	}
+
+	actions := m.filteredPaletteActions()
+	maxItems := 14
+	startIdx := 0
+	if m.paletteCursor >= maxItems {
+		startIdx = m.paletteCursor - maxItems + 1
+	}
+	for i := startIdx; i < len(actions) && i < startIdx+maxItems; i++ {
+		help := actions[i].binding.Help()
+		prefix := "  "
+		style := dialogTextStyle
+		if i == m.paletteCursor {
+			prefix = "> "
+			style = selectedItemStyle
+		}
+		content = append(content, style.Render(fmt.Sprintf("%s%-40s", prefix, truncate(help.Desc, 40)))+" "+helpKeyStyle.Render(help.Key))
+	}
+	if len(actions) == 0 {
+		content = append(content, mutedStyle.Render("  No matching commands"))
+	}
+
+	content = append(content, "", helpStyle.Render("Type: fuzzy search  ↑↓: select  Enter: run  Esc: cancel"))
+
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
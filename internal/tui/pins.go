@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/history"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// loadPinned reads the variables pinned in an app
+func (m *Model) loadPinned(app k8s.App) {
+	m.pinned = nil
+	if m.pins != nil {
+		m.pinned = m.pins.Pinned(history.ViewKey(m.context, app))
+	}
+}
+
+// splitPinned separates the pinned variables from the others, the pinned ones
+// in the order they were pinned
+func (m Model) splitPinned(indices []int) (pinned, rest []int) {
+	if len(m.pinned) == 0 {
+		return nil, indices
+	}
+	for _, name := range m.pinned {
+		for _, i := range indices {
+			if m.envVars[i].Name == name {
+				pinned = append(pinned, i)
+			}
+		}
+	}
+	for _, i := range indices {
+		if !slices.Contains(m.pinned, m.envVars[i].Name) {
+			rest = append(rest, i)
+		}
+	}
+	return pinned, rest
+}
+
+// handlePinToggle pins the selected variable to the top of the Env pane, or
+// unpins it. Pins are saved per app.
+func (m Model) handlePinToggle() (tea.Model, tea.Cmd) {
+	if m.activePane != PaneEnv || len(m.apps) == 0 {
+		return m, nil
+	}
+	ev, ok := m.selectedEnvVar()
+	if !ok {
+		return m, nil
+	}
+	if m.pins == nil {
+		m.statusMessage = "Pins are not saved in this session"
+		return m, m.clearStatusAfter(2 * time.Second)
+	}
+
+	pinned, err := m.pins.Toggle(history.ViewKey(m.context, m.apps[m.appIdx]), ev.Name)
+	m.loadPinned(m.apps[m.appIdx])
+	m.focusEnvVar(ev.Name)
+	switch {
+	case err != nil:
+		m.statusMessage = fmt.Sprintf("Failed to save pins: %v", err)
+	case pinned:
+		m.statusMessage = fmt.Sprintf("Pinned %s", ev.Name)
+	default:
+		m.statusMessage = fmt.Sprintf("Unpinned %s", ev.Name)
+	}
+	return m, m.clearStatusAfter(2 * time.Second)
+}
+
+// renderPinBadge marks a pinned variable
+func (m Model) renderPinBadge(ev k8s.EnvVar) string {
+	if !slices.Contains(m.pinned, ev.Name) {
+		return ""
+	}
+	return " " + helpKeyStyle.Render("★pinned")
+}
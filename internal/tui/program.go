@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// NewProgram creates the envtop program reading keys from in and drawing to out,
+// so the TUI can be embedded in other tools or driven by teatest. opts are applied
+// after the defaults (input, output and the alternate screen) and can override them.
+func NewProgram(model Model, in io.Reader, out io.Writer, opts ...tea.ProgramOption) *tea.Program {
+	// Reveal restrictions depend on the terminal actually drawn to
+	model.altScreen = altScreenCapable(out)
+	if model.ascii || asciiOnly() {
+		model.ascii = true
+		useASCIIStyles()
+	}
+
+	options := []tea.ProgramOption{
+		tea.WithInput(in),
+		tea.WithOutput(out),
+		tea.WithAltScreen(),
+	}
+	return tea.NewProgram(model, append(options, opts...)...)
+}
+
+// Run runs the program until it quits. The terminal title of out is saved
+// before and restored after, as the program changes it while navigating.
+func Run(model Model, in io.Reader, out io.Writer, opts ...tea.ProgramOption) error {
+	p := NewProgram(model, in, out, opts...)
+	SaveTerminalTitle(out)
+	_, err := p.Run()
+	RestoreTerminalTitle(out)
+	return err
+}
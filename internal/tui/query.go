@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ginbear/k8s-envtop/internal/fleet"
+)
+
+// queryMsg carries the result of a fleet-wide variable query
+type queryMsg struct {
+	pattern string
+	result  *fleet.Result
+}
+
+// fleetTargets returns the clusters a query fans out to: every context of a
+// fleet session, or the current context
+func (m Model) fleetTargets() []fleet.Target {
+	if m.fleet == nil {
+		return []fleet.Target{{Client: m.client, Resolver: m.resolver}}
+	}
+	targets := make([]fleet.Target, 0, len(m.fleet))
+	for _, client := range m.fleet {
+		targets = append(targets, fleet.Target{Client: client, Resolver: m.resolvers[client.GetCurrentContext()]})
+	}
+	return targets
+}
+
+// loadQuery looks up variables matching pattern in every app of every namespace
+func (m Model) loadQuery(pattern string) tea.Cmd {
+	targets := m.fleetTargets()
+	return func() tea.Msg {
+		result, err := fleet.Query(context.Background(), targets, "", pattern, fleet.DefaultConcurrency)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return queryMsg{pattern: pattern, result: result}
+	}
+}
+
+// handleQueryStart opens the variable name prompt of the fleet query
+func (m Model) handleQueryStart() (tea.Model, tea.Cmd) {
+	m.queryInput.Reset()
+	m.queryInput.Focus()
+	m.viewMode = ViewModeQueryInput
+	return m, nil
+}
+
+// handleQueryInput handles key press in the fleet query prompt
+func (m Model) handleQueryInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.viewMode = ViewModeNormal
+		return m, nil
+
+	case tea.KeyEnter:
+		pattern := strings.TrimSpace(m.queryInput.Value())
+		if pattern == "" {
+			return m, nil
+		}
+		m.viewMode = ViewModeNormal
+		m.loading = true
+		return m, m.loadQuery(pattern)
+	}
+
+	var cmd tea.Cmd
+	m.queryInput, cmd = m.queryInput.Update(msg)
+	return m, cmd
+}
+
+// handleQueryResult handles key press in the fleet query result
+func (m Model) handleQueryResult(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.queryCursor > 0 {
+			m.queryCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.queryResult != nil && m.queryCursor < len(m.queryResult.Matches)-1 {
+			m.queryCursor++
+		}
+	}
+	return m, nil
+}
+
+// renderQueryInput renders the fleet query prompt
+func (m Model) renderQueryInput() string {
+	dialog := dialogStyle.Width(60)
+
+	scope := "the current context"
+	if m.fleet != nil {
+		scope = fmt.Sprintf("%d contexts", len(m.fleet))
+	}
+	content := []string{
+		dialogTitleStyle.Render("Fleet Query"),
+		"",
+		dialogTextStyle.Render("Variable name or glob pattern, looked up in every app of " + scope + ":"),
+		m.queryInput.View(),
+		"",
+		helpStyle.Render("Enter: query  Esc: cancel"),
+	}
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
+
+// renderQueryResult renders the matches of a fleet query, one row per context/namespace/app
+func (m Model) renderQueryResult() string {
+	result := m.queryResult
+	title := titleStyle.Render(fmt.Sprintf("Fleet Query: %s (%d matches)", m.queryPattern, len(result.Matches)))
+	content := []string{title, ""}
+
+	if len(result.Matches) == 0 {
+		content = append(content, mutedStyle.Render("  No variable matches"))
+	} else {
+		const locWidth = 48
+		const nameWidth = 28
+		header := fmt.Sprintf("  %-*s %-*s %s", locWidth, "CONTEXT/NAMESPACE/APP", nameWidth, "NAME", "VALUE")
+		content = append(content, helpStyle.Render(header))
+
+		maxItems := m.height - 8 - len(result.Failures)
+		if maxItems < 1 {
+			maxItems = 1
+		}
+		startIdx := 0
+		if m.queryCursor >= maxItems {
+			startIdx = m.queryCursor - maxItems + 1
+		}
+
+		for i := startIdx; i < len(result.Matches) && i < startIdx+maxItems; i++ {
+			match := result.Matches[i]
+			prefix := "  "
+			style := itemStyle
+			if i == m.queryCursor {
+				prefix = "> "
+				style = selectedItemStyle
+			}
+
+			loc := match.Namespace + "/" + match.App.Name
+			if match.Context != "" {
+				loc = match.Context + "/" + loc
+			}
+			value := m.diffValue(&match.Var)
+			maxLen := m.width - locWidth - nameWidth - 6
+			if maxLen < 10 {
+				maxLen = 10
+			}
+			row := fmt.Sprintf("%s%-*s %-*s ", prefix, locWidth, truncate(loc, locWidth), nameWidth, truncate(match.Var.Name, nameWidth))
+			content = append(content, style.Render(row)+truncate(value, maxLen))
+		}
+	}
+
+	if len(result.Failures) > 0 {
+		content = append(content, "")
+		for _, f := range result.Failures {
+			content = append(content, warningStyle.Render(truncate("  failed: "+f.Error(), m.width-2)))
+		}
+	}
+
+	content = append(content, "", helpStyle.Render("↑↓: scroll  Esc: back to main view"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ginbear/k8s-envtop/internal/fleet"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// varAcrossMsg carries the value of one variable in every namespace running the app
+type varAcrossMsg struct {
+	ref    k8s.EnvVar // the variable as shown in the selected namespace
+	result *fleet.Result
+}
+
+// handleVarAcrossStart looks up the selected variable of the selected app in every
+// namespace (of every context in a fleet session) where the app exists
+func (m Model) handleVarAcrossStart() (tea.Model, tea.Cmd) {
+	if m.activePane != PaneEnv || len(m.apps) == 0 {
+		return m, nil
+	}
+	envVar, ok := m.selectedEnvVar()
+	if !ok {
+		return m, nil
+	}
+	app := m.apps[m.appIdx]
+	targets := m.fleetTargets()
+
+	m.loading = true
+	return m, func() tea.Msg {
+		result := fleet.QueryVar(context.Background(), targets, app, envVar.Name, fleet.DefaultConcurrency)
+		return varAcrossMsg{ref: envVar, result: result}
+	}
+}
+
+// handleVarAcross handles key press in the variable-by-namespace table
+func (m Model) handleVarAcross(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.varAcrossCursor > 0 {
+			m.varAcrossCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.varAcrossResult != nil && m.varAcrossCursor < len(m.varAcrossResult.Matches)-1 {
+			m.varAcrossCursor++
+		}
+	}
+	return m, nil
+}
+
+// isSelectedNamespace returns true if a match is the namespace the lookup started from
+func (m Model) isSelectedNamespace(match fleet.Match) bool {
+	if len(m.namespaces) == 0 || match.Namespace != m.namespaces[m.namespaceIdx] {
+		return false
+	}
+	return m.fleet == nil || match.Context == m.context
+}
+
+// renderVarAcross renders one variable of an app by namespace, compared with the
+// value in the selected namespace
+func (m Model) renderVarAcross() string {
+	result := m.varAcrossResult
+	ref := m.varAcrossRef
+	title := titleStyle.Render(fmt.Sprintf("%s across namespaces (%d)", ref.Name, len(result.Matches)))
+	content := []string{title, ""}
+
+	const locWidth = 40
+	const statusWidth = 10
+	header := fmt.Sprintf("  %-*s %-*s %s", locWidth, "NAMESPACE", statusWidth, "STATUS", "VALUE")
+	content = append(content, helpStyle.Render(header))
+
+	maxItems := m.height - 8 - len(result.Failures)
+	if maxItems < 1 {
+		maxItems = 1
+	}
+	startIdx := 0
+	if m.varAcrossCursor >= maxItems {
+		startIdx = m.varAcrossCursor - maxItems + 1
+	}
+
+	for i := startIdx; i < len(result.Matches) && i < startIdx+maxItems; i++ {
+		match := result.Matches[i]
+		prefix := "  "
+		style := itemStyle
+		if i == m.varAcrossCursor {
+			prefix = "> "
+			style = selectedItemStyle
+		}
+
+		loc := match.Namespace
+		if match.Context != "" && m.fleet != nil {
+			loc = match.Context + "/" + loc
+		}
+
+		var status string
+		var statusStyle lipgloss.Style
+		value := m.diffValue(&match.Var)
+		switch {
+		case m.isSelectedNamespace(match):
+			status, statusStyle = "current", mutedStyle
+		case match.Missing:
+			status, statusStyle = "missing", diffRemovedStyle
+			value = "(not set)"
+		case m.diffValue(&match.Var) == m.diffValue(&ref):
+			status, statusStyle = "same", diffSameStyle
+		default:
+			status, statusStyle = "differs", diffChangedStyle
+		}
+
+		maxLen := m.width - locWidth - statusWidth - 6
+		if maxLen < 10 {
+			maxLen = 10
+		}
+		row := style.Render(fmt.Sprintf("%s%-*s ", prefix, locWidth, truncate(loc, locWidth)))
+		content = append(content, row+statusStyle.Render(fmt.Sprintf("%-*s", statusWidth, status))+" "+truncate(value, maxLen))
+	}
+
+	if len(result.Failures) > 0 {
+		content = append(content, "")
+		for _, f := range result.Failures {
+			content = append(content, warningStyle.Render(truncate("  failed: "+f.Error(), m.width-2)))
+		}
+	}
+
+	content = append(content, "", helpStyle.Render("↑↓: scroll  Esc: back to main view"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
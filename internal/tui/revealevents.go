@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// revealRecordedMsg reports the Event recorded for a confirmed reveal
+type revealRecordedMsg struct {
+	err error
+}
+
+// recordConfirmedReveal returns the command recording the reveal (or copy)
+// confirmed with OK, or nil when it is not recorded
+func (m Model) recordConfirmedReveal() tea.Cmd {
+	switch {
+	case m.copyField != copyNone:
+		if ev, ok := m.selectedEnvVar(); ok {
+			return m.recordReveal(ev, "copied")
+		}
+	case m.revealMode == RevealModeSecret:
+		return m.recordSecretReveal(m.revealedSecretName, "every key", "revealed")
+	default:
+		for _, ev := range m.envVars {
+			if ev.Name == m.revealedEnvName {
+				action := "revealed"
+				if m.revealMode == RevealModeMasked {
+					action = "revealed masked"
+				}
+				return m.recordReveal(ev, action)
+			}
+		}
+	}
+	return nil
+}
+
+// recordReveal records the reveal (or copy) of a Secret value as an Event on
+// the Secret, when reveal.recordEvents (or $ENVTOP_RECORD_REVEALS=1) is set.
+// Values masked by a redaction rule come from ConfigMaps and are not recorded.
+func (m Model) recordReveal(ev k8s.EnvVar, action string) tea.Cmd {
+	if !ev.IsSecret() {
+		return nil
+	}
+	return m.recordSecretReveal(ev.SourceName, ev.Name, action)
+}
+
+// recordSecretReveal records the reveal of what (a variable, or "every key")
+// of a Secret of the namespace captured when the reveal started, when
+// recording is enabled
+func (m Model) recordSecretReveal(secretName, what, action string) tea.Cmd {
+	enabled := os.Getenv("ENVTOP_RECORD_REVEALS") == "1" || (m.cfg != nil && m.cfg.Reveal.RecordEvents)
+	if !enabled || secretName == "" || m.revealNamespace == "" {
+		return nil
+	}
+	client := m.client
+	namespace := m.revealNamespace
+	return func() tea.Msg {
+		return revealRecordedMsg{err: client.RecordReveal(context.Background(), namespace, secretName, what, action)}
+	}
+}
+
+// handleRevealRecorded goes on with a confirmed reveal once its Event is
+// recorded, and refuses it when the Event could not be created
+func (m Model) handleRevealRecorded(msg revealRecordedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if m.viewMode != ViewModeRevealConfirm {
+		return m, nil
+	}
+	if msg.err != nil {
+		model, cmd := m.closeReveal()
+		m = model.(Model)
+		m.statusMessage = fmt.Sprintf("Reveal refused, it could not be recorded: %v", msg.err)
+		return m, tea.Batch(cmd, m.clearStatusAfter(5*time.Second))
+	}
+	return m.confirmReveal()
+}
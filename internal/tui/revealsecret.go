@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// revealedKey is a key of the Secret revealed as a whole
+type revealedKey struct {
+	key   string
+	value string
+}
+
+// revealSecretMsg carries the keys of the Secret revealed as a whole
+type revealSecretMsg struct {
+	name string
+	keys []revealedKey
+	err  error
+}
+
+// revealSecretRows is the number of keys the revealed Secret table shows at once
+const revealSecretRows = 15
+
+// loadRevealSecret reads a Secret for revealing every key of it. The Secret is
+// read from the API server, not from the cache.
+func (m Model) loadRevealSecret(namespace, name string) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		secret, err := client.GetSecret(context.Background(), namespace, name)
+		if err != nil {
+			return revealSecretMsg{name: name, err: k8s.Classify(err)}
+		}
+		keys := make([]revealedKey, 0, len(secret.Data))
+		for k, v := range secret.Data {
+			keys = append(keys, revealedKey{key: k, value: revealedSecretValue(v)})
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].key < keys[j].key })
+		return revealSecretMsg{name: name, keys: keys}
+	}
+}
+
+// revealedSecretValue renders a value of the revealed Secret on one line:
+// line breaks are escaped, and binary values (keystores, certificates in DER)
+// are shown in base64
+func revealedSecretValue(v []byte) string {
+	s := string(v)
+	if !utf8.ValidString(s) || strings.ContainsFunc(s, func(r rune) bool {
+		return unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t'
+	}) {
+		return "base64:" + k8s.EncodeBase64(v)
+	}
+	return strings.NewReplacer("\r", `\r`, "\n", `\n`, "\t", `\t`).Replace(s)
+}
+
+// handleRevealSecret shows the Secret revealed as a whole, until a key other
+// than scrolling is pressed or the reveal times out
+func (m Model) handleRevealSecret(msg revealSecretMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if m.viewMode != ViewModeRevealConfirm {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.viewMode = ViewModeNormal
+		m.err = fmt.Errorf("failed to read Secret %s: %w", msg.name, msg.err)
+		return m, nil
+	}
+
+	m.revealedSecret = msg.keys
+	m.revealedSecretName = msg.name
+	m.revealScroll = 0
+	m.viewMode = ViewModeRevealShow
+	m.revealExpiry = time.Now().Add(30 * time.Second)
+	return m, tea.Tick(30*time.Second, func(t time.Time) tea.Msg {
+		return revealTimeoutMsg{}
+	})
+}
+
+// scrollRevealedSecret scrolls the revealed Secret table, and returns false for
+// keys that do not scroll it
+func (m *Model) scrollRevealedSecret(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.revealScroll > 0 {
+			m.revealScroll--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.revealScroll < len(m.revealedSecret)-revealSecretRows {
+			m.revealScroll++
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// renderRevealedSecret renders every key and value of the revealed Secret
+func (m Model) renderRevealedSecret() string {
+	width := min(max(m.width-10, 40), 120)
+	dialog := dialogStyle.Width(width)
+
+	title := dialogTitleStyle.Render(fmt.Sprintf("Secret: %s (%d key(s), Plain Text)", m.revealedSecretName, len(m.revealedSecret)))
+	content := []string{title, ""}
+	if len(m.revealedSecret) == 0 {
+		content = append(content, mutedStyle.Render("The Secret has no keys"))
+	}
+
+	keyWidth := 0
+	for _, k := range m.revealedSecret {
+		keyWidth = max(keyWidth, utf8.RuneCountInString(k.key))
+	}
+	keyWidth = min(keyWidth, width/3)
+	end := min(m.revealScroll+revealSecretRows, len(m.revealedSecret))
+	for _, k := range m.revealedSecret[m.revealScroll:end] {
+		key := fmt.Sprintf("%-*s", keyWidth, truncate(k.key, keyWidth))
+		value := truncate(k.value, width-keyWidth-6)
+		content = append(content, envNameStyle.Render(key)+"  "+envValueStyle.Render(value))
+	}
+
+	help := "Press any key to close (auto-closes in 30s)"
+	if len(m.revealedSecret) > revealSecretRows {
+		content = append(content, mutedStyle.Render(fmt.Sprintf("%d-%d of %d", m.revealScroll+1, end, len(m.revealedSecret))))
+		help = "↑↓: scroll  other keys: close (auto-closes in 30s)"
+	}
+	content = append(content, "", warningStyle.Render(help))
+
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// revisionsMsg carries the rollout history of the selected app
+type revisionsMsg struct {
+	revisions []k8s.Revision
+}
+
+// revisionDiffMsg carries the env diff of a past rollout against the current one
+type revisionDiffMsg struct {
+	results []env.DiffResult
+	older   k8s.Revision
+}
+
+// handleRevisionsStart loads the rollout history of the selected Deployment or StatefulSet
+func (m Model) handleRevisionsStart() (tea.Model, tea.Cmd) {
+	if len(m.apps) == 0 || m.appIdx >= len(m.apps) {
+		return m, nil
+	}
+	app := m.apps[m.appIdx]
+	if app.Kind != k8s.AppKindDeployment && app.Kind != k8s.AppKindStatefulSet {
+		m.statusMessage = fmt.Sprintf("%s has no rollout history", app.Kind)
+		return m, m.clearStatusAfter(3 * time.Second)
+	}
+
+	m.loading = true
+	return m, func() tea.Msg {
+		revisions, err := m.client.ListRevisions(context.Background(), app)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return revisionsMsg{revisions: revisions}
+	}
+}
+
+// handleRevisionSelect handles key press in the revision list. The first
+// revision is the current one; Enter compares the selected one against it.
+func (m Model) handleRevisionSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.revisionCursor > 1 {
+			m.revisionCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.revisionCursor < len(m.revisions)-1 {
+			m.revisionCursor++
+		}
+	case key.Matches(msg, m.keys.Enter):
+		if m.revisionCursor < 1 || m.revisionCursor >= len(m.revisions) {
+			return m, nil
+		}
+		namespace := m.apps[m.appIdx].Namespace
+		older, current := m.revisions[m.revisionCursor], m.revisions[0]
+		m.loading = true
+		return m, func() tea.Msg {
+			results, err := m.resolver.CompareRevisions(context.Background(), namespace, older, current)
+			if err != nil {
+				return errorMsg{err: err}
+			}
+			return revisionDiffMsg{results: results, older: older}
+		}
+	}
+	return m, nil
+}
+
+// handleRevisionDiff handles key press in the revision diff view
+func (m Model) handleRevisionDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.revisionDiffCursor > 0 {
+			m.revisionDiffCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.revisionDiffCursor < len(m.revisionDiff)-1 {
+			m.revisionDiffCursor++
+		}
+	case key.Matches(msg, m.keys.Enter):
+		if m.revisionDiffCursor < len(m.revisionDiff) && hasValueDiff(m.revisionDiff[m.revisionDiffCursor]) {
+			m.openValueDiff(m.revisionDiff[m.revisionDiffCursor],
+				fmt.Sprintf("revision %d", m.revisionOlder.Number), fmt.Sprintf("revision %d", m.revisions[0].Number))
+		}
+	}
+	return m, nil
+}
+
+// renderRevisionSelect renders the rollout history of the selected app
+func (m Model) renderRevisionSelect() string {
+	app := m.apps[m.appIdx]
+	title := titleStyle.Render(fmt.Sprintf("Rollout history: %s %s", app.Kind, app.Name))
+	content := []string{title, ""}
+
+	if len(m.revisions) < 2 {
+		content = append(content, mutedStyle.Render("  No previous revision to compare with"))
+	} else {
+		header := fmt.Sprintf("  %-10s %-45s %s", "REVISION", "NAME", "AGE")
+		content = append(content, helpStyle.Render(header))
+	}
+
+	maxItems := m.height - 6
+	if maxItems < 1 {
+		maxItems = 1
+	}
+	startIdx := 0
+	if m.revisionCursor >= maxItems {
+		startIdx = m.revisionCursor - maxItems + 1
+	}
+	for i := startIdx; i < len(m.revisions) && i < startIdx+maxItems && len(m.revisions) >= 2; i++ {
+		rev := m.revisions[i]
+		prefix := "  "
+		style := itemStyle
+		if i == m.revisionCursor {
+			prefix = "> "
+			style = selectedItemStyle
+		}
+		row := fmt.Sprintf("%s%-10d %-45s %s", prefix, rev.Number, truncate(rev.Name, 45), formatAge(time.Since(rev.CreatedAt)))
+		if i == 0 {
+			row += "  (current)"
+			style = mutedStyle
+		}
+		content = append(content, style.Render(row))
+	}
+
+	content = append(content, "", helpStyle.Render("↑↓: select  Enter: diff against current  Esc: back to main view"))
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// renderRevisionDiff renders the env diff of a past rollout against the current one
+func (m Model) renderRevisionDiff() string {
+	app := m.apps[m.appIdx]
+	current := m.revisions[0]
+	title := titleStyle.Render(fmt.Sprintf("Diff: %s revision %d vs %d", app.Name, m.revisionOlder.Number, current.Number))
+
+	header := fmt.Sprintf("%-20s %-20s %-20s %s", "NAME", fmt.Sprintf("revision %d", m.revisionOlder.Number), fmt.Sprintf("revision %d", current.Number), "STATUS")
+	content := []string{title, "", helpStyle.Render(header), ""}
+
+	changed := 0
+	for _, result := range m.revisionDiff {
+		if result.Status.IsDrift() {
+			changed++
+		}
+	}
+	if changed == 0 {
+		content = append(content, mutedStyle.Render("  The env is the same in both revisions"))
+	}
+
+	maxItems := m.height - 11
+	if maxItems < 1 {
+		maxItems = 1
+	}
+	startIdx := 0
+	if m.revisionDiffCursor >= maxItems {
+		startIdx = m.revisionDiffCursor - maxItems + 1
+	}
+	for i := startIdx; i < len(m.revisionDiff) && i < startIdx+maxItems; i++ {
+		content = append(content, m.renderDiffRow(m.revisionDiff[i], i == m.revisionDiffCursor))
+	}
+
+	content = append(content, "",
+		mutedStyle.Render("ConfigMaps and Secrets are read as they are now: only changes of the pod template show up"),
+		helpStyle.Render("↑↓: scroll  Enter: value diff  Esc: back to revisions"),
+	)
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WithSelection pre-selects a namespace, and optionally one of its apps, once
+// they are loaded, e.g. from the --namespace and --app flags
+func (m Model) WithSelection(namespace, app string) Model {
+	m.initialNamespace = namespace
+	m.initialApp = app
+	return m
+}
+
+// selectInitialNamespace selects the namespace requested at startup in the
+// freshly loaded list. The apps pane gets the focus. While further pages of the
+// list are loading, a namespace not found yet is looked up again on each page.
+func (m *Model) selectInitialNamespace() tea.Cmd {
+	namespace := m.initialNamespace
+	if namespace == "" {
+		return nil
+	}
+	m.initialNamespace = ""
+	for i, ns := range m.namespaces {
+		if ns == namespace {
+			m.namespaceIdx = i
+			m.namespaceCursor = i
+			m.activePane = PaneApps
+			return nil
+		}
+	}
+	if m.namespacesMore != "" {
+		m.initialNamespace = namespace
+		return nil
+	}
+	m.initialApp = ""
+	m.statusMessage = fmt.Sprintf("Namespace %s not found", namespace)
+	return m.clearStatusAfter(5 * time.Second)
+}
+
+// selectInitialApp selects the app requested at startup in the freshly loaded
+// apps of the namespace. The env pane gets the focus.
+func (m *Model) selectInitialApp() tea.Cmd {
+	app := m.initialApp
+	if app == "" || m.initialNamespace != "" { // its namespace is still to be found
+		return nil
+	}
+	m.initialApp = ""
+	for pos, i := range m.GetFilteredApps() {
+		if m.apps[i].Name == app {
+			m.appIdx = i
+			m.appCursor = pos
+			m.activePane = PaneEnv
+			return nil
+		}
+	}
+	m.statusMessage = fmt.Sprintf("App %s not found in %s", app, m.namespaces[m.namespaceIdx])
+	return m.clearStatusAfter(5 * time.Second)
+}
@@ -69,6 +69,11 @@ var (
 	envHashStyle = lipgloss.NewStyle().
 			Foreground(mutedColor)
 
+	// A variable whose source the user may not read
+	envForbiddenStyle = lipgloss.NewStyle().
+				Foreground(errorColor).
+				Italic(true)
+
 	// Diff styles
 	diffSameStyle = lipgloss.NewStyle().
 			Foreground(mutedColor)
@@ -82,6 +87,24 @@ var (
 	diffRemovedStyle = lipgloss.NewStyle().
 				Foreground(errorColor)
 
+	// Characters that differ within a changed value
+	diffRemovedCharStyle = lipgloss.NewStyle().
+				Foreground(errorColor).
+				Reverse(true)
+
+	diffAddedCharStyle = lipgloss.NewStyle().
+				Foreground(successColor).
+				Reverse(true)
+
+	// Feature flag styles
+	flagOnStyle = lipgloss.NewStyle().
+			Foreground(successColor).
+			Bold(true)
+
+	flagOffStyle = lipgloss.NewStyle().
+			Foreground(errorColor).
+			Bold(true)
+
 	// Dialog styles
 	dialogStyle = lipgloss.NewStyle().
 			Border(lipgloss.DoubleBorder()).
@@ -144,3 +167,23 @@ func GetSourceKindStyle(kind string) lipgloss.Style {
 		return itemStyle
 	}
 }
+
+// asciiBorder draws boxes with plain ASCII, for terminals that render the
+// box-drawing characters of the rounded and double borders incorrectly
+var asciiBorder = lipgloss.Border{
+	Top:         "-",
+	Bottom:      "-",
+	Left:        "|",
+	Right:       "|",
+	TopLeft:     "+",
+	TopRight:    "+",
+	BottomLeft:  "+",
+	BottomRight: "+",
+}
+
+// useASCIIStyles switches the bordered styles to the ASCII border
+func useASCIIStyles() {
+	paneStyle = paneStyle.Border(asciiBorder)
+	activePaneStyle = activePaneStyle.Border(asciiBorder)
+	dialogStyle = dialogStyle.Border(asciiBorder)
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// noAltScreenTerms are terminal types without an alternate screen (no smcup/rmcup),
+// where everything drawn, including revealed values, stays in the scrollback
+var noAltScreenTerms = map[string]bool{
+	"":       true,
+	"dumb":   true,
+	"linux":  true,
+	"vt52":   true,
+	"vt100":  true,
+	"cons25": true,
+}
+
+// altScreenCapable returns true if out is a terminal whose type supports the alternate screen
+func altScreenCapable(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return false
+	}
+	return !noAltScreenTerms[os.Getenv("TERM")]
+}
+
+// requireAltScreen returns true if reveal is only allowed on alternate screen terminals
+func (m Model) requireAltScreen() bool {
+	return os.Getenv("ENVTOP_REQUIRE_ALT_SCREEN") == "1" || (m.cfg != nil && m.cfg.Reveal.RequireAltScreen)
+}
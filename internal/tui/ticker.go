@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// maxTickerEvents is the number of source events the ticker shows
+const maxTickerEvents = 3
+
+// tickerRefresh is how often the ages of the ticker are redrawn
+const tickerRefresh = 5 * time.Second
+
+// Ticker messages carry the stream they came from, so events of a replaced watch are dropped
+type (
+	tickerEventMsg struct {
+		events <-chan k8s.SourceEvent
+		event  k8s.SourceEvent
+	}
+	tickerRefreshMsg struct {
+		events <-chan k8s.SourceEvent
+	}
+)
+
+// sourceRefs returns the ConfigMaps and Secrets the env of the selected app
+// reads, in a stable order
+func (m Model) sourceRefs() []k8s.SourceRef {
+	seen := make(map[k8s.SourceRef]bool)
+	var refs []k8s.SourceRef
+	for _, ev := range m.allContainerEnv {
+		ref := k8s.SourceRef{Name: ev.SourceName}
+		switch {
+		case ev.SourceKind == k8s.EnvSourceConfigMap:
+			ref.Kind = k8s.WatchKindConfigMap
+		case ev.IsSecret():
+			ref.Kind = k8s.WatchKindSecret
+		default:
+			continue
+		}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	slices.SortFunc(refs, func(a, b k8s.SourceRef) int {
+		return strings.Compare(a.Kind+"/"+a.Name, b.Kind+"/"+b.Name)
+	})
+	return refs
+}
+
+// startTicker watches the env sources of an app for the ticker, replacing the
+// previous watch. The watch is kept when the app still reads the same sources,
+// e.g. after a live refresh.
+func (m *Model) startTicker(app k8s.App) tea.Cmd {
+	refs := m.sourceRefs()
+	key := m.context + "/" + app.Namespace + "/" + k8s.AppKey(app) + "/" + fmt.Sprint(refs)
+	if key == m.tickerKey {
+		return nil
+	}
+	m.stopTicker()
+	m.tickerKey = key
+	if len(refs) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.tickerCancel = cancel
+	m.tickerEvents = m.client.WatchSources(ctx, app.Namespace, refs)
+	return waitForTicker(m.tickerEvents)
+}
+
+// stopTicker stops the watch of the ticker and clears it
+func (m *Model) stopTicker() {
+	if m.tickerCancel != nil {
+		m.tickerCancel()
+		m.tickerCancel = nil
+	}
+	m.tickerEvents = nil
+	m.tickerKey = ""
+	m.ticker = nil
+}
+
+// waitForTicker returns a command delivering the next event of a ticker watch
+func waitForTicker(events <-chan k8s.SourceEvent) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-events
+		if !ok {
+			return nil
+		}
+		return tickerEventMsg{events: events, event: event}
+	}
+}
+
+// handleTickerEvent adds an event to the ticker, newest first
+func (m Model) handleTickerEvent(msg tickerEventMsg) (tea.Model, tea.Cmd) {
+	if msg.events != m.tickerEvents {
+		return m, nil
+	}
+	cmds := []tea.Cmd{waitForTicker(m.tickerEvents)}
+	if len(m.ticker) == 0 {
+		cmds = append(cmds, refreshTicker(m.tickerEvents))
+	}
+	m.ticker = append([]k8s.SourceEvent{msg.event}, m.ticker...)
+	if len(m.ticker) > maxTickerEvents {
+		m.ticker = m.ticker[:maxTickerEvents]
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// refreshTicker schedules the next redraw of the ages of the ticker
+func refreshTicker(events <-chan k8s.SourceEvent) tea.Cmd {
+	return tea.Tick(tickerRefresh, func(time.Time) tea.Msg {
+		return tickerRefreshMsg{events: events}
+	})
+}
+
+// handleTickerRefresh redraws the ticker while it shows events of the current watch
+func (m Model) handleTickerRefresh(msg tickerRefreshMsg) (tea.Model, tea.Cmd) {
+	if msg.events != m.tickerEvents || len(m.ticker) == 0 {
+		return m, nil
+	}
+	return m, refreshTicker(m.tickerEvents)
+}
+
+// renderTicker renders the latest changes to the env sources of the selected
+// app, e.g. "cm/app-config updated 12s ago by helm"
+func (m Model) renderTicker(width int) string {
+	if len(m.ticker) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(m.ticker))
+	for _, ev := range m.ticker {
+		prefix := "cm/"
+		if ev.Kind == k8s.WatchKindSecret {
+			prefix = "sec/"
+		}
+		text := fmt.Sprintf("%s%s %s %s ago", prefix, ev.Name, ev.Type, tickerAge(time.Since(ev.Time)))
+		if ev.Manager != "" {
+			text += " by " + ev.Manager
+		}
+		parts = append(parts, text)
+	}
+	return mutedStyle.Render(truncate("⟳ "+strings.Join(parts, " · "), width))
+}
+
+// tickerAge formats the age of a ticker event, in seconds under a minute
+func tickerAge(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", max(0, int(d.Seconds())))
+	}
+	return formatAge(d)
+}
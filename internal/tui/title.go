@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// XTerm title stack escape sequences
+const (
+	saveTitleSeq    = "\x1b[22;0t"
+	restoreTitleSeq = "\x1b[23;0t"
+)
+
+// SaveTerminalTitle pushes the current terminal title onto the title stack
+func SaveTerminalTitle(w io.Writer) {
+	fmt.Fprint(w, saveTitleSeq)
+}
+
+// RestoreTerminalTitle pops the terminal title saved by SaveTerminalTitle
+// and hands the tmux window name back to automatic renaming
+func RestoreTerminalTitle(w io.Writer) {
+	fmt.Fprint(w, restoreTitleSeq)
+	if inTmux() {
+		_ = exec.Command("tmux", "set-window-option", "-t", os.Getenv("TMUX_PANE"), "automatic-rename", "on").Run()
+	}
+}
+
+// windowTitle returns the title for the current navigation state
+func (m Model) windowTitle() string {
+	title := "envtop: " + m.context
+	if len(m.namespaces) == 0 {
+		return title
+	}
+	title += "/" + m.namespaces[m.namespaceIdx]
+	if len(m.apps) > 0 && m.appIdx < len(m.apps) {
+		title += "/" + m.apps[m.appIdx].Name
+	}
+	return title
+}
+
+// updateTitle returns a command that sets the terminal and tmux window title
+func (m Model) updateTitle() tea.Cmd {
+	title := m.windowTitle()
+	if !inTmux() {
+		return tea.SetWindowTitle(title)
+	}
+	return tea.Batch(
+		tea.SetWindowTitle(title),
+		func() tea.Msg {
+			_ = exec.Command("tmux", "rename-window", "-t", os.Getenv("TMUX_PANE"), title).Run()
+			return nil
+		},
+	)
+}
+
+// inTmux returns true when running inside a tmux session
+func inTmux() bool {
+	return os.Getenv("TMUX") != ""
+}
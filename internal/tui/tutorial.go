@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tutorialStep is one guided prompt of the tutorial. The tutorial moves on to
+// the next step as soon as done reports the user did what was asked.
+type tutorialStep struct {
+	title  string
+	prompt string
+	done   func(m Model) bool // nil for the last step
+}
+
+// tutorialSteps walks through navigation, search, reveal and diff on the demo cluster
+var tutorialSteps = []tutorialStep{
+	{
+		title:  "Navigate",
+		prompt: "Select shop-staging with ↑↓ (or j/k) and press → (or l, Tab) to move to the Apps pane.",
+		done:   func(m Model) bool { return m.activePane == PaneApps },
+	},
+	{
+		title:  "Open an app",
+		prompt: "Select the api Deployment and press → to list its environment variables.",
+		done:   func(m Model) bool { return m.activePane == PaneEnv && len(m.envVars) > 0 },
+	},
+	{
+		title:  "Search",
+		prompt: "Press / and type DATABASE to filter the variables. Enter keeps the selection, Esc cancels.",
+		done: func(m Model) bool {
+			return m.viewMode == ViewModeSearch && m.searchPane == PaneEnv && m.searchInput.Value() != ""
+		},
+	},
+	{
+		title:  "Reveal a Secret",
+		prompt: "Secret values are masked. Select DATABASE_PASSWORD, press r, pick a format and type OK to reveal it for 30 seconds.",
+		done:   func(m Model) bool { return m.viewMode == ViewModeRevealShow },
+	},
+	{
+		title:  "Diff namespaces",
+		prompt: "Press any key to hide the value, then press d and pick shop-prod to compare the env of api across namespaces.",
+		done:   func(m Model) bool { return m.viewMode == ViewModeDiffShow },
+	},
+	{
+		title:  "Done",
+		prompt: "That's the tour! Press ? for every key binding, Esc to go back and q to quit. Run envtop without --tutorial to use your own cluster.",
+	},
+}
+
+// WithTutorial shows the guided prompts of the tutorial above the TUI. The
+// tutorial runs against the demo cluster, so views are not recorded.
+func (m Model) WithTutorial() Model {
+	m.tutorial = true
+	m.tutorialStep = 0
+	return m.WithoutViewHistory()
+}
+
+// Update handles messages and moves the tutorial on once a step is done
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	next, ok := model.(Model)
+	if !ok || !next.tutorial {
+		return model, cmd
+	}
+	for next.tutorialStep < len(tutorialSteps)-1 && tutorialSteps[next.tutorialStep].done(next) {
+		next.tutorialStep++
+	}
+	return next, cmd
+}
+
+// View renders the TUI, in ASCII when the terminal needs it
+func (m Model) View() string {
+	if m.ascii {
+		return asciiGlyphs.Replace(m.tutorialView())
+	}
+	return m.tutorialView()
+}
+
+// tutorialView renders the TUI, below the prompt of the current step in the tutorial
+func (m Model) tutorialView() string {
+	if !m.tutorial || m.width == 0 || m.height == 0 || m.locked {
+		return m.view()
+	}
+
+	banner := m.renderTutorialBanner()
+	m.height -= lipgloss.Height(banner)
+	return lipgloss.JoinVertical(lipgloss.Left, banner, m.view())
+}
+
+// renderTutorialBanner renders the prompt of the current tutorial step
+func (m Model) renderTutorialBanner() string {
+	step := tutorialSteps[m.tutorialStep]
+	title := titleStyle.Render(fmt.Sprintf("Tutorial %d/%d: %s", m.tutorialStep+1, len(tutorialSteps), step.title))
+	prompt := lipgloss.NewStyle().Width(m.width - 2).Render(step.prompt)
+	return lipgloss.JoinVertical(lipgloss.Left, title, warningStyle.Render(prompt), "")
+}
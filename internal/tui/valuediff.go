@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ginbear/k8s-envtop/internal/env"
+)
+
+// hasValueDiff returns true if a diff row is a value change shown in clear
+func hasValueDiff(result env.DiffResult) bool {
+	return result.Status == env.DiffStatusValueDiff && result.EnvA != nil && result.EnvB != nil &&
+		!result.EnvA.IsMasked() && !result.EnvB.IsMasked()
+}
+
+// openValueDiff shows the full diff of the values of a row (see hasValueDiff)
+func (m *Model) openValueDiff(result env.DiffResult, labelA, labelB string) {
+	m.valueDiff = result
+	m.valueDiffLines = env.DiffLines(result.EnvA.Value, result.EnvB.Value)
+	m.valueDiffLabels = [2]string{labelA, labelB}
+	m.valueDiffReturn = m.viewMode
+	m.valueDiffOffset = 0
+	m.viewMode = ViewModeValueDiff
+}
+
+// handleDiffRowEnter opens the value diff of the selected row of the namespace
+// diff, or the ConfigMap diff for rows that are not a value change in clear
+func (m Model) handleDiffRowEnter() (tea.Model, tea.Cmd) {
+	if m.diffCursor >= len(m.diffResults) {
+		return m, nil
+	}
+	if result := m.diffResults[m.diffCursor]; hasValueDiff(result) {
+		m.openValueDiff(result, m.namespaceLabel(m.diffNsA), m.diffNamespaceLabelB(m.diffNsB))
+		return m, nil
+	}
+	return m.handleConfigMapDiffStart()
+}
+
+// handleValueDiff handles key press in the value diff view
+func (m Model) handleValueDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.valueDiffOffset > 0 {
+			m.valueDiffOffset--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.valueDiffOffset < len(m.valueDiffRows())-1 {
+			m.valueDiffOffset++
+		}
+	case key.Matches(msg, m.keys.Enter):
+		// Drill further down into the ConfigMaps of a namespace diff
+		if m.valueDiffReturn == ViewModeDiffShow {
+			m.viewMode = ViewModeDiffShow
+			return m.handleConfigMapDiffStart()
+		}
+	}
+	return m, nil
+}
+
+// diffRune is a character of a value diff line and whether it is highlighted
+type diffRune struct {
+	r         rune
+	highlight bool
+}
+
+// valueDiffRows renders the lines of the value diff, wrapped to the screen width
+func (m Model) valueDiffRows() []string {
+	width := m.width - 4
+	if width < 20 {
+		width = 20
+	}
+
+	var rows []string
+	for _, line := range m.valueDiffLines {
+		marker, base, highlight := "  ", itemStyle, itemStyle
+		switch line.Kind {
+		case env.EditDelete:
+			marker, base, highlight = "- ", diffRemovedStyle, diffRemovedCharStyle
+		case env.EditInsert:
+			marker, base, highlight = "+ ", diffAddedStyle, diffAddedCharStyle
+		}
+
+		var runes []diffRune
+		if line.Chars == nil {
+			for _, r := range line.Text {
+				runes = append(runes, diffRune{r: r})
+			}
+		} else {
+			for _, edit := range line.Chars {
+				// A deleted line shows the deleted characters, an inserted line the inserted ones
+				if edit.Kind != env.EditEqual && edit.Kind != line.Kind {
+					continue
+				}
+				for _, r := range edit.Text {
+					runes = append(runes, diffRune{r: r, highlight: edit.Kind != env.EditEqual})
+				}
+			}
+		}
+
+		for first := true; first || len(runes) > 0; first = false {
+			n := min(width, len(runes))
+			rows = append(rows, base.Render(marker)+renderDiffRunes(runes[:n], base, highlight))
+			runes = runes[n:]
+			marker = "  "
+		}
+	}
+	return rows
+}
+
+// renderDiffRunes renders characters, grouping runs with the same highlighting
+func renderDiffRunes(runes []diffRune, base, highlight lipgloss.Style) string {
+	var b strings.Builder
+	for start := 0; start < len(runes); {
+		end := start
+		var run strings.Builder
+		for end < len(runes) && runes[end].highlight == runes[start].highlight {
+			run.WriteRune(runes[end].r)
+			end++
+		}
+		style := base
+		if runes[start].highlight {
+			style = highlight
+		}
+		b.WriteString(style.Render(run.String()))
+		start = end
+	}
+	return b.String()
+}
+
+// renderValueDiff renders the full diff of two values of a variable
+func (m Model) renderValueDiff() string {
+	title := titleStyle.Render(fmt.Sprintf("%s: %s vs %s", m.valueDiff.Name, m.valueDiffLabels[0], m.valueDiffLabels[1]))
+	content := []string{
+		title,
+		diffRemovedStyle.Render("- "+m.valueDiffLabels[0]) + "  " + diffAddedStyle.Render("+ "+m.valueDiffLabels[1]),
+		"",
+	}
+
+	maxItems := m.height - 6
+	if maxItems < 1 {
+		maxItems = 1
+	}
+	rows := m.valueDiffRows()
+	for i := m.valueDiffOffset; i < len(rows) && i < m.valueDiffOffset+maxItems; i++ {
+		content = append(content, rows[i])
+	}
+
+	help := "↑↓: scroll  Esc: back to diff"
+	if m.valueDiffReturn == ViewModeDiffShow {
+		help = "↑↓: scroll  Enter: ConfigMap diff  Esc: back to diff"
+	}
+	content = append(content, "", helpStyle.Render(help))
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
@@ -1,20 +1,35 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ginbear/k8s-envtop/internal/drift"
 	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/fingerprint"
 	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/netcheck"
+	"github.com/ginbear/k8s-envtop/internal/policy"
+	"github.com/ginbear/k8s-envtop/internal/preview"
 )
 
-// View renders the TUI
-func (m Model) View() string {
+// view renders the TUI
+func (m Model) view() string {
 	if m.width == 0 || m.height == 0 {
 		return "Loading..."
 	}
 
+	// Nothing but the lock screen is drawn while locked
+	if m.locked {
+		return m.renderLockScreen()
+	}
+
 	// Handle different view modes
 	switch m.viewMode {
 	case ViewModeRevealMenu:
@@ -27,16 +42,100 @@ func (m Model) View() string {
 		return m.renderDiffSelect()
 	case ViewModeDiffShow:
 		return m.renderDiffView()
+	case ViewModeConfigMapDiff:
+		return m.renderConfigMapDiffView()
+	case ViewModeFlagMatrix:
+		return m.renderFlagMatrix()
 	case ViewModeSealInput:
 		return m.renderSealInput()
 	case ViewModeSealResult:
 		return m.renderSealResult()
+	case ViewModeVerifyResult:
+		return m.renderVerifyResult()
+	case ViewModePodSelect:
+		return m.renderPodSelect()
+	case ViewModeWorklist:
+		return m.renderWorklist()
+	case ViewModeWorklistJustify:
+		return m.renderWorklistJustify()
+	case ViewModeSecretUsage:
+		return m.renderSecretUsage()
+	case ViewModeConnectivity:
+		return m.renderConnectivity()
+	case ViewModeKubectl:
+		return m.renderKubectl()
+	case ViewModePreviewCleanup:
+		return m.renderPreviewCleanup()
+	case ViewModeQueryInput:
+		return m.renderQueryInput()
+	case ViewModeQueryResult:
+		return m.renderQueryResult()
+	case ViewModeExplain:
+		return m.renderExplain()
+	case ViewModeContextSelect:
+		return m.renderContextSelect()
+	case ViewModeExportInput:
+		return m.renderExportInput()
+	case ViewModeCopyMenu:
+		return m.renderCopyMenu()
+	case ViewModeEditInput:
+		return m.renderEditInput()
+	case ViewModeEditConfirm:
+		return m.renderEditConfirm()
+	case ViewModeBulkProgress:
+		return m.renderBulkProgress()
+	case ViewModeVarAcross:
+		return m.renderVarAcross()
+	case ViewModeGraph:
+		return m.renderGraph()
+	case ViewModeDetail:
+		return m.renderDetail()
+	case ViewModeRevisionSelect:
+		return m.renderRevisionSelect()
+	case ViewModeRevisionDiff:
+		return m.renderRevisionDiff()
+	case ViewModeValueDiff:
+		return m.renderValueDiff()
+	case ViewModeConsumers:
+		return m.renderConsumers()
+	case ViewModePalette:
+		return m.renderPalette()
+	}
+
+	// Splash screen until the first data arrives
+	if !m.namespacesLoaded && m.err == nil {
+		return m.renderSplash()
 	}
 
 	// Normal view with 3 panes
 	return m.renderNormalView()
 }
 
+// renderError renders an error of the status line. The errors envtop can
+// classify show their kind and a remediation hint.
+func renderError(err error) string {
+	var classified *k8s.Error
+	if !errors.As(k8s.Classify(err), &classified) {
+		return errorStyle.Render(fmt.Sprintf("Error: %v", err))
+	}
+	return errorStyle.Render(fmt.Sprintf("%s: %v", classified.Kind, err)) + "  " + warningStyle.Render("→ "+classified.Hint)
+}
+
+// renderSplash renders the startup progress screen
+func (m Model) renderSplash() string {
+	done := diffAddedStyle.Render("✓")
+	pending := warningStyle.Render("…")
+
+	content := []string{
+		titleStyle.Render("envtop"),
+		done + " " + m.t("splash.kubeconfig", m.context),
+		pending + " " + m.t("splash.namespaces"),
+	}
+	content = append(content, m.renderCapabilityLines()...)
+
+	return m.centerDialog(strings.Join(content, "\n"))
+}
+
 // renderNormalView renders the 2-row layout
 // Top row: [Namespaces] [Apps]
 // Bottom row: [Environment Variables]
@@ -50,17 +149,22 @@ func (m Model) renderNormalView() string {
 	// Render error or status message
 	statusLine := ""
 	if m.err != nil {
-		statusLine = errorStyle.Render(fmt.Sprintf("Error: %v", m.err))
+		statusLine = renderError(m.err)
 	} else if m.statusMessage != "" {
 		statusLine = warningStyle.Render(m.statusMessage)
 	}
 
+	ticker := m.renderTicker(m.width - 2)
+
 	// Calculate available height for panes
-	// Total height minus: header(1) + help(1) + status(0-1) + padding(1)
+	// Total height minus: header(1) + help(1) + ticker(0-1) + status(0-1) + padding(1)
 	usedHeight := 4
 	if statusLine != "" {
 		usedHeight++
 	}
+	if ticker != "" {
+		usedHeight++
+	}
 	availableHeight := m.height - usedHeight
 	if availableHeight < 10 {
 		availableHeight = 10
@@ -92,7 +196,11 @@ func (m Model) renderNormalView() string {
 	envPane := m.renderEnvPane(envWidth, envHeight)
 
 	// Join all parts vertically
-	parts := []string{header, topRow, envPane, help}
+	parts := []string{header, topRow, envPane}
+	if ticker != "" {
+		parts = append(parts, ticker)
+	}
+	parts = append(parts, help)
 	if statusLine != "" {
 		parts = append(parts, statusLine)
 	}
@@ -103,11 +211,11 @@ func (m Model) renderNormalView() string {
 // renderHeader renders the top header bar
 func (m Model) renderHeader() string {
 	title := titleStyle.Render("envtop")
-	ctx := fmt.Sprintf("Context: %s", m.context)
+	ctx := m.t("header.context", m.context)
 
 	var status string
 	if m.loading {
-		status = "Loading..."
+		status = m.t("header.loading")
 	} else if len(m.namespaces) > 0 {
 		ns := m.namespaces[m.namespaceIdx]
 		appName := ""
@@ -121,6 +229,18 @@ func (m Model) renderHeader() string {
 		}
 	}
 
+	if account := m.cfg.Session.ServiceAccount; account != "" {
+		if time.Now().Before(m.sessionExpires) {
+			ctx += "  " + warningStyle.Render(m.t("header.session", account, m.printer.DateTime(m.sessionExpires)))
+		} else {
+			ctx += "  " + errorStyle.Render(m.t("header.session_expired", account))
+		}
+	}
+
+	if badges := m.renderCapabilityBadges(); badges != "" {
+		ctx += "  " + badges
+	}
+
 	return fmt.Sprintf("%s  %s  %s", title, ctx, status)
 }
 
@@ -128,22 +248,38 @@ func (m Model) renderHeader() string {
 func (m Model) renderHelp() string {
 	if m.viewMode == ViewModeSearch {
 		keys := []string{
-			helpKeyStyle.Render("Type") + helpStyle.Render(": filter"),
-			helpKeyStyle.Render("↑↓") + helpStyle.Render(": move"),
-			helpKeyStyle.Render("Enter") + helpStyle.Render(": select"),
-			helpKeyStyle.Render("Esc") + helpStyle.Render(": cancel"),
+			helpKeyStyle.Render("Type") + helpStyle.Render(": "+m.t("help.filter")),
+			helpKeyStyle.Render("↑↓") + helpStyle.Render(": "+m.t("help.move")),
+			helpKeyStyle.Render("Enter") + helpStyle.Render(": "+m.t("help.select")),
+			helpKeyStyle.Render("Esc") + helpStyle.Render(": "+m.t("help.cancel")),
 		}
 		return helpStyle.Render(strings.Join(keys, "  "))
 	}
 	keys := []string{
-		helpKeyStyle.Render("Tab") + helpStyle.Render(": switch pane"),
-		helpKeyStyle.Render("↑↓") + helpStyle.Render(": move"),
-		helpKeyStyle.Render("Enter") + helpStyle.Render(": select"),
-		helpKeyStyle.Render("/") + helpStyle.Render(": search"),
-		helpKeyStyle.Render("r") + helpStyle.Render(": reveal"),
-		helpKeyStyle.Render("s") + helpStyle.Render(": seal"),
-		helpKeyStyle.Render("d") + helpStyle.Render(": diff"),
-		helpKeyStyle.Render("q") + helpStyle.Render(": quit"),
+		helpKeyStyle.Render("Tab") + helpStyle.Render(": "+m.t("help.switch_pane")),
+		helpKeyStyle.Render("↑↓") + helpStyle.Render(": "+m.t("help.move")),
+		helpKeyStyle.Render("Enter") + helpStyle.Render(": "+m.t("help.select")),
+	}
+	// Action keys follow the remappings of the config file; disabled actions are left out
+	actions := []struct {
+		binding key.Binding
+		label   string
+	}{
+		{m.keys.Search, "help.search"},
+		{m.keys.Reveal, "help.reveal"},
+		{m.keys.Seal, "help.seal"},
+		{m.keys.Verify, "help.verify"},
+		{m.keys.Diff, "help.diff"},
+		{m.keys.Pods, "help.pod"},
+		{m.keys.Flags, "help.flags"},
+		{m.keys.Usage, "help.secrets"},
+		{m.keys.Palette, "help.commands"},
+		{m.keys.Quit, "help.quit"},
+	}
+	for _, a := range actions {
+		if a.binding.Enabled() {
+			keys = append(keys, helpKeyStyle.Render(a.binding.Help().Key)+helpStyle.Render(": "+m.t(a.label)))
+		}
 	}
 	return helpStyle.Render(strings.Join(keys, "  "))
 }
@@ -154,7 +290,10 @@ func (m Model) renderNamespacesPane(width, height int) string {
 	style := GetPaneStyle(m.activePane == PaneNamespaces || isSearching)
 	style = style.Width(width).Height(height)
 
-	title := titleStyle.Render("Namespaces")
+	title := titleStyle.Render(m.t("pane.namespaces"))
+	if m.namespacesMore != "" {
+		title += mutedStyle.Render(m.t("pane.namespaces_more", len(m.namespaces)))
+	}
 	content := []string{title}
 
 	// Show search input if searching this pane
@@ -165,7 +304,9 @@ func (m Model) renderNamespacesPane(width, height int) string {
 	// Get filtered indices
 	filteredIndices := m.GetFilteredNamespaces()
 
-	maxItems := height - 3
+	detail := m.renderNamespaceDetail(filteredIndices)
+
+	maxItems := height - 3 - len(detail) - len(m.fleet) // context headers in a fleet session
 	if isSearching {
 		maxItems-- // Account for search input
 	}
@@ -177,6 +318,12 @@ func (m Model) renderNamespacesPane(width, height int) string {
 	for cursorPos := startIdx; cursorPos < len(filteredIndices) && cursorPos < startIdx+maxItems; cursorPos++ {
 		i := filteredIndices[cursorPos]
 		ns := m.namespaces[i]
+		if m.fleet != nil {
+			kubeContext := m.namespaceContext(i)
+			if cursorPos == startIdx || m.namespaceContext(filteredIndices[cursorPos-1]) != kubeContext {
+				content = append(content, mutedStyle.Render(truncate(kubeContext, width-3)))
+			}
+		}
 		prefix := "  "
 		style := itemStyle
 
@@ -189,6 +336,9 @@ func (m Model) renderNamespacesPane(width, height int) string {
 		if i == m.namespaceIdx {
 			ns = ns + " *"
 		}
+		if tier := m.namespaceTiers[m.nsKey(m.namespaceContext(i), m.namespaces[i])]; tier != "" {
+			ns = ns + " [" + tier + "]"
+		}
 
 		// Truncate if too long
 		maxLen := width - 5
@@ -200,19 +350,63 @@ func (m Model) renderNamespacesPane(width, height int) string {
 	}
 
 	if len(filteredIndices) == 0 {
-		content = append(content, mutedStyle.Render("  No matches"))
+		content = append(content, mutedStyle.Render("  "+m.t("list.no_matches")))
+	}
+
+	// Pin the detail of the namespace under the cursor to the bottom of the pane
+	if len(detail) > 0 {
+		for len(content) < height-len(detail)-2 {
+			content = append(content, "")
+		}
+		content = append(content, detail...)
 	}
 
 	return GetPaneStyle(m.activePane == PaneNamespaces || isSearching).Width(width).Height(height).Render(strings.Join(content, "\n"))
 }
 
+// renderNamespaceDetail renders the creation time and quota presence of the namespace
+// under the cursor; quotas are known only for the selected namespace
+func (m Model) renderNamespaceDetail(filteredIndices []int) []string {
+	if m.namespaceCursor >= len(filteredIndices) {
+		return nil
+	}
+	i := filteredIndices[m.namespaceCursor]
+	ns := m.namespaces[i]
+	kubeContext := m.namespaceContext(i)
+
+	var detail []string
+	if created, ok := m.namespaceCreated[m.nsKey(kubeContext, ns)]; ok && !created.IsZero() {
+		detail = append(detail, mutedStyle.Render("  "+m.t("namespace.created", m.printer.Date(created), formatAge(time.Since(created)))))
+	}
+	if m.namespaceLimits != nil && m.namespaceLimitsFor == ns && kubeContext == m.context {
+		quota := "  " + m.t("namespace.quota", len(m.namespaceLimits.ResourceQuotas), len(m.namespaceLimits.LimitRanges))
+		detail = append(detail, mutedStyle.Render(quota))
+	}
+	return detail
+}
+
+// formatAge formats a duration like kubectl's AGE column
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
 // renderAppsPane renders the apps pane
 func (m Model) renderAppsPane(width, height int) string {
 	isSearching := m.IsSearchingPane(PaneApps)
 	style := GetPaneStyle(m.activePane == PaneApps || isSearching)
 	style = style.Width(width).Height(height)
 
-	title := titleStyle.Render("Apps")
+	title := titleStyle.Render(m.t("pane.apps"))
+	if m.appFilter != appFilterNone {
+		title += " " + warningStyle.Render("["+m.appFilter.String()+"]")
+	}
 	content := []string{title}
 
 	// Show search input if searching this pane
@@ -224,9 +418,9 @@ func (m Model) renderAppsPane(width, height int) string {
 	filteredIndices := m.GetFilteredApps()
 
 	if len(m.apps) == 0 {
-		content = append(content, mutedStyle.Render("  No apps found"))
+		content = append(content, mutedStyle.Render("  "+m.t("list.no_apps")))
 	} else if len(filteredIndices) == 0 {
-		content = append(content, mutedStyle.Render("  No matches"))
+		content = append(content, mutedStyle.Render("  "+m.t("list.no_matches")))
 	} else {
 		maxItems := height - 3
 		if isSearching {
@@ -250,14 +444,23 @@ func (m Model) renderAppsPane(width, height int) string {
 
 			// Format: name (kind)
 			kindBadge := ""
-			if app.Kind == k8s.AppKindStatefulSet {
+			switch app.Kind {
+			case k8s.AppKindStatefulSet:
 				kindBadge = " [sts]"
-			} else {
+			case k8s.AppKindCronJob:
+				kindBadge = " [cj]"
+			case k8s.AppKindJob:
+				kindBadge = " [job]"
+			default:
 				kindBadge = " [dep]"
 			}
 
+			badges := m.renderHealthBadges(app)
 			name := app.Name
-			maxLen := width - 10
+			maxLen := width - 10 - lipgloss.Width(badges)
+			if maxLen < 10 {
+				maxLen = 10
+			}
 			if len(name) > maxLen {
 				name = name[:maxLen-3] + "..."
 			}
@@ -268,22 +471,69 @@ func (m Model) renderAppsPane(width, height int) string {
 				marker = " *"
 			}
 
-			content = append(content, style.Render(prefix+name+kindBadge+marker))
+			content = append(content, style.Render(prefix+name+kindBadge+marker)+badges)
 		}
 	}
 
 	return GetPaneStyle(m.activePane == PaneApps || isSearching).Width(width).Height(height).Render(strings.Join(content, "\n"))
 }
 
+// renderHealthBadges renders failing pods, config events and a recent rollout of an app
+func (m Model) renderHealthBadges(app k8s.App) string {
+	h, ok := m.healthOf(app)
+	if !ok {
+		return ""
+	}
+	badges := ""
+	if h.FailingPods > 0 {
+		badges += " " + diffRemovedStyle.Render(fmt.Sprintf("!%d", h.FailingPods))
+	}
+	if h.ConfigEvents > 0 {
+		badges += " " + warningStyle.Render(fmt.Sprintf("ev%d", h.ConfigEvents))
+	}
+	if isRecentlyDeployed(h) {
+		badges += " " + diffSameStyle.Render("new")
+	}
+	return badges
+}
+
 // renderEnvPane renders the env pane
 func (m Model) renderEnvPane(width, height int) string {
 	isSearching := m.IsSearchingPane(PaneEnv)
 	style := GetPaneStyle(m.activePane == PaneEnv || isSearching)
 	style = style.Width(width).Height(height)
 
-	title := titleStyle.Render("Environment Variables")
+	titleText := m.t("pane.env")
+	if m.selectedPod != nil {
+		titleText += m.t("pane.env_pod", m.selectedPod.Name, podPlacement(*m.selectedPod))
+		if len(m.injectedVars) > 0 {
+			titleText += m.t("pane.env_injected", m.printer.Number(len(m.injectedVars)))
+		}
+	}
+	title := titleStyle.Render(titleText) + m.containerTitle() + m.changesTitle()
 	content := []string{title}
 
+	if m.security != nil {
+		content = append(content, m.renderSecuritySummary())
+	}
+	forbidden := m.renderForbidden(width - 4)
+	if forbidden != "" {
+		content = append(content, forbidden)
+	}
+	missing := m.renderMissingOptional(width - 4)
+	if missing != "" {
+		content = append(content, missing)
+	}
+	envSize := m.renderEnvSize(width - 4)
+	if envSize != "" {
+		content = append(content, envSize)
+	}
+	scalers := m.renderScalers(width - 4)
+	if scalers != "" {
+		content = append(content, scalers)
+	}
+	mounts := m.renderMountedFiles(width - 4)
+
 	// Show search input if searching this pane
 	if isSearching {
 		content = append(content, m.searchInput.View())
@@ -293,39 +543,294 @@ func (m Model) renderEnvPane(width, height int) string {
 	header := fmt.Sprintf("%-30s %-25s %-14s %s", "NAME", "SOURCE", "KIND", "VALUE")
 	content = append(content, helpStyle.Render(header))
 
-	// Get filtered indices
-	filteredIndices := m.GetFilteredEnvVars()
+	rows := m.envRows()
 
 	if len(m.envVars) == 0 {
-		content = append(content, mutedStyle.Render("  No env vars found"))
-	} else if len(filteredIndices) == 0 {
-		content = append(content, mutedStyle.Render("  No matches"))
+		content = append(content, mutedStyle.Render("  "+m.t("list.no_env")))
+	} else if len(rows) == 0 {
+		content = append(content, mutedStyle.Render("  "+m.t("list.no_matches")))
 	} else {
 		maxItems := height - 5
 		if isSearching {
 			maxItems--
 		}
+		if m.security != nil {
+			maxItems--
+		}
+		if forbidden != "" {
+			maxItems--
+		}
+		if missing != "" {
+			maxItems--
+		}
+		if envSize != "" {
+			maxItems--
+		}
+		if scalers != "" {
+			maxItems--
+		}
+		maxItems -= len(mounts)
+		if maxItems < 1 {
+			maxItems = 1
+		}
 		startIdx := 0
 		if m.envCursor >= maxItems {
 			startIdx = m.envCursor - maxItems + 1
 		}
 
-		for cursorPos := startIdx; cursorPos < len(filteredIndices) && cursorPos < startIdx+maxItems; cursorPos++ {
-			i := filteredIndices[cursorPos]
-			ev := m.envVars[i]
-			content = append(content, m.renderEnvVarRow(ev, cursorPos == m.envCursor, width))
+		for cursorPos := startIdx; cursorPos < len(rows) && cursorPos < startIdx+maxItems; cursorPos++ {
+			row := rows[cursorPos]
+			if row.index < 0 {
+				content = append(content, m.renderGroupRow(row, cursorPos == m.envCursor))
+				continue
+			}
+			content = append(content, m.renderEnvVarRow(m.envVars[row.index], cursorPos == m.envCursor, row.group != "", width))
 		}
 	}
+	content = append(content, mounts...)
 
 	return GetPaneStyle(m.activePane == PaneEnv || isSearching).Width(width).Height(height).Render(strings.Join(content, "\n"))
 }
 
+// maxMountedFiles is the number of mounted files listed below the env
+const maxMountedFiles = 5
+
+// renderMountedFiles renders the "mounted files" section of the env pane: the
+// files the shown containers read from ConfigMap and Secret volumes, and the
+// secrets the Vault agent renders
+func (m Model) renderMountedFiles(width int) []string {
+	container := ""
+	if m.containerIdx > 0 {
+		container = m.containers[m.containerIdx-1]
+	}
+	var files []env.MountedFile
+	for _, f := range m.mounts {
+		if container == "" || f.Container == container || f.Container == "" {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	lines := []string{"", helpStyle.Render(fmt.Sprintf("MOUNTED FILES (%d)", len(files)))}
+	for i, f := range files {
+		if i == maxMountedFiles {
+			lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more", len(files)-maxMountedFiles)))
+			break
+		}
+		row := fmt.Sprintf("  %-40s %s", truncate(f.Path, 40), f)
+		if container == "" && f.Container != "" && len(m.containers) > 1 {
+			row += " (" + f.Container + ")"
+		}
+		style := itemStyle
+		switch {
+		case f.Missing && !f.Optional:
+			style = errorStyle
+		case f.Missing:
+			style = warningStyle
+		}
+		lines = append(lines, style.Render(truncate(row, width)))
+	}
+	return lines
+}
+
+// renderForbidden explains the reduced visibility when the user may not read
+// some sources of the shown variables: their variables are listed as
+// "(forbidden)", and the keys of forbidden envFrom sources are unknown
+func (m Model) renderForbidden(width int) string {
+	seen := make(map[string]bool)
+	var sources []string
+	for _, ev := range m.envVars {
+		if !ev.Forbidden {
+			continue
+		}
+		if s := string(ev.SourceKind) + "/" + ev.SourceName; !seen[s] {
+			seen[s] = true
+			sources = append(sources, s)
+		}
+	}
+	if len(sources) == 0 {
+		return ""
+	}
+	return errorStyle.Render(truncate(fmt.Sprintf("Reduced visibility, no permission to read %d source(s): %s", len(sources), strings.Join(sources, ", ")), width))
+}
+
+// renderMissingOptional warns about optional sources of the shown containers that
+// do not exist: the kubelet skips them silently, so the variables are just absent
+func (m Model) renderMissingOptional(width int) string {
+	container := ""
+	if m.containerIdx > 0 {
+		container = m.containers[m.containerIdx-1]
+	}
+
+	seen := make(map[string]bool)
+	var missing []string
+	for _, miss := range m.missingOptional {
+		if container != "" && miss.Container != container {
+			continue
+		}
+		if s := miss.String(); !seen[s] {
+			seen[s] = true
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	return warningStyle.Render(truncate("optional, not found: "+strings.Join(missing, ", "), width))
+}
+
+// renderEnvSize warns about the shown containers whose env exceeds the size
+// budget of the config, and about variables too large to be passed at all
+func (m Model) renderEnvSize(width int) string {
+	if m.cfg == nil {
+		return ""
+	}
+	budget := m.cfg.EnvSizeBudget()
+	if budget <= 0 {
+		return ""
+	}
+	container := ""
+	if m.containerIdx > 0 {
+		container = m.containers[m.containerIdx-1]
+	}
+
+	var over []string
+	for _, size := range env.ContainerSizes(m.allContainerEnv) {
+		if container != "" && size.Container != container {
+			continue
+		}
+		if size.LargestBytes > env.MaxValueSize {
+			return errorStyle.Render(truncate(fmt.Sprintf("%s of container %s is %s, over the %s limit of a variable: the container cannot start",
+				size.Largest, size.Container, env.FormatSize(size.LargestBytes), env.FormatSize(env.MaxValueSize)), width))
+		}
+		if size.Bytes > budget {
+			over = append(over, fmt.Sprintf("%s %s (%d vars)", size.Container, env.FormatSize(size.Bytes), size.Vars))
+		}
+	}
+	if len(over) == 0 {
+		return ""
+	}
+	return warningStyle.Render(truncate(fmt.Sprintf("env size over the budget of %s: %s", env.FormatSize(budget), strings.Join(over, ", ")), width))
+}
+
+// renderScalers renders the HPAs and KEDA ScaledObjects targeting the app. Trigger
+// metadata often mirrors the env (queue names, hosts); keys read from the env
+// by KEDA ("...FromEnv") are shown with the variable and flagged when it is missing.
+func (m Model) renderScalers(width int) string {
+	if len(m.scalers) == 0 {
+		return ""
+	}
+	defined := make(map[string]bool, len(m.envVars))
+	for _, ev := range m.envVars {
+		defined[ev.Name] = true
+	}
+
+	parts := make([]string, 0, len(m.scalers))
+	missing := false
+	for _, s := range m.scalers {
+		kind := "HPA"
+		if s.Kind == "ScaledObject" {
+			kind = "KEDA"
+		}
+		part := fmt.Sprintf("%s %s %d-%d", kind, s.Name, s.MinReplicas, s.MaxReplicas)
+		for _, t := range s.Triggers {
+			var meta []string
+			fromEnv := t.FromEnv()
+			for _, pair := range t.SortedMetadata() {
+				k, _, _ := strings.Cut(pair, "=")
+				if name, ok := strings.CutSuffix(k, "FromEnv"); ok && fromEnv[name] != "" {
+					ref := name + "=$" + fromEnv[name]
+					if !defined[fromEnv[name]] {
+						ref += "(missing)"
+						missing = true
+					}
+					meta = append(meta, ref)
+					continue
+				}
+				meta = append(meta, pair)
+			}
+			part += fmt.Sprintf(" [%s %s]", t.Type, strings.Join(meta, " "))
+		}
+		parts = append(parts, part)
+	}
+
+	style := mutedStyle
+	if missing {
+		style = warningStyle
+	}
+	return style.Render(truncate("scaled by: "+strings.Join(parts, "; "), width))
+}
+
+// renderSecuritySummary renders the ServiceAccount token and securityContext settings
+// that decide which credentials exist inside the pod
+func (m Model) renderSecuritySummary() string {
+	sec := m.security
+
+	token := diffSameStyle.Render("not mounted")
+	switch {
+	case sec.AutomountSource == k8s.AutomountSourceUnknown:
+		token = warningStyle.Render("unknown")
+	case sec.AutomountToken:
+		token = warningStyle.Render("mounted")
+	}
+	parts := []string{
+		mutedStyle.Render("SA: ") + sec.ServiceAccountName,
+		mutedStyle.Render("token: ") + token + mutedStyle.Render(" ("+sec.AutomountSource+")"),
+	}
+
+	switch {
+	case sec.RunAsNonRoot != nil && *sec.RunAsNonRoot:
+		parts = append(parts, mutedStyle.Render("runAsNonRoot: ")+"true")
+	case sec.RunAsUser != nil:
+		parts = append(parts, mutedStyle.Render("runAsUser: ")+fmt.Sprintf("%d", *sec.RunAsUser))
+	default:
+		parts = append(parts, mutedStyle.Render("runAsNonRoot: ")+"unset")
+	}
+
+	var privileged, escalation, writableRoot, capabilities []string
+	for _, c := range sec.Containers {
+		if c.Privileged {
+			privileged = append(privileged, c.Name)
+		}
+		// allowPrivilegeEscalation defaults to true when unset
+		if c.AllowPrivilegeEscalation == nil || *c.AllowPrivilegeEscalation {
+			escalation = append(escalation, c.Name)
+		}
+		if !c.ReadOnlyRootFilesystem {
+			writableRoot = append(writableRoot, c.Name)
+		}
+		for _, capability := range c.AddedCapabilities {
+			capabilities = append(capabilities, c.Name+":"+capability)
+		}
+	}
+	if len(privileged) > 0 {
+		parts = append(parts, errorStyle.Render("privileged: "+strings.Join(privileged, ",")))
+	}
+	if len(capabilities) > 0 {
+		parts = append(parts, warningStyle.Render("caps: "+strings.Join(capabilities, ",")))
+	}
+	if len(escalation) > 0 {
+		parts = append(parts, warningStyle.Render("privEsc: "+strings.Join(escalation, ",")))
+	}
+	if len(writableRoot) > 0 {
+		parts = append(parts, mutedStyle.Render("writable root: "+strings.Join(writableRoot, ",")))
+	}
+
+	return strings.Join(parts, "  ")
+}
+
 // renderEnvVarRow renders a single env var row
-func (m Model) renderEnvVarRow(ev k8s.EnvVar, selected bool, width int) string {
+func (m Model) renderEnvVarRow(ev k8s.EnvVar, selected, nested bool, width int) string {
 	prefix := "  "
 	if selected {
 		prefix = "> "
 	}
+	if nested {
+		// Members of a prefix group are indented under its header
+		prefix += "  "
+	}
 
 	// Name column (max 28 chars)
 	name := ev.Name
@@ -344,8 +849,14 @@ func (m Model) renderEnvVarRow(ev k8s.EnvVar, selected bool, width int) string {
 		source = "(inline)"
 	case k8s.EnvSourceFieldRef:
 		source = "(fieldRef)"
+		if ev.SourceName != "" {
+			source = "field/" + ev.SourceName
+		}
 	case k8s.EnvSourceResourceRef:
 		source = "(resourceRef)"
+		if ev.SourceName != "" {
+			source = "res/" + ev.SourceName
+		}
 	default:
 		source = "(unknown)"
 	}
@@ -359,23 +870,33 @@ func (m Model) renderEnvVarRow(ev k8s.EnvVar, selected bool, width int) string {
 		kind = kind[:12]
 	}
 
-	// Value column (use remaining width)
+	// Value column (use remaining width); fingerprints are short and shown whole
 	value := ev.Value
 	maxValueLen := width - 75 // Adjusted for wider columns
 	if maxValueLen < 20 {
 		maxValueLen = 20
 	}
-	if len(value) > maxValueLen {
+	if ev.IsMasked() {
+		value = m.hashLabel(ev.Hash)
+	} else if len(value) > maxValueLen {
 		value = value[:maxValueLen-3] + "..."
 	}
 
 	// Add notes for secrets
 	notes := ""
-	if ev.IsSecret() {
+	if ev.IsMasked() {
 		notes = fmt.Sprintf(" len=%d", ev.ValueLen)
 		if ev.IsSealed {
 			notes += " sealed"
 		}
+		if ev.Redacted {
+			notes += " redacted"
+		}
+	}
+	// Show how a computed resource value was derived, e.g. "limits.cpu 1500m / 1"
+	detail := ""
+	if ev.Detail != "" {
+		detail = " " + mutedStyle.Render("("+ev.Detail+")")
 	}
 
 	// Format the row
@@ -389,29 +910,245 @@ func (m Model) renderEnvVarRow(ev k8s.EnvVar, selected bool, width int) string {
 
 	// Color the kind badge
 	kindStyle := GetSourceKindStyle(string(ev.SourceKind))
-	if ev.IsSecret() {
+	if ev.IsMasked() {
 		row = fmt.Sprintf("%-28s %-23s %s %s%s", name, source, kindStyle.Render(fmt.Sprintf("%-12s", kind)), envSecretStyle.Render(value), envHashStyle.Render(notes))
+	} else if ev.Forbidden {
+		row = fmt.Sprintf("%-28s %-23s %s %s", name, source, kindStyle.Render(fmt.Sprintf("%-12s", kind)), envForbiddenStyle.Render(value))
 	} else {
-		row = fmt.Sprintf("%-28s %-23s %s %s", name, source, kindStyle.Render(fmt.Sprintf("%-12s", kind)), envValueStyle.Render(value))
+		row = fmt.Sprintf("%-28s %-23s %s %s%s", name, source, kindStyle.Render(fmt.Sprintf("%-12s", kind)), envValueStyle.Render(value), m.renderFlagBadge(ev)) + detail
 	}
+	row += m.renderPolicyBadge(ev) + m.renderChangedBadge(ev) + m.renderLiveBadge(ev) + m.renderConflictBadge(ev) + m.renderInjectedBadge(ev) + renderTerminatingBadge(ev) + m.renderStaleBadge(ev) + m.renderPinBadge(ev)
 
 	return style.Render(prefix + row)
 }
 
+// renderTerminatingBadge marks a variable whose ConfigMap/Secret is marked for deletion
+func renderTerminatingBadge(ev k8s.EnvVar) string {
+	if !ev.Terminating {
+		return ""
+	}
+	return " " + warningStyle.Render("⌫terminating")
+}
+
+// renderStaleBadge marks a variable whose ConfigMap/Secret changed after
+// running pods started: they still hold the previous value until restarted
+func (m Model) renderStaleBadge(ev k8s.EnvVar) string {
+	source, ok := m.staleSources[env.StaleKey(ev.SourceKind, ev.SourceName)]
+	if !ok {
+		return ""
+	}
+	return " " + warningStyle.Render(fmt.Sprintf("⟳stale %d/%d", len(source.StalePods), source.Pods))
+}
+
+// renderInjectedBadge marks a pod variable that the workload template does not define
+func (m Model) renderInjectedBadge(ev k8s.EnvVar) string {
+	if !m.injectedVars[env.RuntimeKey(ev.Container, ev.Name)] {
+		return ""
+	}
+	return " " + diffAddedStyle.Render("+runtime")
+}
+
+// renderPolicyBadge renders the value policies violated by a variable, colored by severity
+func (m Model) renderPolicyBadge(ev k8s.EnvVar) string {
+	violations := m.violations[ev.Name]
+	if len(violations) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(violations))
+	worst := policy.SeverityInfo
+	for _, v := range violations {
+		names = append(names, v.Policy)
+		if v.Severity.AtLeast(worst) {
+			worst = v.Severity
+		}
+	}
+
+	badge := " [!" + strings.Join(names, ",") + "]"
+	switch worst {
+	case policy.SeverityError:
+		return errorStyle.Render(badge)
+	case policy.SeverityWarning:
+		return warningStyle.Render(badge)
+	default:
+		return mutedStyle.Render(badge)
+	}
+}
+
+// renderFlagBadge renders an on/off badge for feature flag variables
+func (m Model) renderFlagBadge(ev k8s.EnvVar) string {
+	if !m.cfg.IsFeatureFlag(ev.Name) {
+		return ""
+	}
+	switch env.ParseFlagState(ev.Value) {
+	case env.FlagOn:
+		return " " + flagOnStyle.Render("[ON]")
+	case env.FlagOff:
+		return " " + flagOffStyle.Render("[OFF]")
+	}
+	return ""
+}
+
+// renderFlagCell renders a single cell of the feature flag matrix
+func renderFlagCell(value string, set bool, width int) string {
+	if !set {
+		return mutedStyle.Render(fmt.Sprintf("%-*s", width, "-"))
+	}
+	switch env.ParseFlagState(value) {
+	case env.FlagOn:
+		return flagOnStyle.Render(fmt.Sprintf("%-*s", width, "ON"))
+	case env.FlagOff:
+		return flagOffStyle.Render(fmt.Sprintf("%-*s", width, "OFF"))
+	}
+	if len(value) > width-1 {
+		value = value[:width-4] + "..."
+	}
+	return warningStyle.Render(fmt.Sprintf("%-*s", width, value))
+}
+
+// renderFlagMatrix renders the apps × feature flags matrix
+func (m Model) renderFlagMatrix() string {
+	ns := m.namespaces[m.namespaceIdx]
+	title := titleStyle.Render(fmt.Sprintf("Feature Flags: %s", ns))
+	content := []string{title, ""}
+
+	if m.flagMatrix == nil || len(m.flagMatrix.Flags) == 0 {
+		content = append(content, mutedStyle.Render("  No feature flags found in this namespace"))
+		content = append(content, "", helpStyle.Render("Esc: back to main view"))
+		return lipgloss.JoinVertical(lipgloss.Left, content...)
+	}
+
+	const appWidth = 24
+	const cellWidth = 14
+	visibleCols := (m.width - appWidth - 4) / cellWidth
+	if visibleCols < 1 {
+		visibleCols = 1
+	}
+	endCol := m.flagColOffset + visibleCols
+	if endCol > len(m.flagMatrix.Flags) {
+		endCol = len(m.flagMatrix.Flags)
+	}
+	flags := m.flagMatrix.Flags[m.flagColOffset:endCol]
+
+	// Header
+	header := fmt.Sprintf("  %-*s", appWidth, "APP")
+	for _, flag := range flags {
+		if len(flag) > cellWidth-1 {
+			flag = flag[:cellWidth-4] + "..."
+		}
+		header += fmt.Sprintf("%-*s", cellWidth, flag)
+	}
+	content = append(content, helpStyle.Render(header))
+
+	maxItems := m.height - 8
+	startIdx := 0
+	if m.flagCursor >= maxItems {
+		startIdx = m.flagCursor - maxItems + 1
+	}
+
+	for i := startIdx; i < len(m.flagMatrix.Apps) && i < startIdx+maxItems; i++ {
+		app := m.flagMatrix.Apps[i]
+		prefix := "  "
+		style := itemStyle
+		if i == m.flagCursor {
+			prefix = "> "
+			style = selectedItemStyle
+		}
+
+		name := app
+		if len(name) > appWidth-1 {
+			name = name[:appWidth-4] + "..."
+		}
+		row := style.Render(fmt.Sprintf("%s%-*s", prefix, appWidth, name))
+		for _, flag := range flags {
+			value, set := m.flagMatrix.Values[app][flag]
+			row += renderFlagCell(value, set, cellWidth)
+		}
+		content = append(content, row)
+	}
+
+	position := fmt.Sprintf("flags %d-%d of %d", m.flagColOffset+1, endCol, len(m.flagMatrix.Flags))
+	content = append(content, "", helpStyle.Render("↑↓: scroll apps  ←→: scroll flags  Esc: back to main view  ("+position+")"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// renderSecretUsage renders the Secret usage heatmap: one bar per Secret,
+// sized and colored by the share of apps referencing it
+func (m Model) renderSecretUsage() string {
+	ns := m.namespaces[m.namespaceIdx]
+	title := titleStyle.Render(fmt.Sprintf("Secret Usage: %s (%d apps)", ns, len(m.apps)))
+	content := []string{title, ""}
+
+	if len(m.secretUsage) == 0 {
+		content = append(content, mutedStyle.Render("  No Secrets referenced in this namespace"))
+		content = append(content, "", helpStyle.Render("Esc: back to main view"))
+		return lipgloss.JoinVertical(lipgloss.Left, content...)
+	}
+
+	const nameWidth = 32
+	const barWidth = 30
+	header := fmt.Sprintf("  %-*s %-*s %s", nameWidth, "SECRET", barWidth, "USAGE", "APPS")
+	content = append(content, helpStyle.Render(header))
+
+	maxItems := m.height - 8
+	startIdx := 0
+	if m.secretUsageCursor >= maxItems {
+		startIdx = m.secretUsageCursor - maxItems + 1
+	}
+
+	for i := startIdx; i < len(m.secretUsage) && i < startIdx+maxItems; i++ {
+		usage := m.secretUsage[i]
+		prefix := "  "
+		style := itemStyle
+		if i == m.secretUsageCursor {
+			prefix = "> "
+			style = selectedItemStyle
+		}
+
+		share := float64(len(usage.Apps)) / float64(len(m.apps))
+		filled := int(share*barWidth + 0.5)
+		if filled < 1 {
+			filled = 1
+		}
+		barStyle := diffSameStyle
+		switch {
+		case share >= 0.75:
+			barStyle = diffRemovedStyle
+		case share >= 0.5:
+			barStyle = warningStyle
+		}
+		bar := barStyle.Render(strings.Repeat("█", filled)) + strings.Repeat(" ", barWidth-filled)
+
+		row := style.Render(fmt.Sprintf("%s%-*s ", prefix, nameWidth, truncate(usage.Name, nameWidth))) +
+			bar + " " + fmt.Sprintf("%d/%d", len(usage.Apps), len(m.apps))
+		content = append(content, row)
+	}
+
+	// Apps referencing the selected Secret
+	if m.secretUsageCursor < len(m.secretUsage) {
+		selected := m.secretUsage[m.secretUsageCursor]
+		maxLen := m.width - 14
+		if maxLen < 10 {
+			maxLen = 10
+		}
+		content = append(content, "", mutedStyle.Render("  Used by: "+truncate(strings.Join(selected.Apps, ", "), maxLen)))
+	}
+
+	content = append(content, "", helpStyle.Render("↑↓: scroll  Esc: back to main view"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
 // renderRevealMenu renders the reveal mode selection menu
 func (m Model) renderRevealMenu() string {
 	dialog := dialogStyle.Width(50)
 
 	title := dialogTitleStyle.Render("Reveal Secret: " + m.revealedEnvName)
 
-	options := []string{
-		"Display as Base64",
-		"Display as Plain Text",
-	}
-
 	content := []string{title, "", "Select display mode:"}
 
-	for i, opt := range options {
+	for i, opt := range m.revealMenuOptions() {
 		prefix := "  "
 		style := dialogTextStyle
 		if i == m.revealMenuIdx {
@@ -426,16 +1163,38 @@ func (m Model) renderRevealMenu() string {
 	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
 }
 
+// revealMenuOptions returns the options of the reveal menu, in RevealMode order.
+// Revealing the whole source is offered for Secrets only.
+func (m Model) revealMenuOptions() []string {
+	options := []string{
+		"Display as Base64",
+		"Display as Plain Text",
+		"Display masked (first/last 4 chars)",
+	}
+	if m.revealedSecretName != "" {
+		options = append(options, "Reveal all keys of Secret "+m.revealedSecretName)
+	}
+	return options
+}
+
 // renderRevealConfirm renders the reveal confirmation dialog
 func (m Model) renderRevealConfirm() string {
 	dialog := dialogStyle.Width(60)
 
 	title := dialogTitleStyle.Render("⚠️  Security Warning")
 
+	action := "This operation will display the secret value on screen."
+	if m.revealMode == RevealModeSecret {
+		action = "This operation will display every value of Secret " + m.revealedSecretName + " on screen."
+	}
+	if m.copyField != copyNone {
+		action = "This operation will copy the secret value to the clipboard."
+	}
+
 	warning := []string{
 		title,
 		"",
-		dialogTextStyle.Render("This operation will display the secret value on screen."),
+		dialogTextStyle.Render(action),
 		"",
 		dialogTextStyle.Render("Before proceeding, please confirm:"),
 		dialogTextStyle.Render("  • You are not sharing your screen"),
@@ -453,11 +1212,17 @@ func (m Model) renderRevealConfirm() string {
 
 // renderRevealShow renders the revealed secret value
 func (m Model) renderRevealShow() string {
+	if m.revealedSecret != nil {
+		return m.renderRevealedSecret()
+	}
 	dialog := dialogStyle.Width(70)
 
 	modeLabel := "Base64"
-	if m.revealMode == RevealModePlain {
+	switch m.revealMode {
+	case RevealModePlain:
 		modeLabel = "Plain Text"
+	case RevealModeMasked:
+		modeLabel = "Masked"
 	}
 
 	title := dialogTitleStyle.Render("Secret Value: " + m.revealedEnvName + " (" + modeLabel + ")")
@@ -466,6 +1231,8 @@ func (m Model) renderRevealShow() string {
 	copyStatus := "c: copy to clipboard"
 	if m.revealCopied {
 		copyStatus = "✓ Copied to clipboard!"
+	} else if m.revealMode == RevealModeMasked {
+		copyStatus = "copy is not available for a masked value"
 	}
 
 	content := []string{
@@ -492,12 +1259,24 @@ func (m Model) renderDiffSelect() string {
 		app = m.apps[m.appIdx].Name
 	}
 
+	target := fmt.Sprintf("Compare: %s/%s", currentNs, app)
+	if m.diffBulk {
+		target = fmt.Sprintf("Compare all apps in: %s", currentNs)
+	}
+
+	with := "With namespace:"
+	if m.crossCluster() {
+		with = fmt.Sprintf("With namespace in context %s:", m.diffContextB)
+	}
 	content := []string{
 		title,
 		"",
-		dialogTextStyle.Render(fmt.Sprintf("Compare: %s/%s", currentNs, app)),
+		dialogTextStyle.Render(target),
 		"",
-		dialogTextStyle.Render("With namespace:"),
+		dialogTextStyle.Render(with),
+	}
+	if len(m.diffNamespaces) == 0 {
+		content = append(content, mutedStyle.Render("  No other namespace"))
 	}
 
 	maxItems := 10
@@ -513,10 +1292,14 @@ func (m Model) renderDiffSelect() string {
 			prefix = "> "
 			style = selectedItemStyle
 		}
-		content = append(content, style.Render(prefix+m.diffNamespaces[i]))
+		content = append(content, style.Render(prefix+m.diffNamespaceLabelB(m.diffNamespaces[i])))
 	}
 
-	content = append(content, "", helpStyle.Render("↑↓: select  Enter: compare  Esc: cancel"))
+	help := "↑↓: select  Enter: compare  c: other cluster  Esc: cancel"
+	if m.diffBulk {
+		help = "↑↓: select  Enter: compare  Esc: cancel"
+	}
+	content = append(content, "", helpStyle.Render(help))
 
 	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
 }
@@ -524,10 +1307,10 @@ func (m Model) renderDiffSelect() string {
 // renderDiffView renders the diff comparison view
 func (m Model) renderDiffView() string {
 	// Full screen diff view
-	title := titleStyle.Render(fmt.Sprintf("Diff: %s vs %s / %s", m.diffNsA, m.diffNsB, m.diffAppName))
+	title := titleStyle.Render(fmt.Sprintf("Diff: %s / %s", m.diffTitle(), m.diffAppName))
 
 	// Header
-	header := fmt.Sprintf("%-20s %-20s %-20s %s", "NAME", m.diffNsA, m.diffNsB, "STATUS")
+	header := fmt.Sprintf("%-20s %-20s %-20s %s", "NAME", m.namespaceLabel(m.diffNsA), m.diffNamespaceLabelB(m.diffNsB), "STATUS")
 
 	content := []string{title, "", helpStyle.Render(header), ""}
 
@@ -543,7 +1326,63 @@ func (m Model) renderDiffView() string {
 	}
 
 	// Help line
-	content = append(content, "", helpStyle.Render("↑↓: scroll  Esc: back to main view"))
+	content = append(content, "", helpStyle.Render("↑↓: scroll  Enter: value diff / ConfigMap diff  E: export  K: kubectl  Esc: back to main view"))
+	if m.statusMessage != "" {
+		content = append(content, warningStyle.Render(m.statusMessage))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// namespaceLabel returns the namespace name with its tier, if any (e.g. "shop-prd [prod]")
+func (m Model) namespaceLabel(ns string) string {
+	if tier := m.tierOf(ns); tier != "" {
+		return fmt.Sprintf("%s [%s]", ns, tier)
+	}
+	return ns
+}
+
+// diffTitle describes the compared namespaces, showing the promotion direction when known
+func (m Model) diffTitle() string {
+	if m.crossCluster() {
+		return fmt.Sprintf("%s/%s vs %s", m.context, m.namespaceLabel(m.diffNsA), m.diffNamespaceLabelB(m.diffNsB))
+	}
+	if m.isPromotion(m.diffNsA, m.diffNsB) {
+		return fmt.Sprintf("%s → %s (promotion)", m.namespaceLabel(m.diffNsA), m.namespaceLabel(m.diffNsB))
+	}
+	if m.isPromotion(m.diffNsB, m.diffNsA) {
+		return fmt.Sprintf("%s ← %s (promotion)", m.namespaceLabel(m.diffNsA), m.namespaceLabel(m.diffNsB))
+	}
+	return fmt.Sprintf("%s vs %s", m.namespaceLabel(m.diffNsA), m.namespaceLabel(m.diffNsB))
+}
+
+// renderConfigMapDiffView renders the key-level diff of two ConfigMaps
+func (m Model) renderConfigMapDiffView() string {
+	labelA := fmt.Sprintf("%s/cm/%s", m.diffNsA, m.cmDiffNameA)
+	labelB := fmt.Sprintf("%s/cm/%s", m.diffNamespaceLabelB(m.diffNsB), m.cmDiffNameB)
+	title := titleStyle.Render(fmt.Sprintf("ConfigMap Diff: %s vs %s", labelA, labelB))
+
+	// Header
+	header := fmt.Sprintf("%-20s %-20s %-20s %s", "KEY", m.namespaceLabel(m.diffNsA), m.diffNamespaceLabelB(m.diffNsB), "STATUS")
+
+	content := []string{title, "", helpStyle.Render(header), ""}
+
+	if len(m.cmDiffResults) == 0 {
+		content = append(content, mutedStyle.Render("  Both ConfigMaps are empty or missing"))
+	}
+
+	maxItems := m.height - 10
+	startIdx := 0
+	if m.cmDiffCursor >= maxItems {
+		startIdx = m.cmDiffCursor - maxItems + 1
+	}
+
+	for i := startIdx; i < len(m.cmDiffResults) && i < startIdx+maxItems; i++ {
+		content = append(content, m.renderDiffRow(m.cmDiffResults[i], i == m.cmDiffCursor))
+	}
+
+	// Help line
+	content = append(content, "", helpStyle.Render("↑↓: scroll  Esc: back to env diff"))
 
 	return lipgloss.JoinVertical(lipgloss.Left, content...)
 }
@@ -560,32 +1399,8 @@ func (m Model) renderDiffRow(result env.DiffResult, selected bool) string {
 		name = name[:15] + "..."
 	}
 
-	valueA := "(not present)"
-	valueB := "(not present)"
-
-	if result.EnvA != nil {
-		if result.EnvA.IsSecret() {
-			valueA = fmt.Sprintf("HASH: %s", result.EnvA.Hash)
-		} else {
-			valueA = result.EnvA.Value
-		}
-	}
-
-	if result.EnvB != nil {
-		if result.EnvB.IsSecret() {
-			valueB = fmt.Sprintf("HASH: %s", result.EnvB.Hash)
-		} else {
-			valueB = result.EnvB.Value
-		}
-	}
-
-	// Truncate values
-	if len(valueA) > 18 {
-		valueA = valueA[:15] + "..."
-	}
-	if len(valueB) > 18 {
-		valueB = valueB[:15] + "..."
-	}
+	valueA := m.diffCell(result.EnvA)
+	valueB := m.diffCell(result.EnvB)
 
 	// Status styling
 	statusStyle := diffSameStyle
@@ -596,11 +1411,13 @@ func (m Model) renderDiffRow(result env.DiffResult, selected bool) string {
 		statusStyle = diffRemovedStyle
 	case env.DiffStatusOnlyInB:
 		statusStyle = diffAddedStyle
+	case env.DiffStatusForbidden:
+		statusStyle = envForbiddenStyle
 	}
 
 	status := statusStyle.Render(string(result.Status))
 
-	row := fmt.Sprintf("%-18s %-18s %-18s %s", name, valueA, valueB, status)
+	row := fmt.Sprintf("%-18s %s %s %s", name, valueA, valueB, status)
 
 	if selected {
 		return selectedItemStyle.Render(prefix + row)
@@ -645,11 +1462,15 @@ func (m Model) renderSealInput() string {
 
 	// Show focus indicator
 	secretLabel := "Secret name:"
+	keyLabel := "Key (optional, for a SealedSecret manifest):"
 	valueLabel := "Plain text: "
-	if m.sealFocusField == 0 {
-		secretLabel = "▶ Secret name:"
-	} else {
-		valueLabel = "▶ Plain text: "
+	switch m.sealFocusField {
+	case 0:
+		secretLabel = "▶ " + secretLabel
+	case 1:
+		keyLabel = "▶ " + keyLabel
+	default:
+		valueLabel = "▶ " + valueLabel
 	}
 
 	// Build command preview
@@ -658,6 +1479,9 @@ func (m Model) renderSealInput() string {
 		secretName = "<secret-name>"
 	}
 	cmdPreview := fmt.Sprintf("kubeseal --raw --from-file=/dev/stdin --namespace %s --name %s", ns, secretName)
+	if secretKey := strings.TrimSpace(m.sealKeyInput.Value()); secretKey != "" {
+		cmdPreview = fmt.Sprintf("kubectl create secret generic %s -n %s --from-file=%s=/dev/stdin --dry-run=client -o yaml | kubeseal -o yaml", secretName, ns, secretKey)
+	}
 
 	content := []string{
 		title,
@@ -667,18 +1491,34 @@ func (m Model) renderSealInput() string {
 		dialogTextStyle.Render(secretLabel),
 		m.sealSecretInput.View(),
 		"",
+		dialogTextStyle.Render(keyLabel),
+		m.sealKeyInput.View(),
+		"",
 		dialogTextStyle.Render(valueLabel),
-		m.sealValueInput.View(),
+		m.renderMaskedSealValue(),
 		"",
-		mutedStyle.Render("Command:"),
+		mutedStyle.Render("Equivalent kubeseal command:"),
 		mutedStyle.Render(cmdPreview),
 		"",
-		helpStyle.Render("Tab: switch field  Enter: seal  Esc: cancel"),
+		helpStyle.Render("Tab: switch field  Ctrl+J: newline  Enter: seal  Esc: cancel"),
 	}
 
 	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
 }
 
+// renderMaskedSealValue renders the seal plaintext input without exposing its content
+func (m Model) renderMaskedSealValue() string {
+	value := m.sealValueInput.Value()
+	if value == "" {
+		return mutedStyle.Render("(type or paste the value; input is masked)")
+	}
+
+	runes := len([]rune(value))
+	lines := strings.Count(value, "\n") + 1
+	mask := strings.Repeat("•", min(runes, 40))
+	return envSecretStyle.Render(mask) + " " + mutedStyle.Render(fmt.Sprintf("(%d chars, %d lines)", runes, lines))
+}
+
 // renderSealResult renders the seal result dialog
 func (m Model) renderSealResult() string {
 	dialog := dialogStyle.Width(80)
@@ -710,12 +1550,457 @@ func (m Model) renderSealResult() string {
 			dialogTextStyle.Render(fmt.Sprintf("Namespace: %s", ns)),
 			dialogTextStyle.Render(fmt.Sprintf("Secret: %s", m.sealSecretName)),
 			"",
-			envValueStyle.Render(m.sealResult),
-			"",
-			helpStyle.Render(copyStatus),
-			helpStyle.Render("Press any key to close"),
 		}
+
+		if m.sealManifest != "" {
+			if m.sealCopied {
+				copyStatus = "✓ Manifest copied to clipboard!"
+			} else {
+				copyStatus = "c: copy manifest"
+			}
+			applyStatus := fmt.Sprintf("a: apply to SealedSecret %s (key %s)", m.sealSecretName, m.sealKey)
+			if m.sealApplied {
+				applyStatus = "✓ Applied: the controller will update Secret " + m.sealSecretName
+			}
+			content = append(content,
+				envValueStyle.Render(strings.TrimRight(m.sealManifest, "\n")),
+				"",
+				helpStyle.Render(copyStatus),
+				helpStyle.Render(applyStatus),
+			)
+		} else {
+			content = append(content,
+				envValueStyle.Render(m.sealResult),
+				"",
+				helpStyle.Render(copyStatus),
+			)
+		}
+		content = append(content, helpStyle.Render("Press any key to close"))
+	}
+
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
+
+// renderVerifyResult renders the SealedSecret verification result dialog
+func (m Model) renderVerifyResult() string {
+	dialog := dialogStyle.Width(80)
+	r := m.verifyResult
+
+	title := dialogTitleStyle.Render("Verify SealedSecret: " + r.Name)
+
+	decrypt := mutedStyle.Render("unknown")
+	if r.Decryptable != nil {
+		if *r.Decryptable {
+			decrypt = diffAddedStyle.Render("OK")
+		} else {
+			decrypt = diffRemovedStyle.Render("FAILED")
+		}
+	}
+
+	content := []string{
+		title,
+		"",
+		dialogTextStyle.Render(fmt.Sprintf("Secret: %s/%s", r.Namespace, r.SecretName)),
+		dialogTextStyle.Render("Controller decrypt: ") + decrypt,
+	}
+
+	if len(r.MissingKeys) > 0 {
+		content = append(content, diffRemovedStyle.Render("Missing in live Secret: "+strings.Join(r.MissingKeys, ", ")))
+	}
+	if len(r.ExtraKeys) > 0 {
+		content = append(content, diffChangedStyle.Render("Not in sealed manifest: "+strings.Join(r.ExtraKeys, ", ")))
+	}
+	for _, warning := range r.Warnings {
+		content = append(content, warningStyle.Render("! "+warning))
+	}
+
+	content = append(content, "")
+	switch {
+	case r.Stale():
+		content = append(content, errorStyle.Render("Sealed manifest is stale"))
+	case r.Unverified():
+		content = append(content, warningStyle.Render("Keys match, values not compared (no private key)"))
+		content = append(content, mutedStyle.Render("Compare values with: envtop verify --private-key <file>"))
+	default:
+		content = append(content, diffAddedStyle.Render("Sealed manifest matches the live Secret"))
+	}
+	content = append(content, "", helpStyle.Render("Press any key to close"))
+
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
+
+// renderConnectivity renders the connectivity check result
+func (m Model) renderConnectivity() string {
+	dialog := dialogStyle.Width(80)
+	r := m.connResult
+
+	title := dialogTitleStyle.Render("Connectivity: " + m.connEnvName)
+	content := []string{
+		title,
+		"",
+		dialogTextStyle.Render("Endpoint: " + r.Endpoint.String()),
+		"",
+	}
+
+	for _, step := range r.Steps {
+		var mark string
+		switch step.Status {
+		case netcheck.StepOK:
+			mark = diffAddedStyle.Render("✓")
+		case netcheck.StepFailed:
+			mark = diffRemovedStyle.Render("✗")
+		default:
+			mark = mutedStyle.Render("-")
+		}
+		content = append(content, fmt.Sprintf("%s %-10s %s", mark, step.Name, dialogTextStyle.Render(step.Detail)))
+	}
+
+	content = append(content, "")
+	if r.OK() {
+		content = append(content, diffAddedStyle.Render("Endpoint exists in the cluster"))
+	} else {
+		content = append(content, errorStyle.Render("Endpoint is not reachable"))
+	}
+	content = append(content, "", helpStyle.Render("f: TCP connect via port-forward  Other keys: close"))
+
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
+
+// renderKubectl renders the kubectl commands equivalent to the previous view
+func (m Model) renderKubectl() string {
+	width := m.width - 4
+	if width > 120 {
+		width = 120
+	}
+	dialog := dialogStyle.Width(width)
+
+	title := dialogTitleStyle.Render("Equivalent kubectl commands")
+	content := []string{title, ""}
+	for _, command := range m.kubectlCommands {
+		content = append(content, envValueStyle.Render(command))
+	}
+
+	copyStatus := "c: copy to clipboard"
+	if m.kubectlCopied {
+		copyStatus = "✓ Copied to clipboard!"
+	}
+	content = append(content, "", helpStyle.Render(copyStatus+"  Other keys: close"))
+	if m.statusMessage != "" {
+		content = append(content, warningStyle.Render(m.statusMessage))
+	}
+
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
+
+// renderPreviewCleanup renders stale preview namespaces with their deletion commands
+func (m Model) renderPreviewCleanup() string {
+	title := titleStyle.Render(fmt.Sprintf("Stale Preview Namespaces (older than %d days)", m.cfg.PreviewMaxAgeDays()))
+	content := []string{title, ""}
+
+	if len(m.staleNamespaces) == 0 {
+		content = append(content, mutedStyle.Render("  No stale preview namespaces"))
+		content = append(content, "", helpStyle.Render("Esc: back to main view"))
+		return lipgloss.JoinVertical(lipgloss.Left, content...)
+	}
+
+	header := fmt.Sprintf("  %-32s %-12s %-6s %s", "NAMESPACE", "CREATED", "AGE", "APPS")
+	content = append(content, helpStyle.Render(header))
+
+	commands := preview.DeleteCommands(m.context, m.staleNamespaces)
+	maxItems := (m.height - 10) / 2
+	if maxItems < 1 {
+		maxItems = 1
+	}
+	startIdx := 0
+	if m.staleCursor >= maxItems {
+		startIdx = m.staleCursor - maxItems + 1
+	}
+
+	for i := startIdx; i < len(m.staleNamespaces) && i < startIdx+maxItems; i++ {
+		ns := m.staleNamespaces[i]
+		prefix := "  "
+		style := itemStyle
+		if i == m.staleCursor {
+			prefix = "> "
+			style = selectedItemStyle
+		}
+		apps := truncate(strings.Join(ns.Apps, ","), 40)
+		if apps == "" {
+			apps = "(none)"
+		}
+		row := fmt.Sprintf("%s%-32s %-12s %-6s %s", prefix, truncate(ns.Name, 32), ns.CreatedAt.Format("2006-01-02"), formatAge(time.Since(ns.CreatedAt)), apps)
+		content = append(content, style.Render(row))
+	}
+
+	content = append(content, "")
+	for i := startIdx; i < len(commands) && i < startIdx+maxItems; i++ {
+		content = append(content, envValueStyle.Render("  "+commands[i]))
+	}
+
+	copyStatus := "c: copy deletion commands"
+	if m.staleCopied {
+		copyStatus = "✓ Copied to clipboard!"
+	}
+	content = append(content, "", helpStyle.Render("↑↓: scroll  "+copyStatus+"  Esc: back to main view"))
+	if m.statusMessage != "" {
+		content = append(content, warningStyle.Render(m.statusMessage))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// podPlacement renders a pod's node and zone
+func podPlacement(pod k8s.Pod) string {
+	node := pod.NodeName
+	if node == "" {
+		node = "(unscheduled)"
+	}
+	if pod.Zone == "" {
+		return node
+	}
+	return node + " / " + pod.Zone
+}
+
+// renderPodSelect renders the pod picker dialog
+func (m Model) renderPodSelect() string {
+	dialog := dialogStyle.Width(90)
+
+	app := m.apps[m.appIdx]
+	title := dialogTitleStyle.Render("Select pod: " + app.Name)
+
+	content := []string{
+		title,
+		"",
+		m.podFilterInput.View(),
+		"",
+		helpStyle.Render(fmt.Sprintf("  %-40s %-30s %s", "POD", "NODE / ZONE", "PHASE")),
+	}
+
+	pods := m.filteredPods()
+
+	// Row 0 is the workload template
+	templateStyle := dialogTextStyle
+	templatePrefix := "  "
+	if m.podCursor == 0 {
+		templateStyle = selectedItemStyle
+		templatePrefix = "> "
+	}
+	content = append(content, templateStyle.Render(templatePrefix+"(workload template)"))
+
+	maxItems := 12
+	startIdx := 0
+	if m.podCursor-1 >= maxItems {
+		startIdx = m.podCursor - maxItems
+	}
+
+	for i := startIdx; i < len(pods) && i < startIdx+maxItems; i++ {
+		pod := pods[i]
+		prefix := "  "
+		style := dialogTextStyle
+		if i+1 == m.podCursor {
+			prefix = "> "
+			style = selectedItemStyle
+		}
+
+		name := pod.Name
+		if len(name) > 40 {
+			name = name[:37] + "..."
+		}
+		placement := podPlacement(pod)
+		if len(placement) > 30 {
+			placement = placement[:27] + "..."
+		}
+		content = append(content, style.Render(fmt.Sprintf("%s%-40s %-30s %s", prefix, name, placement, pod.Phase)))
+	}
+
+	if len(pods) == 0 {
+		content = append(content, mutedStyle.Render("  No matching pods"))
+	}
+
+	content = append(content, "", helpStyle.Render("Type: filter  ↑↓: select  Enter: resolve  Esc: cancel"))
+
+	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
+}
+
+// renderWorklist renders the drift triage worklist
+func (m Model) renderWorklist() string {
+	pending, expected, needsFix, failed := 0, 0, 0, 0
+	for _, item := range m.worklist {
+		if item.Err != nil {
+			failed++
+			continue
+		}
+		switch item.Mark {
+		case drift.MarkExpected:
+			expected++
+		case drift.MarkNeedsFix:
+			needsFix++
+		default:
+			pending++
+		}
+	}
+
+	title := titleStyle.Render(fmt.Sprintf("Drift Worklist: %s vs %s", m.worklistNsA, m.worklistNsB))
+	summary := helpStyle.Render(fmt.Sprintf("pending %d  expected %d  needs fix %d", pending, expected, needsFix))
+	if failed > 0 {
+		summary += errorStyle.Render(fmt.Sprintf("  not compared %d", failed))
+	}
+	header := fmt.Sprintf("  %-8s %-20s %-24s %-18s %-18s %s", "MARK", "APP", "NAME", m.worklistNsA, m.worklistNsB, "STATUS")
+
+	content := []string{title, summary, m.renderDriftScore(), "", helpStyle.Render(header)}
+
+	if len(m.worklist) == 0 {
+		content = append(content, mutedStyle.Render("  No unresolved drift between these namespaces"))
+	}
+
+	maxItems := m.height - 11
+	startIdx := 0
+	if m.worklistCursor >= maxItems {
+		startIdx = m.worklistCursor - maxItems + 1
+	}
+
+	for i := startIdx; i < len(m.worklist) && i < startIdx+maxItems; i++ {
+		item := m.worklist[i]
+		prefix := "  "
+		style := itemStyle
+		if i == m.worklistCursor {
+			prefix = "> "
+			style = selectedItemStyle
+		}
+
+		app := item.App
+		if len(app) > 20 {
+			app = app[:17] + "..."
+		}
+		if item.Err != nil {
+			row := style.Render(prefix) + errorStyle.Render(fmt.Sprintf("%-8s", "failed")) + style.Render(fmt.Sprintf(" %-20s ", app)) +
+				errorStyle.Render(truncate(item.Err.Error(), max(m.width-36, 20)))
+			content = append(content, row)
+			continue
+		}
+
+		mark := mutedStyle.Render(fmt.Sprintf("%-8s", "pending"))
+		switch item.Mark {
+		case drift.MarkExpected:
+			mark = diffSameStyle.Render(fmt.Sprintf("%-8s", "expected"))
+		case drift.MarkNeedsFix:
+			mark = diffRemovedStyle.Render(fmt.Sprintf("%-8s", "fix"))
+		}
+
+		name := item.Result.Name
+		if len(name) > 24 {
+			name = name[:21] + "..."
+		}
+		valueA := truncate(m.diffValue(item.Result.EnvA), 18)
+		valueB := truncate(m.diffValue(item.Result.EnvB), 18)
+
+		row := style.Render(prefix) + mark + style.Render(fmt.Sprintf(" %-20s %-24s %-18s %-18s ", app, name, valueA, valueB)) + diffChangedStyle.Render(string(item.Result.Status))
+		content = append(content, row)
+	}
+
+	content = append(content, "", helpStyle.Render("↑↓: move  e: expected (ignore)  f: needs fix  u: unmark  x: export fix list  Esc: back"))
+	if m.statusMessage != "" {
+		content = append(content, warningStyle.Render(m.statusMessage))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// renderDriftScore renders the drift score of the worklist namespaces with its trend
+// and the apps whose score got worse since the previous recording
+func (m Model) renderDriftScore() string {
+	total := m.worklistScores.Total
+	line := helpStyle.Render(fmt.Sprintf("drift score %d ", total.Value)) + renderTrend(total)
+
+	var worse []string
+	for app, score := range m.worklistScores.Apps {
+		if score.Trend() == drift.TrendWorsening {
+			worse = append(worse, fmt.Sprintf("%s %d→%d", app, score.Previous, score.Value))
+		}
+	}
+	if len(worse) > 0 {
+		sort.Strings(worse)
+		line += helpStyle.Render("  worse: ") + diffRemovedStyle.Render(strings.Join(worse, ", "))
+	}
+	return line
+}
+
+// renderTrend renders a score trend with its previous value
+func renderTrend(score drift.Score) string {
+	text := fmt.Sprintf("%s %s", score.Trend().Symbol(), score.Trend())
+	if score.HasPrevious {
+		text += fmt.Sprintf(" (was %d)", score.Previous)
+	}
+	switch score.Trend() {
+	case drift.TrendImproving:
+		return diffSameStyle.Render(text)
+	case drift.TrendWorsening:
+		return diffRemovedStyle.Render(text)
+	default:
+		return mutedStyle.Render(text)
+	}
+}
+
+// renderWorklistJustify renders the justification prompt for an expected drift
+func (m Model) renderWorklistJustify() string {
+	dialog := dialogStyle.Width(70)
+	item := m.worklist[m.worklistCursor]
+
+	content := []string{
+		dialogTitleStyle.Render("Mark drift as expected"),
+		"",
+		dialogTextStyle.Render(fmt.Sprintf("%s: %s (%s)", item.App, item.Result.Name, item.Result.Status)),
+		"",
+		dialogTextStyle.Render("Justification:"),
+		m.justifyInput.View(),
+		"",
+		helpStyle.Render("Enter: save ignore  Esc: cancel"),
+	}
+	if m.statusMessage != "" {
+		content = append(content, warningStyle.Render(m.statusMessage))
 	}
 
 	return m.centerDialog(dialog.Render(strings.Join(content, "\n")))
 }
+
+// diffValue renders one side of a diff result (hash for secrets and redacted values)
+func (m Model) diffValue(ev *k8s.EnvVar) string {
+	if ev == nil {
+		return "(not present)"
+	}
+	if ev.IsMasked() {
+		return m.hashLabel(ev.Hash)
+	}
+	return ev.Value
+}
+
+// diffCell renders one side of a diff row in an 18 cell column. Fingerprints
+// are not cut, so that they can still be compared by eye.
+func (m Model) diffCell(ev *k8s.EnvVar) string {
+	value := m.diffValue(ev)
+	if ev == nil || !ev.IsMasked() {
+		value = truncate(value, 18)
+	}
+	if pad := 18 - lipgloss.Width(value); pad > 0 {
+		value += strings.Repeat(" ", pad)
+	}
+	return value
+}
+
+// hashLabel renders the hash of a masked value in the configured fingerprint style
+func (m Model) hashLabel(hash string) string {
+	return "HASH: " + fingerprint.Format(m.hashStyle, hash)
+}
+
+// truncate shortens s to at most n bytes, adding an ellipsis. The cut never
+// splits a multi-byte character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := n - 3
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
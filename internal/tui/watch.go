@@ -0,0 +1,354 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/env"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+)
+
+// watchDebounce coalesces bursts of watch events into one refresh
+const watchDebounce = 500 * time.Millisecond
+
+// Watch messages carry the stream they came from, so events of a replaced watch are dropped
+type (
+	watchEventMsg struct {
+		events <-chan k8s.WatchEvent
+		event  k8s.WatchEvent
+	}
+	watchFlushMsg struct {
+		events <-chan k8s.WatchEvent
+	}
+)
+
+// watchPending records which panes a debounced refresh must reload
+type watchPending struct {
+	namespaces bool
+	apps       bool
+	env        bool
+	scheduled  bool
+}
+
+// startWatch watches the selected namespace, replacing the previous watch
+func (m *Model) startWatch() tea.Cmd {
+	if m.watchCancel != nil {
+		m.watchCancel()
+	}
+	ns := m.namespaces[m.namespaceIdx]
+	ctx, cancel := context.WithCancel(context.Background())
+	m.watchCancel = cancel
+	m.watchEvents = m.client.Watch(ctx, ns)
+	m.watchKey = m.context + "/" + ns
+	m.watchPending = watchPending{}
+	m.envCache = nil
+	m.envCacheOff = false
+	m.watchSeen = nil
+	return waitForWatch(m.watchEvents)
+}
+
+// waitForWatch returns a command delivering the next event of a watch
+func waitForWatch(events <-chan k8s.WatchEvent) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-events
+		if !ok {
+			return nil
+		}
+		return watchEventMsg{events: events, event: event}
+	}
+}
+
+// handleWatchEvent marks the panes affected by a change and schedules their refresh
+func (m Model) handleWatchEvent(msg watchEventMsg) (tea.Model, tea.Cmd) {
+	if msg.events != m.watchEvents {
+		return m, nil
+	}
+	cmds := []tea.Cmd{waitForWatch(m.watchEvents)}
+
+	event := msg.event
+	m.invalidateEnvCache(event)
+	switch {
+	case event.Stopped:
+	case event.Resync:
+		// Changes may have been missed: reload everything the kind can affect
+		if event.Kind == k8s.WatchKindNamespace {
+			m.watchPending.namespaces = true
+		} else {
+			m.watchPending.apps = true
+			m.watchPending.env = true
+		}
+	case event.Kind == k8s.WatchKindNamespace:
+		m.watchPending.namespaces = true
+	case event.Kind == k8s.WatchKindConfigMap, event.Kind == k8s.WatchKindSecret:
+		if m.referencesSource(event.Kind, event.Name) {
+			m.watchPending.env = true
+		}
+	default:
+		m.watchPending.apps = true
+		if len(m.apps) > 0 && m.appIdx < len(m.apps) {
+			app := m.apps[m.appIdx]
+			if string(app.Kind) == event.Kind && app.Name == event.Name {
+				m.watchPending.env = true
+			}
+		}
+	}
+
+	p := &m.watchPending
+	if !p.scheduled && (p.namespaces || p.apps || p.env) {
+		p.scheduled = true
+		events := m.watchEvents
+		cmds = append(cmds, tea.Tick(watchDebounce, func(time.Time) tea.Msg {
+			return watchFlushMsg{events: events}
+		}))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// handleWatchFlush refreshes the panes affected by the changes seen since the last flush
+func (m Model) handleWatchFlush(msg watchFlushMsg) (tea.Model, tea.Cmd) {
+	if msg.events != m.watchEvents {
+		return m, nil
+	}
+	pending := m.watchPending
+	m.watchPending = watchPending{}
+
+	var cmds []tea.Cmd
+	if pending.namespaces {
+		cmds = append(cmds, m.refreshNamespaces())
+	}
+	if pending.apps {
+		cmds = append(cmds, m.refreshApps())
+	}
+	if pending.env && len(m.apps) > 0 {
+		cmds = append(cmds, m.resolveEnvVars(true))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// referencesSource returns true if the env of the selected app reads the ConfigMap or Secret
+func (m Model) referencesSource(kind, name string) bool {
+	for _, ev := range m.containerEnv {
+		if ev.SourceName != name {
+			continue
+		}
+		if kind == k8s.WatchKindConfigMap && ev.SourceKind == k8s.EnvSourceConfigMap {
+			return true
+		}
+		if kind == k8s.WatchKindSecret && ev.IsSecret() {
+			return true
+		}
+	}
+	return false
+}
+
+// handleReload reloads the namespaces, apps and env from the cluster, bypassing
+// the env cache and the ConfigMaps and Secrets kept by the resolver. Like a live
+// refresh, it keeps the selections and the cursors.
+func (m Model) handleReload() (tea.Model, tea.Cmd) {
+	if len(m.namespaces) == 0 {
+		m.loading = true
+		return m, m.loadNamespaces()
+	}
+	m.resolver.InvalidateKind("")
+	m.statusMessage = "Reloaded"
+	return m, tea.Batch(m.refreshNamespaces(), m.refreshApps(), m.resolveEnvVars(true), m.clearStatusAfter(2*time.Second))
+}
+
+// refreshNamespaces reloads the namespace list, keeping the selection
+func (m Model) refreshNamespaces() tea.Cmd {
+	load := m.loadNamespaces()
+	if m.fleet == nil {
+		load = m.loadAllNamespaces()
+	}
+	return func() tea.Msg {
+		// A failed background refresh keeps the current list
+		loaded, ok := load().(namespacesLoadedMsg)
+		if !ok {
+			return nil
+		}
+		loaded.refresh = true
+		return loaded
+	}
+}
+
+// refreshApps reloads the apps of the selected namespace, keeping the selection
+func (m Model) refreshApps() tea.Cmd {
+	namespace := m.namespaces[m.namespaceIdx]
+	return func() tea.Msg {
+		apps, err := m.client.ListApps(context.Background(), namespace)
+		if err != nil {
+			return nil // a failed background refresh keeps the current list
+		}
+		return appsLoadedMsg{apps: apps, refresh: true}
+	}
+}
+
+// liveChanges returns the variables whose value differs between two loads of the same env
+func liveChanges(before, after []k8s.EnvVar) map[string]bool {
+	old := make(map[string]string, len(before))
+	for _, ev := range before {
+		old[ev.Name] = liveValueKey(ev)
+	}
+	changed := make(map[string]bool)
+	for _, ev := range after {
+		if v, ok := old[ev.Name]; !ok || v != liveValueKey(ev) {
+			changed[ev.Name] = true
+		}
+	}
+	return changed
+}
+
+// liveValueKey identifies the value and source of a variable, by hash when it is masked
+func liveValueKey(ev k8s.EnvVar) string {
+	value := ev.Value
+	if ev.IsMasked() {
+		value = ev.Hash
+	}
+	return string(ev.SourceKind) + "/" + ev.SourceName + "/" + value
+}
+
+// renderLiveBadge marks a variable updated by the live watch since the app was selected
+func (m Model) renderLiveBadge(ev k8s.EnvVar) string {
+	if !m.liveChanged[ev.Name] {
+		return ""
+	}
+	return " " + warningStyle.Render("*updated")
+}
+
+// applyNamespaceRefresh replaces the namespace list, keeping the selected namespace and
+// the cursor. It returns false (and resets the selection) if the selected namespace is gone.
+func (m *Model) applyNamespaceRefresh(msg namespacesLoadedMsg) bool {
+	if len(m.namespaces) == 0 {
+		return false
+	}
+	keyAt := func(namespaces, contexts []string, i int) string {
+		kubeContext := m.context
+		if i < len(contexts) {
+			kubeContext = contexts[i]
+		}
+		return m.nsKey(kubeContext, namespaces[i])
+	}
+
+	index := make(map[string]int, len(msg.namespaces))
+	for i := range msg.namespaces {
+		index[keyAt(msg.namespaces, msg.contexts, i)] = i
+	}
+	selected, ok := index[keyAt(m.namespaces, m.namespaceContexts, m.namespaceIdx)]
+	if !ok {
+		m.namespaceIdx = 0
+		m.namespaceCursor = 0
+		return false
+	}
+	// A cursor whose namespace is gone stays at the same position
+	cursor := min(m.namespaceCursor, len(msg.namespaces)-1)
+	if m.namespaceCursor < len(m.namespaces) {
+		if i, ok := index[keyAt(m.namespaces, m.namespaceContexts, m.namespaceCursor)]; ok {
+			cursor = i
+		}
+	}
+
+	m.namespaces = msg.namespaces
+	m.namespaceContexts = msg.contexts
+	m.namespaceTiers = msg.tiers
+	m.namespaceCreated = msg.createdAt
+	m.namespaceIdx = selected
+	m.namespaceCursor = cursor
+	if m.IsSearchingPane(PaneNamespaces) {
+		m.updateFilter(m.searchInput.Value())
+	}
+	return true
+}
+
+// applyAppsRefresh replaces the app list, keeping the selected app and the cursor.
+// It returns false if the selected app is gone.
+func (m *Model) applyAppsRefresh(apps []k8s.App) bool {
+	if m.appIdx >= len(m.apps) {
+		return false
+	}
+	selected := m.apps[m.appIdx]
+	var cursorApp *k8s.App
+	if filtered := m.GetFilteredApps(); m.appCursor < len(filtered) {
+		cursorApp = &m.apps[filtered[m.appCursor]]
+	}
+
+	selectedIdx := -1
+	for i, app := range apps {
+		if app.Name == selected.Name && app.Kind == selected.Kind {
+			selectedIdx = i
+		}
+	}
+	if selectedIdx < 0 {
+		return false
+	}
+
+	cursor := -1
+	if cursorApp != nil {
+		for i, app := range apps {
+			if app.Name == cursorApp.Name && app.Kind == cursorApp.Kind {
+				cursor = i
+			}
+		}
+	}
+
+	// A cursor whose app is gone stays at the same position
+	previous := m.appCursor
+	m.apps = apps
+	m.appIdx = selectedIdx
+	if m.IsSearchingPane(PaneApps) {
+		m.updateFilter(m.searchInput.Value())
+	}
+	filtered := m.GetFilteredApps()
+	m.appCursor = max(0, min(previous, len(filtered)-1))
+	for pos, i := range filtered {
+		if i == cursor {
+			m.appCursor = pos
+		}
+	}
+	return true
+}
+
+// applyEnvRefresh replaces the env of the selected app, keeping the container
+// selection and the cursor, and marks the variables whose value changed
+func (m *Model) applyEnvRefresh(msg envVarsLoadedMsg) {
+	m.injectedVars = msg.injected
+	changed := liveChanges(env.MergeContainers(m.containerEnv), env.MergeContainers(m.visibleEnv(msg.containerEnv)))
+	if len(changed) > 0 && m.liveChanged == nil {
+		m.liveChanged = make(map[string]bool)
+	}
+	for name := range changed {
+		m.liveChanged[name] = true
+	}
+
+	cursorName := ""
+	if ev, ok := m.selectedEnvVar(); ok {
+		cursorName = ev.Name
+	}
+	container := ""
+	if m.containerIdx > 0 {
+		container = m.containers[m.containerIdx-1]
+	}
+
+	m.setContainerEnv(msg.containerEnv)
+	m.missingOptional = msg.missing
+	m.showContainer(0)
+	for i, c := range m.containers {
+		if c == container {
+			m.showContainer(i + 1)
+		}
+	}
+	m.violations = m.evaluatePolicies(msg.envVars)
+
+	// A cursor whose variable is gone stays at the same position
+	previous := m.envCursor
+	if m.IsSearchingPane(PaneEnv) {
+		m.updateFilter(m.searchInput.Value())
+	}
+	rows := m.envRows()
+	m.envCursor = max(0, min(previous, len(rows)-1))
+	for pos, row := range rows {
+		if row.index >= 0 && m.envVars[row.index].Name == cursorName {
+			m.envCursor = pos
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds a single upload
+const DefaultTimeout = 60 * time.Second
+
+// Put uploads data with an HTTP PUT to a presigned object storage URL.
+// Presigned URLs of S3, GCS and Azure Blob (SAS) are supported, so no cloud
+// credentials or SDKs are needed by envtop itself.
+func Put(ctx context.Context, rawURL, contentType string, data []byte) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid upload URL (expected a presigned https URL)")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, rawURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(data))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if strings.HasSuffix(u.Hostname(), ".blob.core.windows.net") {
+		// Azure requires the blob type on Put Blob
+		req.Header.Set("x-ms-blob-type", "BlockBlob")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// The URL carries the signature; do not echo it in errors
+		return fmt.Errorf("failed to upload to %s: %w", u.Host, unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("upload to %s failed: %s: %s", u.Host, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// unwrapURLError strips the *url.Error wrapper, whose message contains the full URL
+func unwrapURLError(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		return urlErr.Err
+	}
+	return err
+}
@@ -1,30 +1,92 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
-	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/cli"
 	"github.com/ginbear/k8s-envtop/internal/k8s"
-	"github.com/ginbear/k8s-envtop/internal/tui"
+	"github.com/ginbear/k8s-envtop/pkg/envtop"
 )
 
 func main() {
-	// Initialize Kubernetes client
-	client, err := k8s.NewClient()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize Kubernetes client: %v\n", err)
-		fmt.Fprintln(os.Stderr, "Please ensure your kubeconfig is properly configured.")
-		os.Exit(1)
+	opts := envtop.Options{Input: os.Stdin, Output: os.Stdout, KubeconfigToken: os.Getenv("ENVTOP_KUBECONFIG_TOKEN")}
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		// Headless subcommands
+		if code, ok := cli.Run(os.Args[1], os.Args[2:]); ok {
+			os.Exit(code)
+		}
+	}
+	if err := parseFlags(&opts, os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		os.Exit(2)
 	}
 
-	// Create TUI model
-	model := tui.NewModel(client)
-
-	// Create and run the Bubble Tea program
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	// Run the TUI on the terminal
+	if err := envtop.Run(opts); err != nil {
+		var clientErr *envtop.ClientError
+		if errors.As(err, &clientErr) {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			fmt.Fprintln(os.Stderr, "Please ensure your kubeconfig is properly configured.")
+			os.Exit(1)
+		}
 		fmt.Fprintf(os.Stderr, "Error running envtop: %v\n", err)
+		var classified *k8s.Error
+		if errors.As(k8s.Classify(err), &classified) {
+			fmt.Fprintf(os.Stderr, "Hint: %s\n", classified.Hint)
+		}
 		os.Exit(1)
 	}
 }
+
+// parseFlags parses the flags of the TUI into opts
+func parseFlags(opts *envtop.Options, args []string) error {
+	fs := flag.NewFlagSet("envtop", flag.ContinueOnError)
+	fs.StringVar(&opts.Kubeconfig, "kubeconfig", "", "kubeconfig file, - for stdin (default $KUBECONFIG or ~/.kube/config)")
+	fs.StringVar(&opts.KubeconfigURL, "kubeconfig-url", "", "download the kubeconfig from this URL, with $ENVTOP_KUBECONFIG_TOKEN as a bearer token")
+	fs.StringVar(&opts.Context, "context", "", "kubeconfig context (default: the current context)")
+	fs.StringVar(&opts.Namespace, "namespace", "", "namespace selected on launch")
+	fs.StringVar(&opts.Namespace, "n", "", "namespace selected on launch (shorthand)")
+	fs.StringVar(&opts.App, "app", "", "app of the namespace selected on launch")
+	fs.BoolVar(&opts.ASCII, "ascii", false, "draw borders and symbols in ASCII (default on Windows consoles)")
+	fs.StringVar(&opts.ServiceAccount, "service-account", "", "run the session with a short-lived token of this service account (namespace/name)")
+	fs.BoolVar(&opts.InCluster, "in-cluster", false, "use the service account of the pod envtop runs in")
+	fs.BoolVar(&opts.Demo, "demo", false, "run against a pseudonymized copy of the cluster")
+	fs.StringVar(&opts.Offline, "offline", "", "run against an in-memory cluster holding the manifests of this directory")
+	fs.BoolVar(&opts.Tutorial, "tutorial", false, "run the guided tutorial against a built-in demo cluster")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() > 0 {
+		err := fmt.Errorf("unknown command: %s", fs.Arg(0))
+		fmt.Fprintln(fs.Output(), err)
+		return err
+	}
+	if opts.App != "" && opts.Namespace == "" {
+		err := errors.New("--app requires --namespace")
+		fmt.Fprintln(fs.Output(), err)
+		return err
+	}
+	if opts.Offline != "" && (opts.Demo || opts.InCluster || opts.Kubeconfig != "" || opts.KubeconfigURL != "" || opts.Context != "") {
+		err := errors.New("--offline cannot be combined with --demo, --in-cluster, --kubeconfig, --kubeconfig-url or --context")
+		fmt.Fprintln(fs.Output(), err)
+		return err
+	}
+	if opts.InCluster && (opts.Kubeconfig != "" || opts.KubeconfigURL != "" || opts.Context != "") {
+		err := errors.New("--in-cluster cannot be combined with --kubeconfig, --kubeconfig-url or --context")
+		fmt.Fprintln(fs.Output(), err)
+		return err
+	}
+	if opts.Kubeconfig != "" && opts.KubeconfigURL != "" {
+		err := errors.New("--kubeconfig cannot be combined with --kubeconfig-url")
+		fmt.Fprintln(fs.Output(), err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+// Package envtop runs the envtop TUI inside other programs, e.g. as a subcommand
+// of a platform CLI, or driven by teatest in integration tests
+package envtop
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ginbear/k8s-envtop/internal/config"
+	"github.com/ginbear/k8s-envtop/internal/demo"
+	"github.com/ginbear/k8s-envtop/internal/fleet"
+	"github.com/ginbear/k8s-envtop/internal/k8s"
+	"github.com/ginbear/k8s-envtop/internal/policy"
+	"github.com/ginbear/k8s-envtop/internal/tui"
+	"github.com/ginbear/k8s-envtop/internal/tutorial"
+)
+
+// Options configures an embedded envtop
+type Options struct {
+	// Input and Output default to os.Stdin and os.Stdout
+	Input  io.Reader
+	Output io.Writer
+	// ConfigPath is the config file ($ENVTOP_CONFIG or ~/.config/envtop/config.yaml by default)
+	ConfigPath string
+	// Demo runs against a pseudonymized copy of the current context's cluster,
+	// for screenshots and recordings that must not leak names or values
+	Demo bool
+	// Offline runs against an in-memory cluster holding the manifests of this
+	// directory instead of the kubeconfig clusters, e.g. the e2e fixtures
+	Offline string
+	// Kubeconfig and Context override the kubeconfig file and its current
+	// context; either one also ignores the contexts of the config file.
+	// Kubeconfig "-" reads the kubeconfig from Input, and the keys from the
+	// terminal instead.
+	Kubeconfig string
+	Context    string
+	// KubeconfigURL downloads the kubeconfig instead, sending KubeconfigToken
+	// as a bearer token, e.g. from the API handing out the kubeconfigs of
+	// preview environments. Like Kubeconfig, it ignores the contexts of the config file.
+	KubeconfigURL   string
+	KubeconfigToken string
+	// Namespace and App are selected on launch
+	Namespace string
+	App       string
+	// ServiceAccount ("namespace/name") overrides session.serviceAccount of the
+	// config file: the session uses a short-lived token of it
+	ServiceAccount string
+	// ASCII draws borders and symbols in ASCII, for terminals that render them
+	// incorrectly; Windows consoles get it by default
+	ASCII bool
+	// InCluster uses the service account of the pod envtop runs in instead of
+	// the kubeconfig; the contexts of the config file are ignored
+	InCluster bool
+	// Tutorial runs the guided tutorial against a built-in demo cluster instead
+	// of the kubeconfig clusters; the config file is ignored
+	Tutorial bool
+	// ProgramOptions are applied after envtop's own options and can override them
+	ProgramOptions []tea.ProgramOption
+}
+
+// NewModel loads the configuration and Kubernetes clients and returns the
+// envtop model, for callers that create the Bubble Tea program themselves
+func NewModel(opts Options) (tea.Model, error) {
+	return newModel(opts)
+}
+
+// NewProgram creates the envtop program on the configured input and output
+func NewProgram(opts Options) (*tea.Program, error) {
+	model, err := newModel(opts)
+	if err != nil {
+		return nil, err
+	}
+	in, out := opts.streams()
+	return tui.NewProgram(model, in, out, opts.programOptions()...), nil
+}
+
+// Run runs envtop until the user quits
+func Run(opts Options) error {
+	model, err := newModel(opts)
+	if err != nil {
+		return err
+	}
+	in, out := opts.streams()
+	return tui.Run(model, in, out, opts.programOptions()...)
+}
+
+// streams returns the input and output, defaulting to the process terminal
+func (o Options) streams() (io.Reader, io.Writer) {
+	in, out := o.Input, o.Output
+	if in == nil {
+		in = os.Stdin
+	}
+	if out == nil {
+		out = os.Stdout
+	}
+	return in, out
+}
+
+// programOptions returns the ProgramOptions, reading the keys from the terminal
+// when the input held the kubeconfig
+func (o Options) programOptions() []tea.ProgramOption {
+	if o.Kubeconfig != k8s.StdinKubeconfig {
+		return o.ProgramOptions
+	}
+	return append([]tea.ProgramOption{tea.WithInputTTY()}, o.ProgramOptions...)
+}
+
+// newModel loads the config, its value policies and the clients of the current
+// context (or every configured context) and creates the TUI model
+func newModel(opts Options) (tui.Model, error) {
+	var model tui.Model
+	if opts.Tutorial {
+		model = tui.NewModel(tutorial.NewClient(), tutorial.Config(), &policy.Set{}).WithTutorial()
+	} else {
+		var err error
+		model, err = newClusterModel(opts)
+		if err != nil {
+			return tui.Model{}, err
+		}
+		model = model.WithSelection(opts.Namespace, opts.App)
+	}
+	if opts.ASCII {
+		model = model.WithASCII()
+	}
+	return model, nil
+}
+
+// newClusterModel creates the TUI model of the kubeconfig (or in-cluster)
+// clusters, or of the demo copy of one
+func newClusterModel(opts Options) (tui.Model, error) {
+	cfgPath := opts.ConfigPath
+	if cfgPath == "" {
+		p, err := config.DefaultPath()
+		if err != nil {
+			return tui.Model{}, fmt.Errorf("failed to locate config file: %w", err)
+		}
+		cfgPath = p
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return tui.Model{}, err
+	}
+	keys, err := tui.DefaultKeyMap().Remap(cfg.Keys)
+	if err != nil {
+		return tui.Model{}, fmt.Errorf("invalid keys in %s: %w", cfgPath, err)
+	}
+	if err := tui.CheckHooks(cfg.Hooks, keys); err != nil {
+		return tui.Model{}, fmt.Errorf("invalid hooks in %s: %w", cfgPath, err)
+	}
+	for _, name := range cfg.Startup.Skip {
+		if _, err := k8s.ParseCapability(name); err != nil {
+			return tui.Model{}, fmt.Errorf("invalid startup.skip in %s: %w", cfgPath, err)
+		}
+	}
+
+	cfgDir, err := config.Dir()
+	if err != nil {
+		return tui.Model{}, fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	policies, err := policy.LoadFiles(cfgDir, cfg.Policies)
+	if err != nil {
+		return tui.Model{}, fmt.Errorf("failed to load policies: %w", err)
+	}
+
+	if opts.Demo {
+		client, err := newDemoClient(opts)
+		if err != nil {
+			return tui.Model{}, err
+		}
+		return tui.NewModel(client, cfg, policies).WithoutViewHistory(), nil
+	}
+	if opts.Offline != "" {
+		client, err := k8s.NewOfflineClient(opts.Offline)
+		if err != nil {
+			return tui.Model{}, err
+		}
+		return tui.NewModel(client, cfg, policies).WithoutViewHistory(), nil
+	}
+
+	contexts := cfg.Contexts
+	if opts.InCluster || opts.Kubeconfig != "" || opts.KubeconfigURL != "" || opts.Context != "" {
+		contexts = nil
+	}
+	clients, err := newClients(contexts, opts)
+	if err != nil {
+		return tui.Model{}, &ClientError{Err: err}
+	}
+	if opts.ServiceAccount != "" {
+		cfg.Session.ServiceAccount = opts.ServiceAccount
+	}
+	var expires time.Time
+	if cfg.Session.ServiceAccount != "" {
+		clients, expires, err = withSessionTokens(clients, cfg.Session.ServiceAccount, cfg.SessionTTL())
+		if err != nil {
+			return tui.Model{}, &ClientError{Err: err}
+		}
+	}
+
+	model := tui.NewModel(clients[0], cfg, policies)
+	if len(contexts) > 0 {
+		model = model.WithFleet(clients)
+	}
+	if cfg.Session.ServiceAccount != "" {
+		model = model.WithSessionExpiry(expires)
+	}
+	return model, nil
+}
+
+// ClientError reports that the Kubernetes clients could not be created,
+// usually because of a missing or invalid kubeconfig
+type ClientError struct {
+	Err error
+}
+
+func (e *ClientError) Error() string {
+	return fmt.Sprintf("failed to initialize Kubernetes client: %v", e.Err)
+}
+
+func (e *ClientError) Unwrap() error {
+	return e.Err
+}
+
+// newDemoClient snapshots the cluster of the current context into a
+// pseudonymized in-memory cluster
+func newDemoClient(opts Options) (*k8s.Client, error) {
+	client, err := newClient(opts)
+	if err != nil {
+		return nil, &ClientError{Err: err}
+	}
+	demoClient, err := demo.NewClient(context.Background(), client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot the cluster for demo mode: %w", err)
+	}
+	return demoClient, nil
+}
+
+// newClients creates one client per configured context, or a single client
+// when none are configured
+func newClients(contexts []config.ContextRef, opts Options) ([]*k8s.Client, error) {
+	if len(contexts) > 0 {
+		return fleet.NewClients(contexts)
+	}
+	client, err := newClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	return []*k8s.Client{client}, nil
+}
+
+// newClient creates the client of the in-cluster config, or of the selected
+// kubeconfig and context (by default $KUBECONFIG and its current context)
+func newClient(opts Options) (*k8s.Client, error) {
+	switch {
+	case opts.InCluster:
+		return k8s.NewInClusterClient()
+	case opts.KubeconfigURL != "":
+		if err := k8s.FetchKubeconfig(context.Background(), opts.KubeconfigURL, opts.KubeconfigToken); err != nil {
+			return nil, err
+		}
+		return k8s.NewClientForContext(opts.KubeconfigURL, opts.Context)
+	case opts.Kubeconfig == k8s.StdinKubeconfig:
+		in, _ := opts.streams()
+		if err := k8s.ReadKubeconfig(k8s.StdinKubeconfig, in); err != nil {
+			return nil, err
+		}
+		return k8s.NewClientForContext(k8s.StdinKubeconfig, opts.Context)
+	case opts.Kubeconfig != "" || opts.Context != "":
+		return k8s.NewClientForContext(opts.Kubeconfig, opts.Context)
+	default:
+		return k8s.NewClient()
+	}
+}
+
+// withSessionTokens replaces the credentials of the clients with short-lived
+// tokens of the session service account, minted in each cluster. It returns
+// when the first token expires.
+func withSessionTokens(clients []*k8s.Client, serviceAccount string, ttl time.Duration) ([]*k8s.Client, time.Time, error) {
+	namespace, name, err := k8s.ParseServiceAccount(serviceAccount)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var expires time.Time
+	tokenClients := make([]*k8s.Client, 0, len(clients))
+	for _, client := range clients {
+		tokenClient, exp, err := client.WithServiceAccountToken(context.Background(), namespace, name, ttl)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("%s: %w", client.GetCurrentContext(), err)
+		}
+		if expires.IsZero() || exp.Before(expires) {
+			expires = exp
+		}
+		tokenClients = append(tokenClients, tokenClient)
+	}
+	return tokenClients, expires, nil
+}